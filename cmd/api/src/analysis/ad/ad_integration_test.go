@@ -1144,7 +1144,7 @@ func TestSyncLAPSPassword(t *testing.T) {
 	}, func(harness integration.HarnessDetails, db graph.Database) {
 		if groupExpansions, err := adAnalysis.ExpandAllRDPLocalGroups(testContext.Context(), db); err != nil {
 			t.Fatalf("error expanding groups in integration test; %v", err)
-		} else if _, err := adAnalysis.PostSyncLAPSPassword(testContext.Context(), db, groupExpansions); err != nil {
+		} else if _, err := adAnalysis.PostSyncLAPSPassword(testContext.Context(), db, groupExpansions, analysis.Scope{}); err != nil {
 			t.Fatalf("error creating SyncLAPSPassword edges in integration test; %v", err)
 		} else {
 			db.ReadTransaction(context.Background(), func(tx graph.Transaction) error {
@@ -1175,7 +1175,7 @@ func TestDCSync(t *testing.T) {
 	}, func(harness integration.HarnessDetails, db graph.Database) {
 		if groupExpansions, err := adAnalysis.ExpandAllRDPLocalGroups(testContext.Context(), db); err != nil {
 			t.Fatalf("error expanding groups in integration test; %v", err)
-		} else if _, err := adAnalysis.PostDCSync(testContext.Context(), db, groupExpansions); err != nil {
+		} else if _, err := adAnalysis.PostDCSync(testContext.Context(), db, groupExpansions, analysis.Scope{}); err != nil {
 			t.Fatalf("error creating DCSync edges in integration test; %v", err)
 		} else {
 			db.ReadTransaction(context.Background(), func(tx graph.Transaction) error {
@@ -1207,7 +1207,7 @@ func TestOwnsWriteOwnerPriorCollectorVersions(t *testing.T) {
 	}, func(harness integration.HarnessDetails, db graph.Database) {
 		if groupExpansions, err := adAnalysis.ExpandAllRDPLocalGroups(testContext.Context(), db); err != nil {
 			t.Fatalf("error expanding groups in integration test; %v", err)
-		} else if _, err := adAnalysis.PostOwnsAndWriteOwner(testContext.Context(), db, groupExpansions); err != nil {
+		} else if _, err := adAnalysis.PostOwnsAndWriteOwner(testContext.Context(), db, groupExpansions, analysis.Scope{}); err != nil {
 			t.Fatalf("error creating Owns/WriteOwner edges in integration test; %v", err)
 		} else {
 			db.ReadTransaction(context.Background(), func(tx graph.Transaction) error {
@@ -1410,7 +1410,7 @@ func TestOwnsWriteOwner(t *testing.T) {
 	}, func(harness integration.HarnessDetails, db graph.Database) {
 		if groupExpansions, err := adAnalysis.ExpandAllRDPLocalGroups(testContext.Context(), db); err != nil {
 			t.Fatalf("error expanding groups in integration test; %v", err)
-		} else if _, err := adAnalysis.PostOwnsAndWriteOwner(testContext.Context(), db, groupExpansions); err != nil {
+		} else if _, err := adAnalysis.PostOwnsAndWriteOwner(testContext.Context(), db, groupExpansions, analysis.Scope{}); err != nil {
 			t.Fatalf("error creating Owns/WriteOwner edges in integration test; %v", err)
 		} else {
 			db.ReadTransaction(context.Background(), func(tx graph.Transaction) error {
@@ -1587,7 +1587,7 @@ func TestGPOAppliesTo(t *testing.T) {
 	err = arrows.WriteGraphToDatabase(graphDB, &fixture)
 	require.NoError(t, err)
 
-	if _, err := adAnalysis.PostGPOs(testCtx.Context(), graphDB); err != nil {
+	if _, err := adAnalysis.PostGPOs(testCtx.Context(), graphDB, analysis.Scope{}); err != nil {
 		t.Fatalf("error creating GPOAppliesTo edges in integration test; %v", err)
 	}
 
@@ -1649,7 +1649,7 @@ func TestCanApplyGPO(t *testing.T) {
 	err = arrows.WriteGraphToDatabase(graphDB, &fixture)
 	require.NoError(t, err)
 
-	if _, err := adAnalysis.PostGPOs(testCtx.Context(), graphDB); err != nil {
+	if _, err := adAnalysis.PostGPOs(testCtx.Context(), graphDB, analysis.Scope{}); err != nil {
 		t.Fatalf("error creating CanApplyGPO edges in integration test; %v", err)
 	}
 
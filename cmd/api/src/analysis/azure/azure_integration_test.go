@@ -32,6 +32,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/specterops/bloodhound/cmd/api/src/test/integration"
+	"github.com/specterops/bloodhound/packages/go/analysis"
 	azureanalysis "github.com/specterops/bloodhound/packages/go/analysis/azure"
 	"github.com/specterops/dawgs/graph"
 	"github.com/specterops/dawgs/ops"
@@ -682,7 +683,7 @@ func TestRoleAddSecret(t *testing.T) {
 		return nil
 	}, func(harness integration.HarnessDetails, tx graph.Transaction) {
 
-		postProcessingStats, err := azureanalysis.AppRoleAssignments(context.Background(), testContext.Graph.Database)
+		postProcessingStats, err := azureanalysis.AppRoleAssignments(context.Background(), testContext.Graph.Database, analysis.Scope{})
 		assert.Nil(t, err)
 		assert.NotNil(t, postProcessingStats.RelationshipsCreated[azure.AddSecret])
 		assert.Equal(t, 4, int(*postProcessingStats.RelationshipsCreated[azure.AddSecret]))
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/specterops/bloodhound/cmd/api/src/test/integration"
+	"github.com/specterops/bloodhound/packages/go/analysis"
 	azureAnalysis "github.com/specterops/bloodhound/packages/go/analysis/azure"
 	schema "github.com/specterops/bloodhound/packages/go/graphschema"
 	"github.com/specterops/bloodhound/packages/go/graphschema/azure"
@@ -40,7 +41,7 @@ func TestAzurePIMRolesAZRoleApprover(t *testing.T) {
 		harness.AZPIMRolesHarness.Setup(testContext)
 		return nil
 	}, func(harness integration.HarnessDetails, db graph.Database) {
-		stats, err := azureAnalysis.CreateAZRoleApproverEdge(testContext.Context(), db)
+		stats, err := azureAnalysis.CreateAZRoleApproverEdge(testContext.Context(), db, analysis.Scope{})
 		require.NoError(t, err)
 
 		require.NotNil(t, stats)
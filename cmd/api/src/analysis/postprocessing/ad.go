@@ -0,0 +1,146 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postprocessing
+
+import (
+	"context"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model/appcfg"
+	"github.com/specterops/bloodhound/packages/go/analysis"
+	adAnalysis "github.com/specterops/bloodhound/packages/go/analysis/ad"
+	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
+	"github.com/specterops/dawgs/graph"
+)
+
+// Active Directory post-processing steps
+const (
+	StepGroupExpansions  = "group_expansions"
+	StepGPOs             = "gpos"
+	StepDCSync           = "dcsync"
+	StepSyncLAPSPassword = "sync_laps_password"
+	StepHasTrustKeys     = "has_trust_keys"
+	StepLocalGroups      = "local_groups"
+	StepADCS             = "adcs"
+	StepOwnsWriteOwner   = "owns_write_owner"
+	StepNTLM             = "ntlm"
+)
+
+func adSteps() []Step {
+	domainSID := ad.DomainSID.String()
+
+	return []Step{{
+		Name: StepGroupExpansions,
+		Run: func(ctx context.Context, state *State) (*analysis.AtomicPostProcessingStats, error) {
+			if groupExpansions, err := adAnalysis.ExpandAllRDPLocalGroups(ctx, state.Graph); err != nil {
+				return nil, err
+			} else {
+				state.GroupExpansions = groupExpansions
+				return nil, nil
+			}
+		},
+	}, {
+		Name:          StepGPOs,
+		Kinds:         []graph.Kind{ad.GPOAppliesTo, ad.CanApplyGPO},
+		ScopeProperty: domainSID,
+		Run: func(ctx context.Context, state *State) (*analysis.AtomicPostProcessingStats, error) {
+			return adAnalysis.PostGPOs(ctx, state.Graph, state.Domains)
+		},
+	}, {
+		Name:          StepDCSync,
+		Kinds:         []graph.Kind{ad.DCSync},
+		ScopeProperty: domainSID,
+		DependsOn:     []string{StepGroupExpansions},
+		Run: func(ctx context.Context, state *State) (*analysis.AtomicPostProcessingStats, error) {
+			return adAnalysis.PostDCSync(ctx, state.Graph, state.GroupExpansions, state.Domains)
+		},
+	}, {
+		Name:          StepSyncLAPSPassword,
+		Kinds:         []graph.Kind{ad.SyncLAPSPassword},
+		ScopeProperty: domainSID,
+		DependsOn:     []string{StepGroupExpansions},
+		Run: func(ctx context.Context, state *State) (*analysis.AtomicPostProcessingStats, error) {
+			return adAnalysis.PostSyncLAPSPassword(ctx, state.Graph, state.GroupExpansions, state.Domains)
+		},
+	}, {
+		// Trust keys link domains with each other and are recreated for the whole graph
+		Name:  StepHasTrustKeys,
+		Kinds: []graph.Kind{ad.HasTrustKeys},
+		Run: func(ctx context.Context, state *State) (*analysis.AtomicPostProcessingStats, error) {
+			return adAnalysis.PostHasTrustKeys(ctx, state.Graph)
+		},
+	}, {
+		Name:          StepLocalGroups,
+		Kinds:         []graph.Kind{ad.CanRDP, ad.AdminTo, ad.CanPSRemote, ad.ExecuteDCOM},
+		ScopeProperty: domainSID,
+		DependsOn:     []string{StepGroupExpansions},
+		Run: func(ctx context.Context, state *State) (*analysis.AtomicPostProcessingStats, error) {
+			return adAnalysis.PostLocalGroups(ctx, state.Graph, state.GroupExpansions, false, state.CitrixEnabled, state.Domains)
+		},
+	}, {
+		// The relationships between certificate authorities, templates and domains are recreated for the whole graph
+		Name: StepADCS,
+		Kinds: []graph.Kind{
+			ad.TrustedForNTAuth,
+			ad.IssuedSignedBy,
+			ad.EnterpriseCAFor,
+			ad.ExtendedByPolicy,
+			ad.EnrollOnBehalfOf,
+			ad.GoldenCert,
+			ad.ADCSESC1,
+			ad.ADCSESC3,
+			ad.ADCSESC4,
+			ad.ADCSESC6a,
+			ad.ADCSESC6b,
+			ad.ADCSESC9a,
+			ad.ADCSESC9b,
+			ad.ADCSESC10a,
+			ad.ADCSESC10b,
+			ad.ADCSESC13,
+		},
+		ScopeProperty: domainSID,
+		GlobalKinds:   []graph.Kind{ad.TrustedForNTAuth, ad.IssuedSignedBy, ad.EnterpriseCAFor, ad.ExtendedByPolicy, ad.EnrollOnBehalfOf},
+		DependsOn:     []string{StepGroupExpansions},
+		Run: func(ctx context.Context, state *State) (*analysis.AtomicPostProcessingStats, error) {
+			// ADCS post-processing no longer depends on the ADCS feature flag, which PostADCS ignores
+			if stats, adcsCache, err := adAnalysis.PostADCS(ctx, state.Graph, state.GroupExpansions, true, state.Domains); err != nil {
+				return stats, err
+			} else {
+				state.ADCSCache = adcsCache
+				return stats, nil
+			}
+		},
+	}, {
+		Name:          StepOwnsWriteOwner,
+		Kinds:         []graph.Kind{ad.Owns, ad.WriteOwner},
+		ScopeProperty: domainSID,
+		DependsOn:     []string{StepGroupExpansions},
+		Run: func(ctx context.Context, state *State) (*analysis.AtomicPostProcessingStats, error) {
+			return adAnalysis.PostOwnsAndWriteOwner(ctx, state.Graph, state.GroupExpansions, state.Domains)
+		},
+	}, {
+		// Relaying to ADCS targets the certificate authorities of every domain and is recreated for the whole graph
+		Name:          StepNTLM,
+		Kinds:         []graph.Kind{ad.CoerceAndRelayNTLMToADCS, ad.CoerceAndRelayNTLMToSMB, ad.CoerceAndRelayNTLMToLDAP, ad.CoerceAndRelayNTLMToLDAPS},
+		ScopeProperty: domainSID,
+		GlobalKinds:   []graph.Kind{ad.CoerceAndRelayNTLMToADCS},
+		DependsOn:     []string{StepGroupExpansions, StepADCS},
+		FeatureFlag:   appcfg.FeatureNTLMPostProcessing,
+		Run: func(ctx context.Context, state *State) (*analysis.AtomicPostProcessingStats, error) {
+			return adAnalysis.PostNTLM(ctx, state.Graph, state.GroupExpansions, state.ADCSCache, true, state.CompositionCounter, state.Domains)
+		},
+	}}
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postprocessing
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/specterops/bloodhound/packages/go/analysis"
+	azureAnalysis "github.com/specterops/bloodhound/packages/go/analysis/azure"
+	"github.com/specterops/bloodhound/packages/go/analysis/hybrid"
+	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
+	"github.com/specterops/bloodhound/packages/go/graphschema/azure"
+	"github.com/specterops/dawgs/graph"
+)
+
+// Azure post-processing steps
+const (
+	StepAzureManagementGroupNames = "azure_management_group_names"
+	StepAzureUserRoleAssignments  = "azure_user_role_assignments"
+	StepAzureExecuteCommand       = "azure_execute_command"
+	StepAzureAppRoleAssignments   = "azure_app_role_assignments"
+	StepAzureRoleApprover         = "azure_role_approver"
+	StepHybrid                    = "hybrid"
+)
+
+func azureSteps() []Step {
+	tenantID := azure.TenantID.String()
+
+	return []Step{{
+		Name: StepAzureManagementGroupNames,
+		Run: func(ctx context.Context, state *State) (*analysis.AtomicPostProcessingStats, error) {
+			// Management group names are cosmetic and do not fail post-processing
+			if err := azureAnalysis.FixManagementGroupNames(ctx, state.Graph); err != nil {
+				slog.WarnContext(ctx, "Error fixing management group names", slog.String("err", err.Error()))
+			}
+
+			return nil, nil
+		},
+	}, {
+		Name:          StepAzureUserRoleAssignments,
+		Kinds:         []graph.Kind{azure.ResetPassword, azure.GlobalAdmin, azure.PrivilegedRoleAdmin, azure.PrivilegedAuthAdmin, azure.AddMembers},
+		ScopeProperty: tenantID,
+		Run: func(ctx context.Context, state *State) (*analysis.AtomicPostProcessingStats, error) {
+			return azureAnalysis.UserRoleAssignments(ctx, state.Graph, state.Tenants)
+		},
+	}, {
+		Name:          StepAzureExecuteCommand,
+		Kinds:         []graph.Kind{azure.ExecuteCommand},
+		ScopeProperty: tenantID,
+		Run: func(ctx context.Context, state *State) (*analysis.AtomicPostProcessingStats, error) {
+			return azureAnalysis.ExecuteCommand(ctx, state.Graph, state.Tenants)
+		},
+	}, {
+		Name: StepAzureAppRoleAssignments,
+		Kinds: []graph.Kind{
+			azure.AddSecret,
+			azure.AZMGAddMember,
+			azure.AZMGAddOwner,
+			azure.AZMGAddSecret,
+			azure.AZMGGrantAppRoles,
+			azure.AZMGGrantRole,
+		},
+		ScopeProperty: tenantID,
+		Run: func(ctx context.Context, state *State) (*analysis.AtomicPostProcessingStats, error) {
+			return azureAnalysis.AppRoleAssignments(ctx, state.Graph, state.Tenants)
+		},
+	}, {
+		// Hybrid relationships link tenants with domains and are recreated for the whole graph
+		Name:  StepHybrid,
+		Kinds: []graph.Kind{ad.SyncedToEntraUser, azure.SyncedToADUser},
+		Run: func(ctx context.Context, state *State) (*analysis.AtomicPostProcessingStats, error) {
+			return hybrid.PostHybrid(ctx, state.Graph)
+		},
+	}, {
+		Name:          StepAzureRoleApprover,
+		Kinds:         []graph.Kind{azure.AZRoleApprover},
+		ScopeProperty: tenantID,
+		Run: func(ctx context.Context, state *State) (*analysis.AtomicPostProcessingStats, error) {
+			return azureAnalysis.CreateAZRoleApproverEdge(ctx, state.Graph, state.Tenants)
+		},
+	}}
+}
@@ -0,0 +1,150 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package postprocessing declares the steps of Active Directory and Azure post-processing run by the datapipe once
+// ingest completes.
+package postprocessing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/specterops/bloodhound/packages/go/analysis"
+	adAnalysis "github.com/specterops/bloodhound/packages/go/analysis/ad"
+	"github.com/specterops/bloodhound/packages/go/analysis/impact"
+	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
+	"github.com/specterops/bloodhound/packages/go/graphschema/azure"
+	"github.com/specterops/dawgs/graph"
+	"github.com/specterops/dawgs/ops"
+	"github.com/specterops/dawgs/query"
+)
+
+// Registry holds the steps of post-processing
+type Registry = analysis.PostProcessorRegistry[*State]
+
+// Step is a single step of post-processing
+type Step = analysis.PostProcessor[*State]
+
+// State is shared by the steps of a run of post-processing
+type State struct {
+	Graph              graph.Database
+	Domains            analysis.Scope
+	Tenants            analysis.Scope
+	CitrixEnabled      bool
+	CompositionCounter *analysis.CompositionCounter
+
+	// Set by the steps that compute them for the steps that depend on them
+	GroupExpansions impact.PathAggregator
+	ADCSCache       adAnalysis.ADCSCache
+}
+
+// Scope returns the scope of the run for the scope property of a step
+func (s *State) Scope(scopeProperty string) analysis.Scope {
+	switch scopeProperty {
+	case ad.DomainSID.String():
+		return s.Domains
+	case azure.TenantID.String():
+		return s.Tenants
+	default:
+		return analysis.Scope{}
+	}
+}
+
+// Steps returns every step of post-processing, Active Directory steps first
+func Steps() []Step {
+	return append(adSteps(), azureSteps()...)
+}
+
+// registry holds every step of post-processing. The steps are fixed, so failing to register them is a programming
+// error that the tests of this package catch.
+var registry = mustNewRegistry()
+
+// NewRegistry returns a registry holding every step of post-processing
+func NewRegistry() (*Registry, error) {
+	registry := analysis.NewPostProcessorRegistry[*State]()
+	return registry, registry.Register(Steps()...)
+}
+
+func mustNewRegistry() *Registry {
+	if registry, err := NewRegistry(); err != nil {
+		panic(fmt.Sprintf("invalid post-processing steps: %v", err))
+	} else {
+		return registry
+	}
+}
+
+// DefaultRegistry returns the registry holding every step of post-processing. It is shared, so no further steps may
+// be registered to it.
+func DefaultRegistry() *Registry {
+	return registry
+}
+
+// PostProcessedRelationships returns every relationship kind created by post-processing
+func PostProcessedRelationships() []graph.Kind {
+	return registry.PostProcessedRelationships()
+}
+
+// Run runs the enabled steps of post-processing for the domains and tenants covered by the state. The domain scope is
+// first extended to the domains linked by trusts to those it covers.
+func Run(ctx context.Context, registry *Registry, state *State, enabled func(step Step) bool, onStepDone func(result analysis.PostProcessorResult)) ([]analysis.PostProcessorResult, error) {
+	if domains, err := ExpandScopeByTrusts(ctx, state.Graph, state.Domains); err != nil {
+		return nil, err
+	} else {
+		state.Domains = domains
+	}
+
+	return registry.Run(ctx, state.Graph, state, analysis.PostProcessingOptions[*State]{
+		BaseKinds:  graph.Kinds{ad.Entity, azure.Entity},
+		Scope:      state.Scope,
+		Enabled:    enabled,
+		OnStepDone: onStepDone,
+	})
+}
+
+// ExpandScopeByTrusts extends the scope to the domains that trust or are trusted by a domain it covers, as changes to
+// a domain may grant its principals access to the domains it is linked with
+func ExpandScopeByTrusts(ctx context.Context, db graph.Database, scope analysis.Scope) (analysis.Scope, error) {
+	if scope.IsFull() || scope.IsEmpty() {
+		return scope, nil
+	}
+
+	var (
+		domainSIDProperty = ad.DomainSID.String()
+		domainSIDs        = scope.IDs()
+	)
+
+	return scope, db.ReadTransaction(ctx, func(tx graph.Transaction) error {
+		if trusts, err := ops.FetchPathSet(tx.Relationships().Filterf(func() graph.Criteria {
+			return query.And(
+				query.KindIn(query.Relationship(), ad.SameForestTrust, ad.CrossForestTrust),
+				query.Or(
+					query.In(query.StartProperty(domainSIDProperty), domainSIDs),
+					query.In(query.EndProperty(domainSIDProperty), domainSIDs),
+				),
+			)
+		})); err != nil {
+			return err
+		} else {
+			for _, domain := range trusts.AllNodes() {
+				if domainSID, err := domain.Properties.Get(domainSIDProperty).String(); err == nil {
+					scope = scope.With(domainSID)
+				}
+			}
+
+			return nil
+		}
+	})
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package postprocessing_test
+
+import (
+	"testing"
+
+	"github.com/specterops/bloodhound/cmd/api/src/analysis/postprocessing"
+	"github.com/specterops/bloodhound/packages/go/analysis/hybrid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRegistry(t *testing.T) {
+	registry, err := postprocessing.NewRegistry()
+	require.NoError(t, err)
+
+	plan, err := registry.Plan()
+	require.NoError(t, err)
+	require.Len(t, plan, len(postprocessing.Steps()))
+
+	ntlm, found := registry.Get(postprocessing.StepNTLM)
+	require.True(t, found)
+	assert.ElementsMatch(t, []string{postprocessing.StepGroupExpansions, postprocessing.StepADCS}, ntlm.DependsOn)
+}
+
+func TestPostProcessedRelationships(t *testing.T) {
+	// Ingest leaves out the kinds listed by the hybrid analysis package, which must stay in line with the steps
+	assert.ElementsMatch(t, hybrid.PostProcessedRelationships(), postprocessing.PostProcessedRelationships())
+	assert.Equal(t, postprocessing.DefaultRegistry().PostProcessedRelationships(), postprocessing.PostProcessedRelationships())
+}
@@ -47,6 +47,7 @@ const (
 	QueryParameterFindingType     = "finding"
 	QueryParameterAssetGroupTagId = "asset_group_tag_id"
 	QueryParameterEnvironments    = "environments"
+	QueryParameterEnvironment     = "environment"
 
 	// URI path parameters
 	URIPathVariableApplicationConfigurationParameter = "parameter"
@@ -67,6 +68,7 @@ const (
 	URIPathVariablePlatformID                        = "platform_id"
 	URIPathVariableRoleID                            = "role_id"
 	URIPathVariableSAMLProviderID                    = "saml_provider_id"
+	URIPathVariableSourceKindID                      = "source_kind_id"
 	URIPathVariableTaskID                            = "task_id"
 	URIPathVariableTenantID                          = "tenant_id"
 	URIPathVariableTokenID                           = "token_id"
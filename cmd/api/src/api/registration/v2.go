@@ -126,8 +126,15 @@ func NewV2API(resources v2.Resources, routerInst *router.Router) {
 	routerInst.GET("/api/v2/file-upload", resources.ListIngestJobs).RequireAuth()
 	routerInst.GET("/api/v2/file-upload/accepted-types", resources.ListAcceptedFileUploadTypes).RequireAuth()
 	routerInst.POST("/api/v2/file-upload/start", resources.StartIngestJob).RequirePermissions(permissions.GraphDBIngest)
+	routerInst.POST("/api/v2/file-upload/validate", resources.ValidateIngestFile).RequirePermissions(permissions.GraphDBIngest)
+	routerInst.POST("/api/v2/file-upload/csv-mapping/validate", resources.ValidateCSVMapping).RequirePermissions(permissions.GraphDBIngest)
 	routerInst.POST(fmt.Sprintf("/api/v2/file-upload/{%s}", v2.FileUploadJobIdPathParameterName), resources.ProcessIngestTask).RequirePermissions(permissions.GraphDBIngest)
 	routerInst.POST(fmt.Sprintf("/api/v2/file-upload/{%s}/end", v2.FileUploadJobIdPathParameterName), resources.EndIngestJob).RequirePermissions(permissions.GraphDBIngest)
+	routerInst.POST(fmt.Sprintf("/api/v2/file-upload/{%s}/cancel", v2.FileUploadJobIdPathParameterName), resources.CancelIngestJob).RequirePermissions(permissions.GraphDBIngest)
+	routerInst.GET(fmt.Sprintf("/api/v2/file-upload/{%s}/files", v2.FileUploadJobIdPathParameterName), resources.ListIngestJobFiles).RequireAuth()
+	routerInst.POST(fmt.Sprintf("/api/v2/file-upload/{%s}/chunked", v2.FileUploadJobIdPathParameterName), resources.StartChunkedIngestTask).RequirePermissions(permissions.GraphDBIngest)
+	routerInst.GET(fmt.Sprintf("/api/v2/file-upload/{%s}/chunked/{%s}", v2.FileUploadJobIdPathParameterName, v2.IngestTaskIdPathParameterName), resources.GetChunkedIngestTaskStatus).RequirePermissions(permissions.GraphDBIngest)
+	routerInst.PUT(fmt.Sprintf("/api/v2/file-upload/{%s}/chunked/{%s}", v2.FileUploadJobIdPathParameterName, v2.IngestTaskIdPathParameterName), resources.ProcessIngestTaskChunk).RequirePermissions(permissions.GraphDBIngest)
 
 	router.With(func() mux.MiddlewareFunc {
 		return middleware.DefaultRateLimitMiddleware(resources.DB)
@@ -196,6 +203,11 @@ func NewV2API(resources v2.Resources, routerInst *router.Router) {
 		routerInst.GET("/api/v2/pathfinding", resources.GetPathfindingResult).Queries("start_node", "{start_node}", "end_node", "{end_node}").RequirePermissions(permissions.GraphDBRead),
 		routerInst.GET("/api/v2/graphs/kinds", resources.ListKinds).RequirePermissions(permissions.GraphDBRead),
 		routerInst.GET("/api/v2/graphs/source-kinds", resources.ListSourceKinds).RequirePermissions(permissions.GraphDBRead),
+		routerInst.POST("/api/v2/graphs/source-kinds", resources.CreateSourceKind).RequirePermissions(permissions.GraphDBWrite),
+		routerInst.GET(fmt.Sprintf("/api/v2/graphs/source-kinds/{%s}", api.URIPathVariableSourceKindID), resources.GetSourceKind).RequirePermissions(permissions.GraphDBRead),
+		routerInst.PUT(fmt.Sprintf("/api/v2/graphs/source-kinds/{%s}", api.URIPathVariableSourceKindID), resources.UpdateSourceKind).RequirePermissions(permissions.GraphDBWrite),
+		routerInst.DELETE(fmt.Sprintf("/api/v2/graphs/source-kinds/{%s}", api.URIPathVariableSourceKindID), resources.DeleteSourceKind).RequirePermissions(permissions.WipeDB),
+		routerInst.GET("/api/v2/graphs/opengraph-kinds", resources.ListOpenGraphKinds).RequirePermissions(permissions.GraphDBRead),
 		routerInst.GET("/api/v2/graphs/shortest-path", resources.GetShortestPath).Queries(params.StartNode.String(), params.StartNode.RouteMatcher(), params.EndNode.String(), params.EndNode.RouteMatcher()).RequirePermissions(permissions.GraphDBRead),
 		routerInst.GET("/api/v2/graphs/edge-composition", resources.GetEdgeComposition).RequirePermissions(permissions.GraphDBRead),
 		routerInst.GET("/api/v2/graphs/relay-targets", resources.GetEdgeRelayTargets).RequirePermissions(permissions.GraphDBRead),
@@ -341,6 +353,9 @@ func NewV2API(resources v2.Resources, routerInst *router.Router) {
 		// TODO: Update the permission on this once we get something more concrete
 		routerInst.GET("/api/v2/analysis/status", resources.GetAnalysisRequest).RequirePermissions(permissions.GraphDBRead),
 		routerInst.PUT("/api/v2/analysis", resources.RequestAnalysis).RequirePermissions(permissions.GraphDBWrite),
+		routerInst.POST("/api/v2/analysis/cancel", resources.CancelAnalysis).RequirePermissions(permissions.GraphDBWrite),
+		routerInst.GET("/api/v2/analysis/runs", resources.ListAnalysisRuns).RequirePermissions(permissions.GraphDBRead),
+		routerInst.GET(fmt.Sprintf("/api/v2/analysis/runs/{%s}", v2.AnalysisRunIdPathParameterName), resources.GetAnalysisRun).RequirePermissions(permissions.GraphDBRead),
 
 		// Custom Node Management
 		routerInst.GET("/api/v2/custom-nodes", resources.GetCustomNodeKinds).RequireAuth(),
@@ -25,11 +25,15 @@ import (
 	"github.com/specterops/bloodhound/cmd/api/src/api"
 	"github.com/specterops/bloodhound/cmd/api/src/auth"
 	"github.com/specterops/bloodhound/cmd/api/src/ctx"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
 	"github.com/specterops/bloodhound/cmd/api/src/model/appcfg"
 	"github.com/specterops/bloodhound/packages/go/bhlog/measure"
 )
 
-const ErrAnalysisScheduledMode = "analysis is configured to run on a schedule, unable to run just in time"
+const (
+	ErrAnalysisScheduledMode = "analysis is configured to run on a schedule, unable to run just in time"
+	ErrAnalysisNotRunning    = "analysis is not running"
+)
 
 func (s Resources) GetAnalysisRequest(response http.ResponseWriter, request *http.Request) {
 	if analRequest, err := s.DB.GetAnalysisRequest(request.Context()); err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -61,3 +65,17 @@ func (s Resources) RequestAnalysis(response http.ResponseWriter, request *http.R
 
 	response.WriteHeader(http.StatusAccepted)
 }
+
+// CancelAnalysis asks the datapipe to stop the analysis it is running. The datapipe stops at its next check for
+// cancellation, marking the ingest jobs under analysis as canceled.
+func (s Resources) CancelAnalysis(response http.ResponseWriter, request *http.Request) {
+	defer measure.ContextMeasure(request.Context(), slog.LevelDebug, "Canceling analysis")()
+
+	if canceled, err := s.DB.RequestDatapipeCancel(request.Context(), model.DatapipeStatusAnalyzing); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if !canceled {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusConflict, ErrAnalysisNotRunning, request), response)
+	} else {
+		response.WriteHeader(http.StatusAccepted)
+	}
+}
@@ -399,3 +399,88 @@ func TestResources_RequestAnalysis(t *testing.T) {
 		})
 	}
 }
+
+func TestResources_CancelAnalysis(t *testing.T) {
+	t.Parallel()
+
+	type expected struct {
+		responseBody   string
+		responseCode   int
+		responseHeader http.Header
+	}
+	type testData struct {
+		name       string
+		setupMocks func(t *testing.T, mockDatabase *dbMocks.MockDatabase)
+		expected   expected
+	}
+
+	tt := []testData{
+		{
+			name: "Error: RequestDatapipeCancel database error - Internal Server Error",
+			setupMocks: func(t *testing.T, mockDatabase *dbMocks.MockDatabase) {
+				t.Helper()
+				mockDatabase.EXPECT().RequestDatapipeCancel(gomock.Any(), model.DatapipeStatusAnalyzing).Return(false, errors.New("error"))
+			},
+			expected: expected{
+				responseCode:   http.StatusInternalServerError,
+				responseBody:   `{"errors":[{"context":"","message":"an internal error has occurred that is preventing the service from servicing this request"}],"http_status":500,"request_id":"id","timestamp":"0001-01-01T00:00:00Z"}`,
+				responseHeader: http.Header{"Content-Type": []string{"application/json"}},
+			},
+		},
+		{
+			name: "Error: analysis not running - Conflict",
+			setupMocks: func(t *testing.T, mockDatabase *dbMocks.MockDatabase) {
+				t.Helper()
+				mockDatabase.EXPECT().RequestDatapipeCancel(gomock.Any(), model.DatapipeStatusAnalyzing).Return(false, nil)
+			},
+			expected: expected{
+				responseCode:   http.StatusConflict,
+				responseBody:   `{"errors":[{"context":"","message":"analysis is not running"}],"http_status":409,"request_id":"id","timestamp":"0001-01-01T00:00:00Z"}`,
+				responseHeader: http.Header{"Content-Type": []string{"application/json"}},
+			},
+		},
+		{
+			name: "Success: analysis cancel requested - Accepted",
+			setupMocks: func(t *testing.T, mockDatabase *dbMocks.MockDatabase) {
+				t.Helper()
+				mockDatabase.EXPECT().RequestDatapipeCancel(gomock.Any(), model.DatapipeStatusAnalyzing).Return(true, nil)
+			},
+			expected: expected{
+				responseCode:   http.StatusAccepted,
+				responseBody:   ``,
+				responseHeader: http.Header{},
+			},
+		},
+	}
+	for _, testCase := range tt {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			var (
+				ctrl         = gomock.NewController(t)
+				mockDatabase = dbMocks.NewMockDatabase(ctrl)
+				resources    = v2.Resources{DB: mockDatabase}
+				response     = httptest.NewRecorder()
+				request      = httptest.NewRequest(http.MethodPost, "/api/v2/analysis/cancel", nil)
+				router       = mux.NewRouter()
+			)
+
+			testCase.setupMocks(t, mockDatabase)
+
+			requestCtx := ctx.Context{RequestID: "id"}
+			request = request.WithContext(context.WithValue(context.Background(), ctx.ValueKey, requestCtx.WithRequestID("id")))
+
+			router.HandleFunc("/api/v2/analysis/cancel", resources.CancelAnalysis).Methods(http.MethodPost)
+			router.ServeHTTP(response, request)
+
+			status, header, body := test.ProcessResponse(t, response)
+
+			assert.Equal(t, testCase.expected.responseCode, status)
+			assert.Equal(t, testCase.expected.responseHeader, header)
+			if body != "" {
+				assert.JSONEq(t, testCase.expected.responseBody, body)
+			} else {
+				assert.Equal(t, testCase.expected.responseBody, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v2
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/specterops/bloodhound/cmd/api/src/api"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+)
+
+const AnalysisRunIdPathParameterName = "analysis_run_id"
+
+// ListAnalysisRuns returns the history of analysis runs, most recent first, along with the outcome of each of their
+// steps and the relationships each step created and deleted by kind
+func (s Resources) ListAnalysisRuns(response http.ResponseWriter, request *http.Request) {
+	queryParams := request.URL.Query()
+
+	if skip, err := ParseSkipQueryParameter(queryParams, 0); err != nil {
+		api.WriteErrorResponse(request.Context(), ErrBadQueryParameter(request, model.PaginationQueryParameterSkip, err), response)
+	} else if limit, err := ParseLimitQueryParameter(queryParams, 10); err != nil {
+		api.WriteErrorResponse(request.Context(), ErrBadQueryParameter(request, model.PaginationQueryParameterLimit, err), response)
+	} else if runs, count, err := s.DB.GetAnalysisRuns(request.Context(), skip, limit); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteResponseWrapperWithPagination(request.Context(), runs, limit, skip, count, http.StatusOK, response)
+	}
+}
+
+// GetAnalysisRun returns a single analysis run along with the outcome of each of its steps
+func (s Resources) GetAnalysisRun(response http.ResponseWriter, request *http.Request) {
+	if runID, err := strconv.ParseInt(mux.Vars(request)[AnalysisRunIdPathParameterName], 10, 64); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if run, err := s.DB.GetAnalysisRun(request.Context(), runID); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteBasicResponse(request.Context(), run, http.StatusOK, response)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2024 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v2_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	v2 "github.com/specterops/bloodhound/cmd/api/src/api/v2"
+	"github.com/specterops/bloodhound/cmd/api/src/ctx"
+	"github.com/specterops/bloodhound/cmd/api/src/database"
+	dbmocks "github.com/specterops/bloodhound/cmd/api/src/database/mocks"
+	"github.com/specterops/bloodhound/cmd/api/src/database/types"
+	"github.com/specterops/bloodhound/cmd/api/src/database/types/null"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/utils/test"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestResources_AnalysisRuns(t *testing.T) {
+	t.Parallel()
+
+	type mock struct {
+		mockDatabase *dbmocks.MockDatabase
+	}
+	type expected struct {
+		responseBody string
+		responseCode int
+	}
+	type testData struct {
+		name       string
+		path       string
+		setupMocks func(t *testing.T, mock *mock)
+		expected   expected
+	}
+
+	var (
+		startedAt = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+		run       = model.AnalysisRun{
+			StartedAt: startedAt,
+			EndedAt:   null.TimeFrom(startedAt.Add(time.Minute)),
+			Status:    model.AnalysisRunStatusPartiallyComplete,
+			Scoped:    true,
+			Steps: model.AnalysisRunSteps{{
+				RunID:                7,
+				Name:                 "adcs",
+				Status:               model.AnalysisRunStepStatusComplete,
+				DurationMilliseconds: 3000,
+				RelationshipsCreated: types.JSONBInt64Object{"ADCSESC1": 12},
+				RelationshipsDeleted: types.JSONBInt64Object{"ADCSESC1": 2000},
+				BigSerial:            model.BigSerial{ID: 1},
+			}},
+			BigSerial: model.BigSerial{ID: 7},
+		}
+		runJSON = `{"id":7, "started_at":"2025-06-01T12:00:00Z", "ended_at":"2025-06-01T12:01:00Z", "status":"partially_complete", "scoped":true, "steps":[
+			{"id":1, "run_id":7, "name":"adcs", "status":"complete", "error":"", "duration_ms":3000, "relationships_created":{"ADCSESC1":12}, "relationships_deleted":{"ADCSESC1":2000}, "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z", "deleted_at":{"Time":"0001-01-01T00:00:00Z", "Valid":false}}
+		], "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z", "deleted_at":{"Time":"0001-01-01T00:00:00Z", "Valid":false}}`
+	)
+
+	tt := []testData{
+		{
+			name: "Error: Invalid Limit - 400",
+			path: "/api/v2/analysis/runs?limit=invalid",
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+			},
+			expected: expected{
+				responseCode: http.StatusBadRequest,
+				responseBody: `{"errors":[{"context":"", "message":"query parameter \"limit\" is malformed: error converting limit value invalid to int: strconv.Atoi: parsing \"invalid\": invalid syntax"}], "http_status":400, "request_id":"id", "timestamp":"0001-01-01T00:00:00Z"}`,
+			},
+		},
+		{
+			name: "Error: Database Error Listing Runs - 500",
+			path: "/api/v2/analysis/runs",
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+				mock.mockDatabase.EXPECT().GetAnalysisRuns(gomock.Any(), 0, 10).Return(nil, 0, errors.New("database error"))
+			},
+			expected: expected{
+				responseCode: http.StatusInternalServerError,
+				responseBody: `{"errors":[{"context":"", "message":"an internal error has occurred that is preventing the service from servicing this request"}], "http_status":500, "request_id":"id", "timestamp":"0001-01-01T00:00:00Z"}`,
+			},
+		},
+		{
+			name: "Success: Runs - 200",
+			path: "/api/v2/analysis/runs?skip=1&limit=1",
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+				mock.mockDatabase.EXPECT().GetAnalysisRuns(gomock.Any(), 1, 1).Return(model.AnalysisRuns{run}, 2, nil)
+			},
+			expected: expected{
+				responseCode: http.StatusOK,
+				responseBody: fmt.Sprintf(`{"count":2, "limit":1, "skip":1, "data":[%s]}`, runJSON),
+			},
+		},
+		{
+			name: "Error: Invalid Run ID - 400",
+			path: "/api/v2/analysis/runs/invalid",
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+			},
+			expected: expected{
+				responseCode: http.StatusBadRequest,
+				responseBody: `{"errors":[{"context":"", "message":"id is malformed."}], "http_status":400, "request_id":"id", "timestamp":"0001-01-01T00:00:00Z"}`,
+			},
+		},
+		{
+			name: "Error: Run Not Found - 404",
+			path: "/api/v2/analysis/runs/8",
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+				mock.mockDatabase.EXPECT().GetAnalysisRun(gomock.Any(), int64(8)).Return(model.AnalysisRun{}, database.ErrNotFound)
+			},
+			expected: expected{
+				responseCode: http.StatusNotFound,
+				responseBody: `{"errors":[{"context":"", "message":"resource not found"}], "http_status":404, "request_id":"id", "timestamp":"0001-01-01T00:00:00Z"}`,
+			},
+		},
+		{
+			name: "Success: Run - 200",
+			path: "/api/v2/analysis/runs/7",
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+				mock.mockDatabase.EXPECT().GetAnalysisRun(gomock.Any(), int64(7)).Return(run, nil)
+			},
+			expected: expected{
+				responseCode: http.StatusOK,
+				responseBody: fmt.Sprintf(`{"data":%s}`, runJSON),
+			},
+		},
+	}
+
+	for _, testCase := range tt {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			mocks := &mock{
+				mockDatabase: dbmocks.NewMockDatabase(ctrl),
+			}
+
+			testCase.setupMocks(t, mocks)
+
+			resources := v2.Resources{
+				DB: mocks.mockDatabase,
+			}
+
+			requestCtx := ctx.Context{RequestID: "id"}
+			request := httptest.NewRequest(http.MethodGet, testCase.path, nil)
+			request = request.WithContext(context.WithValue(context.Background(), ctx.ValueKey, requestCtx.WithRequestID("id")))
+
+			response := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.HandleFunc("/api/v2/analysis/runs", resources.ListAnalysisRuns).Methods(http.MethodGet)
+			router.HandleFunc(fmt.Sprintf("/api/v2/analysis/runs/{%s}", v2.AnalysisRunIdPathParameterName), resources.GetAnalysisRun).Methods(http.MethodGet)
+
+			router.ServeHTTP(response, request)
+
+			status, _, body := test.ProcessResponse(t, response)
+
+			assert.Equal(t, testCase.expected.responseCode, status)
+			assert.JSONEq(t, testCase.expected.responseBody, body)
+		})
+	}
+}
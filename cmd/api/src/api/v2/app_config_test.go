@@ -226,4 +226,55 @@ func Test_SetApplicationConfiguration(t *testing.T) {
 				status, http.StatusOK)
 		}
 	})
+
+	t.Run("Invalid Ingest Transform Rule", func(t *testing.T) {
+		appConfigRequest = appcfg.AppConfigUpdateRequest{
+			Key: string(appcfg.IngestTransformRulesKey),
+			Value: map[string]any{
+				"rules": []map[string]any{
+					{"kind": "User", "action": "regex_extract", "property": "description", "pattern": "owner: ("},
+				},
+			},
+		}
+
+		reqBody, _ := json.Marshal(appConfigRequest)
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/config", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		resources.SetApplicationConfiguration(rec, req)
+
+		if status := rec.Code; status != http.StatusBadRequest {
+			t.Errorf("handler returned wrong status code: got %v want %v",
+				status, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("Success Ingest Transform Rules", func(t *testing.T) {
+		appConfigRequest = appcfg.AppConfigUpdateRequest{
+			Key: string(appcfg.IngestTransformRulesKey),
+			Value: map[string]any{
+				"rules": []map[string]any{
+					{"kind": "User", "action": "regex_extract", "property": "description", "target": "owneremail", "pattern": `owner:\\s*(\\S+@\\S+)`},
+					{"kind": "Base", "action": "drop", "property": "extensionattribute1"},
+				},
+			},
+		}
+
+		mockDB.EXPECT().
+			SetConfigurationParameter(gomock.Any(), gomock.Any()).
+			Return(nil)
+
+		reqBody, _ := json.Marshal(appConfigRequest)
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/config", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		resources.SetApplicationConfiguration(rec, req)
+
+		if status := rec.Code; status != http.StatusOK {
+			t.Errorf("handler returned wrong status code: got %v want %v",
+				status, http.StatusOK)
+		}
+	})
 }
@@ -17,6 +17,7 @@
 package v2
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -143,7 +144,7 @@ func (s Resources) GetAssetGroupTags(response http.ResponseWriter, request *http
 }
 
 // Checks that the selector seeds are valid.
-func validateSelectorSeeds(graph queries.Graph, seeds []model.SelectorSeed) error {
+func validateSelectorSeeds(ctx context.Context, graph queries.Graph, seeds []model.SelectorSeed) error {
 	if len(seeds) <= 0 {
 		return fmt.Errorf("seeds are required")
 	}
@@ -159,7 +160,7 @@ func validateSelectorSeeds(graph queries.Graph, seeds []model.SelectorSeed) erro
 			return fmt.Errorf("all seeds must be of the same type")
 		}
 		if seed.Type == model.SelectorTypeCypher {
-			if _, err := graph.PrepareCypherQuery(seed.Value, queries.DefaultQueryFitnessLowerBoundSelector); err != nil {
+			if _, err := graph.PrepareCypherQuery(ctx, seed.Value, queries.DefaultQueryFitnessLowerBoundSelector); err != nil {
 				return fmt.Errorf("cypher is invalid: %v", err)
 			}
 		}
@@ -187,7 +188,7 @@ func (s *Resources) CreateAssetGroupTagSelector(response http.ResponseWriter, re
 	} else if actor, isUser := auth.GetUserFromAuthCtx(ctx.FromRequest(request).AuthCtx); !isUser {
 		slog.Error("Unable to get user from auth context")
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "unknown user", request), response)
-	} else if err := validateSelectorSeeds(s.GraphQuery, sel.Seeds); err != nil {
+	} else if err := validateSelectorSeeds(request.Context(), s.GraphQuery, sel.Seeds); err != nil {
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
 	} else if selector, err := s.DB.CreateAssetGroupTagSelector(request.Context(), assetTagId, actor, sel.Name, sel.Description, false, true, sel.AutoCertify, sel.Seeds); err != nil {
 		api.HandleDatabaseError(request, response, err)
@@ -267,7 +268,7 @@ func (s *Resources) UpdateAssetGroupTagSelector(response http.ResponseWriter, re
 		// if seeds are not included, call the DB update with them set to nil
 		var seedsTemp []model.SelectorSeed
 		if len(selUpdateReq.Seeds) > 0 {
-			if err := validateSelectorSeeds(s.GraphQuery, selUpdateReq.Seeds); err != nil {
+			if err := validateSelectorSeeds(request.Context(), s.GraphQuery, selUpdateReq.Seeds); err != nil {
 				api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
 				return
 			}
@@ -752,7 +753,7 @@ func (s *Resources) PreviewSelectors(response http.ResponseWriter, request *http
 	} else if _, isUser := auth.GetUserFromAuthCtx(ctx.FromRequest(request).AuthCtx); !isUser {
 		slog.Error("Unable to get user from auth context")
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "unknown user", request), response)
-	} else if err := validateSelectorSeeds(s.GraphQuery, seeds.Seeds); err != nil {
+	} else if err := validateSelectorSeeds(request.Context(), s.GraphQuery, seeds.Seeds); err != nil {
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
 	} else {
 		nodes := datapipe.FetchNodesFromSeeds(request.Context(), s.Graph, seeds.Seeds, model.AssetGroupExpansionMethodAll, limit)
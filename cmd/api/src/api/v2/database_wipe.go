@@ -33,8 +33,9 @@ import (
 )
 
 type DatabaseWipe struct {
-	DeleteCollectedGraphData bool  `json:"deleteCollectedGraphData"`
-	DeleteSourceKinds        []int `json:"deleteSourceKinds"` // an id of 0 represents "sourceless" data
+	DeleteCollectedGraphData bool     `json:"deleteCollectedGraphData"`
+	DeleteSourceKinds        []int    `json:"deleteSourceKinds"`  // an id of 0 represents "sourceless" data
+	DeleteEnvironments       []string `json:"deleteEnvironments"` // limits the graph data deleted to these environments
 
 	DeleteFileIngestHistory   bool  `json:"deleteFileIngestHistory"`
 	DeleteDataQualityHistory  bool  `json:"deleteDataQualityHistory"`
@@ -62,6 +63,29 @@ func (s Resources) HandleDatabaseWipe(response http.ResponseWriter, request *htt
 		return
 	}
 
+	// environments only limit the graph data selected for deletion by another option
+	if len(payload.DeleteEnvironments) > 0 {
+		if !payload.DeleteCollectedGraphData && len(payload.DeleteSourceKinds) == 0 {
+			api.WriteErrorResponse(
+				request.Context(),
+				api.BuildErrorResponse(http.StatusBadRequest, "deleteEnvironments may only be specified along with deleteCollectedGraphData or deleteSourceKinds", request),
+				response,
+			)
+			return
+		}
+
+		for _, environment := range payload.DeleteEnvironments {
+			if err := model.ValidateEnvironment(environment); err != nil || environment == "" {
+				api.WriteErrorResponse(
+					request.Context(),
+					api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf("invalid environment %q in deleteEnvironments", environment), request),
+					response,
+				)
+				return
+			}
+		}
+	}
+
 	// return `BadRequest` if request is empty
 	isEmptyRequest := !payload.DeleteCollectedGraphData && !payload.DeleteDataQualityHistory && !payload.DeleteFileIngestHistory && len(payload.DeleteAssetGroupSelectors) == 0 && len(payload.DeleteSourceKinds) == 0
 	if isEmptyRequest {
@@ -257,9 +281,10 @@ func (s Resources) handleAuditLogForDatabaseWipe(ctx context.Context, auditEntry
 
 func (s Resources) BuildDeleteRequest(ctx context.Context, userID string, payload DatabaseWipe) (model.AnalysisRequest, error) {
 	deleteRequest := model.AnalysisRequest{
-		RequestedBy:    userID,
-		RequestType:    model.AnalysisRequestDeletion,
-		DeleteAllGraph: payload.DeleteCollectedGraphData,
+		RequestedBy:        userID,
+		RequestType:        model.AnalysisRequestDeletion,
+		DeleteAllGraph:     payload.DeleteCollectedGraphData,
+		DeleteEnvironments: payload.DeleteEnvironments,
 	}
 
 	if slices.Contains(payload.DeleteSourceKinds, 0) {
@@ -17,18 +17,22 @@
 package v2_test
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"testing"
 
 	"github.com/gofrs/uuid"
+	"github.com/lib/pq"
 	v2 "github.com/specterops/bloodhound/cmd/api/src/api/v2"
 	"github.com/specterops/bloodhound/cmd/api/src/api/v2/apitest"
 	dbMocks "github.com/specterops/bloodhound/cmd/api/src/database/mocks"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
 	"github.com/specterops/bloodhound/cmd/api/src/model/appcfg"
 	graph_mocks "github.com/specterops/bloodhound/cmd/api/src/vendormocks/dawgs/graph"
 	"github.com/specterops/bloodhound/packages/go/headers"
 	"github.com/specterops/bloodhound/packages/go/mediatypes"
+	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
 
@@ -116,6 +120,49 @@ func TestDatabaseWipe(t *testing.T) {
 					apitest.StatusCode(output, http.StatusNoContent)
 				},
 			},
+			{
+				Name: "endpoint returns a 400 error if environments are given without graph data to delete",
+				Input: func(input *apitest.Input) {
+					apitest.SetHeader(input, headers.ContentType.String(), mediatypes.ApplicationJson.String())
+					apitest.BodyStruct(input, v2.DatabaseWipe{DeleteFileIngestHistory: true, DeleteEnvironments: []string{"subsidiary-a"}})
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusBadRequest)
+					apitest.BodyContains(output, "deleteEnvironments may only be specified along with deleteCollectedGraphData or deleteSourceKinds")
+				},
+			},
+			{
+				Name: "endpoint returns a 400 error if an environment is invalid",
+				Input: func(input *apitest.Input) {
+					apitest.SetHeader(input, headers.ContentType.String(), mediatypes.ApplicationJson.String())
+					apitest.BodyStruct(input, v2.DatabaseWipe{DeleteCollectedGraphData: true, DeleteEnvironments: []string{""}})
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusBadRequest)
+					apitest.BodyContains(output, "invalid environment")
+				},
+			},
+			{
+				Name: "deletion of collected graph data is limited to the environments given",
+				Input: func(input *apitest.Input) {
+					apitest.SetHeader(input, headers.ContentType.String(), mediatypes.ApplicationJson.String())
+					apitest.BodyStruct(input, v2.DatabaseWipe{DeleteCollectedGraphData: true, DeleteEnvironments: []string{"subsidiary-a"}})
+				},
+				Setup: func() {
+					mockDB.EXPECT().GetFlagByKey(gomock.Any(), gomock.Any()).Return(appcfg.FeatureFlag{
+						Enabled: true,
+					}, nil)
+					mockDB.EXPECT().AppendAuditLog(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+					mockDB.EXPECT().RequestCollectedGraphDataDeletion(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, request model.AnalysisRequest) error {
+						assert.True(t, request.DeleteAllGraph)
+						assert.Equal(t, pq.StringArray{"subsidiary-a"}, request.DeleteEnvironments)
+						return nil
+					})
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusNoContent)
+				},
+			},
 			{
 				Name: "failed deletion of high value selectors",
 				Input: func(input *apitest.Input) {
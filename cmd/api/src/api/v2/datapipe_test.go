@@ -24,6 +24,7 @@ import (
 
 	v2 "github.com/specterops/bloodhound/cmd/api/src/api/v2"
 	dbMocks "github.com/specterops/bloodhound/cmd/api/src/database/mocks"
+	"github.com/specterops/bloodhound/cmd/api/src/database/types/null"
 	"github.com/specterops/bloodhound/cmd/api/src/model"
 	"github.com/specterops/bloodhound/cmd/api/src/utils/test"
 	"go.uber.org/mock/gomock"
@@ -64,6 +65,26 @@ func TestResources_GetDatapipeStatus(t *testing.T) {
 			ResponseStatusCode(200)
 	})
 
+	t.Run("success getting datapipe status with a scheduled analysis", func(t *testing.T) {
+		nextScheduledAnalysisAt := time.Now().Add(time.Hour).UTC()
+
+		mockDB.EXPECT().GetDatapipeStatus(gomock.Any()).Return(model.DatapipeStatusWrapper{
+			Status:                  "idle",
+			NextScheduledAnalysisAt: null.TimeFrom(nextScheduledAnalysisAt),
+		}, nil)
+
+		test.Request(t).
+			WithMethod(http.MethodGet).
+			WithURL(url).
+			OnHandlerFunc(resources.GetDatapipeStatus).
+			Require().
+			ResponseJSONBody(model.DatapipeStatusWrapper{
+				Status:                  "idle",
+				NextScheduledAnalysisAt: null.TimeFrom(nextScheduledAnalysisAt),
+			}).
+			ResponseStatusCode(200)
+	})
+
 	t.Run("error getting datapipe status", func(t *testing.T) {
 		mockDB.EXPECT().GetDatapipeStatus(gomock.Any()).Return(model.DatapipeStatusWrapper{}, fmt.Errorf("an error"))
 
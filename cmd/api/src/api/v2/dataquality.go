@@ -21,6 +21,7 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/specterops/bloodhound/cmd/api/src/api"
@@ -28,7 +29,11 @@ import (
 	"github.com/specterops/bloodhound/cmd/api/src/utils"
 	"github.com/specterops/bloodhound/packages/go/analysis/ad"
 	"github.com/specterops/bloodhound/packages/go/bhlog/measure"
+	adSchema "github.com/specterops/bloodhound/packages/go/graphschema/ad"
+	"github.com/specterops/bloodhound/packages/go/graphschema/azure"
+	"github.com/specterops/bloodhound/packages/go/graphschema/common"
 	"github.com/specterops/dawgs/graph"
+	"github.com/specterops/dawgs/query"
 )
 
 const (
@@ -190,6 +195,8 @@ func (s *Resources) GetPlatformAggregateStats(response http.ResponseWriter, requ
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf(utils.ErrorInvalidLimit, queryParams["limit"]), request), response)
 	} else if skip, err := ParseSkipQueryParameter(queryParams, 0); err != nil {
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf(utils.ErrorInvalidSkip, queryParams["skip"]), request), response)
+	} else if environment := queryParams.Get(api.QueryParameterEnvironment); environment != "" {
+		s.writeEnvironmentAggregateStats(response, request, id, environment, start, end, limit, skip)
 	} else {
 		var (
 			stats any
@@ -217,3 +224,70 @@ func (s *Resources) GetPlatformAggregateStats(response http.ResponseWriter, requ
 		api.WriteResponseWrapperWithTimeWindowAndPagination(request.Context(), stats, start, end, limit, skip, count, http.StatusOK, response)
 	}
 }
+
+// writeEnvironmentAggregateStats writes the data quality stats of the domains or tenants tagged with the environment
+// given, summed per day. Unlike the aggregations of the whole platform, these are computed from the stats of each
+// domain or tenant when requested.
+func (s *Resources) writeEnvironmentAggregateStats(response http.ResponseWriter, request *http.Request, platformID string, environment string, start time.Time, end time.Time, limit int, skip int) {
+	var scopeKind graph.Kind
+
+	switch platformID {
+	case "ad":
+		scopeKind = adSchema.Domain
+	case "azure":
+		scopeKind = azure.Tenant
+	default:
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf(ErrInvalidPlatformId, platformID), request), response)
+		return
+	}
+
+	if err := model.ValidateEnvironment(environment); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if nodes, err := s.GraphQuery.GetFilteredAndSortedNodes(nil, query.And(
+		query.Kind(query.Node(), scopeKind),
+		query.Equals(query.NodeProperty(common.Environment.String()), environment),
+	)); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, fmt.Sprintf("%s: %s", api.ErrorResponseDetailsInternalServerError, err), request), response)
+	} else {
+		var (
+			ids   = make([]string, 0, len(nodes))
+			stats any
+			count int
+		)
+
+		for _, node := range nodes {
+			if objectID, err := node.Properties.Get(common.ObjectID.String()).String(); err == nil {
+				ids = append(ids, objectID)
+			}
+		}
+
+		if platformID == "ad" {
+			if adStats, err := s.DB.GetAggregateADDataQualityStats(request.Context(), ids, start, end); err != nil {
+				api.HandleDatabaseError(request, response, err)
+				return
+			} else {
+				stats, count = paginateSlice(adStats, skip, limit), len(adStats)
+			}
+		} else {
+			if azureStats, err := s.DB.GetAggregateAzureDataQualityStats(request.Context(), ids, start, end); err != nil {
+				api.HandleDatabaseError(request, response, err)
+				return
+			} else {
+				stats, count = paginateSlice(azureStats, skip, limit), len(azureStats)
+			}
+		}
+
+		api.WriteResponseWrapperWithTimeWindowAndPagination(request.Context(), stats, start, end, limit, skip, count, http.StatusOK, response)
+	}
+}
+
+// paginateSlice returns the page of values selected by skip and limit
+func paginateSlice[T any](values []T, skip int, limit int) []T {
+	if skip >= len(values) {
+		return []T{}
+	} else if limit > 0 && skip+limit < len(values) {
+		return values[skip : skip+limit]
+	}
+
+	return values[skip:]
+}
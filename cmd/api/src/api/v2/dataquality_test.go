@@ -26,9 +26,14 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/specterops/bloodhound/cmd/api/src/api/v2/apitest"
+	queriesmocks "github.com/specterops/bloodhound/cmd/api/src/queries/mocks"
 	"github.com/specterops/bloodhound/cmd/api/src/utils"
 	"github.com/specterops/bloodhound/cmd/api/src/utils/test"
 	graphmocks "github.com/specterops/bloodhound/cmd/api/src/vendormocks/dawgs/graph"
+	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
+	"github.com/specterops/bloodhound/packages/go/graphschema/common"
+	"github.com/specterops/dawgs/graph"
 
 	"github.com/gorilla/mux"
 	"github.com/specterops/bloodhound/cmd/api/src/api"
@@ -912,6 +917,89 @@ func TestGetPlatformAggregateStats_Success(t *testing.T) {
 	}
 }
 
+func TestGetPlatformAggregateStats_Environment(t *testing.T) {
+	var (
+		mockCtrl         = gomock.NewController(t)
+		mockDB           = mocks.NewMockDatabase(mockCtrl)
+		mockGraphQueries = queriesmocks.NewMockGraph(mockCtrl)
+		resources        = v2.Resources{DB: mockDB, GraphQuery: mockGraphQueries}
+		domain           = graph.NewNode(1, graph.AsProperties(map[string]any{
+			common.ObjectID.String():    "S-1-5-21-1",
+			common.Environment.String(): "subsidiary-a",
+		}), ad.Domain)
+	)
+	defer mockCtrl.Finish()
+
+	apitest.NewHarness(t, resources.GetPlatformAggregateStats).
+		WithCommonRequest(func(input *apitest.Input) {
+			apitest.SetURLVar(input, api.URIPathVariablePlatformID, "ad")
+			apitest.AddQueryParam(input, api.QueryParameterEnvironment, "subsidiary-a")
+		}).
+		Run([]apitest.Case{
+			{
+				Name: "invalid environment",
+				Input: func(input *apitest.Input) {
+					apitest.DeleteQueryParam(input, api.QueryParameterEnvironment)
+					apitest.AddQueryParam(input, api.QueryParameterEnvironment, "subsidiary a")
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusBadRequest)
+					apitest.BodyContains(output, "invalid environment")
+				},
+			},
+			{
+				Name: "invalid platform",
+				Input: func(input *apitest.Input) {
+					apitest.SetURLVar(input, api.URIPathVariablePlatformID, "gcp")
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusBadRequest)
+				},
+			},
+			{
+				Name: "graph error",
+				Setup: func() {
+					mockGraphQueries.EXPECT().GetFilteredAndSortedNodes(gomock.Any(), gomock.Any()).Return(nil, errors.New("graph error"))
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusInternalServerError)
+				},
+			},
+			{
+				Name: "AD stats of the domains in the environment",
+				Input: func(input *apitest.Input) {
+					apitest.AddQueryParam(input, "skip", "1")
+				},
+				Setup: func() {
+					mockGraphQueries.EXPECT().GetFilteredAndSortedNodes(gomock.Any(), gomock.Any()).Return([]*graph.Node{domain}, nil)
+					mockDB.EXPECT().GetAggregateADDataQualityStats(gomock.Any(), []string{"S-1-5-21-1"}, gomock.Any(), gomock.Any()).Return(model.ADDataQualityStats{{Users: 1}, {Users: 2}}, nil)
+				},
+				Test: func(output apitest.Output) {
+					var stats model.ADDataQualityStats
+
+					apitest.StatusCode(output, http.StatusOK)
+					apitest.UnmarshalData(output, &stats)
+					apitest.Equal(output, 1, len(stats))
+					apitest.Equal(output, 2, stats[0].Users)
+					apitest.BodyContains(output, `"count":2`)
+				},
+			},
+			{
+				Name: "Azure stats of the tenants in the environment",
+				Input: func(input *apitest.Input) {
+					apitest.SetURLVar(input, api.URIPathVariablePlatformID, "azure")
+				},
+				Setup: func() {
+					mockGraphQueries.EXPECT().GetFilteredAndSortedNodes(gomock.Any(), gomock.Any()).Return([]*graph.Node{}, nil)
+					mockDB.EXPECT().GetAggregateAzureDataQualityStats(gomock.Any(), []string{}, gomock.Any(), gomock.Any()).Return(model.AzureDataQualityStats{}, nil)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusOK)
+				},
+			},
+		})
+}
+
 func TestResources_GetDatabaseCompleteness(t *testing.T) {
 	t.Parallel()
 
@@ -35,12 +35,17 @@ import (
 	ingestModel "github.com/specterops/bloodhound/cmd/api/src/model/ingest"
 	"github.com/specterops/bloodhound/packages/go/bhlog/measure"
 	"github.com/specterops/bloodhound/packages/go/headers"
+	"github.com/specterops/dawgs/graph"
 
+	"github.com/specterops/bloodhound/cmd/api/src/services/graphify"
 	"github.com/specterops/bloodhound/cmd/api/src/services/job"
 	"github.com/specterops/bloodhound/cmd/api/src/services/upload"
 )
 
-const FileUploadJobIdPathParameterName = "file_upload_job_id"
+const (
+	FileUploadJobIdPathParameterName = "file_upload_job_id"
+	IngestTaskIdPathParameterName    = "ingest_task_id"
+)
 
 func (s Resources) ListIngestJobs(response http.ResponseWriter, request *http.Request) {
 	var (
@@ -110,13 +115,51 @@ func (s Resources) ListIngestJobs(response http.ResponseWriter, request *http.Re
 	}
 }
 
+// ListIngestJobFiles returns the result recorded for every file processed as part of an ingest job, including files
+// extracted from uploaded archives, so that failed files can be found without reading the server logs.
+func (s Resources) ListIngestJobFiles(response http.ResponseWriter, request *http.Request) {
+	var (
+		queryParams = request.URL.Query()
+		jobIdString = mux.Vars(request)[FileUploadJobIdPathParameterName]
+	)
+
+	if jobID, err := strconv.ParseInt(jobIdString, 10, 64); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if skip, err := ParseSkipQueryParameter(queryParams, 0); err != nil {
+		api.WriteErrorResponse(request.Context(), ErrBadQueryParameter(request, model.PaginationQueryParameterSkip, err), response)
+	} else if limit, err := ParseLimitQueryParameter(queryParams, 100); err != nil {
+		api.WriteErrorResponse(request.Context(), ErrBadQueryParameter(request, model.PaginationQueryParameterLimit, err), response)
+	} else if ingestJob, err := job.GetIngestJobByID(request.Context(), s.DB, jobID); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if fileResults, count, err := job.GetIngestFileResultsForJob(request.Context(), s.DB, ingestJob.ID, skip, limit); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteResponseWrapperWithPagination(request.Context(), fileResults, limit, skip, count, http.StatusOK, response)
+	}
+}
+
+// StartIngestJobRequest is the optional body of a request to start an ingest job
+type StartIngestJobRequest struct {
+	AuthoritativeMode model.AuthoritativeMode `json:"authoritative_mode"`
+	Environment       string                  `json:"environment"`
+}
+
 func (s Resources) StartIngestJob(response http.ResponseWriter, request *http.Request) {
 	defer measure.ContextMeasure(request.Context(), slog.LevelDebug, "Starting new ingest job")()
-	reqCtx := ctx.Get(request.Context())
+	var (
+		reqCtx       = ctx.Get(request.Context())
+		startRequest StartIngestJobRequest
+	)
 
 	if user, valid := auth.GetUserFromAuthCtx(reqCtx.AuthCtx); !valid {
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusUnauthorized, api.ErrorResponseDetailsAuthenticationInvalid, request), response)
-	} else if ingestJob, err := job.StartIngestJob(request.Context(), s.DB, user); err != nil {
+	} else if request.Body != nil && request.ContentLength != 0 && api.ReadJSONRequestPayloadLimited(&startRequest, request) != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponsePayloadUnmarshalError, request), response)
+	} else if !startRequest.AuthoritativeMode.IsValid() {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf("invalid authoritative mode %q: must be one of flag or delete", startRequest.AuthoritativeMode), request), response)
+	} else if err := model.ValidateEnvironment(startRequest.Environment); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if ingestJob, err := job.StartIngestJob(request.Context(), s.DB, user, startRequest.AuthoritativeMode, startRequest.Environment); err != nil {
 		api.HandleDatabaseError(request, response, err)
 	} else {
 		api.WriteBasicResponse(request.Context(), ingestJob, http.StatusCreated, response)
@@ -135,14 +178,199 @@ func (s Resources) ProcessIngestTask(response http.ResponseWriter, request *http
 	}
 
 	if !IsValidContentTypeForUpload(request.Header) {
-		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, "Content type must be application/json or application/zip", request), response)
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, "Content type must be application/json, application/x-ndjson, multipart/form-data for csv files or a supported archive type (zip, gzip, tar.gz or zstd)", request), response)
 	} else if jobID, err := strconv.Atoi(jobIdString); err != nil {
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
 	} else if ingestJob, err := job.GetIngestJobByID(request.Context(), s.DB, int64(jobID)); err != nil {
 		api.HandleDatabaseError(request, response, err)
-	} else if ingestTaskParams, err := upload.SaveIngestFile(s.Config.TempDirectory(), request, validator); errors.Is(err, upload.ErrInvalidJSON) {
+	} else if ingestTaskParams, err := upload.SaveIngestFile(s.Config.TempDirectory(), request, validator); err != nil {
+		writeIngestFileError(request, response, err)
+	} else if _, err = upload.CreateIngestTask(request.Context(), s.DB, upload.IngestTaskParams{Filename: ingestTaskParams.Filename, OriginalFilename: ingestTaskParams.OriginalFilename, FileType: ingestTaskParams.FileType, CSVMapping: ingestTaskParams.CSVMapping, RequestID: requestId, JobID: int64(jobID), Environment: ingestJob.Environment}); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if err = job.TouchIngestJobLastIngest(request.Context(), s.DB, ingestJob); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		response.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// ValidateIngestFile runs an uploaded file through the ingest decoders without writing anything to the graph and
+// reports what would have been ingested. The upload is not attached to an ingest job and is discarded once read.
+func (s Resources) ValidateIngestFile(response http.ResponseWriter, request *http.Request) {
+	var (
+		validator = upload.NewIngestValidator(s.IngestSchema)
+		report    upload.ValidationReport
+	)
+
+	if request.Body != nil {
+		defer request.Body.Close()
+	}
+
+	if !IsValidContentTypeForUpload(request.Header) {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, "Content type must be application/json, application/x-ndjson, multipart/form-data for csv files or a supported archive type (zip, gzip, tar.gz or zstd)", request), response)
+	} else if sourceKinds, err := s.DB.GetSourceKinds(request.Context()); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if ingestTaskParams, err := upload.SaveIngestFile(s.Config.TempDirectory(), request, validator); errors.As(err, &report) {
+		api.WriteBasicResponse(request.Context(), graphify.NewDryRunReportForViolations(report), http.StatusOK, response)
+	} else if err != nil {
+		writeIngestFileError(request, response, err)
+	} else {
+		var (
+			knownSourceKinds = make(graph.Kinds, 0, len(sourceKinds))
+			graphifyService  = graphify.NewGraphifyService(request.Context(), s.DB, s.Graph, s.Config, s.IngestSchema)
+		)
+
+		for _, sourceKind := range sourceKinds {
+			knownSourceKinds = append(knownSourceKinds, sourceKind.Name)
+		}
+
+		if dryRunReport, err := graphifyService.DryRunIngestFile(request.Context(), ingestTaskParams.Filename, ingestTaskParams.OriginalFilename, ingestTaskParams.FileType, ingestTaskParams.CSVMapping, knownSourceKinds); err != nil {
+			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, fmt.Sprintf("Error validating ingest file: %v", err), request), response)
+		} else {
+			api.WriteBasicResponse(request.Context(), dryRunReport, http.StatusOK, response)
+		}
+	}
+}
+
+// StartChunkedIngestTask opens a resumable upload for a single collection file. The Content-Type of this request
+// declares the type of the file that will be assembled from the chunks sent to ProcessIngestTaskChunk.
+func (s Resources) StartChunkedIngestTask(response http.ResponseWriter, request *http.Request) {
+	var (
+		requestId   = ctx.FromRequest(request).RequestID
+		jobIdString = mux.Vars(request)[FileUploadJobIdPathParameterName]
+	)
+
+	if !IsValidContentTypeForUpload(request.Header) {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, "Content type must be application/json, application/x-ndjson, multipart/form-data for csv files or a supported archive type (zip, gzip, tar.gz or zstd)", request), response)
+	} else if jobID, err := strconv.ParseInt(jobIdString, 10, 64); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if ingestJob, err := job.GetIngestJobByID(request.Context(), s.DB, jobID); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if ingestJob.Status != model.JobStatusRunning {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, "job must be in running status to upload files", request), response)
+	} else if fileType, err := upload.FileTypeFromHeader(request.Header); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if fileType == model.FileTypeCSV {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, "csv files must be uploaded in a single request with their mapping", request), response)
+	} else if ingestTask, err := upload.StartChunkedUpload(request.Context(), s.DB, s.Config.TempDirectory(), upload.IngestTaskParams{FileType: fileType, OriginalFilename: upload.FileNameFromHeader(request.Header), RequestID: requestId, JobID: jobID, Environment: ingestJob.Environment}); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if err = job.TouchIngestJobLastIngest(request.Context(), s.DB, ingestJob); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if status, err := upload.GetChunkedUploadStatus(ingestTask); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, api.ErrorResponseDetailsInternalServerError, request), response)
+	} else {
+		api.WriteBasicResponse(request.Context(), status, http.StatusCreated, response)
+	}
+}
+
+// getChunkedIngestTask resolves the job and task path parameters of a chunked upload request, writing the
+// appropriate error response and returning false if either is invalid
+func (s Resources) getChunkedIngestTask(response http.ResponseWriter, request *http.Request) (model.IngestJob, model.IngestTask, bool) {
+	var (
+		jobIdString  = mux.Vars(request)[FileUploadJobIdPathParameterName]
+		taskIdString = mux.Vars(request)[IngestTaskIdPathParameterName]
+	)
+
+	if jobID, err := strconv.ParseInt(jobIdString, 10, 64); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if taskID, err := strconv.ParseInt(taskIdString, 10, 64); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if ingestJob, err := job.GetIngestJobByID(request.Context(), s.DB, jobID); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if ingestTask, err := s.DB.GetIngestTask(request.Context(), taskID); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if ingestTask.JobId.ValueOrZero() != jobID {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusNotFound, api.ErrorResponseDetailsResourceNotFound, request), response)
+	} else {
+		return ingestJob, ingestTask, true
+	}
+
+	return model.IngestJob{}, model.IngestTask{}, false
+}
+
+// GetChunkedIngestTaskStatus reports how many bytes of a chunked upload have been received so that an interrupted
+// client knows which offset to resume from.
+func (s Resources) GetChunkedIngestTaskStatus(response http.ResponseWriter, request *http.Request) {
+	if _, ingestTask, ok := s.getChunkedIngestTask(response, request); !ok {
+		return
+	} else if status, err := upload.GetChunkedUploadStatus(ingestTask); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, api.ErrorResponseDetailsInternalServerError, request), response)
+	} else {
+		api.WriteBasicResponse(request.Context(), status, http.StatusOK, response)
+	}
+}
+
+// ProcessIngestTaskChunk appends the byte range given by the Content-Range header to a chunked upload. Once the
+// final byte has been received the assembled file is validated in the same way as a single request upload and the
+// task is released to the ingest pipeline.
+func (s Resources) ProcessIngestTaskChunk(response http.ResponseWriter, request *http.Request) {
+	validator := upload.NewIngestValidator(s.IngestSchema)
+
+	if request.Body != nil {
+		defer request.Body.Close()
+	}
+
+	ingestJob, ingestTask, ok := s.getChunkedIngestTask(response, request)
+	if !ok {
+		return
+	}
+
+	if ingestJob.Status != model.JobStatusRunning {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, "job must be in running status to upload files", request), response)
+	} else if !ingestTask.Partial {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusConflict, upload.ErrUploadNotPartial.Error(), request), response)
+	} else if contentRange, err := upload.ParseContentRange(request.Header.Get(headers.ContentRange.String())); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if status, err := upload.WriteIngestChunk(ingestTask, contentRange, request.Body); errors.Is(err, upload.ErrUploadNotPartial) {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusConflict, err.Error(), request), response)
+	} else if errors.Is(err, upload.ErrChunkOffsetMismatch) {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusConflict, fmt.Sprintf("%v: expected chunk starting at byte %d", err, status.ReceivedBytes), request), response)
+	} else if errors.Is(err, upload.ErrChunkTotalMismatch) {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, fmt.Sprintf("Error saving ingest chunk: %v", err), request), response)
+	} else if err := job.TouchIngestJobLastIngest(request.Context(), s.DB, ingestJob); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if status.ReceivedBytes < status.TotalBytes {
+		ingestTask.TotalBytes = status.TotalBytes
+
+		if err := s.DB.UpdateIngestTask(request.Context(), ingestTask); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else {
+			api.WriteBasicResponse(request.Context(), status, http.StatusOK, response)
+		}
+	} else if fileName, err := upload.FinalizeChunkedUpload(s.Config.TempDirectory(), ingestTask, validator); errors.Is(err, upload.ErrUploadNotPartial) {
+		// Another request for the same final chunk already finalized the upload
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusConflict, err.Error(), request), response)
+	} else if err != nil {
+		// The partial file is gone at this point, so the upload cannot be resumed and the task is discarded
+		if err := s.DB.DeleteIngestTask(request.Context(), ingestTask); err != nil {
+			slog.ErrorContext(request.Context(), fmt.Sprintf("Error removing failed chunked ingest task %d: %v", ingestTask.ID, err))
+		}
+
+		writeIngestFileError(request, response, err)
+	} else {
+		ingestTask.FileName = fileName
+		ingestTask.TotalBytes = status.TotalBytes
+		ingestTask.Partial = false
+
+		if err := s.DB.UpdateIngestTask(request.Context(), ingestTask); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else {
+			status.Complete = true
+			api.WriteBasicResponse(request.Context(), status, http.StatusAccepted, response)
+		}
+	}
+}
+
+// writeIngestFileError writes the API error for a failed attempt to save and validate an ingest file. Schema
+// validation failures are reported back to the client one error detail per violation.
+func writeIngestFileError(request *http.Request, response http.ResponseWriter, err error) {
+	var report upload.ValidationReport
+
+	if errors.Is(err, upload.ErrInvalidJSON) || errors.Is(err, upload.ErrInvalidCSVUpload) {
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf("Error saving ingest file: %v", err), request), response)
-	} else if report, ok := err.(upload.ValidationReport); ok {
+	} else if errors.As(err, &report) {
 		var (
 			msgs       = report.BuildAPIError()
 			errDetails = []api.ErrorDetails{}
@@ -160,14 +388,8 @@ func (s Resources) ProcessIngestTask(response http.ResponseWriter, request *http
 		}
 
 		api.WriteErrorResponse(request.Context(), e, response)
-	} else if err != nil {
-		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, fmt.Sprintf("Error saving ingest file: %v", err), request), response)
-	} else if _, err = upload.CreateIngestTask(request.Context(), s.DB, upload.IngestTaskParams{Filename: ingestTaskParams.Filename, FileType: ingestTaskParams.FileType, RequestID: requestId, JobID: int64(jobID)}); err != nil {
-		api.HandleDatabaseError(request, response, err)
-	} else if err = job.TouchIngestJobLastIngest(request.Context(), s.DB, ingestJob); err != nil {
-		api.HandleDatabaseError(request, response, err)
 	} else {
-		response.WriteHeader(http.StatusAccepted)
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, fmt.Sprintf("Error saving ingest file: %v", err), request), response)
 	}
 }
 
@@ -182,6 +404,10 @@ func (s Resources) EndIngestJob(response http.ResponseWriter, request *http.Requ
 		api.HandleDatabaseError(request, response, err)
 	} else if ingestJob.Status != model.JobStatusRunning {
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, "job must be in running status to end", request), response)
+	} else if hasPartialTasks, err := job.HasPartialIngestTasks(request.Context(), s.DB, ingestJob.ID); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if hasPartialTasks {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, "job has chunked uploads that have not received all of their chunks", request), response)
 	} else if err := job.EndIngestJob(request.Context(), s.DB, ingestJob); err != nil {
 		api.HandleDatabaseError(request, response, err)
 	} else {
@@ -189,10 +415,79 @@ func (s Resources) EndIngestJob(response http.ResponseWriter, request *http.Requ
 	}
 }
 
+// CancelIngestJob cancels a job that is still receiving or ingesting files. As a canceled job is never analyzed,
+// analysis is requested for the data it may have written to the graph.
+func (s Resources) CancelIngestJob(response http.ResponseWriter, request *http.Request) {
+	defer measure.ContextMeasure(request.Context(), slog.LevelDebug, "Canceled ingest job")()
+
+	jobIdString := mux.Vars(request)[FileUploadJobIdPathParameterName]
+
+	if jobID, err := strconv.Atoi(jobIdString); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if ingestJob, err := job.GetIngestJobByID(request.Context(), s.DB, int64(jobID)); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if _, err := job.CancelIngestJob(request.Context(), s.DB, ingestJob); errors.Is(err, job.ErrIngestJobNotCancelable) {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusConflict, err.Error(), request), response)
+	} else if err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if err := s.requestAnalysisOfCanceledJob(request, ingestJob); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		response.WriteHeader(http.StatusOK)
+	}
+}
+
+// requestAnalysisOfCanceledJob requests analysis if the job given, as it was before being canceled, may have written
+// data to the graph
+func (s Resources) requestAnalysisOfCanceledJob(request *http.Request, ingestJob model.IngestJob) error {
+	if ingestJob.Status != model.JobStatusIngesting && ingestJob.TotalFiles == 0 {
+		return nil
+	}
+
+	requester := "unknown-user"
+	if user, isUser := auth.GetUserFromAuthCtx(ctx.FromRequest(request).AuthCtx); isUser {
+		requester = user.ID.String()
+	}
+
+	return s.DB.RequestAnalysis(request.Context(), requester)
+}
+
 func (s Resources) ListAcceptedFileUploadTypes(response http.ResponseWriter, request *http.Request) {
 	api.WriteBasicResponse(request.Context(), ingestModel.AllowedFileUploadTypes, http.StatusOK, response)
 }
 
+type CSVMappingValidationResponse struct {
+	Valid   bool     `json:"valid"`
+	Errors  []string `json:"errors"`
+	Columns []string `json:"columns"`
+}
+
+// ValidateCSVMapping checks a CSV mapping before a file is uploaded with it, returning every problem with the mapping
+// and the columns the header of a file must name to be ingested with it
+func (s Resources) ValidateCSVMapping(response http.ResponseWriter, request *http.Request) {
+	var mapping ingestModel.CSVMapping
+
+	if err := api.ReadJSONRequestPayloadLimited(&mapping, request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponsePayloadUnmarshalError, request), response)
+	} else {
+		problems := mapping.Validate()
+		if problems == nil {
+			problems = []string{}
+		}
+
+		columns := mapping.Columns()
+		if columns == nil {
+			columns = []string{}
+		}
+
+		api.WriteBasicResponse(request.Context(), CSVMappingValidationResponse{
+			Valid:   len(problems) == 0,
+			Errors:  problems,
+			Columns: columns,
+		}, http.StatusOK, response)
+	}
+}
+
 func IsValidContentTypeForUpload(header http.Header) bool {
 	rawValue := header.Get(headers.ContentType.String())
 	if rawValue == "" {
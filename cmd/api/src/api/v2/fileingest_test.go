@@ -28,21 +28,29 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/gorilla/mux"
+	"github.com/specterops/bloodhound/cmd/api/src/api"
 	v2 "github.com/specterops/bloodhound/cmd/api/src/api/v2"
 	"github.com/specterops/bloodhound/cmd/api/src/api/v2/apitest"
 	"github.com/specterops/bloodhound/cmd/api/src/auth"
 	"github.com/specterops/bloodhound/cmd/api/src/config"
 	"github.com/specterops/bloodhound/cmd/api/src/ctx"
+	"github.com/specterops/bloodhound/cmd/api/src/database"
 	dbmocks "github.com/specterops/bloodhound/cmd/api/src/database/mocks"
 	"github.com/specterops/bloodhound/cmd/api/src/database/types/null"
 	"github.com/specterops/bloodhound/cmd/api/src/model"
 	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
+	"github.com/specterops/bloodhound/cmd/api/src/services/upload"
+	graphmocks "github.com/specterops/bloodhound/cmd/api/src/vendormocks/dawgs/graph"
 	"github.com/specterops/bloodhound/packages/go/headers"
+	"github.com/specterops/bloodhound/packages/go/mediatypes"
+	"github.com/specterops/dawgs/graph"
 
 	"github.com/specterops/bloodhound/cmd/api/src/utils/test"
 	"github.com/stretchr/testify/assert"
@@ -214,7 +222,135 @@ func TestResources_StartIngestJob(t *testing.T) {
 			},
 			expected: expected{
 				responseCode:   http.StatusCreated,
-				responseBody:   `{"data":{"created_at":"0001-01-01T00:00:00Z", "deleted_at":{"Time":"0001-01-01T00:00:00Z", "Valid":false}, "end_time":"0001-01-01T00:00:00Z", "failed_files":0, "id":0, "last_ingest":"0001-01-01T00:00:00Z", "start_time":"0001-01-01T00:00:00Z", "status":1, "status_message":"", "total_files":0, "updated_at":"0001-01-01T00:00:00Z", "user_email_address": "email@notreal.com", "user_id":"00000000-0000-0000-0000-000000000000"}}`,
+				responseBody:   `{"data":{"authoritative_mode":"", "collected_domains":null, "collected_source_kinds":null, "created_at":"0001-01-01T00:00:00Z", "deleted_at":{"Time":"0001-01-01T00:00:00Z", "Valid":false}, "end_time":"0001-01-01T00:00:00Z", "environment":"", "failed_files":0, "id":0, "last_ingest":"0001-01-01T00:00:00Z", "redacted_properties":null, "stale_nodes":0, "stale_relationships":0, "deleted_nodes":0, "deleted_relationships":0, "start_time":"0001-01-01T00:00:00Z", "status":1, "status_message":"", "total_files":0, "touched_domains":null, "touched_tenants":null, "unscoped_objects":0, "updated_at":"0001-01-01T00:00:00Z", "user_email_address": "email@notreal.com", "user_id":"00000000-0000-0000-0000-000000000000"}}`,
+				responseHeader: http.Header{"Content-Type": []string{"application/json"}},
+			},
+		}, {
+			name: "Success: Authoritative Mode - 201",
+			buildRequest: func() *http.Request {
+				request := httptest.NewRequest(http.MethodPost, "/api/v2/file-upload/start", strings.NewReader(`{"authoritative_mode": "delete"}`))
+				request.Header.Set(headers.ContentType.String(), mediatypes.ApplicationJson.String())
+
+				requestCtx := ctx.Context{
+					RequestID: "id",
+					AuthCtx: auth.Context{
+						Owner:   model.User{},
+						Session: model.UserSession{},
+					},
+				}
+
+				return request.WithContext(context.WithValue(context.Background(), ctx.ValueKey, requestCtx.WithRequestID("id")))
+			},
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+				mock.mockDatabase.EXPECT().CreateIngestJob(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, job model.IngestJob) (model.IngestJob, error) {
+					assert.Equal(t, model.AuthoritativeModeDelete, job.AuthoritativeMode)
+					return model.IngestJob{Status: model.JobStatusRunning, AuthoritativeMode: job.AuthoritativeMode}, nil
+				})
+			},
+			expected: expected{
+				responseCode:   http.StatusCreated,
+				responseBody:   `{"data":{"authoritative_mode":"delete", "collected_domains":null, "collected_source_kinds":null, "created_at":"0001-01-01T00:00:00Z", "deleted_at":{"Time":"0001-01-01T00:00:00Z", "Valid":false}, "end_time":"0001-01-01T00:00:00Z", "environment":"", "failed_files":0, "id":0, "last_ingest":"0001-01-01T00:00:00Z", "redacted_properties":null, "stale_nodes":0, "stale_relationships":0, "deleted_nodes":0, "deleted_relationships":0, "start_time":"0001-01-01T00:00:00Z", "status":1, "status_message":"", "total_files":0, "touched_domains":null, "touched_tenants":null, "unscoped_objects":0, "updated_at":"0001-01-01T00:00:00Z", "user_email_address":null, "user_id":"00000000-0000-0000-0000-000000000000"}}`,
+				responseHeader: http.Header{"Content-Type": []string{"application/json"}},
+			},
+		}, {
+			name: "Success: Environment - 201",
+			buildRequest: func() *http.Request {
+				request := httptest.NewRequest(http.MethodPost, "/api/v2/file-upload/start", strings.NewReader(`{"environment": "subsidiary-a"}`))
+				request.Header.Set(headers.ContentType.String(), mediatypes.ApplicationJson.String())
+
+				requestCtx := ctx.Context{
+					RequestID: "id",
+					AuthCtx: auth.Context{
+						Owner:   model.User{},
+						Session: model.UserSession{},
+					},
+				}
+
+				return request.WithContext(context.WithValue(context.Background(), ctx.ValueKey, requestCtx.WithRequestID("id")))
+			},
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+				mock.mockDatabase.EXPECT().CreateIngestJob(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, job model.IngestJob) (model.IngestJob, error) {
+					assert.Equal(t, "subsidiary-a", job.Environment)
+					return model.IngestJob{Status: model.JobStatusRunning, Environment: job.Environment}, nil
+				})
+			},
+			expected: expected{
+				responseCode:   http.StatusCreated,
+				responseBody:   `{"data":{"authoritative_mode":"", "collected_domains":null, "collected_source_kinds":null, "created_at":"0001-01-01T00:00:00Z", "deleted_at":{"Time":"0001-01-01T00:00:00Z", "Valid":false}, "end_time":"0001-01-01T00:00:00Z", "environment":"subsidiary-a", "failed_files":0, "id":0, "last_ingest":"0001-01-01T00:00:00Z", "redacted_properties":null, "stale_nodes":0, "stale_relationships":0, "deleted_nodes":0, "deleted_relationships":0, "start_time":"0001-01-01T00:00:00Z", "status":1, "status_message":"", "total_files":0, "touched_domains":null, "touched_tenants":null, "unscoped_objects":0, "updated_at":"0001-01-01T00:00:00Z", "user_email_address":null, "user_id":"00000000-0000-0000-0000-000000000000"}}`,
+				responseHeader: http.Header{"Content-Type": []string{"application/json"}},
+			},
+		}, {
+			name: "Error: Invalid Environment - 400",
+			buildRequest: func() *http.Request {
+				request := httptest.NewRequest(http.MethodPost, "/api/v2/file-upload/start", strings.NewReader(`{"environment": "subsidiary a"}`))
+				request.Header.Set(headers.ContentType.String(), mediatypes.ApplicationJson.String())
+
+				requestCtx := ctx.Context{
+					RequestID: "id",
+					AuthCtx: auth.Context{
+						Owner:   model.User{},
+						Session: model.UserSession{},
+					},
+				}
+
+				return request.WithContext(context.WithValue(context.Background(), ctx.ValueKey, requestCtx.WithRequestID("id")))
+			},
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+			},
+			expected: expected{
+				responseCode:   http.StatusBadRequest,
+				responseBody:   `{"errors":[{"context":"", "message":"invalid environment \"subsidiary a\": must match ^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$"}],"http_status":400,"request_id":"id","timestamp":"0001-01-01T00:00:00Z"}`,
+				responseHeader: http.Header{"Content-Type": []string{"application/json"}},
+			},
+		}, {
+			name: "Error: Invalid Authoritative Mode - 400",
+			buildRequest: func() *http.Request {
+				request := httptest.NewRequest(http.MethodPost, "/api/v2/file-upload/start", strings.NewReader(`{"authoritative_mode": "purge"}`))
+				request.Header.Set(headers.ContentType.String(), mediatypes.ApplicationJson.String())
+
+				requestCtx := ctx.Context{
+					RequestID: "id",
+					AuthCtx: auth.Context{
+						Owner:   model.User{},
+						Session: model.UserSession{},
+					},
+				}
+
+				return request.WithContext(context.WithValue(context.Background(), ctx.ValueKey, requestCtx.WithRequestID("id")))
+			},
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+			},
+			expected: expected{
+				responseCode:   http.StatusBadRequest,
+				responseBody:   `{"errors":[{"context":"", "message":"invalid authoritative mode \"purge\": must be one of flag or delete"}],"http_status":400,"request_id":"id","timestamp":"0001-01-01T00:00:00Z"}`,
+				responseHeader: http.Header{"Content-Type": []string{"application/json"}},
+			},
+		}, {
+			name: "Error: Malformed Body - 400",
+			buildRequest: func() *http.Request {
+				request := httptest.NewRequest(http.MethodPost, "/api/v2/file-upload/start", strings.NewReader(`{"authoritative_mode": `))
+				request.Header.Set(headers.ContentType.String(), mediatypes.ApplicationJson.String())
+
+				requestCtx := ctx.Context{
+					RequestID: "id",
+					AuthCtx: auth.Context{
+						Owner:   model.User{},
+						Session: model.UserSession{},
+					},
+				}
+
+				return request.WithContext(context.WithValue(context.Background(), ctx.ValueKey, requestCtx.WithRequestID("id")))
+			},
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+			},
+			expected: expected{
+				responseCode:   http.StatusBadRequest,
+				responseBody:   `{"errors":[{"context":"", "message":"error unmarshalling JSON payload"}],"http_status":400,"request_id":"id","timestamp":"0001-01-01T00:00:00Z"}`,
 				responseHeader: http.Header{"Content-Type": []string{"application/json"}},
 			},
 		},
@@ -354,6 +490,7 @@ func TestResources_EndIngestJob(t *testing.T) {
 					User:             model.User{PrincipalName: "name"},
 					Status:           model.JobStatusRunning,
 				}, nil)
+				mock.mockDatabase.EXPECT().GetIngestTasksForJob(gomock.Any(), gomock.Any()).Return(model.IngestTasks{}, nil)
 				mock.mockDatabase.EXPECT().UpdateIngestJob(gomock.Any(), gomock.Any()).Return(errors.New("random error"))
 			},
 			expected: expected{
@@ -362,6 +499,36 @@ func TestResources_EndIngestJob(t *testing.T) {
 				responseHeader: http.Header{"Content-Type": []string{"application/json"}},
 			},
 		},
+		{
+			name: "Error: Incomplete Chunked Upload - 400",
+			buildRequest: func() *http.Request {
+				request := &http.Request{
+					URL: &url.URL{Path: "/api/v2/file-upload/123/end"}, Method: http.MethodPost,
+				}
+
+				requestCtx := ctx.Context{
+					RequestID: "id",
+					AuthCtx: auth.Context{
+						Owner:   model.User{},
+						Session: model.UserSession{},
+					},
+				}
+
+				return request.WithContext(context.WithValue(context.Background(), ctx.ValueKey, requestCtx.WithRequestID("id")))
+			},
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+				mock.mockDatabase.EXPECT().GetIngestJob(gomock.Any(), gomock.Any()).Return(model.IngestJob{
+					Status: model.JobStatusRunning,
+				}, nil)
+				mock.mockDatabase.EXPECT().GetIngestTasksForJob(gomock.Any(), gomock.Any()).Return(model.IngestTasks{{Partial: true}}, nil)
+			},
+			expected: expected{
+				responseCode:   http.StatusBadRequest,
+				responseBody:   `{"errors":[{"context":"", "message":"job has chunked uploads that have not received all of their chunks"}], "http_status":400, "request_id":"id", "timestamp":"0001-01-01T00:00:00Z"}`,
+				responseHeader: http.Header{"Content-Type": []string{"application/json"}},
+			},
+		},
 		{
 			name: "Error: GetIngestJob Database Error - 500",
 			buildRequest: func() *http.Request {
@@ -414,6 +581,7 @@ func TestResources_EndIngestJob(t *testing.T) {
 					User:             model.User{PrincipalName: "name"},
 					Status:           model.JobStatusRunning,
 				}, nil)
+				mock.mockDatabase.EXPECT().GetIngestTasksForJob(gomock.Any(), gomock.Any()).Return(model.IngestTasks{}, nil)
 				mock.mockDatabase.EXPECT().UpdateIngestJob(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			expected: expected{
@@ -460,6 +628,272 @@ func TestResources_EndIngestJob(t *testing.T) {
 	}
 }
 
+func TestResources_CancelIngestJob(t *testing.T) {
+	t.Parallel()
+
+	type expected struct {
+		responseBody   string
+		responseCode   int
+		responseHeader http.Header
+	}
+	type testData struct {
+		name       string
+		path       string
+		setupMocks func(t *testing.T, mockDatabase *dbmocks.MockDatabase)
+		expected   expected
+	}
+
+	tt := []testData{
+		{
+			name:       "Error: Invalid Job - 400",
+			path:       "/api/v2/file-upload/invalid/cancel",
+			setupMocks: func(t *testing.T, mockDatabase *dbmocks.MockDatabase) {},
+			expected: expected{
+				responseCode:   http.StatusBadRequest,
+				responseBody:   `{"errors":[{"context":"", "message":"id is malformed."}], "http_status":400, "request_id":"id", "timestamp":"0001-01-01T00:00:00Z"}`,
+				responseHeader: http.Header{"Content-Type": []string{"application/json"}},
+			},
+		},
+		{
+			name: "Error: Job Not Found - 404",
+			path: "/api/v2/file-upload/123/cancel",
+			setupMocks: func(t *testing.T, mockDatabase *dbmocks.MockDatabase) {
+				mockDatabase.EXPECT().GetIngestJob(gomock.Any(), int64(123)).Return(model.IngestJob{}, database.ErrNotFound)
+			},
+			expected: expected{
+				responseCode:   http.StatusNotFound,
+				responseBody:   `{"errors":[{"context":"","message":"resource not found"}],"http_status":404,"request_id":"id","timestamp":"0001-01-01T00:00:00Z"}`,
+				responseHeader: http.Header{"Content-Type": []string{"application/json"}},
+			},
+		},
+		{
+			name: "Error: Job Waiting For Analysis - 409",
+			path: "/api/v2/file-upload/123/cancel",
+			setupMocks: func(t *testing.T, mockDatabase *dbmocks.MockDatabase) {
+				mockDatabase.EXPECT().GetIngestJob(gomock.Any(), int64(123)).Return(model.IngestJob{Status: model.JobStatusAnalyzing}, nil)
+			},
+			expected: expected{
+				responseCode:   http.StatusConflict,
+				responseBody:   `{"errors":[{"context":"","message":"job must be running or ingesting to be canceled"}],"http_status":409,"request_id":"id","timestamp":"0001-01-01T00:00:00Z"}`,
+				responseHeader: http.Header{"Content-Type": []string{"application/json"}},
+			},
+		},
+		{
+			name: "Error: Update Database Error - 500",
+			path: "/api/v2/file-upload/123/cancel",
+			setupMocks: func(t *testing.T, mockDatabase *dbmocks.MockDatabase) {
+				mockDatabase.EXPECT().GetIngestJob(gomock.Any(), int64(123)).Return(model.IngestJob{Status: model.JobStatusRunning}, nil)
+				mockDatabase.EXPECT().UpdateIngestJob(gomock.Any(), gomock.Any()).Return(errors.New("random error"))
+			},
+			expected: expected{
+				responseCode:   http.StatusInternalServerError,
+				responseBody:   `{"errors":[{"context":"","message":"an internal error has occurred that is preventing the service from servicing this request"}],"http_status":500,"request_id":"id","timestamp":"0001-01-01T00:00:00Z"}`,
+				responseHeader: http.Header{"Content-Type": []string{"application/json"}},
+			},
+		},
+		{
+			name: "Success: Running Job Without Files - 200",
+			path: "/api/v2/file-upload/123/cancel",
+			setupMocks: func(t *testing.T, mockDatabase *dbmocks.MockDatabase) {
+				mockDatabase.EXPECT().GetIngestJob(gomock.Any(), int64(123)).Return(model.IngestJob{BigSerial: model.BigSerial{ID: 123}, Status: model.JobStatusRunning}, nil)
+				mockDatabase.EXPECT().UpdateIngestJob(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, job model.IngestJob) error {
+					assert.Equal(t, model.JobStatusCanceled, job.Status)
+					return nil
+				})
+				mockDatabase.EXPECT().GetIngestTasksForJob(gomock.Any(), int64(123)).Return(model.IngestTasks{{FileName: "/nonexistent/chunked"}}, nil)
+				mockDatabase.EXPECT().DeleteIngestTask(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expected: expected{
+				responseCode:   http.StatusOK,
+				responseBody:   ``,
+				responseHeader: http.Header{},
+			},
+		},
+		{
+			name: "Success: Ingesting Job Requests Analysis - 200",
+			path: "/api/v2/file-upload/123/cancel",
+			setupMocks: func(t *testing.T, mockDatabase *dbmocks.MockDatabase) {
+				mockDatabase.EXPECT().GetIngestJob(gomock.Any(), int64(123)).Return(model.IngestJob{BigSerial: model.BigSerial{ID: 123}, Status: model.JobStatusIngesting}, nil)
+				mockDatabase.EXPECT().UpdateIngestJob(gomock.Any(), gomock.Any()).Return(nil)
+				mockDatabase.EXPECT().GetIngestTasksForJob(gomock.Any(), int64(123)).Return(model.IngestTasks{}, nil)
+				mockDatabase.EXPECT().RequestAnalysis(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expected: expected{
+				responseCode:   http.StatusOK,
+				responseBody:   ``,
+				responseHeader: http.Header{},
+			},
+		},
+	}
+
+	for _, testCase := range tt {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			var (
+				ctrl         = gomock.NewController(t)
+				mockDatabase = dbmocks.NewMockDatabase(ctrl)
+				resources    = v2.Resources{DB: mockDatabase}
+				response     = httptest.NewRecorder()
+				router       = mux.NewRouter()
+			)
+
+			testCase.setupMocks(t, mockDatabase)
+
+			requestCtx := ctx.Context{RequestID: "id"}
+			request := httptest.NewRequest(http.MethodPost, testCase.path, nil)
+			request = request.WithContext(context.WithValue(context.Background(), ctx.ValueKey, requestCtx.WithRequestID("id")))
+
+			router.HandleFunc(fmt.Sprintf("/api/v2/file-upload/{%s}/cancel", v2.FileUploadJobIdPathParameterName), resources.CancelIngestJob).Methods(http.MethodPost)
+			router.ServeHTTP(response, request)
+
+			status, header, body := test.ProcessResponse(t, response)
+
+			assert.Equal(t, testCase.expected.responseCode, status)
+			assert.Equal(t, testCase.expected.responseHeader, header)
+			if body != "" {
+				assert.JSONEq(t, testCase.expected.responseBody, body)
+			} else {
+				assert.Equal(t, testCase.expected.responseBody, body)
+			}
+		})
+	}
+}
+
+func TestResources_ListIngestJobFiles(t *testing.T) {
+	t.Parallel()
+
+	type mock struct {
+		mockDatabase *dbmocks.MockDatabase
+	}
+	type expected struct {
+		responseBody string
+		responseCode int
+	}
+	type testData struct {
+		name       string
+		path       string
+		setupMocks func(t *testing.T, mock *mock)
+		expected   expected
+	}
+
+	tt := []testData{
+		{
+			name: "Error: Invalid Job ID - 400",
+			path: "/api/v2/file-upload/invalid/files",
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+			},
+			expected: expected{
+				responseCode: http.StatusBadRequest,
+				responseBody: `{"errors":[{"context":"", "message":"id is malformed."}], "http_status":400, "request_id":"id", "timestamp":"0001-01-01T00:00:00Z"}`,
+			},
+		},
+		{
+			name: "Error: Invalid Limit - 400",
+			path: "/api/v2/file-upload/123/files?limit=invalid",
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+			},
+			expected: expected{
+				responseCode: http.StatusBadRequest,
+				responseBody: `{"errors":[{"context":"", "message":"query parameter \"limit\" is malformed: error converting limit value invalid to int: strconv.Atoi: parsing \"invalid\": invalid syntax"}], "http_status":400, "request_id":"id", "timestamp":"0001-01-01T00:00:00Z"}`,
+			},
+		},
+		{
+			name: "Error: Job Not Found - 404",
+			path: "/api/v2/file-upload/123/files",
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+				mock.mockDatabase.EXPECT().GetIngestJob(gomock.Any(), int64(123)).Return(model.IngestJob{}, database.ErrNotFound)
+			},
+			expected: expected{
+				responseCode: http.StatusNotFound,
+				responseBody: `{"errors":[{"context":"", "message":"resource not found"}], "http_status":404, "request_id":"id", "timestamp":"0001-01-01T00:00:00Z"}`,
+			},
+		},
+		{
+			name: "Error: Database Error - 500",
+			path: "/api/v2/file-upload/123/files",
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+				mock.mockDatabase.EXPECT().GetIngestJob(gomock.Any(), int64(123)).Return(model.IngestJob{BigSerial: model.BigSerial{ID: 123}}, nil)
+				mock.mockDatabase.EXPECT().GetIngestFileResultsForJob(gomock.Any(), int64(123), 0, 100).Return(nil, 0, errors.New("database error"))
+			},
+			expected: expected{
+				responseCode: http.StatusInternalServerError,
+				responseBody: `{"errors":[{"context":"", "message":"an internal error has occurred that is preventing the service from servicing this request"}], "http_status":500, "request_id":"id", "timestamp":"0001-01-01T00:00:00Z"}`,
+			},
+		},
+		{
+			name: "Success: File Results - 200",
+			path: "/api/v2/file-upload/123/files?skip=1&limit=2",
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+				mock.mockDatabase.EXPECT().GetIngestJob(gomock.Any(), int64(123)).Return(model.IngestJob{BigSerial: model.BigSerial{ID: 123}}, nil)
+				mock.mockDatabase.EXPECT().GetIngestFileResultsForJob(gomock.Any(), int64(123), 1, 2).Return(model.IngestFileResults{
+					{
+						JobID:             123,
+						FileName:          "collection/users.json",
+						DataType:          ingest.DataTypeUser,
+						NodeCount:         10,
+						RelationshipCount: 20,
+						BigSerial:         model.BigSerial{ID: 2},
+					},
+					{
+						JobID:                    123,
+						FileName:                 "collection/opengraph.json",
+						DataType:                 ingest.DataTypeOpenGraph,
+						RelationshipCount:        4,
+						SkippedRelationshipCount: 1,
+						Error:                    "skipping invalid relationship. unable to resolve endpoints. source: alice, target: bob",
+						BigSerial:                model.BigSerial{ID: 3},
+					},
+				}, 3, nil)
+			},
+			expected: expected{
+				responseCode: http.StatusOK,
+				responseBody: `{"count":3, "limit":2, "skip":1, "data":[
+					{"id":2, "job_id":123, "file_name":"collection/users.json", "data_type":"users", "node_count":10, "relationship_count":20, "skipped_relationship_count":0, "invalid_relationship_count":0, "deleted_node_count":0, "deleted_relationship_count":0, "error":"", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z", "deleted_at":{"Time":"0001-01-01T00:00:00Z", "Valid":false}},
+					{"id":3, "job_id":123, "file_name":"collection/opengraph.json", "data_type":"opengraph", "node_count":0, "relationship_count":4, "skipped_relationship_count":1, "invalid_relationship_count":0, "deleted_node_count":0, "deleted_relationship_count":0, "error":"skipping invalid relationship. unable to resolve endpoints. source: alice, target: bob", "created_at":"0001-01-01T00:00:00Z", "updated_at":"0001-01-01T00:00:00Z", "deleted_at":{"Time":"0001-01-01T00:00:00Z", "Valid":false}}
+				]}`,
+			},
+		},
+	}
+
+	for _, testCase := range tt {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			mocks := &mock{
+				mockDatabase: dbmocks.NewMockDatabase(ctrl),
+			}
+
+			testCase.setupMocks(t, mocks)
+
+			resources := v2.Resources{
+				DB: mocks.mockDatabase,
+			}
+
+			requestCtx := ctx.Context{RequestID: "id"}
+			request := httptest.NewRequest(http.MethodGet, testCase.path, nil)
+			request = request.WithContext(context.WithValue(context.Background(), ctx.ValueKey, requestCtx.WithRequestID("id")))
+
+			response := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.HandleFunc(fmt.Sprintf("/api/v2/file-upload/{%s}/files", v2.FileUploadJobIdPathParameterName), resources.ListIngestJobFiles).Methods(http.MethodGet)
+
+			router.ServeHTTP(response, request)
+
+			status, _, body := test.ProcessResponse(t, response)
+
+			assert.Equal(t, testCase.expected.responseCode, status)
+			assert.JSONEq(t, testCase.expected.responseBody, body)
+		})
+	}
+}
+
 func TestResources_ListAcceptedFileUploadTypes(t *testing.T) {
 	bytes, err := json.Marshal(ingest.AllowedFileUploadTypes)
 	if err != nil {
@@ -478,6 +912,55 @@ func TestResources_ListAcceptedFileUploadTypes(t *testing.T) {
 		})
 }
 
+func TestResources_ValidateCSVMapping(t *testing.T) {
+	apitest.
+		NewHarness(t, v2.Resources{}.ValidateCSVMapping).
+		Run([]apitest.Case{
+			{
+				Name: "RequestMarshalError",
+				Input: func(input *apitest.Input) {
+					apitest.SetHeader(input, headers.ContentType.String(), mediatypes.ApplicationJson.String())
+					apitest.BodyString(input, `{"node": [`)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusBadRequest)
+					apitest.BodyContains(output, api.ErrorResponsePayloadUnmarshalError)
+				},
+			},
+			{
+				Name: "ValidMapping",
+				Input: func(input *apitest.Input) {
+					apitest.SetHeader(input, headers.ContentType.String(), mediatypes.ApplicationJson.String())
+					apitest.BodyString(input, `{"source_kind": "GithubBase", "node": {"id_column": "id", "properties": [{"column": "login"}]}}`)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusOK)
+
+					var result v2.CSVMappingValidationResponse
+					apitest.UnmarshalData(output, &result)
+					apitest.Equal(output, v2.CSVMappingValidationResponse{Valid: true, Errors: []string{}, Columns: []string{"id", "login"}}, result)
+				},
+			},
+			{
+				Name: "InvalidMapping",
+				Input: func(input *apitest.Input) {
+					apitest.SetHeader(input, headers.ContentType.String(), mediatypes.ApplicationJson.String())
+					apitest.BodyString(input, `{"edge": {"start": {"column": "user"}, "end": {"column": "team", "match_by": "email"}}}`)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusOK)
+
+					var result v2.CSVMappingValidationResponse
+					apitest.UnmarshalData(output, &result)
+					apitest.Equal(output, v2.CSVMappingValidationResponse{
+						Errors:  []string{"edge must have exactly one of kind or kind_column", "edge.end.match_by must be one of [id name property]"},
+						Columns: []string{"user", "team"},
+					}, result)
+				},
+			},
+		})
+}
+
 func TestResources_ProcessIngestTask(t *testing.T) {
 	type mock struct {
 		mockDatabase *dbmocks.MockDatabase
@@ -508,7 +991,7 @@ func TestResources_ProcessIngestTask(t *testing.T) {
 			setupMocks: func(t *testing.T, mock *mock) {},
 			expected: expected{
 				responseCode:   http.StatusBadRequest,
-				responseBody:   `{"errors":[{"context":"","message":"Content type must be application/json or application/zip"}],"http_status":400,"request_id":"","timestamp":"0001-01-01T00:00:00Z"}`,
+				responseBody:   `{"errors":[{"context":"","message":"Content type must be application/json, application/x-ndjson, multipart/form-data for csv files or a supported archive type (zip, gzip, tar.gz or zstd)"}],"http_status":400,"request_id":"","timestamp":"0001-01-01T00:00:00Z"}`,
 				responseHeader: http.Header{"Content-Type": []string{"application/json"}},
 			},
 		},
@@ -684,6 +1167,35 @@ func TestResources_ProcessIngestTask(t *testing.T) {
 				responseHeader: http.Header{},
 			},
 		},
+		{
+			name: "Success: file uploaded with its name - Accepted",
+			buildRequest: func() *http.Request {
+				return &http.Request{
+					URL: &url.URL{
+						Path: "/api/v2/file-upload/1",
+					},
+					Method: http.MethodPost,
+					Body:   io.NopCloser(bytes.NewReader([]byte(`{"meta": {"type": "domains", "version": 4, "count": 1}, "data": [{"domain": "example.com"}]}`))),
+					Header: http.Header{
+						headers.ContentType.String():        []string{"application/json"},
+						headers.ContentDisposition.String(): []string{`attachment; filename="domains.json"`},
+					},
+				}
+			},
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+				mock.mockDatabase.EXPECT().GetIngestJob(gomock.Any(), int64(1)).Return(model.IngestJob{}, nil)
+				mock.mockDatabase.EXPECT().CreateIngestTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, task model.IngestTask) (model.IngestTask, error) {
+					assert.Equal(t, "domains.json", task.OriginalFileName)
+					return task, nil
+				})
+				mock.mockDatabase.EXPECT().UpdateIngestJob(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expected: expected{
+				responseCode:   http.StatusAccepted,
+				responseHeader: http.Header{},
+			},
+		},
 	}
 	for _, testCase := range tt {
 		t.Run(testCase.name, func(t *testing.T) {
@@ -730,6 +1242,334 @@ func TestResources_ProcessIngestTask(t *testing.T) {
 	}
 }
 
+func TestResources_ProcessIngestTaskChunk(t *testing.T) {
+	const payload = `{"meta": {"type": "domains", "version": 4, "count": 1}, "data": [{"domain": "example.com"}]}`
+
+	type mock struct {
+		mockDatabase *dbmocks.MockDatabase
+	}
+	type expected struct {
+		responseBody string
+		responseCode int
+	}
+	type testData struct {
+		name         string
+		contentRange string
+		chunk        string
+		received     string
+		setupMocks   func(t *testing.T, mock *mock, task model.IngestTask)
+		expected     expected
+	}
+
+	half := len(payload) / 2
+
+	tt := []testData{
+		{
+			name:         "Error: malformed content range - Bad Request",
+			contentRange: "bytes */100",
+			chunk:        payload,
+			setupMocks: func(t *testing.T, mock *mock, task model.IngestTask) {
+				t.Helper()
+				mock.mockDatabase.EXPECT().GetIngestJob(gomock.Any(), int64(1)).Return(model.IngestJob{Status: model.JobStatusRunning}, nil)
+				mock.mockDatabase.EXPECT().GetIngestTask(gomock.Any(), int64(2)).Return(task, nil)
+			},
+			expected: expected{
+				responseCode: http.StatusBadRequest,
+				responseBody: `{"errors":[{"context":"","message":"invalid content range: missing byte span"}],"http_status":400,"request_id":"","timestamp":"0001-01-01T00:00:00Z"}`,
+			},
+		},
+		{
+			name:         "Error: task belongs to another job - Not Found",
+			contentRange: fmt.Sprintf("bytes 0-%d/%d", len(payload)-1, len(payload)),
+			chunk:        payload,
+			setupMocks: func(t *testing.T, mock *mock, task model.IngestTask) {
+				t.Helper()
+				task.JobId = null.Int64From(5)
+				mock.mockDatabase.EXPECT().GetIngestJob(gomock.Any(), int64(1)).Return(model.IngestJob{Status: model.JobStatusRunning}, nil)
+				mock.mockDatabase.EXPECT().GetIngestTask(gomock.Any(), int64(2)).Return(task, nil)
+			},
+			expected: expected{
+				responseCode: http.StatusNotFound,
+				responseBody: `{"errors":[{"context":"","message":"resource not found"}],"http_status":404,"request_id":"","timestamp":"0001-01-01T00:00:00Z"}`,
+			},
+		},
+		{
+			name:         "Error: chunk offset does not match - Conflict",
+			contentRange: fmt.Sprintf("bytes %d-%d/%d", half, len(payload)-1, len(payload)),
+			chunk:        payload[half:],
+			setupMocks: func(t *testing.T, mock *mock, task model.IngestTask) {
+				t.Helper()
+				mock.mockDatabase.EXPECT().GetIngestJob(gomock.Any(), int64(1)).Return(model.IngestJob{Status: model.JobStatusRunning}, nil)
+				mock.mockDatabase.EXPECT().GetIngestTask(gomock.Any(), int64(2)).Return(task, nil)
+			},
+			expected: expected{
+				responseCode: http.StatusConflict,
+				responseBody: `{"errors":[{"context":"","message":"chunk offset does not match the number of bytes received: expected chunk starting at byte 0"}],"http_status":409,"request_id":"","timestamp":"0001-01-01T00:00:00Z"}`,
+			},
+		},
+		{
+			name:         "Success: intermediate chunk - OK",
+			contentRange: fmt.Sprintf("bytes 0-%d/%d", half-1, len(payload)),
+			chunk:        payload[:half],
+			setupMocks: func(t *testing.T, mock *mock, task model.IngestTask) {
+				t.Helper()
+				mock.mockDatabase.EXPECT().GetIngestJob(gomock.Any(), int64(1)).Return(model.IngestJob{Status: model.JobStatusRunning}, nil)
+				mock.mockDatabase.EXPECT().GetIngestTask(gomock.Any(), int64(2)).Return(task, nil)
+				mock.mockDatabase.EXPECT().UpdateIngestJob(gomock.Any(), gomock.Any()).Return(nil)
+				mock.mockDatabase.EXPECT().UpdateIngestTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, task model.IngestTask) error {
+					assert.True(t, task.Partial)
+					assert.Equal(t, int64(len(payload)), task.TotalBytes)
+					return nil
+				})
+			},
+			expected: expected{
+				responseCode: http.StatusOK,
+				responseBody: fmt.Sprintf(`{"data":{"task_id":2,"received_bytes":%d,"total_bytes":%d,"complete":false}}`, half, len(payload)),
+			},
+		},
+		{
+			name:         "Success: final chunk - Accepted",
+			contentRange: fmt.Sprintf("bytes %d-%d/%d", half, len(payload)-1, len(payload)),
+			chunk:        payload[half:],
+			received:     payload[:half],
+			setupMocks: func(t *testing.T, mock *mock, task model.IngestTask) {
+				t.Helper()
+				task.TotalBytes = int64(len(payload))
+				mock.mockDatabase.EXPECT().GetIngestJob(gomock.Any(), int64(1)).Return(model.IngestJob{Status: model.JobStatusRunning}, nil)
+				mock.mockDatabase.EXPECT().GetIngestTask(gomock.Any(), int64(2)).Return(task, nil)
+				mock.mockDatabase.EXPECT().UpdateIngestJob(gomock.Any(), gomock.Any()).Return(nil)
+				mock.mockDatabase.EXPECT().UpdateIngestTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, finalTask model.IngestTask) error {
+					assert.False(t, finalTask.Partial)
+					assert.NotEqual(t, task.FileName, finalTask.FileName)
+					assert.NoFileExists(t, task.FileName)
+
+					content, err := os.ReadFile(finalTask.FileName)
+					require.NoError(t, err)
+					assert.Equal(t, payload, string(content))
+					return nil
+				})
+			},
+			expected: expected{
+				responseCode: http.StatusAccepted,
+				responseBody: fmt.Sprintf(`{"data":{"task_id":2,"received_bytes":%d,"total_bytes":%d,"complete":true}}`, len(payload), len(payload)),
+			},
+		},
+	}
+	for _, testCase := range tt {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mocks := &mock{
+				mockDatabase: dbmocks.NewMockDatabase(ctrl),
+			}
+
+			resources := v2.Resources{
+				DB:     mocks.mockDatabase,
+				Config: config.Configuration{WorkDir: t.TempDir()},
+			}
+
+			require.NoError(t, os.Mkdir(resources.Config.TempDirectory(), 0755))
+
+			partialFile, err := os.CreateTemp(resources.Config.TempDirectory(), "bh")
+			require.NoError(t, err)
+			_, err = partialFile.WriteString(testCase.received)
+			require.NoError(t, err)
+			require.NoError(t, partialFile.Close())
+
+			task := model.IngestTask{
+				FileName:  partialFile.Name(),
+				JobId:     null.Int64From(1),
+				FileType:  model.FileTypeJson,
+				Partial:   true,
+				BigSerial: model.BigSerial{ID: 2},
+			}
+
+			testCase.setupMocks(t, mocks, task)
+
+			request := &http.Request{
+				URL:    &url.URL{Path: "/api/v2/file-upload/1/chunked/2"},
+				Method: http.MethodPut,
+				Body:   io.NopCloser(bytes.NewReader([]byte(testCase.chunk))),
+				Header: http.Header{
+					headers.ContentRange.String(): []string{testCase.contentRange},
+				},
+			}
+
+			response := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.HandleFunc(fmt.Sprintf("/api/v2/file-upload/{%s}/chunked/{%s}", v2.FileUploadJobIdPathParameterName, v2.IngestTaskIdPathParameterName), resources.ProcessIngestTaskChunk).Methods(request.Method)
+			router.ServeHTTP(response, request)
+
+			status, _, body := test.ProcessResponse(t, response)
+
+			assert.Equal(t, testCase.expected.responseCode, status)
+			assert.JSONEq(t, testCase.expected.responseBody, body)
+		})
+	}
+}
+
+func TestResources_ValidateIngestFile(t *testing.T) {
+	type mock struct {
+		mockDatabase *dbmocks.MockDatabase
+		mockGraph    *graphmocks.MockDatabase
+	}
+	type expected struct {
+		responseBody string
+		responseCode int
+	}
+	type testData struct {
+		name         string
+		buildRequest func() *http.Request
+		setupMocks   func(t *testing.T, mock *mock)
+		expected     expected
+	}
+
+	ingestSchema, err := upload.LoadIngestSchema()
+	require.NoError(t, err)
+
+	buildRequest := func(contentType, body string) func() *http.Request {
+		return func() *http.Request {
+			request := &http.Request{
+				URL: &url.URL{
+					Path: "/api/v2/file-upload/validate",
+				},
+				Method: http.MethodPost,
+				Body:   io.NopCloser(bytes.NewBufferString(body)),
+				Header: http.Header{},
+			}
+
+			if contentType != "" {
+				request.Header.Set(headers.ContentType.String(), contentType)
+			}
+
+			return request
+		}
+	}
+
+	tt := []testData{
+		{
+			name:         "Error: missing content_type request header - Bad Request",
+			buildRequest: buildRequest("", ""),
+			setupMocks:   func(t *testing.T, mock *mock) {},
+			expected: expected{
+				responseCode: http.StatusBadRequest,
+				responseBody: `{"errors":[{"context":"","message":"Content type must be application/json, application/x-ndjson, multipart/form-data for csv files or a supported archive type (zip, gzip, tar.gz or zstd)"}],"http_status":400,"request_id":"","timestamp":"0001-01-01T00:00:00Z"}`,
+			},
+		},
+		{
+			name:         "Error: GetSourceKinds database error - Internal Server Error",
+			buildRequest: buildRequest("application/json", ""),
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+				mock.mockDatabase.EXPECT().GetSourceKinds(gomock.Any()).Return(nil, errors.New("error"))
+			},
+			expected: expected{
+				responseCode: http.StatusInternalServerError,
+				responseBody: `{"errors":[{"context":"","message":"an internal error has occurred that is preventing the service from servicing this request"}],"http_status":500,"request_id":"","timestamp":"0001-01-01T00:00:00Z"}`,
+			},
+		},
+		{
+			name:         "Error: file is not valid json - Bad Request",
+			buildRequest: buildRequest("application/json", "ingest"),
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+				mock.mockDatabase.EXPECT().GetSourceKinds(gomock.Any()).Return([]database.SourceKind{}, nil)
+			},
+			expected: expected{
+				responseCode: http.StatusBadRequest,
+				responseBody: `{"errors":[{"context":"","message":"Error saving ingest file: file is not valid json"}],"http_status":400,"request_id":"","timestamp":"0001-01-01T00:00:00Z"}`,
+			},
+		},
+		{
+			name:         "Success: schema violations are reported - OK",
+			buildRequest: buildRequest("application/json", `{"graph": {"nodes": [{"id": "1", "kinds": ["Person"], "properties": {"name": {"nested": true}}}]}}`),
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+				mock.mockDatabase.EXPECT().GetSourceKinds(gomock.Any()).Return([]database.SourceKind{}, nil)
+			},
+			expected: expected{
+				responseCode: http.StatusOK,
+				responseBody: `{"data":{"files":[{"file_name":"","data_type":"","node_counts":{},"relationship_counts":{},"skipped_relationship_count":0,"invalid_relationship_count":0,"unresolved_relationships":[],"schema_violations":["nodes[0] schema validation failed with 1 error(s): [Invalid property 'name': objects are not allowed in the property bag. Use only strings, numbers, booleans, nulls, or arrays of these types.]"],"error":""}],"new_source_kinds":[]}}`,
+			},
+		},
+		{
+			name:         "Success: file is read without writing to the graph - OK",
+			buildRequest: buildRequest("application/json", `{"metadata": {"source_kind": "Base"}, "graph": {"nodes": [{"id": "1", "kinds": ["Person", "Base"], "properties": {}}, {"id": "2", "kinds": ["Person"], "properties": {}}], "edges": [{"start": {"value": "1"}, "end": {"value": "2"}, "kind": "Knows"}]}}`),
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+				mock.mockDatabase.EXPECT().GetSourceKinds(gomock.Any()).Return([]database.SourceKind{{ID: 1, Name: graph.StringKind("Existing")}}, nil)
+				mock.mockDatabase.EXPECT().GetOpenGraphKinds(gomock.Any()).Return(model.OpenGraphKinds{}, nil)
+				mock.mockGraph.EXPECT().ReadTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, delegate graph.TransactionDelegate, _ ...graph.TransactionOption) error {
+					return delegate(graphmocks.NewMockTransaction(gomock.NewController(t)))
+				})
+			},
+			expected: expected{
+				responseCode: http.StatusOK,
+				responseBody: `{"data":{"files":[{"file_name":"","data_type":"opengraph","node_counts":{"Base":2,"Person":2},"relationship_counts":{"Knows":1},"skipped_relationship_count":0,"invalid_relationship_count":0,"unresolved_relationships":[],"schema_violations":[],"error":""}],"new_source_kinds":["Base"]}}`,
+			},
+		},
+		{
+			name:         "Success: edges failing their declared properties are reported - OK",
+			buildRequest: buildRequest("application/json", `{"graph": {"nodes": [{"id": "1", "kinds": ["Person"], "properties": {}}, {"id": "2", "kinds": ["Person"], "properties": {}}], "edges": [{"start": {"value": "1"}, "end": {"value": "2"}, "kind": "Knows", "properties": {"weight": 3}}, {"start": {"value": "2"}, "end": {"value": "1"}, "kind": "Knows", "properties": {"weight": "heavy"}}]}}`),
+			setupMocks: func(t *testing.T, mock *mock) {
+				t.Helper()
+				mock.mockDatabase.EXPECT().GetSourceKinds(gomock.Any()).Return([]database.SourceKind{}, nil)
+				mock.mockDatabase.EXPECT().GetOpenGraphKinds(gomock.Any()).Return(model.OpenGraphKinds{
+					{KindName: "Knows", IsEdge: true, Properties: model.OpenGraphProperties{{Name: "weight", Type: model.OpenGraphPropertyTypeInteger, Required: true}}},
+				}, nil)
+				mock.mockGraph.EXPECT().ReadTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, delegate graph.TransactionDelegate, _ ...graph.TransactionOption) error {
+					return delegate(graphmocks.NewMockTransaction(gomock.NewController(t)))
+				})
+			},
+			expected: expected{
+				responseCode: http.StatusOK,
+				responseBody: `{"data":{"files":[{"file_name":"","data_type":"opengraph","node_counts":{"Person":2},"relationship_counts":{"Knows":1},"skipped_relationship_count":0,"invalid_relationship_count":1,"unresolved_relationships":[],"schema_violations":[],"error":"skipping invalid relationship Knows from 2 to 1: property \"weight\" must be of type integer"}],"new_source_kinds":[]}}`,
+			},
+		},
+	}
+	for _, testCase := range tt {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mocks := &mock{
+				mockDatabase: dbmocks.NewMockDatabase(ctrl),
+				mockGraph:    graphmocks.NewMockDatabase(ctrl),
+			}
+
+			request := testCase.buildRequest()
+			testCase.setupMocks(t, mocks)
+
+			resources := v2.Resources{
+				DB:           mocks.mockDatabase,
+				Graph:        mocks.mockGraph,
+				Config:       config.Configuration{WorkDir: t.TempDir()},
+				IngestSchema: ingestSchema,
+			}
+
+			require.NoError(t, os.Mkdir(resources.Config.TempDirectory(), 0755))
+
+			response := httptest.NewRecorder()
+
+			router := mux.NewRouter()
+			router.HandleFunc("/api/v2/file-upload/validate", resources.ValidateIngestFile).Methods(request.Method)
+			router.ServeHTTP(response, request)
+
+			status, _, body := test.ProcessResponse(t, response)
+
+			// Uploads are saved under a random temp file name, which is not useful to compare
+			body = regexp.MustCompile(`"file_name":"bh\d+"`).ReplaceAllString(body, `"file_name":""`)
+
+			assert.Equal(t, testCase.expected.responseCode, status)
+			assert.JSONEq(t, testCase.expected.responseBody, body)
+
+			entries, err := os.ReadDir(resources.Config.TempDirectory())
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
+
 func TestIsValidContentTypeForUpload(t *testing.T) {
 	tests := []struct {
 		name   string
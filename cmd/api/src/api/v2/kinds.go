@@ -23,6 +23,7 @@ import (
 
 	"github.com/specterops/bloodhound/cmd/api/src/api"
 	"github.com/specterops/bloodhound/cmd/api/src/database"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
 	"github.com/specterops/dawgs/graph"
 )
 
@@ -45,6 +46,10 @@ func (s Resources) ListKinds(response http.ResponseWriter, request *http.Request
 	}
 }
 
+// SourcelessSourceKindName is listed with the source kinds, with an ID of 0, so that data without a source kind can be
+// selected for deletion
+const SourcelessSourceKindName = "Sourceless"
+
 type ListSourceKindsResponse struct {
 	Kinds []database.SourceKind `json:"kinds"`
 }
@@ -59,7 +64,21 @@ func (s Resources) ListSourceKinds(response http.ResponseWriter, request *http.R
 	} else {
 		// inject 0, Sourceless into the payload. We don't track this as an official kind
 		// but it will facilitate delete requests for data that isn't associated with a kind.
-		kinds = append(kinds, database.SourceKind{ID: 0, Name: graph.StringKind("Sourceless")})
+		kinds = append(kinds, database.SourceKind{ID: 0, Name: graph.StringKind(SourcelessSourceKindName), Owners: []string{}})
 		api.WriteBasicResponse(request.Context(), ListSourceKindsResponse{Kinds: kinds}, http.StatusOK, response)
 	}
 }
+
+type ListOpenGraphKindsResponse struct {
+	Kinds model.OpenGraphKinds `json:"kinds"`
+}
+
+// ListOpenGraphKinds returns the node and edge kinds declared by the schema section of ingested OpenGraph payloads,
+// including their property types and whether pathfinding traverses edges of the kind.
+func (s Resources) ListOpenGraphKinds(response http.ResponseWriter, request *http.Request) {
+	if kinds, err := s.DB.GetOpenGraphKinds(request.Context()); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteBasicResponse(request.Context(), ListOpenGraphKindsResponse{Kinds: kinds}, http.StatusOK, response)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v2_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	v2 "github.com/specterops/bloodhound/cmd/api/src/api/v2"
+	dbmocks "github.com/specterops/bloodhound/cmd/api/src/database/mocks"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/utils/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestResources_ListOpenGraphKinds(t *testing.T) {
+	t.Parallel()
+
+	type mock struct {
+		mockDatabase *dbmocks.MockDatabase
+	}
+	type expected struct {
+		responseBody string
+		responseCode int
+	}
+	type testData struct {
+		name       string
+		setupMocks func(t *testing.T, mock *mock)
+		expected   expected
+	}
+
+	var declaredAt = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tt := []testData{
+		{
+			name: "Error: database error",
+			setupMocks: func(t *testing.T, mocks *mock) {
+				t.Helper()
+				mocks.mockDatabase.EXPECT().GetOpenGraphKinds(gomock.Any()).Return(nil, errors.New("database error"))
+			},
+			expected: expected{
+				responseCode: http.StatusInternalServerError,
+				responseBody: `{"errors":[{"context":"","message":"an internal error has occurred that is preventing the service from servicing this request"}],"http_status":500,"request_id":"","timestamp":"0001-01-01T00:00:00Z"}`,
+			},
+		},
+		{
+			name: "Success: declared kinds are listed",
+			setupMocks: func(t *testing.T, mocks *mock) {
+				t.Helper()
+				mocks.mockDatabase.EXPECT().GetOpenGraphKinds(gomock.Any()).Return(model.OpenGraphKinds{
+					{
+						ID:          1,
+						SourceKind:  "GithubBase",
+						KindName:    "GithubUser",
+						DisplayName: "GitHub User",
+						Properties:  model.OpenGraphProperties{{Name: "login", Type: model.OpenGraphPropertyTypeString, Indexed: true}},
+						Basic:       model.Basic{CreatedAt: declaredAt, UpdatedAt: declaredAt},
+					},
+					{
+						ID:          2,
+						SourceKind:  "GithubBase",
+						KindName:    "GithubMemberOf",
+						IsEdge:      true,
+						Traversable: true,
+						Properties:  model.OpenGraphProperties{},
+						Basic:       model.Basic{CreatedAt: declaredAt, UpdatedAt: declaredAt},
+					},
+				}, nil)
+			},
+			expected: expected{
+				responseCode: http.StatusOK,
+				responseBody: `{"data":{"kinds":[` +
+					`{"id":1,"source_kind":"GithubBase","kind_name":"GithubUser","is_edge":false,"display_name":"GitHub User","traversable":false,"properties":[{"name":"login","display_name":"","type":"string","indexed":true,"required":false}],"created_at":"2025-06-01T00:00:00Z","updated_at":"2025-06-01T00:00:00Z","deleted_at":{"Time":"0001-01-01T00:00:00Z","Valid":false}},` +
+					`{"id":2,"source_kind":"GithubBase","kind_name":"GithubMemberOf","is_edge":true,"display_name":"","traversable":true,"properties":[],"created_at":"2025-06-01T00:00:00Z","updated_at":"2025-06-01T00:00:00Z","deleted_at":{"Time":"0001-01-01T00:00:00Z","Valid":false}}` +
+					`]}}`,
+			},
+		},
+	}
+	for _, testCase := range tt {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+
+			mocks := &mock{
+				mockDatabase: dbmocks.NewMockDatabase(ctrl),
+			}
+
+			testCase.setupMocks(t, mocks)
+
+			resources := v2.Resources{
+				DB: mocks.mockDatabase,
+			}
+
+			request, err := http.NewRequest(http.MethodGet, "/api/v2/graphs/opengraph-kinds", nil)
+			require.NoError(t, err)
+
+			response := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc("/api/v2/graphs/opengraph-kinds", resources.ListOpenGraphKinds).Methods(request.Method)
+			router.ServeHTTP(response, request)
+
+			status, _, body := test.ProcessResponse(t, response)
+
+			require.Equal(t, testCase.expected.responseCode, status)
+			assert.JSONEq(t, testCase.expected.responseBody, body)
+		})
+	}
+}
@@ -109,8 +109,10 @@ func parseRelationshipKindsParam(validKinds graph.Kinds, relationshipKindsParam
 	return validKinds, "in", nil
 }
 
-func parseRelationshipKindsParamFilter(relationshipKindsParam string) (graph.Criteria, error) {
-	validKinds := graph.Kinds(ad.Relationships()).Concatenate(azure.Relationships())
+// parseRelationshipKindsParamFilter builds the relationship filter for pathfinding. Along with the AD and Azure
+// relationship kinds, the OpenGraph edge kinds declared as traversable may be requested and are traversed by default.
+func parseRelationshipKindsParamFilter(relationshipKindsParam string, openGraphKinds graph.Kinds) (graph.Criteria, error) {
+	validKinds := graph.Kinds(ad.Relationships()).Concatenate(azure.Relationships()).Concatenate(openGraphKinds)
 
 	if filterKinds, filterOperation, err := parseRelationshipKindsParam(validKinds, relationshipKindsParam); err != nil {
 		return nil, err
@@ -133,7 +135,9 @@ func (s Resources) GetShortestPath(response http.ResponseWriter, request *http.R
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, "Missing query parameter: start_node", request), response)
 	} else if endNode == "" {
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, "Missing query parameter: end_node", request), response)
-	} else if kindFilter, err := parseRelationshipKindsParamFilter(relationshipKindsParam); err != nil {
+	} else if openGraphKinds, err := s.GraphQuery.GetTraversableOpenGraphKinds(request.Context()); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, err.Error(), request), response)
+	} else if kindFilter, err := parseRelationshipKindsParamFilter(relationshipKindsParam, openGraphKinds); err != nil {
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
 	} else if paths, err := s.GraphQuery.GetAllShortestPaths(request.Context(), startNode, endNode, kindFilter); err != nil {
 		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, err.Error(), request), response)
@@ -127,6 +127,9 @@ func TestResources_GetShortestPath(t *testing.T) {
 					apitest.AddQueryParam(input, "end_node", "someOtherID")
 					apitest.AddQueryParam(input, "relationship_kinds", "wrx")
 				},
+				Setup: func() {
+					mockGraph.EXPECT().GetTraversableOpenGraphKinds(gomock.Any()).Return(graph.Kinds{graph.StringKind("GithubMemberOf")}, nil)
+				},
 				Test: func(output apitest.Output) {
 					apitest.StatusCode(output, http.StatusBadRequest)
 					apitest.UnmarshalBody(output, &api.ErrorWrapper{})
@@ -140,6 +143,9 @@ func TestResources_GetShortestPath(t *testing.T) {
 					apitest.AddQueryParam(input, "end_node", "someOtherID")
 					apitest.AddQueryParam(input, "relationship_kinds", "abcd:Owns,GenericAll,GenericWrite")
 				},
+				Setup: func() {
+					mockGraph.EXPECT().GetTraversableOpenGraphKinds(gomock.Any()).Return(graph.Kinds{graph.StringKind("GithubMemberOf")}, nil)
+				},
 				Test: func(output apitest.Output) {
 					apitest.StatusCode(output, http.StatusBadRequest)
 					apitest.UnmarshalBody(output, &api.ErrorWrapper{})
@@ -153,6 +159,9 @@ func TestResources_GetShortestPath(t *testing.T) {
 					apitest.AddQueryParam(input, "end_node", "someOtherID")
 					apitest.AddQueryParam(input, "relationship_kinds", "abcd:")
 				},
+				Setup: func() {
+					mockGraph.EXPECT().GetTraversableOpenGraphKinds(gomock.Any()).Return(graph.Kinds{graph.StringKind("GithubMemberOf")}, nil)
+				},
 				Test: func(output apitest.Output) {
 					apitest.StatusCode(output, http.StatusBadRequest)
 					apitest.UnmarshalBody(output, &api.ErrorWrapper{})
@@ -165,11 +174,45 @@ func TestResources_GetShortestPath(t *testing.T) {
 					apitest.AddQueryParam(input, "end_node", "someOtherID")
 					apitest.AddQueryParam(input, "relationship_kinds", "in:Owns,avbcs,GenericAll")
 				},
+				Setup: func() {
+					mockGraph.EXPECT().GetTraversableOpenGraphKinds(gomock.Any()).Return(graph.Kinds{graph.StringKind("GithubMemberOf")}, nil)
+				},
 				Test: func(output apitest.Output) {
 					apitest.StatusCode(output, http.StatusBadRequest)
 					apitest.UnmarshalBody(output, &api.ErrorWrapper{})
 				},
 			},
+			{
+				Name: "GetTraversableOpenGraphKindsError",
+				Input: func(input *apitest.Input) {
+					apitest.AddQueryParam(input, "start_node", "someID")
+					apitest.AddQueryParam(input, "end_node", "someOtherID")
+				},
+				Setup: func() {
+					mockGraph.EXPECT().GetTraversableOpenGraphKinds(gomock.Any()).Return(nil, errors.New("database error"))
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusInternalServerError)
+					apitest.BodyContains(output, "database error")
+				},
+			},
+			{
+				Name: "NotFoundTraversableOpenGraphKind",
+				Input: func(input *apitest.Input) {
+					apitest.AddQueryParam(input, "start_node", "someID")
+					apitest.AddQueryParam(input, "end_node", "someOtherID")
+					apitest.AddQueryParam(input, "relationship_kinds", "in:Owns,GithubMemberOf")
+				},
+				Setup: func() {
+					mockGraph.EXPECT().GetTraversableOpenGraphKinds(gomock.Any()).Return(graph.Kinds{graph.StringKind("GithubMemberOf")}, nil)
+					mockGraph.EXPECT().
+						GetAllShortestPaths(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+						Return(graph.NewPathSet(), nil)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusNotFound)
+				},
+			},
 			{
 				Name: "GraphDBGetShortestPathsError",
 				Input: func(input *apitest.Input) {
@@ -177,6 +220,7 @@ func TestResources_GetShortestPath(t *testing.T) {
 					apitest.AddQueryParam(input, "end_node", "someOtherID")
 				},
 				Setup: func() {
+					mockGraph.EXPECT().GetTraversableOpenGraphKinds(gomock.Any()).Return(graph.Kinds{graph.StringKind("GithubMemberOf")}, nil)
 					mockGraph.EXPECT().
 						GetAllShortestPaths(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 						Return(nil, errors.New("graph error"))
@@ -194,6 +238,7 @@ func TestResources_GetShortestPath(t *testing.T) {
 					apitest.AddQueryParam(input, "end_node", "someOtherID")
 				},
 				Setup: func() {
+					mockGraph.EXPECT().GetTraversableOpenGraphKinds(gomock.Any()).Return(graph.Kinds{graph.StringKind("GithubMemberOf")}, nil)
 					mockGraph.EXPECT().
 						GetAllShortestPaths(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 						Return(graph.NewPathSet(), nil)
@@ -212,6 +257,7 @@ func TestResources_GetShortestPath(t *testing.T) {
 					apitest.AddQueryParam(input, "relationship_kinds", "nin:Owns,GenericAll,AZMGServicePrincipalEndpoint_ReadWrite_All")
 				},
 				Setup: func() {
+					mockGraph.EXPECT().GetTraversableOpenGraphKinds(gomock.Any()).Return(graph.Kinds{graph.StringKind("GithubMemberOf")}, nil)
 					mockGraph.EXPECT().
 						GetAllShortestPaths(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 						Return(graph.NewPathSet(), nil)
@@ -228,6 +274,7 @@ func TestResources_GetShortestPath(t *testing.T) {
 					apitest.AddQueryParam(input, "relationship_kinds", "in:Owns,GenericAll,GenericWrite,AZMGServicePrincipalEndpoint_ReadWrite_All")
 				},
 				Setup: func() {
+					mockGraph.EXPECT().GetTraversableOpenGraphKinds(gomock.Any()).Return(graph.Kinds{graph.StringKind("GithubMemberOf")}, nil)
 					mockGraph.EXPECT().
 						GetAllShortestPaths(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 						Return(graph.NewPathSet(), nil)
@@ -244,6 +291,7 @@ func TestResources_GetShortestPath(t *testing.T) {
 					apitest.AddQueryParam(input, "relationship_kinds", "nin:Owns,GenericAll,AZMGServicePrincipalEndpoint_ReadWrite_All")
 				},
 				Setup: func() {
+					mockGraph.EXPECT().GetTraversableOpenGraphKinds(gomock.Any()).Return(graph.Kinds{graph.StringKind("GithubMemberOf")}, nil)
 					mockGraph.EXPECT().
 						GetAllShortestPaths(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 						Return(graph.NewPathSet(graph.Path{
@@ -282,6 +330,7 @@ func TestResources_GetShortestPath(t *testing.T) {
 					apitest.AddQueryParam(input, "relationship_kinds", "in:Owns,GenericAll,GenericWrite,AZMGServicePrincipalEndpoint_ReadWrite_All")
 				},
 				Setup: func() {
+					mockGraph.EXPECT().GetTraversableOpenGraphKinds(gomock.Any()).Return(graph.Kinds{graph.StringKind("GithubMemberOf")}, nil)
 					mockGraph.EXPECT().
 						GetAllShortestPaths(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 						Return(graph.NewPathSet(graph.Path{
@@ -320,6 +369,7 @@ func TestResources_GetShortestPath(t *testing.T) {
 					apitest.AddQueryParam(input, "relationship_kinds", "in:Owns")
 				},
 				Setup: func() {
+					mockGraph.EXPECT().GetTraversableOpenGraphKinds(gomock.Any()).Return(graph.Kinds{graph.StringKind("GithubMemberOf")}, nil)
 					mockGraph.EXPECT().
 						GetAllShortestPaths(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 						Return(graph.NewPathSet(), nil)
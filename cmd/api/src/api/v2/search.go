@@ -69,10 +69,11 @@ func setNodeProperties(nodes []*graph.Node) model.DomainSelectors {
 	domains := model.DomainSelectors{}
 	for _, node := range nodes {
 		var (
-			name, _      = node.Properties.GetOrDefault(common.Name.String(), "NO NAME").String()
-			objectID, _  = node.Properties.GetOrDefault(common.ObjectID.String(), "NO OBJECT ID").String()
-			collected, _ = node.Properties.GetOrDefault(common.Collected.String(), false).Bool()
-			domainType   = "active-directory"
+			name, _        = node.Properties.GetOrDefault(common.Name.String(), "NO NAME").String()
+			objectID, _    = node.Properties.GetOrDefault(common.ObjectID.String(), "NO OBJECT ID").String()
+			collected, _   = node.Properties.GetOrDefault(common.Collected.String(), false).Bool()
+			environment, _ = node.Properties.GetOrDefault(common.Environment.String(), "").String()
+			domainType     = "active-directory"
 		)
 
 		if node.Kinds.ContainsOneOf(azure.Tenant) {
@@ -80,10 +81,11 @@ func setNodeProperties(nodes []*graph.Node) model.DomainSelectors {
 		}
 
 		domains = append(domains, model.DomainSelector{
-			Type:      domainType,
-			Name:      name,
-			ObjectID:  objectID,
-			Collected: collected,
+			Type:        domainType,
+			Name:        name,
+			ObjectID:    objectID,
+			Collected:   collected,
+			Environment: environment,
 		})
 	}
 
@@ -25,6 +25,8 @@ import (
 	v2 "github.com/specterops/bloodhound/cmd/api/src/api/v2"
 	"github.com/specterops/bloodhound/cmd/api/src/api/v2/apitest"
 	graphMocks "github.com/specterops/bloodhound/cmd/api/src/queries/mocks"
+	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
+	"github.com/specterops/bloodhound/packages/go/graphschema/common"
 	"github.com/specterops/dawgs/graph"
 	"go.uber.org/mock/gomock"
 )
@@ -129,5 +131,24 @@ func TestResources_GetAvailableDomains(t *testing.T) {
 					apitest.BodyContains(output, "[]")
 				},
 			},
+			{
+				Name: "SuccessFilteredByEnvironment",
+				Input: func(input *apitest.Input) {
+					apitest.AddQueryParam(input, "environment", "eq:subsidiary-a")
+				},
+				Setup: func() {
+					mockGraphQueries.EXPECT().GetFilteredAndSortedNodes(gomock.Any(), gomock.Any()).Return([]*graph.Node{
+						graph.NewNode(1, graph.AsProperties(map[string]any{
+							common.Name.String():        "EXAMPLE.LOCAL",
+							common.ObjectID.String():    "S-1-5-21-1",
+							common.Environment.String(): "subsidiary-a",
+						}), ad.Domain),
+					}, nil)
+				},
+				Test: func(output apitest.Output) {
+					apitest.StatusCode(output, http.StatusOK)
+					apitest.BodyContains(output, `"environment":"subsidiary-a"`)
+				},
+			},
 		})
 }
@@ -0,0 +1,187 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v2
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/specterops/bloodhound/cmd/api/src/api"
+	"github.com/specterops/bloodhound/cmd/api/src/auth"
+	"github.com/specterops/bloodhound/cmd/api/src/ctx"
+	"github.com/specterops/bloodhound/cmd/api/src/database"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/model/appcfg"
+	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
+	"github.com/specterops/bloodhound/packages/go/graphschema/azure"
+	"github.com/specterops/dawgs/graph"
+)
+
+const maxSourceKindNameLength = 256
+
+type UpdateSourceKindRequest struct {
+	Description   string   `json:"description"`
+	Owners        []string `json:"owners"`
+	RetentionDays int      `json:"retention_days"`
+}
+
+type CreateSourceKindRequest struct {
+	Name string `json:"name"`
+	UpdateSourceKindRequest
+}
+
+func (s UpdateSourceKindRequest) validate() error {
+	if s.RetentionDays < 0 {
+		return fmt.Errorf("retention_days must not be negative")
+	}
+
+	for _, owner := range s.Owners {
+		if strings.TrimSpace(owner) == "" {
+			return fmt.Errorf("owners must not contain an empty value")
+		}
+	}
+
+	return nil
+}
+
+func (s CreateSourceKindRequest) validate() error {
+	if name := strings.TrimSpace(s.Name); name == "" {
+		return fmt.Errorf("name is required")
+	} else if len(name) > maxSourceKindNameLength {
+		return fmt.Errorf("name must be at most %d characters", maxSourceKindNameLength)
+	} else if name == SourcelessSourceKindName {
+		return fmt.Errorf("%s is reserved for data without a source kind", SourcelessSourceKindName)
+	}
+
+	return s.UpdateSourceKindRequest.validate()
+}
+
+func (s UpdateSourceKindRequest) sourceKind(id int) database.SourceKind {
+	owners := make([]string, 0, len(s.Owners))
+	for _, owner := range s.Owners {
+		owners = append(owners, strings.TrimSpace(owner))
+	}
+
+	return database.SourceKind{
+		ID:            id,
+		Description:   s.Description,
+		Owners:        owners,
+		RetentionDays: s.RetentionDays,
+	}
+}
+
+// isReservedSourceKind returns true for the source kinds of AD and Azure data, which are always registered
+func isReservedSourceKind(kind graph.Kind) bool {
+	return kind.Is(ad.Entity) || kind.Is(azure.Entity)
+}
+
+func (s Resources) GetSourceKind(response http.ResponseWriter, request *http.Request) {
+	if id, err := strconv.Atoi(mux.Vars(request)[api.URIPathVariableSourceKindID]); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if sourceKind, err := s.DB.GetSourceKindByID(request.Context(), id); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteBasicResponse(request.Context(), sourceKind, http.StatusOK, response)
+	}
+}
+
+// CreateSourceKind registers a source kind ahead of its first ingest. Source kinds are otherwise registered when data
+// is first ingested for them.
+func (s Resources) CreateSourceKind(response http.ResponseWriter, request *http.Request) {
+	var createRequest CreateSourceKindRequest
+
+	if err := api.ReadJSONRequestPayloadLimited(&createRequest, request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponsePayloadUnmarshalError, request), response)
+	} else if err := createRequest.validate(); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else {
+		sourceKind := createRequest.sourceKind(0)
+		sourceKind.Name = graph.StringKind(strings.TrimSpace(createRequest.Name))
+
+		if created, err := s.DB.CreateSourceKind(request.Context(), sourceKind); errors.Is(err, database.ErrDuplicateSourceKindName) {
+			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusConflict, fmt.Sprintf("%s: source kind %s already exists", api.ErrorResponseConflict, sourceKind.Name), request), response)
+		} else if err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else {
+			api.WriteBasicResponse(request.Context(), created, http.StatusCreated, response)
+		}
+	}
+}
+
+// UpdateSourceKind replaces the description, owners and retention period of a source kind
+func (s Resources) UpdateSourceKind(response http.ResponseWriter, request *http.Request) {
+	var updateRequest UpdateSourceKindRequest
+
+	if id, err := strconv.Atoi(mux.Vars(request)[api.URIPathVariableSourceKindID]); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if err := api.ReadJSONRequestPayloadLimited(&updateRequest, request); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponsePayloadUnmarshalError, request), response)
+	} else if err := updateRequest.validate(); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, err.Error(), request), response)
+	} else if updated, err := s.DB.UpdateSourceKind(request.Context(), updateRequest.sourceKind(id)); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else {
+		api.WriteBasicResponse(request.Context(), updated, http.StatusOK, response)
+	}
+}
+
+// DeleteSourceKind purges the graph data of a source kind and removes it. The purge is carried out by the datapipe
+// with the same machinery as the deletion requests of HandleDatabaseWipe, so the source kind is only removed once its
+// data has been deleted. The source kinds of AD and Azure data can not be removed.
+func (s Resources) DeleteSourceKind(response http.ResponseWriter, request *http.Request) {
+	if id, err := strconv.Atoi(mux.Vars(request)[api.URIPathVariableSourceKindID]); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, api.ErrorResponseDetailsIDMalformed, request), response)
+	} else if sourceKind, err := s.DB.GetSourceKindByID(request.Context(), id); err != nil {
+		api.HandleDatabaseError(request, response, err)
+	} else if isReservedSourceKind(sourceKind.Name) {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, fmt.Sprintf("source kind %s can not be deleted", sourceKind.Name), request), response)
+	} else if clearGraphDataFlag, err := s.DB.GetFlagByKey(request.Context(), appcfg.FeatureClearGraphData); err != nil {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "unable to inspect the feature flag for clearing graph data", request), response)
+	} else if !clearGraphDataFlag.Enabled {
+		api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusBadRequest, "deleting graph data is currently disabled", request), response)
+	} else {
+		var userID string
+		if user, isUser := auth.GetUserFromAuthCtx(ctx.FromRequest(request).AuthCtx); !isUser {
+			slog.WarnContext(request.Context(), "encountered source kind deletion for unknown user, this shouldn't happen")
+			userID = "unknown-user-source-kind-deletion"
+		} else {
+			userID = user.ID.String()
+		}
+
+		if auditEntry, err := model.NewAuditEntry(model.AuditLogActionDeleteBloodhoundData, model.AuditLogStatusIntent, model.AuditData{
+			"delete_source_kind": sourceKind.Name.String(),
+		}); err != nil {
+			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, api.ErrorResponseDetailsInternalServerError, request), response)
+		} else if err := s.DB.AppendAuditLog(request.Context(), auditEntry); err != nil {
+			api.WriteErrorResponse(request.Context(), api.BuildErrorResponse(http.StatusInternalServerError, "failure creating an intent audit log", request), response)
+		} else if err := s.DB.RequestCollectedGraphDataDeletion(request.Context(), model.AnalysisRequest{
+			RequestedBy:       userID,
+			RequestType:       model.AnalysisRequestDeletion,
+			DeleteSourceKinds: []string{sourceKind.Name.String()},
+		}); err != nil {
+			api.HandleDatabaseError(request, response, err)
+		} else {
+			s.handleAuditLogForDatabaseWipe(request.Context(), &auditEntry, true, "source kind "+sourceKind.Name.String())
+			response.WriteHeader(http.StatusAccepted)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package v2_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/specterops/bloodhound/cmd/api/src/api"
+	v2 "github.com/specterops/bloodhound/cmd/api/src/api/v2"
+	"github.com/specterops/bloodhound/cmd/api/src/database"
+	dbmocks "github.com/specterops/bloodhound/cmd/api/src/database/mocks"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/model/appcfg"
+	"github.com/specterops/bloodhound/cmd/api/src/utils/test"
+	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
+	"github.com/specterops/bloodhound/packages/go/headers"
+	"github.com/specterops/bloodhound/packages/go/mediatypes"
+	"github.com/specterops/dawgs/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type sourceKindTestCase struct {
+	name         string
+	id           string
+	body         string
+	setupMocks   func(t *testing.T, mockDB *dbmocks.MockDatabase)
+	responseCode int
+	responseBody string
+}
+
+func runSourceKindTestCases(t *testing.T, method, path string, handler func(resources v2.Resources) http.HandlerFunc, testCases []sourceKindTestCase) {
+	t.Helper()
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				ctrl      = gomock.NewController(t)
+				mockDB    = dbmocks.NewMockDatabase(ctrl)
+				resources = v2.Resources{DB: mockDB}
+				url       = "/api/v2/graphs/source-kinds"
+			)
+
+			if testCase.setupMocks != nil {
+				testCase.setupMocks(t, mockDB)
+			}
+
+			if testCase.id != "" {
+				url = fmt.Sprintf("%s/%s", url, testCase.id)
+			}
+
+			request, err := http.NewRequest(method, url, bytes.NewBufferString(testCase.body))
+			require.NoError(t, err)
+			request.Header.Set(headers.ContentType.String(), mediatypes.ApplicationJson.String())
+
+			response := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc(path, handler(resources)).Methods(method)
+			router.ServeHTTP(response, request)
+
+			status, _, body := test.ProcessResponse(t, response)
+
+			require.Equal(t, testCase.responseCode, status)
+			if testCase.responseBody != "" {
+				assert.JSONEq(t, testCase.responseBody, body)
+			}
+		})
+	}
+}
+
+var sourceKindTestTime = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func githubSourceKind() database.SourceKind {
+	return database.SourceKind{
+		ID:            3,
+		Name:          graph.StringKind("GithubBase"),
+		Description:   "GitHub organizations",
+		Owners:        []string{"platform"},
+		RetentionDays: 30,
+		Version:       2,
+		CreatedAt:     sourceKindTestTime,
+		UpdatedAt:     sourceKindTestTime,
+	}
+}
+
+const githubSourceKindJSON = `{"data":{"id":3,"name":"GithubBase","description":"GitHub organizations","owners":["platform"],"retention_days":30,"version":2,"last_ingested_at":null,"created_at":"2025-06-01T00:00:00Z","updated_at":"2025-06-01T00:00:00Z"}}`
+
+func TestResources_GetSourceKind(t *testing.T) {
+	t.Parallel()
+
+	runSourceKindTestCases(t, http.MethodGet, fmt.Sprintf("/api/v2/graphs/source-kinds/{%s}", api.URIPathVariableSourceKindID), func(resources v2.Resources) http.HandlerFunc {
+		return resources.GetSourceKind
+	}, []sourceKindTestCase{
+		{
+			name:         "Error: malformed id",
+			id:           "github",
+			responseCode: http.StatusBadRequest,
+		},
+		{
+			name: "Error: source kind not found",
+			id:   "3",
+			setupMocks: func(t *testing.T, mockDB *dbmocks.MockDatabase) {
+				mockDB.EXPECT().GetSourceKindByID(gomock.Any(), 3).Return(database.SourceKind{}, database.ErrNotFound)
+			},
+			responseCode: http.StatusNotFound,
+		},
+		{
+			name: "Success: source kind returned",
+			id:   "3",
+			setupMocks: func(t *testing.T, mockDB *dbmocks.MockDatabase) {
+				mockDB.EXPECT().GetSourceKindByID(gomock.Any(), 3).Return(githubSourceKind(), nil)
+			},
+			responseCode: http.StatusOK,
+			responseBody: githubSourceKindJSON,
+		},
+	})
+}
+
+func TestResources_CreateSourceKind(t *testing.T) {
+	t.Parallel()
+
+	runSourceKindTestCases(t, http.MethodPost, "/api/v2/graphs/source-kinds", func(resources v2.Resources) http.HandlerFunc {
+		return resources.CreateSourceKind
+	}, []sourceKindTestCase{
+		{
+			name:         "Error: malformed body",
+			body:         `{"name":`,
+			responseCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Error: name is required",
+			body:         `{"name":" "}`,
+			responseCode: http.StatusBadRequest,
+			responseBody: `{"errors":[{"context":"","message":"name is required"}],"http_status":400,"request_id":"","timestamp":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:         "Error: Sourceless is reserved",
+			body:         `{"name":"Sourceless"}`,
+			responseCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Error: negative retention period",
+			body:         `{"name":"GithubBase","retention_days":-1}`,
+			responseCode: http.StatusBadRequest,
+			responseBody: `{"errors":[{"context":"","message":"retention_days must not be negative"}],"http_status":400,"request_id":"","timestamp":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name:         "Error: empty owner",
+			body:         `{"name":"GithubBase","owners":["platform",""]}`,
+			responseCode: http.StatusBadRequest,
+		},
+		{
+			name: "Error: duplicate name",
+			body: `{"name":"GithubBase"}`,
+			setupMocks: func(t *testing.T, mockDB *dbmocks.MockDatabase) {
+				mockDB.EXPECT().CreateSourceKind(gomock.Any(), gomock.Any()).Return(database.SourceKind{}, database.ErrDuplicateSourceKindName)
+			},
+			responseCode: http.StatusConflict,
+			responseBody: `{"errors":[{"context":"","message":"Conflict: source kind GithubBase already exists"}],"http_status":409,"request_id":"","timestamp":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name: "Success: source kind created",
+			body: `{"name":" GithubBase ","description":"GitHub organizations","owners":[" platform "],"retention_days":30}`,
+			setupMocks: func(t *testing.T, mockDB *dbmocks.MockDatabase) {
+				mockDB.EXPECT().CreateSourceKind(gomock.Any(), database.SourceKind{
+					Name:          graph.StringKind("GithubBase"),
+					Description:   "GitHub organizations",
+					Owners:        []string{"platform"},
+					RetentionDays: 30,
+				}).Return(githubSourceKind(), nil)
+			},
+			responseCode: http.StatusCreated,
+			responseBody: githubSourceKindJSON,
+		},
+	})
+}
+
+func TestResources_UpdateSourceKind(t *testing.T) {
+	t.Parallel()
+
+	runSourceKindTestCases(t, http.MethodPut, fmt.Sprintf("/api/v2/graphs/source-kinds/{%s}", api.URIPathVariableSourceKindID), func(resources v2.Resources) http.HandlerFunc {
+		return resources.UpdateSourceKind
+	}, []sourceKindTestCase{
+		{
+			name:         "Error: malformed id",
+			id:           "github",
+			body:         `{}`,
+			responseCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Error: negative retention period",
+			id:           "3",
+			body:         `{"retention_days":-7}`,
+			responseCode: http.StatusBadRequest,
+		},
+		{
+			name: "Error: source kind not found",
+			id:   "3",
+			body: `{"retention_days":30}`,
+			setupMocks: func(t *testing.T, mockDB *dbmocks.MockDatabase) {
+				mockDB.EXPECT().UpdateSourceKind(gomock.Any(), gomock.Any()).Return(database.SourceKind{}, database.ErrNotFound)
+			},
+			responseCode: http.StatusNotFound,
+		},
+		{
+			name: "Success: source kind updated",
+			id:   "3",
+			body: `{"description":"GitHub organizations","owners":["platform"],"retention_days":30}`,
+			setupMocks: func(t *testing.T, mockDB *dbmocks.MockDatabase) {
+				mockDB.EXPECT().UpdateSourceKind(gomock.Any(), database.SourceKind{
+					ID:            3,
+					Description:   "GitHub organizations",
+					Owners:        []string{"platform"},
+					RetentionDays: 30,
+				}).Return(githubSourceKind(), nil)
+			},
+			responseCode: http.StatusOK,
+			responseBody: githubSourceKindJSON,
+		},
+	})
+}
+
+func TestResources_DeleteSourceKind(t *testing.T) {
+	t.Parallel()
+
+	runSourceKindTestCases(t, http.MethodDelete, fmt.Sprintf("/api/v2/graphs/source-kinds/{%s}", api.URIPathVariableSourceKindID), func(resources v2.Resources) http.HandlerFunc {
+		return resources.DeleteSourceKind
+	}, []sourceKindTestCase{
+		{
+			name:         "Error: malformed id",
+			id:           "github",
+			responseCode: http.StatusBadRequest,
+		},
+		{
+			name: "Error: source kind not found",
+			id:   "3",
+			setupMocks: func(t *testing.T, mockDB *dbmocks.MockDatabase) {
+				mockDB.EXPECT().GetSourceKindByID(gomock.Any(), 3).Return(database.SourceKind{}, database.ErrNotFound)
+			},
+			responseCode: http.StatusNotFound,
+		},
+		{
+			name: "Error: AD source kind can not be deleted",
+			id:   "1",
+			setupMocks: func(t *testing.T, mockDB *dbmocks.MockDatabase) {
+				mockDB.EXPECT().GetSourceKindByID(gomock.Any(), 1).Return(database.SourceKind{ID: 1, Name: ad.Entity}, nil)
+			},
+			responseCode: http.StatusBadRequest,
+			responseBody: `{"errors":[{"context":"","message":"source kind Base can not be deleted"}],"http_status":400,"request_id":"","timestamp":"0001-01-01T00:00:00Z"}`,
+		},
+		{
+			name: "Error: clearing graph data is disabled",
+			id:   "3",
+			setupMocks: func(t *testing.T, mockDB *dbmocks.MockDatabase) {
+				mockDB.EXPECT().GetSourceKindByID(gomock.Any(), 3).Return(githubSourceKind(), nil)
+				mockDB.EXPECT().GetFlagByKey(gomock.Any(), appcfg.FeatureClearGraphData).Return(appcfg.FeatureFlag{Enabled: false}, nil)
+			},
+			responseCode: http.StatusBadRequest,
+		},
+		{
+			name: "Error: deletion request failed",
+			id:   "3",
+			setupMocks: func(t *testing.T, mockDB *dbmocks.MockDatabase) {
+				mockDB.EXPECT().GetSourceKindByID(gomock.Any(), 3).Return(githubSourceKind(), nil)
+				mockDB.EXPECT().GetFlagByKey(gomock.Any(), appcfg.FeatureClearGraphData).Return(appcfg.FeatureFlag{Enabled: true}, nil)
+				mockDB.EXPECT().AppendAuditLog(gomock.Any(), gomock.Any()).Return(nil)
+				mockDB.EXPECT().RequestCollectedGraphDataDeletion(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+			},
+			responseCode: http.StatusInternalServerError,
+		},
+		{
+			name: "Success: purge of the source kind requested",
+			id:   "3",
+			setupMocks: func(t *testing.T, mockDB *dbmocks.MockDatabase) {
+				mockDB.EXPECT().GetSourceKindByID(gomock.Any(), 3).Return(githubSourceKind(), nil)
+				mockDB.EXPECT().GetFlagByKey(gomock.Any(), appcfg.FeatureClearGraphData).Return(appcfg.FeatureFlag{Enabled: true}, nil)
+				mockDB.EXPECT().AppendAuditLog(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+				mockDB.EXPECT().RequestCollectedGraphDataDeletion(gomock.Any(), model.AnalysisRequest{
+					RequestedBy:       "unknown-user-source-kind-deletion",
+					RequestType:       model.AnalysisRequestDeletion,
+					DeleteSourceKinds: []string{"GithubBase"},
+				}).Return(nil)
+			},
+			responseCode: http.StatusAccepted,
+		},
+	})
+}
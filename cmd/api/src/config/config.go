@@ -133,6 +133,46 @@ type DefaultAdminConfiguration struct {
 	ExpireNow     bool   `json:"expire_now"`
 }
 
+// DropFolderConfiguration configures ingest of collection files written to a watched directory. The drop folder is
+// disabled unless a directory is set.
+type DropFolderConfiguration struct {
+	Directory    string `json:"directory"`
+	ServiceUser  string `json:"service_user"`
+	PollInterval int    `json:"poll_interval"`
+	StableAfter  int    `json:"stable_after"`
+}
+
+func (s DropFolderConfiguration) Enabled() bool {
+	return s.Directory != ""
+}
+
+const (
+	S3AfterIngestDelete = "delete"
+	S3AfterIngestTag    = "tag"
+)
+
+// S3IngestConfiguration configures ingest of collection files pulled from an S3-compatible bucket. Endpoint may be
+// left empty for AWS S3; S3-compatible servers such as MinIO usually also need UsePathStyle. AfterIngest may be set
+// to delete or tag objects once they have been submitted for ingest. RequestTimeout bounds each request to the bucket,
+// including the download of an object. S3 ingest is disabled unless a bucket is set.
+type S3IngestConfiguration struct {
+	Endpoint        string `json:"endpoint"`
+	Region          string `json:"region"`
+	Bucket          string `json:"bucket"`
+	Prefix          string `json:"prefix"`
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	UsePathStyle    bool   `json:"use_path_style"`
+	ServiceUser     string `json:"service_user"`
+	PollInterval    int    `json:"poll_interval"`
+	RequestTimeout  int    `json:"request_timeout"`
+	AfterIngest     string `json:"after_ingest"`
+}
+
+func (s S3IngestConfiguration) Enabled() bool {
+	return s.Bucket != ""
+}
+
 type Configuration struct {
 	Version                      int                       `json:"version"`
 	BindAddress                  string                    `json:"bind_addr"`
@@ -158,8 +198,12 @@ type Configuration struct {
 	EnableAPILogging             bool                      `json:"enable_api_logging"`
 	EnableCypherMutations        bool                      `json:"enable_cypher_mutations"`
 	DisableAnalysis              bool                      `json:"disable_analysis"`
+	DisableIncrementalAnalysis   bool                      `json:"disable_incremental_analysis"`
 	DisableCypherComplexityLimit bool                      `json:"disable_cypher_complexity_limit"`
 	DisableIngest                bool                      `json:"disable_ingest"`
+	IngestConcurrency            int                       `json:"ingest_concurrency"`
+	DropFolder                   DropFolderConfiguration   `json:"drop_folder"`
+	S3Ingest                     S3IngestConfiguration     `json:"s3_ingest"`
 	DisableMigrations            bool                      `json:"disable_migrations"`
 	GraphQueryMemoryLimit        uint16                    `json:"graph_query_memory_limit"`
 	EnableTextLogger             bool                      `json:"enable_text_logger"`
@@ -48,8 +48,10 @@ func NewDefaultConfiguration() (Configuration, error) {
 			EnableStartupWaitPeriod:      true,
 			EnableAPILogging:             true,
 			DisableAnalysis:              false,
+			DisableIncrementalAnalysis:   false, // Analysis only post-processes the domains and tenants touched by ingest
 			DisableCypherComplexityLimit: false,
 			DisableIngest:                false,
+			IngestConcurrency:            1, // Files within an upload are ingested one at a time by default
 			DisableMigrations:            false,
 			EnableCypherMutations:        false,
 			RecreateDefaultAdmin:         false,
@@ -58,6 +60,15 @@ func NewDefaultConfiguration() (Configuration, error) {
 			TLS:                          TLSConfiguration{},
 			SAML:                         SAMLConfiguration{},
 			GraphDriver:                  neo4j.DriverName, // Default to PG as the graph driver
+			DropFolder: DropFolderConfiguration{
+				PollInterval: 10, // Seconds between scans of the drop folder
+				StableAfter:  30, // Seconds a file must go unmodified before it is picked up
+			},
+			S3Ingest: S3IngestConfiguration{
+				Region:         "us-east-1",
+				PollInterval:   300, // Seconds between listings of the bucket
+				RequestTimeout: 600, // Seconds a single request to the bucket, including an object download, may take
+			},
 			Database: DatabaseConfiguration{
 				MaxConcurrentSessions: 10,
 			},
@@ -22,10 +22,11 @@ import (
 	"fmt"
 	"log/slog"
 
-	"github.com/specterops/bloodhound/cmd/api/src/analysis/ad"
-	"github.com/specterops/bloodhound/cmd/api/src/analysis/azure"
+	"github.com/specterops/bloodhound/cmd/api/src/analysis/postprocessing"
 	"github.com/specterops/bloodhound/cmd/api/src/config"
 	"github.com/specterops/bloodhound/cmd/api/src/database"
+	"github.com/specterops/bloodhound/cmd/api/src/metrics"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
 	"github.com/specterops/bloodhound/cmd/api/src/model/appcfg"
 	"github.com/specterops/bloodhound/cmd/api/src/services/agi"
 	"github.com/specterops/bloodhound/cmd/api/src/services/dataquality"
@@ -37,10 +38,60 @@ import (
 var (
 	ErrAnalysisFailed             = errors.New("analysis failed")
 	ErrAnalysisPartiallyCompleted = errors.New("analysis partially completed")
+	ErrAnalysisCanceled           = errors.New("analysis canceled")
 )
 
+// AnalysisScope limits post-processing to the domains and tenants touched by ingest since the last successful analysis.
+// The zero value covers the whole graph.
+type AnalysisScope struct {
+	Domains analysis.Scope
+	Tenants analysis.Scope
+}
+
+// IsFull returns true if the scope covers the whole graph
+func (s AnalysisScope) IsFull() bool {
+	return s.Domains.IsFull() && s.Tenants.IsFull()
+}
+
+// NewAnalysisScope returns the scope covering the domains and tenants touched by the ingest jobs given. The scope covers
+// the whole graph if any of the jobs wrote data that could not be attributed to a domain or tenant, or deleted nodes
+// or relationships, as the domains and tenants of deleted data are not recorded.
+func NewAnalysisScope(ingestJobs []model.IngestJob) AnalysisScope {
+	var (
+		domains = analysis.NewScope()
+		tenants = analysis.NewScope()
+	)
+
+	for _, ingestJob := range ingestJobs {
+		if ingestJob.UnscopedObjects > 0 || ingestJob.DeletedNodes > 0 || ingestJob.DeletedRelationships > 0 {
+			return AnalysisScope{}
+		}
+
+		domains = domains.With(ingestJob.TouchedDomains...)
+		tenants = tenants.With(ingestJob.TouchedTenants...)
+	}
+
+	return AnalysisScope{
+		Domains: domains,
+		Tenants: tenants,
+	}
+}
+
+// RunAnalysisOperations runs analysis for the domains and tenants covered by the scope, recording the run and the
+// outcome of its steps in the analysis run history
+func RunAnalysisOperations(ctx context.Context, db database.Database, graphDB graph.Database, _ config.Configuration, scope AnalysisScope) error {
+	recorder := startAnalysisRun(ctx, db, scope)
+
+	err := runAnalysisOperations(ctx, db, graphDB, scope, recorder)
+
+	// The outcome of a canceled run is still recorded
+	recorder.finish(context.WithoutCancel(ctx), err)
+
+	return err
+}
+
 // TODO Cleanup tieringEnabled after Tiering GA
-func RunAnalysisOperations(ctx context.Context, db database.Database, graphDB graph.Database, _ config.Configuration) error {
+func runAnalysisOperations(ctx context.Context, db database.Database, graphDB graph.Database, scope AnalysisScope, recorder *analysisRunRecorder) error {
 	var (
 		collectedErrors      []error
 		compositionIdCounter = analysis.NewCompositionCounter()
@@ -66,54 +117,113 @@ func RunAnalysisOperations(ctx context.Context, db database.Database, graphDB gr
 	}
 
 	var (
-		adFailed          = false
-		azureFailed       = false
-		agiFailed         = false
-		dataQualityFailed = false
+		postProcessingFailed    = false
+		postProcessingSucceeded = false
+		agiFailed               = false
+		dataQualityFailed       = false
 	)
 
-	// TODO: Cleanup #ADCSFeatureFlag after full launch.
-	if adcsFlag, err := db.GetFlagByKey(ctx, appcfg.FeatureAdcs); err != nil {
-		collectedErrors = append(collectedErrors, fmt.Errorf("error retrieving ADCS feature flag: %w", err))
-	} else if ntlmFlag, err := db.GetFlagByKey(ctx, appcfg.FeatureNTLMPostProcessing); err != nil {
-		collectedErrors = append(collectedErrors, fmt.Errorf("error retrieving NTLM Post Processing feature flag: %w", err))
-	} else if stats, err := ad.Post(ctx, graphDB, adcsFlag.Enabled, appcfg.GetCitrixRDPSupport(ctx, db), ntlmFlag.Enabled, &compositionIdCounter); err != nil {
-		collectedErrors = append(collectedErrors, fmt.Errorf("error during ad post: %w", err))
-		adFailed = true
-	} else {
-		stats.LogStats()
+	results, err := runPostProcessing(ctx, db, graphDB, scope, &compositionIdCounter, recorder.recordPostProcessingStep)
+	if err != nil {
+		collectedErrors = append(collectedErrors, fmt.Errorf("post-processing failed: %w", err))
+		postProcessingFailed = true
 	}
 
-	if stats, err := azure.Post(ctx, graphDB); err != nil {
-		collectedErrors = append(collectedErrors, fmt.Errorf("error during azure post: %w", err))
-		azureFailed = true
-	} else {
-		stats.LogStats()
+	for _, result := range results {
+		if result.Err != nil {
+			collectedErrors = append(collectedErrors, fmt.Errorf("post-processing step %s failed: %w", result.Name, result.Err))
+			postProcessingFailed = true
+		} else if !result.Skipped {
+			postProcessingSucceeded = true
+		}
 	}
 
 	if !tieringEnabled {
-		if err := agi.RunAssetGroupIsolationCollections(ctx, db, graphDB); err != nil {
+		agiDone := recorder.measureStep(metrics.AnalysisStepAGI)
+
+		err := agi.RunAssetGroupIsolationCollections(ctx, db, graphDB)
+		if err != nil {
 			collectedErrors = append(collectedErrors, fmt.Errorf("asset group isolation collection failed: %w", err))
 			agiFailed = true
 		}
+
+		agiDone(err)
 	}
 
-	if err := dataquality.SaveDataQuality(ctx, db, graphDB); err != nil {
+	dataQualityDone := recorder.measureStep(metrics.AnalysisStepDataQuality)
+
+	err = dataquality.SaveDataQuality(ctx, db, graphDB)
+	if err != nil {
 		collectedErrors = append(collectedErrors, fmt.Errorf("error saving data quality stat: %v", err))
 		dataQualityFailed = true
 	}
 
+	dataQualityDone(err)
+
 	if len(collectedErrors) > 0 {
 		for _, err := range collectedErrors {
 			slog.ErrorContext(ctx, fmt.Sprintf("Analysis error encountered: %v", err))
 		}
 	}
 
-	if adFailed && azureFailed && agiFailed && dataQualityFailed {
+	if errors.Is(context.Cause(ctx), ErrDatapipeCanceled) {
+		return ErrAnalysisCanceled
+	} else if postProcessingFailed && !postProcessingSucceeded && agiFailed && dataQualityFailed {
 		return ErrAnalysisFailed
-	} else if adFailed || azureFailed || agiFailed || dataQualityFailed {
+	} else if postProcessingFailed || agiFailed || dataQualityFailed {
 		return ErrAnalysisPartiallyCompleted
 	}
 
 	return nil
 }
+
+// runPostProcessing runs the post-processing steps enabled for the domains and tenants covered by the scope, passing
+// the result of each step to onStepDone. Steps fail independently of each other and their failures are reported by
+// their results.
+func runPostProcessing(ctx context.Context, db database.Database, graphDB graph.Database, scope AnalysisScope, compositionCounter *analysis.CompositionCounter, onStepDone func(result analysis.PostProcessorResult)) ([]analysis.PostProcessorResult, error) {
+	registry := postprocessing.DefaultRegistry()
+	enabled, flagErr := enabledPostProcessingSteps(ctx, db, registry)
+
+	results, err := postprocessing.Run(ctx, registry, &postprocessing.State{
+		Graph:              graphDB,
+		Domains:            scope.Domains,
+		Tenants:            scope.Tenants,
+		CitrixEnabled:      appcfg.GetCitrixRDPSupport(ctx, db),
+		CompositionCounter: compositionCounter,
+	}, enabled, onStepDone)
+
+	return results, errors.Join(flagErr, err)
+}
+
+// enabledPostProcessingSteps returns the predicate selecting the post-processing steps to run. Steps disabled through
+// app config are left out, as are steps gated by a feature flag that is disabled or could not be fetched.
+func enabledPostProcessingSteps(ctx context.Context, db database.Database, registry *postprocessing.Registry) (func(step postprocessing.Step) bool, error) {
+	var (
+		disabledSteps = map[string]bool{}
+		enabledFlags  = map[string]bool{}
+		errs          []error
+	)
+
+	for _, name := range appcfg.GetPostProcessingParameter(ctx, db).DisabledSteps {
+		if _, found := registry.Get(name); !found {
+			slog.WarnContext(ctx, fmt.Sprintf("Unknown post-processing step %s disabled through app config", name))
+		}
+
+		disabledSteps[name] = true
+	}
+
+	for _, step := range postprocessing.Steps() {
+		if _, fetched := enabledFlags[step.FeatureFlag]; step.FeatureFlag == "" || fetched {
+			continue
+		} else if flag, err := db.GetFlagByKey(ctx, step.FeatureFlag); err != nil {
+			errs = append(errs, fmt.Errorf("error retrieving feature flag %s: %w", step.FeatureFlag, err))
+			enabledFlags[step.FeatureFlag] = false
+		} else {
+			enabledFlags[step.FeatureFlag] = flag.Enabled
+		}
+	}
+
+	return func(step postprocessing.Step) bool {
+		return !disabledSteps[step.Name] && (step.FeatureFlag == "" || enabledFlags[step.FeatureFlag])
+	}, errors.Join(errs...)
+}
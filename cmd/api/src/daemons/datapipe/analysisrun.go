@@ -0,0 +1,167 @@
+// Copyright 2023 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package datapipe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/specterops/bloodhound/cmd/api/src/database"
+	"github.com/specterops/bloodhound/cmd/api/src/database/types"
+	"github.com/specterops/bloodhound/cmd/api/src/database/types/null"
+	"github.com/specterops/bloodhound/cmd/api/src/metrics"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/packages/go/analysis"
+	"github.com/specterops/dawgs/graph"
+)
+
+// analysisRunRecorder records a run of analysis and the outcome of each of its steps in the analysis run history, and
+// times the steps in the analysis metrics. A run that cannot be recorded is logged and does not affect analysis.
+type analysisRunRecorder struct {
+	db  database.AnalysisRunData
+	run model.AnalysisRun
+}
+
+// startAnalysisRun records the start of a run of analysis covering the scope given
+func startAnalysisRun(ctx context.Context, db database.AnalysisRunData, scope AnalysisScope) *analysisRunRecorder {
+	recorder := &analysisRunRecorder{
+		db: db,
+		run: model.AnalysisRun{
+			StartedAt: time.Now().UTC(),
+			Status:    model.AnalysisRunStatusRunning,
+			Scoped:    !scope.IsFull(),
+		},
+	}
+
+	if run, err := db.CreateAnalysisRun(ctx, recorder.run); err != nil {
+		slog.ErrorContext(ctx, fmt.Sprintf("Error recording the start of analysis: %v", err))
+	} else {
+		recorder.run = run
+	}
+
+	return recorder
+}
+
+// measureStep starts timing the step given. The returned function records the step with the error it ended with.
+func (s *analysisRunRecorder) measureStep(name string) func(err error) {
+	var (
+		start = time.Now()
+		done  = metrics.MeasureAnalysisStep(name)
+	)
+
+	return func(err error) {
+		done()
+		s.recordStep(model.AnalysisRunStep{
+			Name:                 name,
+			Status:               stepStatus(false, err),
+			Error:                errorString(err),
+			DurationMilliseconds: time.Since(start).Milliseconds(),
+		})
+	}
+}
+
+// recordPostProcessingStep records a post-processing step with the relationships it created and deleted
+func (s *analysisRunRecorder) recordPostProcessingStep(result analysis.PostProcessorResult) {
+	if !result.Skipped && result.Err == nil {
+		metrics.AnalysisStepDuration.WithLabelValues(result.Name).Observe(result.Duration.Seconds())
+	}
+
+	step := model.AnalysisRunStep{
+		Name:                 result.Name,
+		Status:               stepStatus(result.Skipped, result.Err),
+		Error:                errorString(result.Err),
+		DurationMilliseconds: result.Duration.Milliseconds(),
+	}
+
+	if result.Stats != nil {
+		step.RelationshipsCreated = relationshipCounts(result.Stats.RelationshipsCreated)
+		step.RelationshipsDeleted = relationshipCounts(result.Stats.RelationshipsDeleted)
+	}
+
+	s.recordStep(step)
+}
+
+func (s *analysisRunRecorder) recordStep(step model.AnalysisRunStep) {
+	if step.RelationshipsCreated == nil {
+		step.RelationshipsCreated = types.JSONBInt64Object{}
+	}
+
+	if step.RelationshipsDeleted == nil {
+		step.RelationshipsDeleted = types.JSONBInt64Object{}
+	}
+
+	step.RunID = s.run.ID
+	s.run.Steps = append(s.run.Steps, step)
+}
+
+// finish records the end of the run and its outcome, as given by the error analysis ended with
+func (s *analysisRunRecorder) finish(ctx context.Context, err error) model.AnalysisRun {
+	switch {
+	case err == nil:
+		s.run.Status = model.AnalysisRunStatusComplete
+	case errors.Is(err, ErrAnalysisPartiallyCompleted):
+		s.run.Status = model.AnalysisRunStatusPartiallyComplete
+	case errors.Is(err, ErrAnalysisCanceled):
+		s.run.Status = model.AnalysisRunStatusCanceled
+	default:
+		s.run.Status = model.AnalysisRunStatusFailed
+	}
+
+	s.run.EndedAt = null.TimeFrom(time.Now().UTC())
+
+	if s.run.ID == 0 {
+		// The start of the run could not be recorded, so there is nothing to update
+		return s.run
+	} else if err := s.db.UpdateAnalysisRun(ctx, s.run); err != nil {
+		slog.ErrorContext(ctx, fmt.Sprintf("Error recording the outcome of analysis: %v", err))
+	}
+
+	return s.run
+}
+
+func stepStatus(skipped bool, err error) model.AnalysisRunStepStatus {
+	if err != nil {
+		return model.AnalysisRunStepStatusFailed
+	} else if skipped {
+		return model.AnalysisRunStepStatusSkipped
+	} else {
+		return model.AnalysisRunStepStatusComplete
+	}
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
+
+// relationshipCounts returns the relationship counts given by relationship kind name
+func relationshipCounts(counts map[graph.Kind]*int32) types.JSONBInt64Object {
+	result := make(types.JSONBInt64Object, len(counts))
+
+	for kind, count := range counts {
+		result[kind.String()] += int64(atomic.LoadInt32(count))
+	}
+
+	return result
+}
@@ -0,0 +1,112 @@
+// Copyright 2023 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package datapipe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/specterops/bloodhound/cmd/api/src/database/mocks"
+	"github.com/specterops/bloodhound/cmd/api/src/database/types"
+	"github.com/specterops/bloodhound/cmd/api/src/metrics"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/packages/go/analysis"
+	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAnalysisRunRecorder(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		mockDB = mocks.NewMockDatabase(gomock.NewController(t))
+		stats  = analysis.NewAtomicPostProcessingStats()
+		saved  model.AnalysisRun
+	)
+
+	stats.AddRelationshipsCreated(ad.ADCSESC1, 12)
+	stats.AddRelationshipsDeleted(ad.ADCSESC1, 2000)
+
+	mockDB.EXPECT().CreateAnalysisRun(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, run model.AnalysisRun) (model.AnalysisRun, error) {
+		assert.Equal(t, model.AnalysisRunStatusRunning, run.Status)
+		assert.True(t, run.Scoped)
+
+		run.ID = 7
+		return run, nil
+	})
+	mockDB.EXPECT().UpdateAnalysisRun(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, run model.AnalysisRun) error {
+		saved = run
+		return nil
+	})
+
+	recorder := startAnalysisRun(ctx, mockDB, NewAnalysisScope([]model.IngestJob{{TouchedDomains: []string{"S-1-5-21-1"}}}))
+	recorder.recordPostProcessingStep(analysis.PostProcessorResult{Name: "adcs", Stats: &stats, Duration: 3 * time.Second})
+	recorder.recordPostProcessingStep(analysis.PostProcessorResult{Name: "ntlm", Skipped: true})
+	recorder.measureStep(metrics.AnalysisStepDataQuality)(errors.New("data quality failed"))
+	recorder.finish(ctx, fmt.Errorf("analysis: %w", ErrAnalysisPartiallyCompleted))
+
+	assert.Equal(t, int64(7), saved.ID)
+	assert.Equal(t, model.AnalysisRunStatusPartiallyComplete, saved.Status)
+	assert.True(t, saved.EndedAt.Valid)
+	require.Len(t, saved.Steps, 3)
+
+	assert.Equal(t, model.AnalysisRunStep{
+		RunID:                7,
+		Name:                 "adcs",
+		Status:               model.AnalysisRunStepStatusComplete,
+		DurationMilliseconds: 3000,
+		RelationshipsCreated: types.JSONBInt64Object{ad.ADCSESC1.String(): 12},
+		RelationshipsDeleted: types.JSONBInt64Object{ad.ADCSESC1.String(): 2000},
+	}, saved.Steps[0])
+
+	assert.Equal(t, model.AnalysisRunStepStatusSkipped, saved.Steps[1].Status)
+	assert.Equal(t, types.JSONBInt64Object{}, saved.Steps[1].RelationshipsCreated)
+
+	assert.Equal(t, metrics.AnalysisStepDataQuality, saved.Steps[2].Name)
+	assert.Equal(t, model.AnalysisRunStepStatusFailed, saved.Steps[2].Status)
+	assert.Equal(t, "data quality failed", saved.Steps[2].Error)
+}
+
+func TestAnalysisRunRecorder_NotRecorded(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		mockDB = mocks.NewMockDatabase(gomock.NewController(t))
+	)
+
+	// A run that could not be recorded at its start is not updated at its end
+	mockDB.EXPECT().CreateAnalysisRun(gomock.Any(), gomock.Any()).Return(model.AnalysisRun{}, errors.New("database error"))
+
+	run := startAnalysisRun(ctx, mockDB, AnalysisScope{}).finish(ctx, ErrAnalysisFailed)
+	assert.Equal(t, model.AnalysisRunStatusFailed, run.Status)
+	assert.False(t, run.Scoped)
+}
+
+func TestAnalysisRunRecorder_Canceled(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		mockDB = mocks.NewMockDatabase(gomock.NewController(t))
+	)
+
+	mockDB.EXPECT().CreateAnalysisRun(gomock.Any(), gomock.Any()).Return(model.AnalysisRun{}, errors.New("database error"))
+
+	run := startAnalysisRun(ctx, mockDB, AnalysisScope{}).finish(ctx, ErrAnalysisCanceled)
+	assert.Equal(t, model.AnalysisRunStatusCanceled, run.Status)
+}
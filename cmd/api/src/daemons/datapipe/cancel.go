@@ -0,0 +1,107 @@
+// Copyright 2023 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package datapipe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/specterops/bloodhound/cmd/api/src/database"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/services/graphify"
+)
+
+// cancelPollInterval is how often a running step of the datapipe checks whether it was canceled through the API
+const cancelPollInterval = 5 * time.Second
+
+var (
+	// ErrDatapipeCanceled is the cause of the cancellation of the context of a step of the datapipe that was canceled
+	// through the API
+	ErrDatapipeCanceled = errors.New("datapipe step canceled")
+
+	// ErrIngestJobCanceled is the cause of the cancellation of the context of an ingest task whose job was canceled
+	ErrIngestJobCanceled = errors.New("ingest job canceled")
+)
+
+// cancelWhen returns a copy of ctx that is canceled with the cause given once canceled reports true. canceled is
+// checked right away, then every interval until the returned context is done.
+func cancelWhen(ctx context.Context, interval time.Duration, cause error, canceled func(ctx context.Context) bool) (context.Context, context.CancelFunc) {
+	cancelContext, cancel := context.WithCancelCause(ctx)
+
+	if canceled(cancelContext) {
+		cancel(cause)
+	} else {
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-cancelContext.Done():
+					return
+				case <-ticker.C:
+					if canceled(cancelContext) {
+						cancel(cause)
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	return cancelContext, func() {
+		cancel(context.Canceled)
+	}
+}
+
+// datapipeCancelRequested reports whether the step the datapipe is running was canceled through the API
+func datapipeCancelRequested(db database.DatapipeStatusData) func(ctx context.Context) bool {
+	return func(ctx context.Context) bool {
+		if cancelRequested, err := db.IsDatapipeCancelRequested(ctx); err != nil {
+			if ctx.Err() == nil {
+				slog.ErrorContext(ctx, fmt.Sprintf("Error checking for datapipe cancellation: %v", err))
+			}
+
+			return false
+		} else {
+			return cancelRequested
+		}
+	}
+}
+
+// taskContextFunc generates a graphify.TaskContextFunc whose contexts are canceled once the job of their task is
+// canceled. Only used as a callback, so not exposed
+func taskContextFunc(db database.Database) graphify.TaskContextFunc {
+	return func(ctx context.Context, task model.IngestTask) (context.Context, context.CancelFunc) {
+		return cancelWhen(ctx, cancelPollInterval, ErrIngestJobCanceled, func(ctx context.Context) bool {
+			if !task.JobId.Valid {
+				return false
+			} else if ingestJob, err := db.GetIngestJob(ctx, task.JobId.Int64); err != nil {
+				if ctx.Err() == nil {
+					slog.ErrorContext(ctx, fmt.Sprintf("Error checking for cancellation of ingest job %d: %v", task.JobId.Int64, err))
+				}
+
+				return false
+			} else {
+				return ingestJob.Status == model.JobStatusCanceled
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2023 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package datapipe
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/specterops/bloodhound/cmd/api/src/database/mocks"
+	"github.com/specterops/bloodhound/cmd/api/src/database/types/null"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// cancelTestPipeline runs the action given as every step of the datapipe
+type cancelTestPipeline struct {
+	action func(ctx context.Context) error
+}
+
+func (s cancelTestPipeline) Start(ctx context.Context) error       { return s.action(ctx) }
+func (s cancelTestPipeline) PruneData(ctx context.Context) error   { return s.action(ctx) }
+func (s cancelTestPipeline) DeleteData(ctx context.Context) error  { return s.action(ctx) }
+func (s cancelTestPipeline) IngestTasks(ctx context.Context) error { return s.action(ctx) }
+func (s cancelTestPipeline) Analyze(ctx context.Context) error     { return s.action(ctx) }
+
+func (s cancelTestPipeline) IsPrimary(ctx context.Context, _ model.DatapipeStatus) (bool, context.Context) {
+	return true, ctx
+}
+
+func TestCancelWhen(t *testing.T) {
+	t.Run("canceled right away", func(t *testing.T) {
+		ctx, cancel := cancelWhen(context.Background(), time.Hour, ErrDatapipeCanceled, func(context.Context) bool {
+			return true
+		})
+		defer cancel()
+
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+		assert.ErrorIs(t, context.Cause(ctx), ErrDatapipeCanceled)
+	})
+
+	t.Run("canceled once checked", func(t *testing.T) {
+		checks := make(chan bool, 2)
+		checks <- false
+		checks <- true
+
+		ctx, cancel := cancelWhen(context.Background(), time.Millisecond, ErrDatapipeCanceled, func(context.Context) bool {
+			return <-checks
+		})
+		defer cancel()
+
+		<-ctx.Done()
+		assert.ErrorIs(t, context.Cause(ctx), ErrDatapipeCanceled)
+	})
+
+	t.Run("not canceled", func(t *testing.T) {
+		ctx, cancel := cancelWhen(context.Background(), time.Hour, ErrDatapipeCanceled, func(context.Context) bool {
+			return false
+		})
+
+		assert.NoError(t, ctx.Err())
+
+		cancel()
+		assert.ErrorIs(t, context.Cause(ctx), context.Canceled)
+		assert.NotErrorIs(t, context.Cause(ctx), ErrDatapipeCanceled)
+	})
+}
+
+func TestTaskContextFunc(t *testing.T) {
+	var (
+		mockDB      = mocks.NewMockDatabase(gomock.NewController(t))
+		taskContext = taskContextFunc(mockDB)
+	)
+
+	t.Run("task of a canceled job", func(t *testing.T) {
+		mockDB.EXPECT().GetIngestJob(gomock.Any(), int64(1)).Return(model.IngestJob{Status: model.JobStatusCanceled}, nil)
+
+		ctx, cancel := taskContext(context.Background(), model.IngestTask{JobId: null.Int64From(1)})
+		defer cancel()
+
+		assert.ErrorIs(t, context.Cause(ctx), ErrIngestJobCanceled)
+	})
+
+	t.Run("task of an ingesting job", func(t *testing.T) {
+		mockDB.EXPECT().GetIngestJob(gomock.Any(), int64(2)).Return(model.IngestJob{Status: model.JobStatusIngesting}, nil)
+
+		ctx, cancel := taskContext(context.Background(), model.IngestTask{JobId: null.Int64From(2)})
+		defer cancel()
+
+		assert.NoError(t, ctx.Err())
+	})
+
+	t.Run("task without a job", func(t *testing.T) {
+		ctx, cancel := taskContext(context.Background(), model.IngestTask{})
+		defer cancel()
+
+		assert.NoError(t, ctx.Err())
+	})
+}
+
+func TestDaemon_WithDatapipeStatus_Canceled(t *testing.T) {
+	var (
+		mockDB   = mocks.NewMockDatabase(gomock.NewController(t))
+		canceled = errors.New("canceled")
+		daemon   = NewDaemon(cancelTestPipeline{action: func(ctx context.Context) error {
+			<-ctx.Done()
+			require.ErrorIs(t, context.Cause(ctx), ErrDatapipeCanceled)
+			return canceled
+		}}, 0, 0, mockDB)
+	)
+
+	daemon.cancelPollInterval = time.Millisecond
+
+	gomock.InOrder(
+		mockDB.EXPECT().SetDatapipeStatus(gomock.Any(), model.DatapipeStatusAnalyzing).Return(nil),
+		mockDB.EXPECT().IsDatapipeCancelRequested(gomock.Any()).Return(false, nil),
+		mockDB.EXPECT().IsDatapipeCancelRequested(gomock.Any()).Return(true, nil),
+		mockDB.EXPECT().SetDatapipeStatus(gomock.Any(), model.DatapipeStatusIdle).DoAndReturn(func(ctx context.Context, _ model.DatapipeStatus) error {
+			// The idle status is set with the context of the datapipe rather than that of the canceled step
+			assert.NoError(t, ctx.Err())
+			return nil
+		}),
+	)
+
+	daemon.WithDatapipeStatus(context.Background(), model.DatapipeStatusAnalyzing, daemon.pipeline.Analyze)
+}
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/specterops/bloodhound/cmd/api/src/database"
+	"github.com/specterops/bloodhound/cmd/api/src/metrics"
 	"github.com/specterops/bloodhound/cmd/api/src/model"
 )
 
@@ -49,10 +50,11 @@ type Pipeline interface {
 }
 
 type Daemon struct {
-	startDelay   time.Duration
-	tickInterval time.Duration
-	pipeline     Pipeline
-	db           database.Database
+	startDelay         time.Duration
+	tickInterval       time.Duration
+	cancelPollInterval time.Duration
+	pipeline           Pipeline
+	db                 database.Database
 }
 
 func (s *Daemon) Name() string {
@@ -61,10 +63,11 @@ func (s *Daemon) Name() string {
 
 func NewDaemon(pipeline Pipeline, startDelay time.Duration, tickInterval time.Duration, db database.Database) *Daemon {
 	return &Daemon{
-		db:           db,
-		tickInterval: tickInterval,
-		pipeline:     pipeline,
-		startDelay:   startDelay,
+		db:                 db,
+		tickInterval:       tickInterval,
+		cancelPollInterval: cancelPollInterval,
+		pipeline:           pipeline,
+		startDelay:         startDelay,
 	}
 }
 
@@ -106,6 +109,8 @@ func (s *Daemon) Stop(ctx context.Context) error {
 
 // Any function can be wrapped with a datapipe lock, giving it the status. If everything locks
 // the datapipe through this same wrapper, it should always defer the idle status after.
+//
+// The context given to the function is canceled with ErrDatapipeCanceled if the step is canceled through the API.
 func (s *Daemon) WithDatapipeStatus(ctx context.Context, status model.DatapipeStatus, action func(context.Context) error) {
 
 	active, pipelineContext := s.pipeline.IsPrimary(ctx, status)
@@ -116,6 +121,8 @@ func (s *Daemon) WithDatapipeStatus(ctx context.Context, status model.DatapipeSt
 	defer func() {
 		if err := s.db.SetDatapipeStatus(pipelineContext, model.DatapipeStatusIdle); err != nil {
 			slog.ErrorContext(pipelineContext, "Error setting datapipe status to idle", slog.String("err", err.Error()))
+		} else {
+			metrics.RecordDatapipeStatus(model.DatapipeStatusIdle)
 		}
 	}()
 
@@ -124,7 +131,12 @@ func (s *Daemon) WithDatapipeStatus(ctx context.Context, status model.DatapipeSt
 		return
 	}
 
-	if err := action(pipelineContext); err != nil {
+	metrics.RecordDatapipeStatus(status)
+
+	actionContext, cancel := cancelWhen(pipelineContext, s.cancelPollInterval, ErrDatapipeCanceled, datapipeCancelRequested(s.db))
+	defer cancel()
+
+	if err := action(actionContext); err != nil {
 		slog.ErrorContext(pipelineContext, "Datapipe action failed", slog.String("err", err.Error()))
 	}
 }
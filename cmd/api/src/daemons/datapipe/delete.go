@@ -34,7 +34,19 @@ func DeleteCollectedGraphData(ctx context.Context, graphDB graph.Database, delet
 	slog.Info("DeleteCollectedGraphData",
 		slog.Bool("delete all data", deleteRequest.DeleteAllGraph),
 		slog.Bool("delete sourceless data", deleteRequest.DeleteSourcelessGraph),
-		slog.String("delete source kinds", strings.Join(deleteRequest.DeleteSourceKinds, ",")))
+		slog.String("delete source kinds", strings.Join(deleteRequest.DeleteSourceKinds, ",")),
+		slog.String("delete environments", strings.Join(deleteRequest.DeleteEnvironments, ",")))
+
+	if len(deleteRequest.DeleteEnvironments) > 0 && deleteRequest.DeleteAllGraph {
+		// Edges written by an environment may connect nodes of other environments, which are not deleted below
+		if err := graphDB.WriteTransaction(ctx, func(tx graph.Transaction) error {
+			return tx.Relationships().Filter(
+				query.In(query.RelationshipProperty(common.Environment.String()), []string(deleteRequest.DeleteEnvironments)),
+			).Delete()
+		}); err != nil {
+			return fmt.Errorf("error deleting graph relationships: %w", err)
+		}
+	}
 
 	operation := ops.StartNewOperation[graph.ID](ops.OperationContext{
 		Parent:     ctx,
@@ -55,7 +67,7 @@ func DeleteCollectedGraphData(ctx context.Context, graphDB graph.Database, delet
 		)
 
 		// Always exclude MigrationData
-		migrationFilter := query.Not(query.Kind(query.Node(), common.MigrationData))
+		var baseFilter graph.Criteria = query.Not(query.Kind(query.Node(), common.MigrationData))
 
 		if !deleteRequest.DeleteAllGraph {
 			if deleteRequest.DeleteSourcelessGraph {
@@ -71,15 +83,23 @@ func DeleteCollectedGraphData(ctx context.Context, graphDB graph.Database, delet
 			}
 		}
 
+		// Only the data tagged with one of the environments given is deleted when the request is limited to them
+		if len(deleteRequest.DeleteEnvironments) > 0 {
+			baseFilter = query.And(
+				baseFilter,
+				query.In(query.NodeProperty(common.Environment.String()), []string(deleteRequest.DeleteEnvironments)),
+			)
+		}
+
 		if len(filters) > 0 {
 			nodeQuery = tx.Nodes().Filter(
 				query.And(
-					migrationFilter,
+					baseFilter,
 					query.Or(filters...),
 				),
 			)
 		} else {
-			nodeQuery = tx.Nodes().Filter(migrationFilter)
+			nodeQuery = tx.Nodes().Filter(baseFilter)
 		}
 
 		return nodeQuery.FetchIDs(func(cursor graph.Cursor[graph.ID]) error {
@@ -26,8 +26,10 @@ import (
 
 	"github.com/specterops/bloodhound/cmd/api/src/daemons/datapipe"
 	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/packages/go/graphschema/common"
 	"github.com/specterops/bloodhound/packages/go/lab/generic"
 	"github.com/specterops/dawgs/graph"
+	"github.com/specterops/dawgs/query"
 	"github.com/stretchr/testify/require"
 )
 
@@ -47,10 +49,10 @@ func TestDeleteAllData(t *testing.T) {
 	defer teardownIntegrationTestSuite(t, &testSuite)
 
 	for _, file := range files {
-		total, failed, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeJson}, time.Now())
+		results, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeJson}, time.Now())
 		require.NoError(t, err)
-		require.Zero(t, failed)
-		require.Equal(t, 1, total)
+		require.Zero(t, results.Failed())
+		require.Len(t, results, 1)
 	}
 
 	// DELETE ALL
@@ -86,10 +88,10 @@ func TestDeleteAllData_Alternative(t *testing.T) {
 	defer teardownIntegrationTestSuite(t, &testSuite)
 
 	for _, file := range files {
-		total, failed, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeJson}, time.Now())
+		results, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeJson}, time.Now())
 		require.NoError(t, err)
-		require.Zero(t, failed)
-		require.Equal(t, 1, total)
+		require.Zero(t, results.Failed())
+		require.Len(t, results, 1)
 	}
 
 	// DELETE ALL
@@ -122,10 +124,10 @@ func TestDeleteSourcelessData(t *testing.T) {
 	defer teardownIntegrationTestSuite(t, &testSuite)
 
 	for _, file := range files {
-		total, failed, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeJson}, time.Now())
+		results, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeJson}, time.Now())
 		require.NoError(t, err)
-		require.Zero(t, failed)
-		require.Equal(t, 1, total)
+		require.Zero(t, results.Failed())
+		require.Len(t, results, 1)
 	}
 
 	var (
@@ -157,10 +159,10 @@ func TestDeleteSourceKindsData(t *testing.T) {
 	defer teardownIntegrationTestSuite(t, &testSuite)
 
 	for _, file := range files {
-		total, failed, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeJson}, time.Now())
+		results, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeJson}, time.Now())
 		require.NoError(t, err)
-		require.Zero(t, failed)
-		require.Equal(t, 1, total)
+		require.Zero(t, results.Failed())
+		require.Len(t, results, 1)
 	}
 
 	var (
@@ -175,3 +177,44 @@ func TestDeleteSourceKindsData(t *testing.T) {
 	require.NoError(t, err)
 	generic.AssertDatabaseGraph(t, ctx, testSuite.GraphDB, &expected)
 }
+
+// TestDeleteEnvironmentData covers a deletion limited to environments, which only removes the data tagged with them
+func TestDeleteEnvironmentData(t *testing.T) {
+	var (
+		ctx = context.Background()
+
+		fixturesPath = path.Join("fixtures", "TestDeleteAllData", "opengraph")
+
+		testSuite = setupIntegrationTestSuite(t, fixturesPath)
+
+		sourceKinds = []graph.Kind{graph.StringKind("Base"), graph.StringKind("AZBase"), graph.StringKind("GithubBase")}
+
+		countTaggedNodes = func(environment string) int64 {
+			var count int64
+
+			require.NoError(t, testSuite.GraphDB.ReadTransaction(ctx, func(tx graph.Transaction) error {
+				var err error
+				count, err = tx.Nodes().Filter(query.Equals(query.NodeProperty(common.Environment.String()), environment)).Count()
+				return err
+			}))
+
+			return count
+		}
+	)
+
+	defer teardownIntegrationTestSuite(t, &testSuite)
+
+	results, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: path.Join(testSuite.WorkDir, "base.json"), FileType: model.FileTypeJson, Environment: "subsidiary-a"}, time.Now())
+	require.NoError(t, err)
+	require.Zero(t, results.Failed())
+
+	tagged := countTaggedNodes("subsidiary-a")
+	require.NotZero(t, tagged)
+
+	// The data of other environments is left alone
+	require.NoError(t, datapipe.DeleteCollectedGraphData(ctx, testSuite.GraphDB, model.AnalysisRequest{DeleteAllGraph: true, DeleteEnvironments: []string{"subsidiary-b"}}, sourceKinds))
+	require.Equal(t, tagged, countTaggedNodes("subsidiary-a"))
+
+	require.NoError(t, datapipe.DeleteCollectedGraphData(ctx, testSuite.GraphDB, model.AnalysisRequest{DeleteAllGraph: true, DeleteEnvironments: []string{"subsidiary-a"}}, sourceKinds))
+	require.Zero(t, countTaggedNodes("subsidiary-a"))
+}
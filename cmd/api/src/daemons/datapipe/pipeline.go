@@ -21,9 +21,13 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"time"
 
+	"github.com/lib/pq"
 	"github.com/specterops/bloodhound/cmd/api/src/config"
 	"github.com/specterops/bloodhound/cmd/api/src/database"
+	"github.com/specterops/bloodhound/cmd/api/src/database/types/null"
 	"github.com/specterops/bloodhound/cmd/api/src/model"
 	"github.com/specterops/bloodhound/cmd/api/src/model/appcfg"
 	"github.com/specterops/bloodhound/cmd/api/src/services/graphify"
@@ -38,6 +42,9 @@ import (
 
 var ErrAnalysisDisabled = errors.New("analysis is disabled by configuration")
 
+// analysisRequester is recorded as the requester of the analyses the datapipe requests for itself
+const analysisRequester = "datapipe"
+
 type BHCEPipeline struct {
 	db                  database.Database
 	graphdb             graph.Database
@@ -94,9 +101,13 @@ func (s *BHCEPipeline) DeleteData(ctx context.Context) error {
 		for _, k := range sourceKinds {
 			kinds = append(kinds, k.Name)
 		}
-		// Filter out reserved kinds before removing records from source_kinds table
+		// Filter out reserved kinds before removing records from source_kinds table. Only the source kinds whose data
+		// was deleted are removed, so that the others keep their description, owners and retention period. Deleting
+		// the data of some environments leaves that of the others, so no source kind is removed.
 		for _, kind := range kinds {
-			if !kind.Is(ad.Entity) && !kind.Is(azure.Entity) {
+			if kind.Is(ad.Entity) || kind.Is(azure.Entity) || len(deleteRequest.DeleteEnvironments) > 0 {
+				continue
+			} else if deleteRequest.DeleteAllGraph || slices.Contains(deleteRequest.DeleteSourceKinds, kind.String()) {
 				filteredKinds = append(filteredKinds, kind)
 			}
 		}
@@ -131,30 +142,104 @@ func (s *BHCEPipeline) PruneData(ctx context.Context) error {
 // Daemon object enough to be self standing and pulled to an internal package namespace
 func (s *BHCEPipeline) IngestTasks(ctx context.Context) error {
 	// Ingest all available ingest tasks
-	s.graphifyService.ProcessTasks(updateJobFunc(ctx, s.db))
+	s.graphifyService.ProcessTasks(updateJobFunc(ctx, s.db), taskContextFunc(s.db))
 
 	// Manage time-out state progression for ingest jobs
 	s.jobService.ProcessStaleIngestJobs()
 
 	// Manage nominal state transitions for ingest jobs
-	s.jobService.ProcessFinishedIngestJobs()
+	s.jobService.ProcessFinishedIngestJobs(sweepStaleDataFunc(ctx, s.graphifyService), auditRedactedPropertiesFunc(ctx, s.db))
 	return nil
 }
 
 // updateJobFunc generates a valid graphify.UpdateJobFunc by injecting the parent context and database interface
 // Only used as a callback, so not exposed
 func updateJobFunc(ctx context.Context, db database.Database) graphify.UpdateJobFunc {
-	return func(jobID int64, totalFiles int, totalFailed int) {
+	return func(jobID int64, fileResults model.IngestFileResults) {
 		if job, err := db.GetIngestJob(ctx, jobID); err != nil {
 			slog.ErrorContext(ctx, fmt.Sprintf("Failed to fetch job for ingest task %d: %v", jobID, err))
 		} else {
-			job.TotalFiles += totalFiles
-			job.FailedFiles += totalFailed
+			job.TotalFiles += len(fileResults)
+			job.FailedFiles += fileResults.Failed()
+
+			for _, fileResult := range fileResults {
+				job.CollectedDomains = appendMissing(job.CollectedDomains, fileResult.CollectedDomains...)
+				job.CollectedSourceKinds = appendMissing(job.CollectedSourceKinds, fileResult.CollectedSourceKinds...)
+				job.RedactedProperties = appendMissing(job.RedactedProperties, fileResult.RedactedProperties...)
+				job.TouchedDomains = appendMissing(job.TouchedDomains, fileResult.TouchedDomains...)
+				job.TouchedTenants = appendMissing(job.TouchedTenants, fileResult.TouchedTenants...)
+				job.UnscopedObjects += fileResult.UnscopedObjects
+				job.DeletedNodes += fileResult.DeletedNodeCount
+				job.DeletedRelationships += fileResult.DeletedRelationshipCount
+			}
 
 			if err = db.UpdateIngestJob(ctx, job); err != nil {
 				slog.ErrorContext(ctx, fmt.Sprintf("Failed to update number of failed files for ingest job ID %d: %v", job.ID, err))
 			}
+
+			if err = db.MarkSourceKindsIngested(ctx, graph.StringsToKinds(fileResults.IngestedSourceKinds()), time.Now().UTC()); err != nil {
+				slog.ErrorContext(ctx, fmt.Sprintf("Failed to record the source kinds ingested by ingest job ID %d: %v", job.ID, err))
+			}
+
+			for idx := range fileResults {
+				fileResults[idx].JobID = job.ID
+			}
+
+			if err = db.CreateIngestFileResults(ctx, fileResults); err != nil {
+				slog.ErrorContext(ctx, fmt.Sprintf("Failed to save file results for ingest job ID %d: %v", job.ID, err))
+			}
+		}
+	}
+}
+
+func appendMissing(values pq.StringArray, additions ...string) pq.StringArray {
+	for _, addition := range additions {
+		if !slices.Contains(values, addition) {
+			values = append(values, addition)
+		}
+	}
+
+	return values
+}
+
+// sweepStaleDataFunc generates a job.FinishIngestJobFunc that sweeps the graph data not seen by authoritative ingest
+// jobs and records the outcome on the job. Jobs with files that failed to ingest did not collect their scope in full,
+// so nothing is swept for them.
+func sweepStaleDataFunc(ctx context.Context, graphifyService graphify.GraphifyService) job.FinishIngestJobFunc {
+	return func(ingestJob model.IngestJob) model.IngestJob {
+		if !ingestJob.AuthoritativeMode.Enabled() {
+			return ingestJob
+		} else if ingestJob.FailedFiles > 0 {
+			slog.WarnContext(ctx, fmt.Sprintf("Skipping stale data sweep for ingest job %d: %d file(s) failed to ingest", ingestJob.ID, ingestJob.FailedFiles))
+		} else if counts, err := graphifyService.SweepStaleData(ctx, ingestJob); err != nil {
+			slog.ErrorContext(ctx, fmt.Sprintf("Failed to sweep stale data for ingest job %d: %v", ingestJob.ID, err))
+		} else {
+			slog.InfoContext(ctx, fmt.Sprintf("Ingest job %d found %d stale node(s) and %d stale relationship(s) (%s)", ingestJob.ID, counts.Nodes, counts.Relationships, ingestJob.AuthoritativeMode))
+
+			ingestJob.StaleNodes = counts.Nodes
+			ingestJob.StaleRelationships = counts.Relationships
+		}
+
+		return ingestJob
+	}
+}
+
+// auditRedactedPropertiesFunc generates a job.FinishIngestJobFunc that records an audit entry listing the properties
+// redacted from the files of an ingest job, if any were
+func auditRedactedPropertiesFunc(ctx context.Context, db database.Database) job.FinishIngestJobFunc {
+	return func(ingestJob model.IngestJob) model.IngestJob {
+		if len(ingestJob.RedactedProperties) == 0 {
+			return ingestJob
+		} else if auditEntry, err := model.NewAuditEntry(model.AuditLogActionRedactIngestedProperties, model.AuditLogStatusSuccess, model.AuditData{
+			"ingest_job_id":       ingestJob.ID,
+			"redacted_properties": []string(ingestJob.RedactedProperties),
+		}); err != nil {
+			slog.ErrorContext(ctx, fmt.Sprintf("Failed to create redaction audit entry for ingest job %d: %v", ingestJob.ID, err))
+		} else if err := db.AppendAuditLog(ctx, auditEntry); err != nil {
+			slog.ErrorContext(ctx, fmt.Sprintf("Failed to record redaction audit entry for ingest job %d: %v", ingestJob.ID, err))
 		}
+
+		return ingestJob
 	}
 }
 
@@ -163,11 +248,45 @@ func (s *BHCEPipeline) IsPrimary(ctx context.Context, status model.DatapipeStatu
 	return true, ctx
 }
 
+// checkAnalysisSchedule records the next occurrence of the analysis schedule while scheduled analysis is enabled, and
+// clears it otherwise. It reports whether scheduled analysis is enabled and whether the occurrence recorded by the
+// previous check has come due.
+func checkAnalysisSchedule(ctx context.Context, db database.Database, now time.Time) (bool, bool, error) {
+	if schedule, err := appcfg.GetScheduledAnalysisParameter(ctx, db); err != nil {
+		return false, false, fmt.Errorf("fetching analysis schedule: %w", err)
+	} else if status, err := db.GetDatapipeStatus(ctx); err != nil {
+		return false, false, fmt.Errorf("fetching datapipe status: %w", err)
+	} else if !schedule.Enabled {
+		if status.NextScheduledAnalysisAt.Valid {
+			return false, false, db.SetNextScheduledAnalysisTime(ctx, null.Time{})
+		}
+
+		return false, false, nil
+	} else if nextRun, err := schedule.NextRun(now); err != nil {
+		return true, false, err
+	} else {
+		// The next occurrence is recomputed on every check so that changes to the schedule apply right away
+		due := status.NextScheduledAnalysisAt.Valid && !now.Before(status.NextScheduledAnalysisAt.Time)
+
+		if !status.NextScheduledAnalysisAt.Valid || !status.NextScheduledAnalysisAt.Time.Equal(nextRun) {
+			if err := db.SetNextScheduledAnalysisTime(ctx, null.TimeFrom(nextRun)); err != nil {
+				return true, due, fmt.Errorf("recording next scheduled analysis: %w", err)
+			}
+		}
+
+		return true, due, nil
+	}
+}
+
 func (s *BHCEPipeline) Analyze(ctx context.Context) error {
-	// If there are completed ingest jobs or if analysis was user-requested, perform analysis.
-	if hasJobsWaitingForAnalysis, err := s.jobService.HasIngestJobsWaitingForAnalysis(); err != nil {
+	// If there are completed ingest jobs or if analysis was user-requested, perform analysis. When scheduled analysis
+	// is enabled, completed ingest jobs wait for the next occurrence of the schedule instead, at which point analysis
+	// runs whether or not there are any.
+	if scheduled, scheduleDue, err := checkAnalysisSchedule(ctx, s.db, time.Now().UTC()); err != nil {
+		return fmt.Errorf("checking analysis schedule: %v", err)
+	} else if jobsWaitingForAnalysis, err := s.jobService.GetIngestJobsWaitingForAnalysis(); err != nil {
 		return fmt.Errorf("looking up jobs for analysis: %v", err)
-	} else if hasJobsWaitingForAnalysis || s.db.HasAnalysisRequest(ctx) {
+	} else if analysisRequested := s.db.HasAnalysisRequest(ctx); (len(jobsWaitingForAnalysis) > 0 && !scheduled) || scheduleDue || analysisRequested {
 		// Ensure that the user-requested analysis switch is deleted. This is done at the beginning of the
 		// function so that any re-analysis requests are caught while analysis is in-progress.
 		if err := s.db.DeleteAnalysisRequest(ctx); err != nil {
@@ -180,30 +299,65 @@ func (s *BHCEPipeline) Analyze(ctx context.Context) error {
 
 		defer measure.LogAndMeasure(slog.LevelInfo, "Graph Analysis")()
 
-		if err := RunAnalysisOperations(ctx, s.db, s.graphdb, s.cfg); err != nil {
-			if errors.Is(err, ErrAnalysisFailed) {
-				s.jobService.FailAnalyzedIngestJobs()
-			} else if errors.Is(err, ErrAnalysisPartiallyCompleted) {
-				s.jobService.PartialCompleteIngestJobs()
-			}
-			return fmt.Errorf("analysis failure: %v", err)
-		} else if err := s.db.UpdateLastAnalysisCompleteTime(ctx); err != nil {
-			return fmt.Errorf("update last analysis completion time: %v", err)
-		} else {
-			s.jobService.CompleteAnalyzedIngestJobs()
-
-			// This is cacheclearing. The analysis is still successful here
-			if _, err := s.db.GetFlagByKey(ctx, appcfg.FeatureEntityPanelCaching); err != nil {
-				slog.ErrorContext(ctx, fmt.Sprintf("Error retrieving entity panel caching flag: %v", err))
-			} else if err := s.cache.Reset(); err != nil {
-				slog.Error(fmt.Sprintf("Error while resetting the cache: %v", err))
-			} else {
-				slog.Info("Cache successfully reset by datapipe daemon")
+		// Analysis of completed ingest jobs only post-processes the domains and tenants they touched. Requested
+		// analysis, and scheduled analysis without any jobs, covers the whole graph.
+		scope := AnalysisScope{}
+		if !analysisRequested && !s.cfg.DisableIncrementalAnalysis && len(jobsWaitingForAnalysis) > 0 {
+			scope = NewAnalysisScope(jobsWaitingForAnalysis)
+		}
+
+		return s.finishAnalysis(ctx, scope, RunAnalysisOperations(ctx, s.db, s.graphdb, s.cfg, scope))
+	} else {
+		return nil
+	}
+}
+
+// finishAnalysis moves the ingest jobs under analysis on according to the error analysis of the scope given ended with
+func (s *BHCEPipeline) finishAnalysis(ctx context.Context, scope AnalysisScope, err error) error {
+	if errors.Is(err, ErrAnalysisCanceled) {
+		// The context of a canceled analysis is done, but the outcome must still be recorded
+		ctx = context.WithoutCancel(ctx)
+
+		// The relationships of the post-processing steps that did not complete were deleted up front, so the whole
+		// graph is analyzed again. The request to cancel is cleared along with the analyzing status, so the next
+		// analysis runs to completion unless it is canceled as well.
+		slog.WarnContext(ctx, "Analysis was canceled, requesting analysis of the whole graph to restore post-processed relationships")
+
+		if err := s.db.RequestAnalysis(ctx, analysisRequester); err != nil {
+			slog.ErrorContext(ctx, fmt.Sprintf("Error requesting analysis of the whole graph: %v", err))
+		}
+
+		s.jobService.CancelAnalyzedIngestJobs()
+		return nil
+	} else if err != nil {
+		// The domains and tenants of the jobs are forgotten along with them, so the next analysis must cover
+		// the whole graph
+		if !scope.IsFull() {
+			if err := s.db.RequestAnalysis(ctx, analysisRequester); err != nil {
+				slog.ErrorContext(ctx, fmt.Sprintf("Error requesting analysis of the whole graph: %v", err))
 			}
+		}
 
-			return nil
+		if errors.Is(err, ErrAnalysisFailed) {
+			s.jobService.FailAnalyzedIngestJobs()
+		} else if errors.Is(err, ErrAnalysisPartiallyCompleted) {
+			s.jobService.PartialCompleteIngestJobs()
 		}
+		return fmt.Errorf("analysis failure: %v", err)
+	} else if err := s.db.UpdateLastAnalysisCompleteTime(ctx); err != nil {
+		return fmt.Errorf("update last analysis completion time: %v", err)
 	} else {
+		s.jobService.CompleteAnalyzedIngestJobs()
+
+		// This is cacheclearing. The analysis is still successful here
+		if _, err := s.db.GetFlagByKey(ctx, appcfg.FeatureEntityPanelCaching); err != nil {
+			slog.ErrorContext(ctx, fmt.Sprintf("Error retrieving entity panel caching flag: %v", err))
+		} else if err := s.cache.Reset(); err != nil {
+			slog.Error(fmt.Sprintf("Error while resetting the cache: %v", err))
+		} else {
+			slog.Info("Cache successfully reset by datapipe daemon")
+		}
+
 		return nil
 	}
 }
@@ -0,0 +1,247 @@
+// Copyright 2023 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package datapipe
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/specterops/bloodhound/cmd/api/src/database/mocks"
+	"github.com/specterops/bloodhound/cmd/api/src/database/types"
+	"github.com/specterops/bloodhound/cmd/api/src/database/types/null"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/model/appcfg"
+	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
+	"github.com/specterops/bloodhound/cmd/api/src/services/graphify"
+	"github.com/specterops/bloodhound/cmd/api/src/services/job"
+	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
+	"github.com/specterops/bloodhound/packages/go/graphschema/azure"
+	"github.com/specterops/dawgs/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUpdateJobFunc_CollectedScope(t *testing.T) {
+	var (
+		mockDB      = mocks.NewMockDatabase(gomock.NewController(t))
+		fileResults = model.IngestFileResults{
+			{FileName: "domains.json", DataType: ingest.DataTypeDomain, NodeCount: 2, CollectedDomains: []string{"S-1-5-21-1", "S-1-5-21-2"}, TouchedDomains: []string{"S-1-5-21-1", "S-1-5-21-2"}},
+			{FileName: "github.json", DataType: ingest.DataTypeOpenGraph, NodeCount: 1, CollectedSourceKinds: []string{"GithubBase"}, RedactedProperties: []string{"userpassword"}, DeletedNodeCount: 2, DeletedRelationshipCount: 3, UnscopedObjects: 1},
+			{FileName: "azure.json", DataType: ingest.DataTypeAzure, NodeCount: 1, TouchedTenants: []string{"TENANT-1"}},
+			{FileName: "users.json", Error: "bad file"},
+		}
+	)
+
+	mockDB.EXPECT().GetIngestJob(gomock.Any(), int64(1)).Return(model.IngestJob{
+		CollectedDomains:   pq.StringArray{"S-1-5-21-1"},
+		TotalFiles:         2,
+		RedactedProperties: pq.StringArray{"description", "userpassword"},
+		DeletedNodes:       1,
+		TouchedDomains:     pq.StringArray{"S-1-5-21-1"},
+	}, nil)
+	mockDB.EXPECT().UpdateIngestJob(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, job model.IngestJob) error {
+		assert.Equal(t, pq.StringArray{"S-1-5-21-1", "S-1-5-21-2"}, job.CollectedDomains)
+		assert.Equal(t, pq.StringArray{"GithubBase"}, job.CollectedSourceKinds)
+		assert.Equal(t, pq.StringArray{"description", "userpassword"}, job.RedactedProperties)
+		assert.Equal(t, pq.StringArray{"S-1-5-21-1", "S-1-5-21-2"}, job.TouchedDomains)
+		assert.Equal(t, pq.StringArray{"TENANT-1"}, job.TouchedTenants)
+		assert.Equal(t, int64(1), job.UnscopedObjects)
+		assert.Equal(t, 6, job.TotalFiles)
+		assert.Equal(t, 1, job.FailedFiles)
+		assert.Equal(t, int64(3), job.DeletedNodes)
+		assert.Equal(t, int64(3), job.DeletedRelationships)
+		return nil
+	})
+	mockDB.EXPECT().MarkSourceKindsIngested(gomock.Any(), graph.Kinds{ad.Entity, graph.StringKind("GithubBase"), azure.Entity}, gomock.Any()).Return(nil)
+	mockDB.EXPECT().CreateIngestFileResults(gomock.Any(), gomock.Any()).Return(nil)
+
+	updateJobFunc(context.Background(), mockDB)(1, fileResults)
+}
+
+func TestSweepStaleDataFunc(t *testing.T) {
+	// Neither case reaches the graph, so the service is left without one
+	sweep := sweepStaleDataFunc(context.Background(), graphify.GraphifyService{})
+
+	t.Run("jobs that are not authoritative are not swept", func(t *testing.T) {
+		job := model.IngestJob{CollectedDomains: pq.StringArray{"S-1-5-21-1"}}
+		assert.Equal(t, job, sweep(job))
+	})
+
+	t.Run("jobs with failed files are not swept", func(t *testing.T) {
+		job := model.IngestJob{AuthoritativeMode: model.AuthoritativeModeDelete, CollectedDomains: pq.StringArray{"S-1-5-21-1"}, TotalFiles: 2, FailedFiles: 1}
+		assert.Equal(t, job, sweep(job))
+	})
+}
+
+func TestAuditRedactedPropertiesFunc(t *testing.T) {
+	t.Run("an audit entry lists the properties redacted", func(t *testing.T) {
+		var (
+			mockDB = mocks.NewMockDatabase(gomock.NewController(t))
+			job    = model.IngestJob{BigSerial: model.BigSerial{ID: 4}, RedactedProperties: pq.StringArray{"description", "userpassword"}}
+		)
+
+		mockDB.EXPECT().AppendAuditLog(gomock.Any(), model.AuditEntry{
+			Action: model.AuditLogActionRedactIngestedProperties,
+			Status: model.AuditLogStatusSuccess,
+			Model: model.AuditData{
+				"ingest_job_id":       int64(4),
+				"redacted_properties": []string{"description", "userpassword"},
+			},
+		}).Return(nil)
+
+		assert.Equal(t, job, auditRedactedPropertiesFunc(context.Background(), mockDB)(job))
+	})
+
+	t.Run("no audit entry is recorded when nothing was redacted", func(t *testing.T) {
+		job := model.IngestJob{BigSerial: model.BigSerial{ID: 4}}
+		assert.Equal(t, job, auditRedactedPropertiesFunc(context.Background(), mocks.NewMockDatabase(gomock.NewController(t)))(job))
+	})
+}
+
+func TestCheckAnalysisSchedule(t *testing.T) {
+	var (
+		daily = appcfg.ScheduledAnalysisParameter{Enabled: true, RRule: "DTSTART:20240101T020000Z\nRRULE:FREQ=DAILY"}
+		now   = time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+		next  = time.Date(2024, 3, 11, 2, 0, 0, 0, time.UTC)
+	)
+
+	expectSchedule := func(t *testing.T, mockDB *mocks.MockDatabase, schedule appcfg.ScheduledAnalysisParameter) {
+		value, err := types.NewJSONBObject(schedule)
+		require.NoError(t, err)
+
+		mockDB.EXPECT().GetConfigurationParameter(gomock.Any(), appcfg.ScheduledAnalysis).Return(appcfg.Parameter{Key: appcfg.ScheduledAnalysis, Value: value}, nil)
+	}
+
+	t.Run("the first check records the next occurrence without running analysis", func(t *testing.T) {
+		mockDB := mocks.NewMockDatabase(gomock.NewController(t))
+
+		expectSchedule(t, mockDB, daily)
+		mockDB.EXPECT().GetDatapipeStatus(gomock.Any()).Return(model.DatapipeStatusWrapper{}, nil)
+		mockDB.EXPECT().SetNextScheduledAnalysisTime(gomock.Any(), null.TimeFrom(next)).Return(nil)
+
+		scheduled, due, err := checkAnalysisSchedule(context.Background(), mockDB, now)
+		require.NoError(t, err)
+		assert.True(t, scheduled)
+		assert.False(t, due)
+	})
+
+	t.Run("analysis is not due before the recorded occurrence", func(t *testing.T) {
+		mockDB := mocks.NewMockDatabase(gomock.NewController(t))
+
+		expectSchedule(t, mockDB, daily)
+		mockDB.EXPECT().GetDatapipeStatus(gomock.Any()).Return(model.DatapipeStatusWrapper{NextScheduledAnalysisAt: null.TimeFrom(next)}, nil)
+
+		scheduled, due, err := checkAnalysisSchedule(context.Background(), mockDB, now)
+		require.NoError(t, err)
+		assert.True(t, scheduled)
+		assert.False(t, due)
+	})
+
+	t.Run("analysis is due once the recorded occurrence has passed", func(t *testing.T) {
+		var (
+			mockDB = mocks.NewMockDatabase(gomock.NewController(t))
+			later  = next.Add(time.Minute)
+		)
+
+		expectSchedule(t, mockDB, daily)
+		mockDB.EXPECT().GetDatapipeStatus(gomock.Any()).Return(model.DatapipeStatusWrapper{NextScheduledAnalysisAt: null.TimeFrom(next)}, nil)
+		mockDB.EXPECT().SetNextScheduledAnalysisTime(gomock.Any(), null.TimeFrom(next.AddDate(0, 0, 1))).Return(nil)
+
+		scheduled, due, err := checkAnalysisSchedule(context.Background(), mockDB, later)
+		require.NoError(t, err)
+		assert.True(t, scheduled)
+		assert.True(t, due)
+	})
+
+	t.Run("disabling the schedule clears the recorded occurrence", func(t *testing.T) {
+		mockDB := mocks.NewMockDatabase(gomock.NewController(t))
+
+		expectSchedule(t, mockDB, appcfg.ScheduledAnalysisParameter{})
+		mockDB.EXPECT().GetDatapipeStatus(gomock.Any()).Return(model.DatapipeStatusWrapper{NextScheduledAnalysisAt: null.TimeFrom(next)}, nil)
+		mockDB.EXPECT().SetNextScheduledAnalysisTime(gomock.Any(), null.Time{}).Return(nil)
+
+		scheduled, due, err := checkAnalysisSchedule(context.Background(), mockDB, now)
+		require.NoError(t, err)
+		assert.False(t, scheduled)
+		assert.False(t, due)
+	})
+}
+
+func TestNewAnalysisScope(t *testing.T) {
+	t.Run("the scope covers the domains and tenants touched by the jobs", func(t *testing.T) {
+		scope := NewAnalysisScope([]model.IngestJob{
+			{TouchedDomains: pq.StringArray{"S-1-5-21-1"}},
+			{TouchedDomains: pq.StringArray{"S-1-5-21-1", "S-1-5-21-2"}, TouchedTenants: pq.StringArray{"TENANT-1"}},
+		})
+
+		assert.False(t, scope.IsFull())
+		assert.Equal(t, []string{"S-1-5-21-1", "S-1-5-21-2"}, scope.Domains.IDs())
+		assert.Equal(t, []string{"TENANT-1"}, scope.Tenants.IDs())
+	})
+
+	t.Run("jobs that touched no tenants leave the tenant scope empty", func(t *testing.T) {
+		scope := NewAnalysisScope([]model.IngestJob{{TouchedDomains: pq.StringArray{"S-1-5-21-1"}}})
+
+		assert.True(t, scope.Tenants.IsEmpty())
+		assert.False(t, scope.Domains.IsEmpty())
+	})
+
+	t.Run("a job with unscoped objects covers the whole graph", func(t *testing.T) {
+		scope := NewAnalysisScope([]model.IngestJob{
+			{TouchedDomains: pq.StringArray{"S-1-5-21-1"}},
+			{UnscopedObjects: 2},
+		})
+
+		assert.True(t, scope.IsFull())
+	})
+
+	t.Run("a job that only deleted data covers the whole graph", func(t *testing.T) {
+		for _, ingestJob := range []model.IngestJob{{DeletedNodes: 1}, {DeletedRelationships: 3}} {
+			scope := NewAnalysisScope([]model.IngestJob{ingestJob})
+
+			assert.True(t, scope.IsFull())
+			assert.False(t, scope.Domains.IsEmpty())
+			assert.False(t, scope.Tenants.IsEmpty())
+		}
+	})
+}
+
+func TestBHCEPipeline_FinishAnalysis_Canceled(t *testing.T) {
+	var (
+		mockDB      = mocks.NewMockDatabase(gomock.NewController(t))
+		pipeline    = &BHCEPipeline{db: mockDB, jobService: job.NewJobService(context.Background(), mockDB)}
+		ctx, cancel = context.WithCancelCause(context.Background())
+	)
+
+	// Analysis is canceled through the context of the step
+	cancel(ErrDatapipeCanceled)
+
+	mockDB.EXPECT().RequestAnalysis(gomock.Any(), analysisRequester).DoAndReturn(func(ctx context.Context, _ string) error {
+		assert.NoError(t, ctx.Err())
+		return nil
+	})
+	mockDB.EXPECT().GetIngestJobsWithStatus(gomock.Any(), model.JobStatusAnalyzing).Return([]model.IngestJob{{Status: model.JobStatusAnalyzing}}, nil)
+	mockDB.EXPECT().UpdateIngestJob(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, ingestJob model.IngestJob) error {
+		assert.Equal(t, model.JobStatusCanceled, ingestJob.Status)
+		return nil
+	})
+
+	require.NoError(t, pipeline.finishAnalysis(ctx, NewAnalysisScope([]model.IngestJob{{TouchedDomains: pq.StringArray{"S-1-5-21-1"}}}), ErrAnalysisCanceled))
+}
@@ -0,0 +1,237 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dropfolder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/specterops/bloodhound/cmd/api/src/config"
+	"github.com/specterops/bloodhound/cmd/api/src/database"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/services/job"
+	"github.com/specterops/bloodhound/cmd/api/src/services/upload"
+)
+
+const (
+	DoneDirectoryName   = "done"
+	FailedDirectoryName = "failed"
+)
+
+// observedFile is the size and modification time of a file in the drop folder as of the last scan
+type observedFile struct {
+	size    int64
+	modTime time.Time
+}
+
+// Daemon watches a drop folder and submits collection files written to it as ingest jobs
+type Daemon struct {
+	exitC     chan struct{}
+	db        database.Database
+	cfg       config.Configuration
+	validator upload.IngestValidator
+	observed  map[string]observedFile
+}
+
+// ErrInvalidPollInterval is returned by NewDaemon when the configured poll interval is not positive
+var ErrInvalidPollInterval = errors.New("drop folder poll interval must be greater than zero")
+
+// NewDaemon creates a new drop folder daemon
+func NewDaemon(cfg config.Configuration, db database.Database, ingestSchema upload.IngestSchema) (*Daemon, error) {
+	if cfg.DropFolder.PollInterval <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidPollInterval, cfg.DropFolder.PollInterval)
+	}
+
+	return &Daemon{
+		exitC:     make(chan struct{}),
+		db:        db,
+		cfg:       cfg,
+		validator: upload.NewIngestValidator(ingestSchema),
+		observed:  map[string]observedFile{},
+	}, nil
+}
+
+// Name returns the name of the daemon
+func (s *Daemon) Name() string {
+	return "Drop Folder Daemon"
+}
+
+// Start begins the daemon and waits for a stop signal in the exit channel
+func (s *Daemon) Start(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(s.cfg.DropFolder.PollInterval) * time.Second)
+
+	defer close(s.exitC)
+	defer ticker.Stop()
+
+	if err := s.createDirectories(); err != nil {
+		slog.ErrorContext(ctx, fmt.Sprintf("Drop folder %s is unavailable: %v", s.cfg.DropFolder.Directory, err))
+		<-s.exitC
+		return
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			s.Scan(ctx)
+
+		case <-s.exitC:
+			return
+		}
+	}
+}
+
+// Stop passes in a stop signal to the exit channel, thereby killing the daemon
+func (s *Daemon) Stop(ctx context.Context) error {
+	s.exitC <- struct{}{}
+
+	select {
+	case <-s.exitC:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+func (s *Daemon) createDirectories() error {
+	for _, name := range []string{DoneDirectoryName, FailedDirectoryName} {
+		if err := os.MkdirAll(filepath.Join(s.cfg.DropFolder.Directory, name), 0755); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// stableFiles lists the files in the drop folder that have not changed since the previous scan and have gone
+// unmodified for at least the configured period. Collectors writing to a network share give no signal that a file is
+// complete, so a file is only trusted once it stops changing.
+func (s *Daemon) stableFiles() ([]string, error) {
+	var (
+		stableAfter = time.Duration(s.cfg.DropFolder.StableAfter) * time.Second
+		observed    = map[string]observedFile{}
+		stable      []string
+	)
+
+	entries, err := os.ReadDir(s.cfg.DropFolder.Directory)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		} else if _, ok := upload.FileTypeFromName(entry.Name()); !ok {
+			continue
+		} else if info, err := entry.Info(); errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		} else {
+			current := observedFile{size: info.Size(), modTime: info.ModTime()}
+
+			if previous, seen := s.observed[entry.Name()]; seen && previous == current && time.Since(current.modTime) >= stableAfter {
+				stable = append(stable, entry.Name())
+			} else {
+				observed[entry.Name()] = current
+			}
+		}
+	}
+
+	// Files that were picked up or have disappeared are no longer tracked
+	s.observed = observed
+	return stable, nil
+}
+
+// Scan submits every stable file in the drop folder as part of a single ingest job owned by the configured service
+// user. Files that pass validation are copied to the ingest work directory and moved to the done folder; files that
+// fail validation are moved to the failed folder. Files are left in place to be retried on the next scan if the job
+// could not be created.
+func (s *Daemon) Scan(ctx context.Context) {
+	var ingestJob model.IngestJob
+
+	if names, err := s.stableFiles(); err != nil {
+		slog.ErrorContext(ctx, fmt.Sprintf("Error reading drop folder %s: %v", s.cfg.DropFolder.Directory, err))
+	} else if len(names) == 0 {
+		return
+	} else if user, err := s.db.LookupUser(ctx, s.cfg.DropFolder.ServiceUser); err != nil {
+		slog.ErrorContext(ctx, fmt.Sprintf("Error looking up drop folder service user %s: %v", s.cfg.DropFolder.ServiceUser, err))
+	} else if ingestJob, err = job.StartIngestJob(ctx, s.db, user, model.AuthoritativeModeNone, ""); err != nil {
+		slog.ErrorContext(ctx, fmt.Sprintf("Error starting ingest job for drop folder: %v", err))
+	} else {
+		for _, name := range names {
+			s.submitFile(ctx, ingestJob, name)
+		}
+
+		if err := job.EndIngestJob(ctx, s.db, ingestJob); err != nil {
+			slog.ErrorContext(ctx, fmt.Sprintf("Error ending drop folder ingest job %d: %v", ingestJob.ID, err))
+		}
+	}
+}
+
+func (s *Daemon) submitFile(ctx context.Context, ingestJob model.IngestJob, name string) {
+	var (
+		path        = filepath.Join(s.cfg.DropFolder.Directory, name)
+		fileType, _ = upload.FileTypeFromName(name)
+	)
+
+	if tempFileName, err := s.copyAndValidate(path, fileType); err != nil {
+		slog.WarnContext(ctx, fmt.Sprintf("Drop folder file %s failed validation: %v", name, err))
+		s.moveFile(ctx, name, FailedDirectoryName)
+	} else if _, err := upload.CreateIngestTask(ctx, s.db, upload.IngestTaskParams{Filename: tempFileName, OriginalFilename: name, FileType: fileType, JobID: ingestJob.ID, Environment: ingestJob.Environment}); err != nil {
+		slog.ErrorContext(ctx, fmt.Sprintf("Error creating ingest task for drop folder file %s: %v", name, err))
+
+		if err := os.Remove(tempFileName); err != nil {
+			slog.ErrorContext(ctx, fmt.Sprintf("Error deleting ingest file %s: %v", tempFileName, err))
+		}
+	} else {
+		slog.InfoContext(ctx, fmt.Sprintf("Submitted drop folder file %s to ingest job %d", name, ingestJob.ID))
+		s.moveFile(ctx, name, DoneDirectoryName)
+	}
+}
+
+func (s *Daemon) copyAndValidate(path string, fileType model.FileType) (string, error) {
+	if file, err := os.Open(path); err != nil {
+		return "", err
+	} else {
+		defer file.Close()
+		return upload.WriteAndValidateFile(file, s.cfg.TempDirectory(), s.validator.FileValidatorFor(fileType))
+	}
+}
+
+// moveFile moves a file out of the drop folder into one of its subfolders. A timestamp is prepended to the name if a
+// file with the same name was moved there previously.
+func (s *Daemon) moveFile(ctx context.Context, name, directory string) {
+	var (
+		source      = filepath.Join(s.cfg.DropFolder.Directory, name)
+		destination = filepath.Join(s.cfg.DropFolder.Directory, directory, name)
+	)
+
+	if _, err := os.Stat(destination); err == nil {
+		destination = filepath.Join(s.cfg.DropFolder.Directory, directory, fmt.Sprintf("%s-%s", time.Now().UTC().Format("20060102T150405.000Z"), name))
+	}
+
+	if err := os.Rename(source, destination); err != nil {
+		slog.ErrorContext(ctx, fmt.Sprintf("Error moving drop folder file %s to %s: %v", source, destination, err))
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package dropfolder_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/specterops/bloodhound/cmd/api/src/config"
+	"github.com/specterops/bloodhound/cmd/api/src/daemons/dropfolder"
+	"github.com/specterops/bloodhound/cmd/api/src/database/mocks"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/services/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const validPayload = `{"meta": {"type": "domains", "version": 5, "count": 1}, "data": [{"domain": "example.com"}]}`
+
+func newTestDaemon(t *testing.T, db *mocks.MockDatabase) (*dropfolder.Daemon, config.Configuration) {
+	t.Helper()
+
+	schema, err := upload.LoadIngestSchema()
+	require.NoError(t, err)
+
+	cfg := config.Configuration{
+		WorkDir: t.TempDir(),
+		DropFolder: config.DropFolderConfiguration{
+			Directory:    t.TempDir(),
+			ServiceUser:  "collector",
+			PollInterval: 10,
+			StableAfter:  60,
+		},
+	}
+
+	require.NoError(t, os.Mkdir(cfg.TempDirectory(), 0755))
+	for _, name := range []string{dropfolder.DoneDirectoryName, dropfolder.FailedDirectoryName} {
+		require.NoError(t, os.Mkdir(filepath.Join(cfg.DropFolder.Directory, name), 0755))
+	}
+
+	daemon, err := dropfolder.NewDaemon(cfg, db, schema)
+	require.NoError(t, err)
+
+	return daemon, cfg
+}
+
+// writeDropFile writes a file to the drop folder with a modification time age in the past
+func writeDropFile(t *testing.T, cfg config.Configuration, name, content string, age time.Duration) {
+	t.Helper()
+
+	var (
+		path    = filepath.Join(cfg.DropFolder.Directory, name)
+		modTime = time.Now().Add(-age)
+	)
+
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestDropFolder_Name(t *testing.T) {
+	daemon, _ := newTestDaemon(t, mocks.NewMockDatabase(gomock.NewController(t)))
+	require.Equal(t, "Drop Folder Daemon", daemon.Name())
+}
+
+func TestDropFolder_NewDaemon_InvalidPollInterval(t *testing.T) {
+	schema, err := upload.LoadIngestSchema()
+	require.NoError(t, err)
+
+	for _, pollInterval := range []int{0, -1} {
+		cfg := config.Configuration{
+			DropFolder: config.DropFolderConfiguration{
+				Directory:    t.TempDir(),
+				PollInterval: pollInterval,
+			},
+		}
+
+		_, err := dropfolder.NewDaemon(cfg, mocks.NewMockDatabase(gomock.NewController(t)), schema)
+		assert.ErrorIs(t, err, dropfolder.ErrInvalidPollInterval)
+	}
+}
+
+func TestDropFolder_Scan(t *testing.T) {
+	t.Run("stable files are submitted to a single job", func(t *testing.T) {
+		var (
+			mockDB      = mocks.NewMockDatabase(gomock.NewController(t))
+			daemon, cfg = newTestDaemon(t, mockDB)
+			user        = model.User{PrincipalName: "collector"}
+		)
+
+		writeDropFile(t, cfg, "domains.json", validPayload, time.Hour)
+		writeDropFile(t, cfg, "invalid.json", `{"meta": {"type": "domains"`, time.Hour)
+		writeDropFile(t, cfg, "writing.json", validPayload, 0)
+		writeDropFile(t, cfg, "notes.txt", "ignored", time.Hour)
+
+		// The first scan only records the files it has seen
+		daemon.Scan(context.Background())
+
+		mockDB.EXPECT().LookupUser(gomock.Any(), "collector").Return(user, nil)
+		mockDB.EXPECT().CreateIngestJob(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, job model.IngestJob) (model.IngestJob, error) {
+			assert.Equal(t, model.JobStatusRunning, job.Status)
+			assert.Equal(t, user, job.User)
+			job.ID = 7
+			return job, nil
+		})
+		mockDB.EXPECT().CreateIngestTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, task model.IngestTask) (model.IngestTask, error) {
+			assert.Equal(t, int64(7), task.JobId.ValueOrZero())
+			assert.Equal(t, model.FileTypeJson, task.FileType)
+
+			content, err := os.ReadFile(task.FileName)
+			require.NoError(t, err)
+			assert.Equal(t, validPayload, string(content))
+			return task, nil
+		})
+		mockDB.EXPECT().UpdateIngestJob(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, job model.IngestJob) error {
+			assert.Equal(t, model.JobStatusIngesting, job.Status)
+			return nil
+		})
+
+		daemon.Scan(context.Background())
+
+		assert.FileExists(t, filepath.Join(cfg.DropFolder.Directory, dropfolder.DoneDirectoryName, "domains.json"))
+		assert.FileExists(t, filepath.Join(cfg.DropFolder.Directory, dropfolder.FailedDirectoryName, "invalid.json"))
+		assert.FileExists(t, filepath.Join(cfg.DropFolder.Directory, "writing.json"))
+		assert.FileExists(t, filepath.Join(cfg.DropFolder.Directory, "notes.txt"))
+	})
+
+	t.Run("files are left in place when the service user cannot be found", func(t *testing.T) {
+		var (
+			mockDB      = mocks.NewMockDatabase(gomock.NewController(t))
+			daemon, cfg = newTestDaemon(t, mockDB)
+		)
+
+		writeDropFile(t, cfg, "domains.json", validPayload, time.Hour)
+		daemon.Scan(context.Background())
+
+		mockDB.EXPECT().LookupUser(gomock.Any(), "collector").Return(model.User{}, errors.New("not found"))
+		daemon.Scan(context.Background())
+
+		assert.FileExists(t, filepath.Join(cfg.DropFolder.Directory, "domains.json"))
+	})
+
+	t.Run("files that change between scans are not submitted", func(t *testing.T) {
+		var (
+			mockDB      = mocks.NewMockDatabase(gomock.NewController(t))
+			daemon, cfg = newTestDaemon(t, mockDB)
+		)
+
+		writeDropFile(t, cfg, "domains.json", validPayload[:10], time.Hour)
+		daemon.Scan(context.Background())
+
+		writeDropFile(t, cfg, "domains.json", validPayload, time.Hour)
+		daemon.Scan(context.Background())
+
+		assert.FileExists(t, filepath.Join(cfg.DropFolder.Directory, "domains.json"))
+	})
+}
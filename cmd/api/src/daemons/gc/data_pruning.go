@@ -21,19 +21,22 @@ import (
 	"time"
 
 	"github.com/specterops/bloodhound/cmd/api/src/database"
+	"github.com/specterops/dawgs/graph"
 )
 
 // Daemon holds data relevant to the data daemon
 type Daemon struct {
-	exitC chan struct{}
-	db    database.Database
+	exitC   chan struct{}
+	db      database.Database
+	graphdb graph.Database
 }
 
 // NewDataPruningDaemon creates a new data pruning daemon
-func NewDataPruningDaemon(db database.Database) *Daemon {
+func NewDataPruningDaemon(db database.Database, graphdb graph.Database) *Daemon {
 	return &Daemon{
-		exitC: make(chan struct{}),
-		db:    db,
+		exitC:   make(chan struct{}),
+		db:      db,
+		graphdb: graphdb,
 	}
 }
 
@@ -49,9 +52,10 @@ func (s *Daemon) Start(ctx context.Context) {
 	defer close(s.exitC)
 	defer ticker.Stop()
 
-	// prune sessions and collections once when the daemon starts up
+	// prune sessions, collections and expired source kind data once when the daemon starts up
 	s.db.SweepSessions(ctx)
 	s.db.SweepAssetGroupCollections(ctx)
+	s.PruneExpiredSourceKindData(ctx)
 
 	// thereafter, prune conditionally once a day
 	for {
@@ -59,6 +63,7 @@ func (s *Daemon) Start(ctx context.Context) {
 		case <-ticker.C:
 			s.db.SweepSessions(ctx)
 			s.db.SweepAssetGroupCollections(ctx)
+			s.PruneExpiredSourceKindData(ctx)
 
 		case <-s.exitC:
 			return
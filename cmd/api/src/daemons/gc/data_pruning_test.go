@@ -21,7 +21,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/specterops/bloodhound/cmd/api/src/database"
 	"github.com/specterops/bloodhound/cmd/api/src/database/mocks"
+	graphmocks "github.com/specterops/bloodhound/cmd/api/src/vendormocks/dawgs/graph"
+	"github.com/specterops/dawgs/graph"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
@@ -30,7 +33,7 @@ func TestGC_NewDataPruningDaemon(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
-	daemon := NewDataPruningDaemon(mocks.NewMockDatabase(mockCtrl))
+	daemon := NewDataPruningDaemon(mocks.NewMockDatabase(mockCtrl), graphmocks.NewMockDatabase(mockCtrl))
 	require.NotNil(t, daemon)
 }
 
@@ -38,7 +41,7 @@ func TestGC_Name(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
-	daemon := NewDataPruningDaemon(mocks.NewMockDatabase(mockCtrl))
+	daemon := NewDataPruningDaemon(mocks.NewMockDatabase(mockCtrl), graphmocks.NewMockDatabase(mockCtrl))
 	require.NotNil(t, daemon)
 
 	result := daemon.Name()
@@ -58,8 +61,9 @@ func TestGC_Start(t *testing.T) {
 	mockDB.EXPECT().SweepAssetGroupCollections(gomock.Any()).Do(func(ctx context.Context) {
 		time.Sleep(1 * time.Millisecond)
 	})
+	mockDB.EXPECT().GetSourceKinds(gomock.Any()).Return([]database.SourceKind{{ID: 1, Name: graph.StringKind("Base")}}, nil)
 
-	daemon := NewDataPruningDaemon(mockDB)
+	daemon := NewDataPruningDaemon(mockDB, graphmocks.NewMockDatabase(mockCtrl))
 	require.NotNil(t, daemon)
 
 	go func() {
@@ -0,0 +1,97 @@
+// Copyright 2023 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/specterops/bloodhound/cmd/api/src/database"
+	"github.com/specterops/bloodhound/packages/go/graphschema/common"
+	"github.com/specterops/dawgs/graph"
+	"github.com/specterops/dawgs/query"
+)
+
+// expiredSourceKindNodes matches the nodes of a source kind that have not been seen by an ingest since the cutoff.
+// Nodes without a last seen time are never matched.
+func expiredSourceKindNodes(sourceKind graph.Kind, cutoff time.Time) graph.Criteria {
+	return query.And(
+		query.Kind(query.Node(), sourceKind),
+		query.Not(query.Kind(query.Node(), common.MigrationData)),
+		query.LessThan(query.NodeProperty(common.LastSeen.String()), cutoff),
+	)
+}
+
+// PruneExpiredSourceKindData deletes the nodes of each source kind with a retention period that have not been seen by
+// an ingest within that period, along with their relationships. Analysis is requested if anything was deleted so that
+// post-processed relationships are rebuilt without the deleted nodes.
+func (s *Daemon) PruneExpiredSourceKindData(ctx context.Context) {
+	sourceKinds, err := s.db.GetSourceKinds(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, fmt.Sprintf("Failed to fetch source kinds for retention: %v", err))
+		return
+	}
+
+	var pruned int64
+
+	for _, sourceKind := range sourceKinds {
+		if sourceKind.RetentionDays <= 0 {
+			continue
+		}
+
+		count, err := s.pruneSourceKindData(ctx, sourceKind, time.Now().UTC().AddDate(0, 0, -sourceKind.RetentionDays))
+		if err != nil {
+			slog.ErrorContext(ctx, fmt.Sprintf("Failed to prune expired nodes of source kind %s: %v", sourceKind.Name, err))
+			continue
+		} else if count > 0 {
+			slog.InfoContext(ctx, fmt.Sprintf("Pruned %d node(s) of source kind %s not seen in the last %d day(s)", count, sourceKind.Name, sourceKind.RetentionDays))
+		}
+
+		pruned += count
+	}
+
+	if pruned > 0 {
+		if err := s.db.RequestAnalysis(ctx, "gc"); err != nil {
+			slog.ErrorContext(ctx, fmt.Sprintf("Failed to request analysis after pruning expired source kind data: %v", err))
+		}
+	}
+}
+
+func (s *Daemon) pruneSourceKindData(ctx context.Context, sourceKind database.SourceKind, cutoff time.Time) (int64, error) {
+	var count int64
+
+	return count, s.graphdb.WriteTransaction(ctx, func(tx graph.Transaction) error {
+		var (
+			err          error
+			expiredNodes = func() graph.Criteria {
+				return expiredSourceKindNodes(sourceKind.Name, cutoff)
+			}
+		)
+
+		if count, err = tx.Nodes().Filterf(expiredNodes).Count(); err != nil {
+			return fmt.Errorf("error counting expired nodes: %w", err)
+		} else if count == 0 {
+			return nil
+		} else if err := tx.Nodes().Filterf(expiredNodes).Delete(); err != nil {
+			return fmt.Errorf("error deleting expired nodes: %w", err)
+		}
+
+		return nil
+	})
+}
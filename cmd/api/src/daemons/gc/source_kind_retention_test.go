@@ -0,0 +1,99 @@
+// Copyright 2023 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/specterops/bloodhound/cmd/api/src/database"
+	"github.com/specterops/bloodhound/cmd/api/src/database/mocks"
+	graphmocks "github.com/specterops/bloodhound/cmd/api/src/vendormocks/dawgs/graph"
+	"github.com/specterops/dawgs/graph"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGC_PruneExpiredSourceKindData(t *testing.T) {
+	t.Run("source kinds without a retention period are not pruned", func(t *testing.T) {
+		var (
+			mockCtrl  = gomock.NewController(t)
+			mockDB    = mocks.NewMockDatabase(mockCtrl)
+			mockGraph = graphmocks.NewMockDatabase(mockCtrl)
+		)
+
+		mockDB.EXPECT().GetSourceKinds(gomock.Any()).Return([]database.SourceKind{{ID: 1, Name: graph.StringKind("Base")}}, nil)
+
+		NewDataPruningDaemon(mockDB, mockGraph).PruneExpiredSourceKindData(context.Background())
+	})
+
+	t.Run("expired nodes are deleted and analysis is requested", func(t *testing.T) {
+		var (
+			mockCtrl      = gomock.NewController(t)
+			mockDB        = mocks.NewMockDatabase(mockCtrl)
+			mockGraph     = graphmocks.NewMockDatabase(mockCtrl)
+			mockTx        = graphmocks.NewMockTransaction(mockCtrl)
+			mockNodeQuery = graphmocks.NewMockNodeQuery(mockCtrl)
+		)
+
+		mockDB.EXPECT().GetSourceKinds(gomock.Any()).Return([]database.SourceKind{
+			{ID: 1, Name: graph.StringKind("Base")},
+			{ID: 3, Name: graph.StringKind("GithubBase"), RetentionDays: 30},
+		}, nil)
+		mockGraph.EXPECT().WriteTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, delegate graph.TransactionDelegate, _ ...graph.TransactionOption) error {
+			return delegate(mockTx)
+		})
+		mockTx.EXPECT().Nodes().Return(mockNodeQuery).Times(2)
+		mockNodeQuery.EXPECT().Filterf(gomock.Any()).Return(mockNodeQuery).Times(2)
+		mockNodeQuery.EXPECT().Count().Return(int64(4), nil)
+		mockNodeQuery.EXPECT().Delete().Return(nil)
+		mockDB.EXPECT().RequestAnalysis(gomock.Any(), "gc").Return(nil)
+
+		NewDataPruningDaemon(mockDB, mockGraph).PruneExpiredSourceKindData(context.Background())
+	})
+
+	t.Run("nothing is deleted when no nodes have expired", func(t *testing.T) {
+		var (
+			mockCtrl      = gomock.NewController(t)
+			mockDB        = mocks.NewMockDatabase(mockCtrl)
+			mockGraph     = graphmocks.NewMockDatabase(mockCtrl)
+			mockTx        = graphmocks.NewMockTransaction(mockCtrl)
+			mockNodeQuery = graphmocks.NewMockNodeQuery(mockCtrl)
+		)
+
+		mockDB.EXPECT().GetSourceKinds(gomock.Any()).Return([]database.SourceKind{{ID: 3, Name: graph.StringKind("GithubBase"), RetentionDays: 30}}, nil)
+		mockGraph.EXPECT().WriteTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, delegate graph.TransactionDelegate, _ ...graph.TransactionOption) error {
+			return delegate(mockTx)
+		})
+		mockTx.EXPECT().Nodes().Return(mockNodeQuery)
+		mockNodeQuery.EXPECT().Filterf(gomock.Any()).Return(mockNodeQuery)
+		mockNodeQuery.EXPECT().Count().Return(int64(0), nil)
+
+		NewDataPruningDaemon(mockDB, mockGraph).PruneExpiredSourceKindData(context.Background())
+	})
+
+	t.Run("source kinds can not be fetched", func(t *testing.T) {
+		var (
+			mockCtrl = gomock.NewController(t)
+			mockDB   = mocks.NewMockDatabase(mockCtrl)
+		)
+
+		mockDB.EXPECT().GetSourceKinds(gomock.Any()).Return(nil, errors.New("database error"))
+
+		NewDataPruningDaemon(mockDB, graphmocks.NewMockDatabase(mockCtrl)).PruneExpiredSourceKindData(context.Background())
+	})
+}
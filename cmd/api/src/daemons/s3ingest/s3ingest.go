@@ -0,0 +1,267 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package s3ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/specterops/bloodhound/cmd/api/src/config"
+	"github.com/specterops/bloodhound/cmd/api/src/database"
+	"github.com/specterops/bloodhound/cmd/api/src/database/types/null"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/services/job"
+	"github.com/specterops/bloodhound/cmd/api/src/services/objectstore"
+	"github.com/specterops/bloodhound/cmd/api/src/services/upload"
+)
+
+// IngestJobTagKey is the tag set on objects after they are submitted for ingest when objects are configured to be
+// tagged. Its value is the ID of the ingest job.
+const IngestJobTagKey = "bloodhound-ingest-job"
+
+var (
+	errDownloadObject = errors.New("error downloading object")
+
+	ErrInvalidPollInterval   = errors.New("S3 ingest poll interval must be greater than zero")
+	ErrInvalidRequestTimeout = errors.New("S3 ingest request timeout must be greater than zero")
+)
+
+// Daemon periodically pulls new collection files from an S3-compatible bucket and submits them as ingest jobs
+type Daemon struct {
+	exitC     chan struct{}
+	db        database.Database
+	cfg       config.Configuration
+	client    *objectstore.S3Client
+	validator upload.IngestValidator
+}
+
+// NewDaemon creates a new S3 ingest daemon. The poll interval and request timeout configured must both be positive;
+// httpClient is expected to enforce the request timeout.
+func NewDaemon(cfg config.Configuration, db database.Database, ingestSchema upload.IngestSchema, httpClient *http.Client) (*Daemon, error) {
+	if cfg.S3Ingest.PollInterval <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidPollInterval, cfg.S3Ingest.PollInterval)
+	} else if cfg.S3Ingest.RequestTimeout <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidRequestTimeout, cfg.S3Ingest.RequestTimeout)
+	}
+
+	client, err := objectstore.NewS3Client(objectstore.Config{
+		Endpoint:     cfg.S3Ingest.Endpoint,
+		Region:       cfg.S3Ingest.Region,
+		Bucket:       cfg.S3Ingest.Bucket,
+		UsePathStyle: cfg.S3Ingest.UsePathStyle,
+		Credentials: objectstore.Credentials{
+			AccessKeyID:     cfg.S3Ingest.AccessKeyID,
+			SecretAccessKey: cfg.S3Ingest.SecretAccessKey,
+		},
+	}, httpClient)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Daemon{
+		exitC:     make(chan struct{}),
+		db:        db,
+		cfg:       cfg,
+		client:    client,
+		validator: upload.NewIngestValidator(ingestSchema),
+	}, nil
+}
+
+// Name returns the name of the daemon
+func (s *Daemon) Name() string {
+	return "S3 Ingest Daemon"
+}
+
+// Start begins the daemon and waits for a stop signal in the exit channel
+func (s *Daemon) Start(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(s.cfg.S3Ingest.PollInterval) * time.Second)
+
+	defer close(s.exitC)
+	defer ticker.Stop()
+
+	// scan once when the daemon starts up
+	s.Scan(ctx)
+
+	for {
+		select {
+		case <-ticker.C:
+			s.Scan(ctx)
+
+		case <-s.exitC:
+			return
+		}
+	}
+}
+
+// Stop passes in a stop signal to the exit channel, thereby killing the daemon
+func (s *Daemon) Stop(ctx context.Context) error {
+	s.exitC <- struct{}{}
+
+	select {
+	case <-s.exitC:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+func ingestedObjectID(key, etag string) string {
+	return key + "\x00" + etag
+}
+
+// pendingObjects lists the collection files under the configured prefix that have not been pulled at their current
+// ETag
+func (s *Daemon) pendingObjects(ctx context.Context) ([]objectstore.Object, error) {
+	var (
+		pending  []objectstore.Object
+		ingested = map[string]struct{}{}
+	)
+
+	if objects, err := s.client.ListObjects(ctx, s.cfg.S3Ingest.Prefix); err != nil {
+		return nil, err
+	} else if ingestedObjects, err := s.db.GetIngestedObjects(ctx, s.client.Bucket()); err != nil {
+		return nil, err
+	} else {
+		for _, ingestedObject := range ingestedObjects {
+			ingested[ingestedObjectID(ingestedObject.Key, ingestedObject.ETag)] = struct{}{}
+		}
+
+		for _, object := range objects {
+			if _, ok := upload.FileTypeFromName(object.Key); !ok || strings.HasSuffix(object.Key, "/") {
+				continue
+			} else if _, found := ingested[ingestedObjectID(object.Key, object.ETag)]; !found {
+				pending = append(pending, object)
+			}
+		}
+
+		return pending, nil
+	}
+}
+
+// Scan submits every new or changed collection file in the bucket as part of a single ingest job owned by the
+// configured service user. Objects that fail validation are recorded so they are not pulled again until they change.
+// Objects that could not be downloaded are retried on the next scan.
+func (s *Daemon) Scan(ctx context.Context) {
+	var ingestJob model.IngestJob
+
+	if objects, err := s.pendingObjects(ctx); err != nil {
+		slog.ErrorContext(ctx, fmt.Sprintf("Error listing objects in bucket %s: %v", s.client.Bucket(), err))
+	} else if len(objects) == 0 {
+		return
+	} else if user, err := s.db.LookupUser(ctx, s.cfg.S3Ingest.ServiceUser); err != nil {
+		slog.ErrorContext(ctx, fmt.Sprintf("Error looking up S3 ingest service user %s: %v", s.cfg.S3Ingest.ServiceUser, err))
+	} else if ingestJob, err = job.StartIngestJob(ctx, s.db, user, model.AuthoritativeModeNone, ""); err != nil {
+		slog.ErrorContext(ctx, fmt.Sprintf("Error starting ingest job for bucket %s: %v", s.client.Bucket(), err))
+	} else {
+		for _, object := range objects {
+			s.submitObject(ctx, ingestJob, object)
+		}
+
+		if err := job.EndIngestJob(ctx, s.db, ingestJob); err != nil {
+			slog.ErrorContext(ctx, fmt.Sprintf("Error ending S3 ingest job %d: %v", ingestJob.ID, err))
+		}
+	}
+}
+
+func (s *Daemon) submitObject(ctx context.Context, ingestJob model.IngestJob, object objectstore.Object) {
+	var (
+		fileType, _    = upload.FileTypeFromName(object.Key)
+		ingestedObject = model.IngestedObject{
+			Bucket: s.client.Bucket(),
+			Key:    object.Key,
+			ETag:   object.ETag,
+		}
+	)
+
+	if tempFileName, err := s.downloadAndValidate(ctx, object.Key, fileType); errors.Is(err, errDownloadObject) {
+		slog.ErrorContext(ctx, fmt.Sprintf("Error downloading object %s from bucket %s: %v", object.Key, s.client.Bucket(), err))
+		return
+	} else if err != nil {
+		slog.WarnContext(ctx, fmt.Sprintf("Object %s from bucket %s failed validation: %v", object.Key, s.client.Bucket(), err))
+		ingestedObject.Error = err.Error()
+	} else if _, err := upload.CreateIngestTask(ctx, s.db, upload.IngestTaskParams{Filename: tempFileName, OriginalFilename: path.Base(object.Key), FileType: fileType, JobID: ingestJob.ID, Environment: ingestJob.Environment}); err != nil {
+		slog.ErrorContext(ctx, fmt.Sprintf("Error creating ingest task for object %s: %v", object.Key, err))
+
+		if err := os.Remove(tempFileName); err != nil {
+			slog.ErrorContext(ctx, fmt.Sprintf("Error deleting ingest file %s: %v", tempFileName, err))
+		}
+		return
+	} else {
+		slog.InfoContext(ctx, fmt.Sprintf("Submitted object %s from bucket %s to ingest job %d", object.Key, s.client.Bucket(), ingestJob.ID))
+		ingestedObject.JobID = null.Int64From(ingestJob.ID)
+	}
+
+	if _, err := s.db.CreateIngestedObject(ctx, ingestedObject); err != nil {
+		slog.ErrorContext(ctx, fmt.Sprintf("Error recording ingested object %s: %v", object.Key, err))
+	} else if ingestedObject.JobID.Valid {
+		s.afterIngest(ctx, ingestJob, object.Key)
+	}
+}
+
+// downloadAndValidate copies an object into the ingest work directory. The object is downloaded in full before it is
+// validated so that a dropped connection is not mistaken for a bad file.
+func (s *Daemon) downloadAndValidate(ctx context.Context, key string, fileType model.FileType) (string, error) {
+	download, err := os.CreateTemp(s.cfg.TempDirectory(), "s3")
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errDownloadObject, err)
+	}
+
+	defer func() {
+		download.Close()
+		if err := os.Remove(download.Name()); err != nil {
+			slog.ErrorContext(ctx, fmt.Sprintf("Error deleting downloaded object %s: %v", download.Name(), err))
+		}
+	}()
+
+	if body, err := s.client.GetObject(ctx, key); err != nil {
+		return "", fmt.Errorf("%w: %w", errDownloadObject, err)
+	} else if _, err := io.Copy(download, body); err != nil {
+		body.Close()
+		return "", fmt.Errorf("%w: %w", errDownloadObject, err)
+	} else if err := body.Close(); err != nil {
+		return "", fmt.Errorf("%w: %w", errDownloadObject, err)
+	} else if _, err := download.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("%w: %w", errDownloadObject, err)
+	} else {
+		return upload.WriteAndValidateFile(download, s.cfg.TempDirectory(), s.validator.FileValidatorFor(fileType))
+	}
+}
+
+func (s *Daemon) afterIngest(ctx context.Context, ingestJob model.IngestJob, key string) {
+	switch s.cfg.S3Ingest.AfterIngest {
+	case config.S3AfterIngestDelete:
+		if err := s.client.DeleteObject(ctx, key); err != nil {
+			slog.ErrorContext(ctx, fmt.Sprintf("Error deleting ingested object %s: %v", key, err))
+		}
+
+	case config.S3AfterIngestTag:
+		if err := s.client.PutObjectTagging(ctx, key, map[string]string{IngestJobTagKey: strconv.FormatInt(ingestJob.ID, 10)}); err != nil {
+			slog.ErrorContext(ctx, fmt.Sprintf("Error tagging ingested object %s: %v", key, err))
+		}
+	}
+}
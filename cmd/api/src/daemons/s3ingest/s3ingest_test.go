@@ -0,0 +1,213 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package s3ingest_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/specterops/bloodhound/cmd/api/src/config"
+	"github.com/specterops/bloodhound/cmd/api/src/daemons/s3ingest"
+	"github.com/specterops/bloodhound/cmd/api/src/database/mocks"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/services/objectstore/objectstoretest"
+	"github.com/specterops/bloodhound/cmd/api/src/services/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const validPayload = `{"meta": {"type": "domains", "version": 5, "count": 1}, "data": [{"domain": "example.com"}]}`
+
+func newTestDaemon(t *testing.T, db *mocks.MockDatabase, server *objectstoretest.Server, afterIngest string) *s3ingest.Daemon {
+	t.Helper()
+
+	schema, err := upload.LoadIngestSchema()
+	require.NoError(t, err)
+
+	cfg := config.Configuration{
+		WorkDir: t.TempDir(),
+		S3Ingest: config.S3IngestConfiguration{
+			Endpoint:        server.URL,
+			Region:          "us-east-1",
+			Bucket:          "collections",
+			Prefix:          "sharphound/",
+			AccessKeyID:     "minio",
+			SecretAccessKey: "minio123",
+			UsePathStyle:    true,
+			ServiceUser:     "collector",
+			PollInterval:    300,
+			RequestTimeout:  600,
+			AfterIngest:     afterIngest,
+		},
+	}
+
+	require.NoError(t, os.Mkdir(cfg.TempDirectory(), 0755))
+
+	daemon, err := s3ingest.NewDaemon(cfg, db, schema, server.Client())
+	require.NoError(t, err)
+
+	return daemon
+}
+
+func TestS3Ingest_Name(t *testing.T) {
+	server := objectstoretest.NewServer("collections")
+	defer server.Close()
+
+	daemon := newTestDaemon(t, mocks.NewMockDatabase(gomock.NewController(t)), server, "")
+	require.Equal(t, "S3 Ingest Daemon", daemon.Name())
+}
+
+func TestS3Ingest_NewDaemon_InvalidConfiguration(t *testing.T) {
+	schema, err := upload.LoadIngestSchema()
+	require.NoError(t, err)
+
+	server := objectstoretest.NewServer("collections")
+	defer server.Close()
+
+	var (
+		db  = mocks.NewMockDatabase(gomock.NewController(t))
+		cfg = config.Configuration{
+			S3Ingest: config.S3IngestConfiguration{
+				Endpoint:       server.URL,
+				Region:         "us-east-1",
+				Bucket:         "collections",
+				PollInterval:   300,
+				RequestTimeout: 600,
+			},
+		}
+	)
+
+	t.Run("poll interval must be positive", func(t *testing.T) {
+		invalidCfg := cfg
+		invalidCfg.S3Ingest.PollInterval = 0
+
+		_, err := s3ingest.NewDaemon(invalidCfg, db, schema, server.Client())
+		assert.ErrorIs(t, err, s3ingest.ErrInvalidPollInterval)
+	})
+
+	t.Run("request timeout must be positive", func(t *testing.T) {
+		invalidCfg := cfg
+		invalidCfg.S3Ingest.RequestTimeout = -1
+
+		_, err := s3ingest.NewDaemon(invalidCfg, db, schema, server.Client())
+		assert.ErrorIs(t, err, s3ingest.ErrInvalidRequestTimeout)
+	})
+}
+
+func TestS3Ingest_Scan(t *testing.T) {
+	t.Run("new objects are submitted to a single job and tagged", func(t *testing.T) {
+		var (
+			server  = objectstoretest.NewServer("collections")
+			mockDB  = mocks.NewMockDatabase(gomock.NewController(t))
+			daemon  = newTestDaemon(t, mockDB, server, config.S3AfterIngestTag)
+			invalid = []byte(`{"meta": {"type": "domains"`)
+		)
+
+		defer server.Close()
+
+		server.PutObject("sharphound/domains.json", []byte(validPayload))
+		server.PutObject("sharphound/invalid.json", invalid)
+		server.PutObject("sharphound/seen.json", []byte(validPayload))
+		server.PutObject("sharphound/notes.txt", []byte("ignored"))
+		server.PutObject("azurehound/outside-prefix.json", []byte(validPayload))
+
+		mockDB.EXPECT().GetIngestedObjects(gomock.Any(), "collections").Return(model.IngestedObjects{
+			{Bucket: "collections", Key: "sharphound/seen.json", ETag: objectstoretest.ETag([]byte(validPayload))},
+		}, nil)
+		mockDB.EXPECT().LookupUser(gomock.Any(), "collector").Return(model.User{PrincipalName: "collector"}, nil)
+		mockDB.EXPECT().CreateIngestJob(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, job model.IngestJob) (model.IngestJob, error) {
+			job.ID = 3
+			return job, nil
+		})
+		mockDB.EXPECT().CreateIngestTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, task model.IngestTask) (model.IngestTask, error) {
+			assert.Equal(t, int64(3), task.JobId.ValueOrZero())
+
+			content, err := os.ReadFile(task.FileName)
+			require.NoError(t, err)
+			assert.Equal(t, validPayload, string(content))
+			return task, nil
+		})
+		mockDB.EXPECT().CreateIngestedObject(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, object model.IngestedObject) (model.IngestedObject, error) {
+			assert.Equal(t, "sharphound/domains.json", object.Key)
+			assert.Equal(t, objectstoretest.ETag([]byte(validPayload)), object.ETag)
+			assert.Equal(t, int64(3), object.JobID.ValueOrZero())
+			assert.Empty(t, object.Error)
+			return object, nil
+		})
+		mockDB.EXPECT().CreateIngestedObject(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, object model.IngestedObject) (model.IngestedObject, error) {
+			assert.Equal(t, "sharphound/invalid.json", object.Key)
+			assert.Equal(t, objectstoretest.ETag(invalid), object.ETag)
+			assert.False(t, object.JobID.Valid)
+			assert.NotEmpty(t, object.Error)
+			return object, nil
+		})
+		mockDB.EXPECT().UpdateIngestJob(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, job model.IngestJob) error {
+			assert.Equal(t, model.JobStatusIngesting, job.Status)
+			return nil
+		})
+
+		daemon.Scan(context.Background())
+
+		assert.Equal(t, map[string]string{s3ingest.IngestJobTagKey: "3"}, server.Tags("sharphound/domains.json"))
+		assert.Empty(t, server.Tags("sharphound/invalid.json"))
+	})
+
+	t.Run("submitted objects are deleted", func(t *testing.T) {
+		var (
+			server = objectstoretest.NewServer("collections")
+			mockDB = mocks.NewMockDatabase(gomock.NewController(t))
+			daemon = newTestDaemon(t, mockDB, server, config.S3AfterIngestDelete)
+		)
+
+		defer server.Close()
+
+		server.PutObject("sharphound/domains.json", []byte(validPayload))
+
+		mockDB.EXPECT().GetIngestedObjects(gomock.Any(), "collections").Return(model.IngestedObjects{}, nil)
+		mockDB.EXPECT().LookupUser(gomock.Any(), "collector").Return(model.User{}, nil)
+		mockDB.EXPECT().CreateIngestJob(gomock.Any(), gomock.Any()).Return(model.IngestJob{}, nil)
+		mockDB.EXPECT().CreateIngestTask(gomock.Any(), gomock.Any()).Return(model.IngestTask{}, nil)
+		mockDB.EXPECT().CreateIngestedObject(gomock.Any(), gomock.Any()).Return(model.IngestedObject{}, nil)
+		mockDB.EXPECT().UpdateIngestJob(gomock.Any(), gomock.Any()).Return(nil)
+
+		daemon.Scan(context.Background())
+
+		assert.False(t, server.HasObject("sharphound/domains.json"))
+	})
+
+	t.Run("no job is started when there is nothing new", func(t *testing.T) {
+		var (
+			server = objectstoretest.NewServer("collections")
+			mockDB = mocks.NewMockDatabase(gomock.NewController(t))
+			daemon = newTestDaemon(t, mockDB, server, "")
+		)
+
+		defer server.Close()
+
+		server.PutObject("sharphound/domains.json", []byte(validPayload))
+
+		mockDB.EXPECT().GetIngestedObjects(gomock.Any(), "collections").Return(model.IngestedObjects{
+			{Bucket: "collections", Key: "sharphound/domains.json", ETag: objectstoretest.ETag([]byte(validPayload))},
+		}, nil)
+
+		daemon.Scan(context.Background())
+
+		assert.True(t, server.HasObject("sharphound/domains.json"))
+	})
+}
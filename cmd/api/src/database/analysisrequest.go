@@ -88,6 +88,7 @@ func (s *BloodhoundDB) setAnalysisRequest(ctx context.Context, request model.Ana
 			request.DeleteAllGraph,
 			request.DeleteSourcelessGraph,
 			pq.StringArray(request.DeleteSourceKinds),
+			pq.StringArray(request.DeleteEnvironments),
 		}
 
 		insertSQL = `
@@ -97,9 +98,10 @@ func (s *BloodhoundDB) setAnalysisRequest(ctx context.Context, request model.Ana
 			requested_at,
 			delete_all_graph,
 			delete_sourceless_graph,
-			delete_source_kinds
+			delete_source_kinds,
+			delete_environments
 		)
-		VALUES (?, ?, ?, ?, ?, ?::text[]);`
+		VALUES (?, ?, ?, ?, ?, ?::text[], ?::text[]);`
 		updateSQL = `UPDATE analysis_request_switch
 		SET
 			requested_by = ?,
@@ -107,7 +109,8 @@ func (s *BloodhoundDB) setAnalysisRequest(ctx context.Context, request model.Ana
 			requested_at = ?,
 			delete_all_graph = ?,
 			delete_sourceless_graph = ?,
-			delete_source_kinds = ?::text[];`
+			delete_source_kinds = ?::text[],
+			delete_environments = ?::text[];`
 	)
 	if analysisRequest, err := s.GetAnalysisRequest(ctx); err != nil && !errors.Is(err, ErrNotFound) {
 		return err
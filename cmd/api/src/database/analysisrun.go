@@ -0,0 +1,69 @@
+// Copyright 2023 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package database
+
+import (
+	"context"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"gorm.io/gorm"
+)
+
+type AnalysisRunData interface {
+	CreateAnalysisRun(ctx context.Context, run model.AnalysisRun) (model.AnalysisRun, error)
+	UpdateAnalysisRun(ctx context.Context, run model.AnalysisRun) error
+	GetAnalysisRun(ctx context.Context, id int64) (model.AnalysisRun, error)
+	GetAnalysisRuns(ctx context.Context, skip int, limit int) (model.AnalysisRuns, int, error)
+}
+
+func (s *BloodhoundDB) CreateAnalysisRun(ctx context.Context, run model.AnalysisRun) (model.AnalysisRun, error) {
+	result := s.db.WithContext(ctx).Create(&run)
+	return run, CheckError(result)
+}
+
+// UpdateAnalysisRun saves the run given along with the steps it holds that are not saved yet
+func (s *BloodhoundDB) UpdateAnalysisRun(ctx context.Context, run model.AnalysisRun) error {
+	result := s.db.WithContext(ctx).Save(&run)
+	return CheckError(result)
+}
+
+func (s *BloodhoundDB) GetAnalysisRun(ctx context.Context, id int64) (model.AnalysisRun, error) {
+	var run model.AnalysisRun
+	result := s.db.WithContext(ctx).Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).First(&run, id)
+
+	return run, CheckError(result)
+}
+
+// GetAnalysisRuns returns a page of analysis runs along with their steps, most recent first
+func (s *BloodhoundDB) GetAnalysisRuns(ctx context.Context, skip int, limit int) (model.AnalysisRuns, int, error) {
+	var (
+		runs  model.AnalysisRuns
+		count int64
+	)
+
+	if result := s.db.Model(model.AnalysisRun{}).WithContext(ctx).Count(&count); result.Error != nil {
+		return nil, 0, CheckError(result)
+	}
+
+	result := s.Scope(Paginate(skip, limit)).WithContext(ctx).Preload("Steps", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id")
+	}).Order("started_at desc, id desc").Find(&runs)
+
+	return runs, int(count), CheckError(result)
+}
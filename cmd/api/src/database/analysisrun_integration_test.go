@@ -0,0 +1,76 @@
+// Copyright 2023 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+// +build integration
+
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/specterops/bloodhound/cmd/api/src/database/types"
+	"github.com/specterops/bloodhound/cmd/api/src/database/types/null"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/test/integration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalysisRuns(t *testing.T) {
+	var (
+		testCtx   = context.Background()
+		db        = integration.SetupDB(t)
+		startedAt = time.Now().UTC()
+	)
+
+	first, err := db.CreateAnalysisRun(testCtx, model.AnalysisRun{StartedAt: startedAt.Add(-time.Hour), Status: model.AnalysisRunStatusRunning})
+	require.Nil(t, err)
+
+	second, err := db.CreateAnalysisRun(testCtx, model.AnalysisRun{StartedAt: startedAt, Status: model.AnalysisRunStatusRunning, Scoped: true})
+	require.Nil(t, err)
+
+	second.Status = model.AnalysisRunStatusComplete
+	second.EndedAt = null.TimeFrom(startedAt.Add(time.Minute))
+	second.Steps = model.AnalysisRunSteps{{
+		RunID:                second.ID,
+		Name:                 "adcs",
+		Status:               model.AnalysisRunStepStatusComplete,
+		DurationMilliseconds: 3000,
+		RelationshipsCreated: types.JSONBInt64Object{"ADCSESC1": 12},
+		RelationshipsDeleted: types.JSONBInt64Object{"ADCSESC1": 2000},
+	}}
+	require.Nil(t, db.UpdateAnalysisRun(testCtx, second))
+
+	run, err := db.GetAnalysisRun(testCtx, second.ID)
+	require.Nil(t, err)
+	assert.Equal(t, model.AnalysisRunStatusComplete, run.Status)
+	assert.True(t, run.EndedAt.Valid)
+	require.Len(t, run.Steps, 1)
+	assert.Equal(t, int64(12), run.Steps[0].RelationshipsCreated["ADCSESC1"])
+	assert.Equal(t, int64(2000), run.Steps[0].RelationshipsDeleted["ADCSESC1"])
+
+	runs, count, err := db.GetAnalysisRuns(testCtx, 0, 10)
+	require.Nil(t, err)
+	assert.Equal(t, 2, count)
+	require.Len(t, runs, 2)
+	assert.Equal(t, second.ID, runs[0].ID)
+	assert.Len(t, runs[0].Steps, 1)
+	assert.Equal(t, first.ID, runs[1].ID)
+	assert.Empty(t, runs[1].Steps)
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/specterops/bloodhound/cmd/api/src/database/types/null"
 	"github.com/specterops/bloodhound/cmd/api/src/model"
 )
 
@@ -27,6 +28,9 @@ type DatapipeStatusData interface {
 	UpdateLastAnalysisCompleteTime(ctx context.Context) error
 	SetDatapipeStatus(ctx context.Context, status model.DatapipeStatus) error
 	GetDatapipeStatus(ctx context.Context) (model.DatapipeStatusWrapper, error)
+	SetNextScheduledAnalysisTime(ctx context.Context, nextRun null.Time) error
+	RequestDatapipeCancel(ctx context.Context, status model.DatapipeStatus) (bool, error)
+	IsDatapipeCancelRequested(ctx context.Context) (bool, error)
 }
 
 func (s *BloodhoundDB) UpdateLastAnalysisCompleteTime(ctx context.Context) error {
@@ -36,8 +40,9 @@ func (s *BloodhoundDB) UpdateLastAnalysisCompleteTime(ctx context.Context) error
 
 func (s *BloodhoundDB) SetDatapipeStatus(ctx context.Context, status model.DatapipeStatus) error {
 	now := time.Now().UTC()
-	// All queries will update the status and table update time
-	updateSql := "UPDATE datapipe_status SET status = ?, updated_at = ?"
+	// All queries will update the status and table update time, and clear any cancellation requested for the previous
+	// status
+	updateSql := "UPDATE datapipe_status SET status = ?, updated_at = ?, cancel_requested = false"
 
 	if status == model.DatapipeStatusAnalyzing {
 		// Updates last run anytime we start analysis
@@ -53,7 +58,25 @@ func (s *BloodhoundDB) SetDatapipeStatus(ctx context.Context, status model.Datap
 func (s *BloodhoundDB) GetDatapipeStatus(ctx context.Context) (model.DatapipeStatusWrapper, error) {
 	var datapipeStatus model.DatapipeStatusWrapper
 
-	tx := s.db.WithContext(ctx).Select("status, updated_at, last_complete_analysis_at, last_analysis_run_at").Table("datapipe_status").First(&datapipeStatus)
+	tx := s.db.WithContext(ctx).Select("status, updated_at, last_complete_analysis_at, last_analysis_run_at, next_scheduled_analysis_at, cancel_requested").Table("datapipe_status").First(&datapipeStatus)
 
 	return datapipeStatus, CheckError(tx)
 }
+
+// SetNextScheduledAnalysisTime records the next occurrence of the analysis schedule, or clears it when nextRun is null
+func (s *BloodhoundDB) SetNextScheduledAnalysisTime(ctx context.Context, nextRun null.Time) error {
+	return s.db.WithContext(ctx).Exec("UPDATE datapipe_status SET next_scheduled_analysis_at = ?", nextRun).Error
+}
+
+// RequestDatapipeCancel asks the datapipe to stop the step it is running, provided it is still in the status given. It
+// reports whether the datapipe was in that status.
+func (s *BloodhoundDB) RequestDatapipeCancel(ctx context.Context, status model.DatapipeStatus) (bool, error) {
+	result := s.db.WithContext(ctx).Exec("UPDATE datapipe_status SET cancel_requested = true, updated_at = ? WHERE status = ?", time.Now().UTC(), status)
+	return result.RowsAffected > 0, result.Error
+}
+
+// IsDatapipeCancelRequested reports whether the datapipe was asked to stop the step it is running
+func (s *BloodhoundDB) IsDatapipeCancelRequested(ctx context.Context) (bool, error) {
+	var cancelRequested bool
+	return cancelRequested, s.db.WithContext(ctx).Raw("SELECT cancel_requested FROM datapipe_status").Scan(&cancelRequested).Error
+}
@@ -56,3 +56,34 @@ func TestDatapipeStatus(t *testing.T) {
 	require.Nil(t, err)
 	assert.True(t, !status.LastCompleteAnalysisAt.IsZero())
 }
+
+func TestDatapipeStatus_Cancel(t *testing.T) {
+	var (
+		testCtx = context.Background()
+		db      = integration.SetupDB(t)
+	)
+
+	canceled, err := db.RequestDatapipeCancel(testCtx, model.DatapipeStatusAnalyzing)
+	require.Nil(t, err)
+	assert.False(t, canceled)
+
+	require.Nil(t, db.SetDatapipeStatus(testCtx, model.DatapipeStatusAnalyzing))
+
+	canceled, err = db.RequestDatapipeCancel(testCtx, model.DatapipeStatusAnalyzing)
+	require.Nil(t, err)
+	assert.True(t, canceled)
+
+	cancelRequested, err := db.IsDatapipeCancelRequested(testCtx)
+	require.Nil(t, err)
+	assert.True(t, cancelRequested)
+
+	status, err := db.GetDatapipeStatus(testCtx)
+	require.Nil(t, err)
+	assert.True(t, status.CancelRequested)
+
+	require.Nil(t, db.SetDatapipeStatus(testCtx, model.DatapipeStatusIdle))
+
+	cancelRequested, err = db.IsDatapipeCancelRequested(testCtx)
+	require.Nil(t, err)
+	assert.False(t, cancelRequested)
+}
@@ -193,6 +193,76 @@ func (s *BloodhoundDB) GetAzureDataQualityStats(ctx context.Context, tenantId st
 	return azureDataQualityStats, int(count), nil
 }
 
+// GetAggregateAzureDataQualityStats will aggregate Azure Quality stats by
+// summing the maximum asset counts per tenant per day.
+func (s *BloodhoundDB) GetAggregateAzureDataQualityStats(ctx context.Context, tenantIDs []string, start time.Time, end time.Time) (model.AzureDataQualityStats, error) {
+	var (
+		azureDataQualityStats model.AzureDataQualityStats
+		params                = map[string]any{
+			"ids":   tenantIDs,
+			"start": start,
+			"end":   end,
+		}
+	)
+
+	const aggregateAzureDataQualityStatsSql = `
+WITH aggregated_quality_stats AS (
+    SELECT
+        DATE_TRUNC('day', created_at) AS created_date,
+        MAX(users) AS max_users,
+        MAX(groups) AS max_groups,
+        MAX(groups365) AS max_groups365,
+        MAX(apps) AS max_apps,
+        MAX(service_principals) AS max_service_principals,
+        MAX(devices) AS max_devices,
+        MAX(management_groups) AS max_management_groups,
+        MAX(subscriptions) AS max_subscriptions,
+        MAX(resource_groups) AS max_resource_groups,
+        MAX(vms) AS max_vms,
+        MAX(key_vaults) AS max_key_vaults,
+        MAX(automation_accounts) AS max_automation_accounts,
+        MAX(container_registries) AS max_container_registries,
+        MAX(function_apps) AS max_function_apps,
+        MAX(logic_apps) AS max_logic_apps,
+        MAX(managed_clusters) AS max_managed_clusters,
+        MAX(vm_scale_sets) AS max_vm_scale_sets,
+        MAX(web_apps) AS max_web_apps,
+        MAX(relationships) AS max_relationships
+    FROM azure_data_quality_stats
+    WHERE tenant_id IN @ids
+    AND created_at BETWEEN @start AND @end
+    GROUP BY tenant_id, created_date
+	)
+SELECT
+    created_date AS created_at,
+    SUM(max_users) AS users,
+    SUM(max_groups) AS groups,
+    SUM(max_groups365) AS groups365,
+    SUM(max_apps) AS apps,
+    SUM(max_service_principals) AS service_principals,
+    SUM(max_devices) AS devices,
+    SUM(max_management_groups) AS management_groups,
+    SUM(max_subscriptions) AS subscriptions,
+    SUM(max_resource_groups) AS resource_groups,
+    SUM(max_vms) AS vms,
+    SUM(max_key_vaults) AS key_vaults,
+    SUM(max_automation_accounts) AS automation_accounts,
+    SUM(max_container_registries) AS container_registries,
+    SUM(max_function_apps) AS function_apps,
+    SUM(max_logic_apps) AS logic_apps,
+    SUM(max_managed_clusters) AS managed_clusters,
+    SUM(max_vm_scale_sets) AS vm_scale_sets,
+    SUM(max_web_apps) AS web_apps,
+    SUM(max_relationships) AS relationships
+FROM aggregated_quality_stats
+GROUP BY created_at
+ORDER BY created_at;`
+
+	result := s.db.WithContext(ctx).Raw(aggregateAzureDataQualityStatsSql, params).Scan(&azureDataQualityStats)
+
+	return azureDataQualityStats, CheckError(result)
+}
+
 func (s *BloodhoundDB) CreateAzureDataQualityAggregation(ctx context.Context, aggregation model.AzureDataQualityAggregation) (model.AzureDataQualityAggregation, error) {
 	result := s.db.WithContext(ctx).Create(&aggregation)
 	return aggregation, CheckError(result)
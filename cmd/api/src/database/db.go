@@ -49,6 +49,7 @@ var (
 	ErrDuplicateEmail              = errors.New("duplicate user email address")
 	ErrDuplicateCustomNodeKindName = errors.New("duplicate custom node kind name")
 	ErrDuplicateKindName           = errors.New("duplicate kind name")
+	ErrDuplicateSourceKindName     = errors.New("duplicate source kind name")
 	ErrPositionOutOfRange          = errors.New("position out of range")
 )
 
@@ -73,6 +74,8 @@ type Database interface {
 	CountAllIngestTasks(ctx context.Context) (int64, error)
 	DeleteIngestTask(ctx context.Context, ingestTask model.IngestTask) error
 	GetIngestTasksForJob(ctx context.Context, jobID int64) (model.IngestTasks, error)
+	GetIngestedObjects(ctx context.Context, bucket string) (model.IngestedObjects, error)
+	CreateIngestedObject(ctx context.Context, object model.IngestedObject) (model.IngestedObject, error)
 
 	// Asset Groups
 	agi.AgiData
@@ -149,6 +152,7 @@ type Database interface {
 	GetADDataQualityAggregations(ctx context.Context, start time.Time, end time.Time, sort_by string, limit int, skip int) (model.ADDataQualityAggregations, int, error)
 	GetAzureDataQualityStats(ctx context.Context, tenantId string, start time.Time, end time.Time, sort_by string, limit int, skip int) (model.AzureDataQualityStats, int, error)
 	GetAzureDataQualityAggregations(ctx context.Context, start time.Time, end time.Time, sort_by string, limit int, skip int) (model.AzureDataQualityAggregations, int, error)
+	GetAggregateAzureDataQualityStats(ctx context.Context, tenantIDs []string, start time.Time, end time.Time) (model.AzureDataQualityStats, error)
 	DeleteAllDataQuality(ctx context.Context) error
 
 	// Saved Queries
@@ -160,6 +164,9 @@ type Database interface {
 	// Analysis Request
 	AnalysisRequestData
 
+	// Analysis Runs
+	AnalysisRunData
+
 	// Datapipe Status
 	DatapipeStatusData
 
@@ -174,6 +181,9 @@ type Database interface {
 
 	// Source Kinds
 	SourceKindsData
+
+	// OpenGraph Schema
+	OpenGraphSchemaData
 }
 
 type BloodhoundDB struct {
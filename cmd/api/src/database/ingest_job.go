@@ -100,3 +100,37 @@ func (s *BloodhoundDB) DeleteAllIngestJobs(ctx context.Context) error {
 func (s *BloodhoundDB) DeleteAllIngestTasks(ctx context.Context) error {
 	return CheckError(s.db.WithContext(ctx).Exec("DELETE FROM ingest_tasks"))
 }
+
+func (s *BloodhoundDB) CreateIngestFileResults(ctx context.Context, results model.IngestFileResults) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	return CheckError(s.db.WithContext(ctx).Create(&results))
+}
+
+func (s *BloodhoundDB) GetIngestFileResultsForJob(ctx context.Context, jobID int64, skip int, limit int) (model.IngestFileResults, int, error) {
+	var (
+		results model.IngestFileResults
+		count   int64
+	)
+
+	if result := s.db.Model(model.IngestFileResult{}).WithContext(ctx).Where("job_id = ?", jobID).Count(&count); result.Error != nil {
+		return nil, 0, CheckError(result)
+	}
+
+	result := s.Scope(Paginate(skip, limit)).WithContext(ctx).Where("job_id = ?", jobID).Order("id").Find(&results)
+	return results, int(count), CheckError(result)
+}
+
+func (s *BloodhoundDB) GetIngestedObjects(ctx context.Context, bucket string) (model.IngestedObjects, error) {
+	var objects model.IngestedObjects
+	result := s.db.WithContext(ctx).Where("bucket = ?", bucket).Find(&objects)
+
+	return objects, CheckError(result)
+}
+
+func (s *BloodhoundDB) CreateIngestedObject(ctx context.Context, object model.IngestedObject) (model.IngestedObject, error) {
+	result := s.db.WithContext(ctx).Create(&object)
+	return object, CheckError(result)
+}
@@ -30,6 +30,18 @@ func (s *BloodhoundDB) CreateIngestTask(ctx context.Context, ingestTask model.In
 	return ingestTask, CheckError(result)
 }
 
+func (s *BloodhoundDB) GetIngestTask(ctx context.Context, id int64) (model.IngestTask, error) {
+	var ingestTask model.IngestTask
+	result := s.db.WithContext(ctx).First(&ingestTask, id)
+
+	return ingestTask, CheckError(result)
+}
+
+func (s *BloodhoundDB) UpdateIngestTask(ctx context.Context, ingestTask model.IngestTask) error {
+	result := s.db.WithContext(ctx).Save(&ingestTask)
+	return CheckError(result)
+}
+
 func (s *BloodhoundDB) GetAllIngestTasks(ctx context.Context) (model.IngestTasks, error) {
 	var ingestTasks model.IngestTasks
 	result := s.db.WithContext(ctx).Find(&ingestTasks)
@@ -0,0 +1,182 @@
+-- Copyright 2025 Specter Ops, Inc.
+--
+-- Licensed under the Apache License, Version 2.0
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+--
+-- SPDX-License-Identifier: Apache-2.0
+
+-- Track in-progress chunked uploads on ingest tasks
+ALTER TABLE ingest_tasks
+ADD COLUMN IF NOT EXISTS partial boolean DEFAULT false,
+ADD COLUMN IF NOT EXISTS total_bytes bigint DEFAULT 0;
+
+-- Record the outcome of every file processed for an ingest job
+CREATE TABLE IF NOT EXISTS ingest_file_results
+(
+    id BIGSERIAL NOT NULL,
+    job_id bigint NOT NULL,
+    file_name text NOT NULL DEFAULT '',
+    data_type text NOT NULL DEFAULT '',
+    node_count bigint NOT NULL DEFAULT 0,
+    relationship_count bigint NOT NULL DEFAULT 0,
+    skipped_relationship_count bigint NOT NULL DEFAULT 0,
+    error text NOT NULL DEFAULT '',
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_ingest_file_results_ingest_jobs FOREIGN KEY (job_id) REFERENCES ingest_jobs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_ingest_file_results_job_id ON ingest_file_results USING btree (job_id);
+
+-- Track objects pulled from S3-compatible storage for ingest so that each version of an object is only ingested once
+CREATE TABLE IF NOT EXISTS ingested_objects
+(
+    id BIGSERIAL NOT NULL,
+    bucket text NOT NULL,
+    object_key text NOT NULL,
+    etag text NOT NULL,
+    job_id bigint,
+    error text NOT NULL DEFAULT '',
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_ingested_objects_ingest_jobs FOREIGN KEY (job_id) REFERENCES ingest_jobs(id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ingested_objects_bucket_object_key_etag ON ingested_objects USING btree (bucket, object_key, etag);
+
+-- Authoritative ingest jobs record the scope they collected and how much graph data in that scope they did not see
+ALTER TABLE ingest_jobs
+ADD COLUMN IF NOT EXISTS authoritative_mode text NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS collected_domains text[],
+ADD COLUMN IF NOT EXISTS collected_source_kinds text[],
+ADD COLUMN IF NOT EXISTS stale_nodes bigint NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS stale_relationships bigint NOT NULL DEFAULT 0;
+
+-- Admin configurable rules that transform node properties as they are ingested
+INSERT INTO parameters (key, name, description, value, created_at, updated_at) VALUES ('ingest.transform_rules', 'Ingest Transform Rules', 'This configuration parameter lists rules that rename, drop, coerce, extract or derive node properties as they are ingested.', '{"rules": []}', current_timestamp, current_timestamp) ON CONFLICT DO NOTHING;
+
+-- Admin configurable rules that redact sensitive properties as they are ingested, and the properties redacted by each job
+INSERT INTO parameters (key, name, description, value, created_at, updated_at) VALUES ('ingest.redaction', 'Ingest Redaction Rules', 'This configuration parameter lists rules that hash, mask or drop sensitive node and relationship properties before they are written to the graph.', '{"rules": []}', current_timestamp, current_timestamp) ON CONFLICT DO NOTHING;
+
+ALTER TABLE ingest_jobs
+ADD COLUMN IF NOT EXISTS redacted_properties text[];
+
+-- Node and edge kinds declared by the schema section of OpenGraph payloads
+CREATE TABLE IF NOT EXISTS opengraph_kinds
+(
+    id SERIAL NOT NULL,
+    source_kind varchar(256) NOT NULL DEFAULT '',
+    kind_name varchar(256) NOT NULL,
+    is_edge boolean NOT NULL DEFAULT false,
+    display_name text NOT NULL DEFAULT '',
+    traversable boolean NOT NULL DEFAULT false,
+    properties jsonb NOT NULL DEFAULT '[]',
+    created_at timestamp with time zone DEFAULT NOW(),
+    updated_at timestamp with time zone DEFAULT NOW(),
+    PRIMARY KEY (id),
+    UNIQUE (kind_name, is_edge)
+);
+
+-- Count the graph data removed by the deletion directives of OpenGraph payloads
+ALTER TABLE ingest_file_results
+ADD COLUMN IF NOT EXISTS deleted_node_count bigint NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS deleted_relationship_count bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE ingest_jobs
+ADD COLUMN IF NOT EXISTS deleted_nodes bigint NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS deleted_relationships bigint NOT NULL DEFAULT 0;
+
+-- Column mapping of CSV ingest files, describing how their rows are converted to OpenGraph data
+ALTER TABLE ingest_tasks
+ADD COLUMN IF NOT EXISTS csv_mapping jsonb;
+
+-- Source kinds are managed objects with owners, a description, a retention period for the nodes they ingest and a
+-- record of when data was last ingested for them
+ALTER TABLE source_kinds
+ADD COLUMN IF NOT EXISTS description text NOT NULL DEFAULT '',
+ADD COLUMN IF NOT EXISTS owners text[] NOT NULL DEFAULT ARRAY[]::text[],
+ADD COLUMN IF NOT EXISTS retention_days integer NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS last_ingested_at timestamp with time zone,
+ADD COLUMN IF NOT EXISTS created_at timestamp with time zone DEFAULT NOW(),
+ADD COLUMN IF NOT EXISTS updated_at timestamp with time zone DEFAULT NOW();
+
+-- Count the relationships of generic data that were not ingested because their properties failed validation
+ALTER TABLE ingest_file_results
+ADD COLUMN IF NOT EXISTS invalid_relationship_count bigint NOT NULL DEFAULT 0;
+
+-- Environment labels of ingest jobs, carried by their ingest tasks so that the data they write can be tagged with it
+ALTER TABLE ingest_jobs
+ADD COLUMN IF NOT EXISTS environment text NOT NULL DEFAULT '';
+
+ALTER TABLE ingest_tasks
+ADD COLUMN IF NOT EXISTS environment text NOT NULL DEFAULT '';
+
+-- Limits a collected graph data deletion request to the data of the environments listed
+ALTER TABLE analysis_request_switch
+ADD COLUMN IF NOT EXISTS delete_environments text[] DEFAULT ARRAY[]::text[];
+
+-- Next occurrence of the analysis schedule, kept by the datapipe while scheduled analysis is enabled
+ALTER TABLE datapipe_status
+ADD COLUMN IF NOT EXISTS next_scheduled_analysis_at timestamp with time zone;
+
+-- Domains and tenants written to by ingest jobs, used to limit the analysis of the jobs to those
+ALTER TABLE ingest_jobs
+ADD COLUMN IF NOT EXISTS touched_domains text[],
+ADD COLUMN IF NOT EXISTS touched_tenants text[],
+ADD COLUMN IF NOT EXISTS unscoped_objects bigint NOT NULL DEFAULT 0;
+
+-- Admin configurable list of the post-processing steps that analysis must not run
+INSERT INTO parameters (key, name, description, value, created_at, updated_at) VALUES ('analysis.post_processing', 'Analysis Post-Processing', 'This configuration parameter lists the post-processing steps that analysis does not run. The relationships created by a disabled step are deleted.', '{"disabled_steps": []}', current_timestamp, current_timestamp) ON CONFLICT DO NOTHING;
+
+-- History of analysis runs, with the outcome of each step and the relationships it created and deleted by kind
+CREATE TABLE IF NOT EXISTS analysis_runs
+(
+    id BIGSERIAL NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    ended_at timestamp with time zone,
+    status text NOT NULL DEFAULT '',
+    scoped boolean NOT NULL DEFAULT false,
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_analysis_runs_started_at ON analysis_runs USING btree (started_at);
+
+CREATE TABLE IF NOT EXISTS analysis_run_steps
+(
+    id BIGSERIAL NOT NULL,
+    run_id bigint NOT NULL,
+    name text NOT NULL DEFAULT '',
+    status text NOT NULL DEFAULT '',
+    error text NOT NULL DEFAULT '',
+    duration_milliseconds bigint NOT NULL DEFAULT 0,
+    relationships_created jsonb NOT NULL DEFAULT '{}',
+    relationships_deleted jsonb NOT NULL DEFAULT '{}',
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    PRIMARY KEY (id),
+    CONSTRAINT fk_analysis_run_steps_analysis_runs FOREIGN KEY (run_id) REFERENCES analysis_runs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_analysis_run_steps_run_id ON analysis_run_steps USING btree (run_id);
+
+-- Set when the current step of the datapipe is asked to stop, cleared whenever the datapipe status changes
+ALTER TABLE datapipe_status
+ADD COLUMN IF NOT EXISTS cancel_requested boolean NOT NULL DEFAULT false;
+
+-- Name of the file as it was uploaded, reported in the file results of files that were not extracted from an archive
+ALTER TABLE ingest_tasks
+ADD COLUMN IF NOT EXISTS original_file_name text NOT NULL DEFAULT '';
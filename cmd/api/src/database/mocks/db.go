@@ -148,6 +148,21 @@ func (mr *MockDatabaseMockRecorder) CreateADDataQualityStats(ctx, stats any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateADDataQualityStats", reflect.TypeOf((*MockDatabase)(nil).CreateADDataQualityStats), ctx, stats)
 }
 
+// CreateAnalysisRun mocks base method.
+func (m *MockDatabase) CreateAnalysisRun(ctx context.Context, run model.AnalysisRun) (model.AnalysisRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAnalysisRun", ctx, run)
+	ret0, _ := ret[0].(model.AnalysisRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAnalysisRun indicates an expected call of CreateAnalysisRun.
+func (mr *MockDatabaseMockRecorder) CreateAnalysisRun(ctx, run any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAnalysisRun", reflect.TypeOf((*MockDatabase)(nil).CreateAnalysisRun), ctx, run)
+}
+
 // CreateAssetGroup mocks base method.
 func (m *MockDatabase) CreateAssetGroup(ctx context.Context, name, tag string, systemGroup bool) (model.AssetGroup, error) {
 	m.ctrl.T.Helper()
@@ -326,6 +341,20 @@ func (mr *MockDatabaseMockRecorder) CreateCustomNodeKinds(ctx, customNodeKind an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCustomNodeKinds", reflect.TypeOf((*MockDatabase)(nil).CreateCustomNodeKinds), ctx, customNodeKind)
 }
 
+// CreateIngestFileResults mocks base method.
+func (m *MockDatabase) CreateIngestFileResults(ctx context.Context, results model.IngestFileResults) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIngestFileResults", ctx, results)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateIngestFileResults indicates an expected call of CreateIngestFileResults.
+func (mr *MockDatabaseMockRecorder) CreateIngestFileResults(ctx, results any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIngestFileResults", reflect.TypeOf((*MockDatabase)(nil).CreateIngestFileResults), ctx, results)
+}
+
 // CreateIngestJob mocks base method.
 func (m *MockDatabase) CreateIngestJob(ctx context.Context, job model.IngestJob) (model.IngestJob, error) {
 	m.ctrl.T.Helper()
@@ -356,6 +385,21 @@ func (mr *MockDatabaseMockRecorder) CreateIngestTask(ctx, task any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIngestTask", reflect.TypeOf((*MockDatabase)(nil).CreateIngestTask), ctx, task)
 }
 
+// CreateIngestedObject mocks base method.
+func (m *MockDatabase) CreateIngestedObject(ctx context.Context, object model.IngestedObject) (model.IngestedObject, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIngestedObject", ctx, object)
+	ret0, _ := ret[0].(model.IngestedObject)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIngestedObject indicates an expected call of CreateIngestedObject.
+func (mr *MockDatabaseMockRecorder) CreateIngestedObject(ctx, object any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIngestedObject", reflect.TypeOf((*MockDatabase)(nil).CreateIngestedObject), ctx, object)
+}
+
 // CreateInstallation mocks base method.
 func (m *MockDatabase) CreateInstallation(ctx context.Context) (model.Installation, error) {
 	m.ctrl.T.Helper()
@@ -480,6 +524,21 @@ func (mr *MockDatabaseMockRecorder) CreateSavedQueryPermissionsToUsers(ctx, quer
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSavedQueryPermissionsToUsers", reflect.TypeOf((*MockDatabase)(nil).CreateSavedQueryPermissionsToUsers), varargs...)
 }
 
+// CreateSourceKind mocks base method.
+func (m *MockDatabase) CreateSourceKind(ctx context.Context, sourceKind database.SourceKind) (database.SourceKind, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSourceKind", ctx, sourceKind)
+	ret0, _ := ret[0].(database.SourceKind)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSourceKind indicates an expected call of CreateSourceKind.
+func (mr *MockDatabaseMockRecorder) CreateSourceKind(ctx, sourceKind any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSourceKind", reflect.TypeOf((*MockDatabase)(nil).CreateSourceKind), ctx, sourceKind)
+}
+
 // CreateUser mocks base method.
 func (m *MockDatabase) CreateUser(ctx context.Context, user model.User) (model.User, error) {
 	m.ctrl.T.Helper()
@@ -859,6 +918,21 @@ func (mr *MockDatabaseMockRecorder) GetAggregateADDataQualityStats(ctx, domainSI
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAggregateADDataQualityStats", reflect.TypeOf((*MockDatabase)(nil).GetAggregateADDataQualityStats), ctx, domainSIDs, start, end)
 }
 
+// GetAggregateAzureDataQualityStats mocks base method.
+func (m *MockDatabase) GetAggregateAzureDataQualityStats(ctx context.Context, tenantIDs []string, start, end time.Time) (model.AzureDataQualityStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAggregateAzureDataQualityStats", ctx, tenantIDs, start, end)
+	ret0, _ := ret[0].(model.AzureDataQualityStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAggregateAzureDataQualityStats indicates an expected call of GetAggregateAzureDataQualityStats.
+func (mr *MockDatabaseMockRecorder) GetAggregateAzureDataQualityStats(ctx, tenantIDs, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAggregateAzureDataQualityStats", reflect.TypeOf((*MockDatabase)(nil).GetAggregateAzureDataQualityStats), ctx, tenantIDs, start, end)
+}
+
 // GetAllAssetGroups mocks base method.
 func (m *MockDatabase) GetAllAssetGroups(ctx context.Context, order string, filter model.SQLFilter) (model.AssetGroups, error) {
 	m.ctrl.T.Helper()
@@ -1055,6 +1129,37 @@ func (mr *MockDatabaseMockRecorder) GetAnalysisRequest(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnalysisRequest", reflect.TypeOf((*MockDatabase)(nil).GetAnalysisRequest), ctx)
 }
 
+// GetAnalysisRun mocks base method.
+func (m *MockDatabase) GetAnalysisRun(ctx context.Context, id int64) (model.AnalysisRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnalysisRun", ctx, id)
+	ret0, _ := ret[0].(model.AnalysisRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnalysisRun indicates an expected call of GetAnalysisRun.
+func (mr *MockDatabaseMockRecorder) GetAnalysisRun(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnalysisRun", reflect.TypeOf((*MockDatabase)(nil).GetAnalysisRun), ctx, id)
+}
+
+// GetAnalysisRuns mocks base method.
+func (m *MockDatabase) GetAnalysisRuns(ctx context.Context, skip, limit int) (model.AnalysisRuns, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnalysisRuns", ctx, skip, limit)
+	ret0, _ := ret[0].(model.AnalysisRuns)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAnalysisRuns indicates an expected call of GetAnalysisRuns.
+func (mr *MockDatabaseMockRecorder) GetAnalysisRuns(ctx, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnalysisRuns", reflect.TypeOf((*MockDatabase)(nil).GetAnalysisRuns), ctx, skip, limit)
+}
+
 // GetAssetGroup mocks base method.
 func (m *MockDatabase) GetAssetGroup(ctx context.Context, id int32) (model.AssetGroup, error) {
 	m.ctrl.T.Helper()
@@ -1389,6 +1494,22 @@ func (mr *MockDatabaseMockRecorder) GetFlagByKey(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFlagByKey", reflect.TypeOf((*MockDatabase)(nil).GetFlagByKey), arg0, arg1)
 }
 
+// GetIngestFileResultsForJob mocks base method.
+func (m *MockDatabase) GetIngestFileResultsForJob(ctx context.Context, jobID int64, skip, limit int) (model.IngestFileResults, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngestFileResultsForJob", ctx, jobID, skip, limit)
+	ret0, _ := ret[0].(model.IngestFileResults)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetIngestFileResultsForJob indicates an expected call of GetIngestFileResultsForJob.
+func (mr *MockDatabaseMockRecorder) GetIngestFileResultsForJob(ctx, jobID, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngestFileResultsForJob", reflect.TypeOf((*MockDatabase)(nil).GetIngestFileResultsForJob), ctx, jobID, skip, limit)
+}
+
 // GetIngestJob mocks base method.
 func (m *MockDatabase) GetIngestJob(ctx context.Context, id int64) (model.IngestJob, error) {
 	m.ctrl.T.Helper()
@@ -1419,6 +1540,21 @@ func (mr *MockDatabaseMockRecorder) GetIngestJobsWithStatus(ctx, status any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngestJobsWithStatus", reflect.TypeOf((*MockDatabase)(nil).GetIngestJobsWithStatus), ctx, status)
 }
 
+// GetIngestTask mocks base method.
+func (m *MockDatabase) GetIngestTask(ctx context.Context, id int64) (model.IngestTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngestTask", ctx, id)
+	ret0, _ := ret[0].(model.IngestTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIngestTask indicates an expected call of GetIngestTask.
+func (mr *MockDatabaseMockRecorder) GetIngestTask(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngestTask", reflect.TypeOf((*MockDatabase)(nil).GetIngestTask), ctx, id)
+}
+
 // GetIngestTasksForJob mocks base method.
 func (m *MockDatabase) GetIngestTasksForJob(ctx context.Context, jobID int64) (model.IngestTasks, error) {
 	m.ctrl.T.Helper()
@@ -1434,6 +1570,21 @@ func (mr *MockDatabaseMockRecorder) GetIngestTasksForJob(ctx, jobID any) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngestTasksForJob", reflect.TypeOf((*MockDatabase)(nil).GetIngestTasksForJob), ctx, jobID)
 }
 
+// GetIngestedObjects mocks base method.
+func (m *MockDatabase) GetIngestedObjects(ctx context.Context, bucket string) (model.IngestedObjects, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngestedObjects", ctx, bucket)
+	ret0, _ := ret[0].(model.IngestedObjects)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIngestedObjects indicates an expected call of GetIngestedObjects.
+func (mr *MockDatabaseMockRecorder) GetIngestedObjects(ctx, bucket any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngestedObjects", reflect.TypeOf((*MockDatabase)(nil).GetIngestedObjects), ctx, bucket)
+}
+
 // GetInstallation mocks base method.
 func (m *MockDatabase) GetInstallation(ctx context.Context) (model.Installation, error) {
 	m.ctrl.T.Helper()
@@ -1464,6 +1615,21 @@ func (mr *MockDatabaseMockRecorder) GetLatestAssetGroupCollection(ctx, assetGrou
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestAssetGroupCollection", reflect.TypeOf((*MockDatabase)(nil).GetLatestAssetGroupCollection), ctx, assetGroupID)
 }
 
+// GetOpenGraphKinds mocks base method.
+func (m *MockDatabase) GetOpenGraphKinds(ctx context.Context) (model.OpenGraphKinds, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenGraphKinds", ctx)
+	ret0, _ := ret[0].(model.OpenGraphKinds)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenGraphKinds indicates an expected call of GetOpenGraphKinds.
+func (mr *MockDatabaseMockRecorder) GetOpenGraphKinds(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenGraphKinds", reflect.TypeOf((*MockDatabase)(nil).GetOpenGraphKinds), ctx)
+}
+
 // GetOrderedAssetGroupTagTiers mocks base method.
 func (m *MockDatabase) GetOrderedAssetGroupTagTiers(ctx context.Context) ([]model.AssetGroupTag, error) {
 	m.ctrl.T.Helper()
@@ -1709,6 +1875,21 @@ func (mr *MockDatabaseMockRecorder) GetSharedSavedQueries(ctx, userID any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSharedSavedQueries", reflect.TypeOf((*MockDatabase)(nil).GetSharedSavedQueries), ctx, userID)
 }
 
+// GetSourceKindByID mocks base method.
+func (m *MockDatabase) GetSourceKindByID(ctx context.Context, id int) (database.SourceKind, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSourceKindByID", ctx, id)
+	ret0, _ := ret[0].(database.SourceKind)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSourceKindByID indicates an expected call of GetSourceKindByID.
+func (mr *MockDatabaseMockRecorder) GetSourceKindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSourceKindByID", reflect.TypeOf((*MockDatabase)(nil).GetSourceKindByID), ctx, id)
+}
+
 // GetSourceKinds mocks base method.
 func (m *MockDatabase) GetSourceKinds(ctx context.Context) ([]database.SourceKind, error) {
 	m.ctrl.T.Helper()
@@ -1857,6 +2038,21 @@ func (mr *MockDatabaseMockRecorder) InsertSelectorNode(ctx, selectorId, nodeId,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertSelectorNode", reflect.TypeOf((*MockDatabase)(nil).InsertSelectorNode), ctx, selectorId, nodeId, certified, certifiedBy, source)
 }
 
+// IsDatapipeCancelRequested mocks base method.
+func (m *MockDatabase) IsDatapipeCancelRequested(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsDatapipeCancelRequested", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsDatapipeCancelRequested indicates an expected call of IsDatapipeCancelRequested.
+func (mr *MockDatabaseMockRecorder) IsDatapipeCancelRequested(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsDatapipeCancelRequested", reflect.TypeOf((*MockDatabase)(nil).IsDatapipeCancelRequested), ctx)
+}
+
 // IsSavedQueryPublic mocks base method.
 func (m *MockDatabase) IsSavedQueryPublic(ctx context.Context, savedQueryID int64) (bool, error) {
 	m.ctrl.T.Helper()
@@ -1964,6 +2160,20 @@ func (mr *MockDatabaseMockRecorder) LookupUser(ctx, principalName any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupUser", reflect.TypeOf((*MockDatabase)(nil).LookupUser), ctx, principalName)
 }
 
+// MarkSourceKindsIngested mocks base method.
+func (m *MockDatabase) MarkSourceKindsIngested(ctx context.Context, kinds graph.Kinds, ingestedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSourceKindsIngested", ctx, kinds, ingestedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSourceKindsIngested indicates an expected call of MarkSourceKindsIngested.
+func (mr *MockDatabaseMockRecorder) MarkSourceKindsIngested(ctx, kinds, ingestedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSourceKindsIngested", reflect.TypeOf((*MockDatabase)(nil).MarkSourceKindsIngested), ctx, kinds, ingestedAt)
+}
+
 // Migrate mocks base method.
 func (m *MockDatabase) Migrate(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -2020,6 +2230,21 @@ func (mr *MockDatabaseMockRecorder) RequestCollectedGraphDataDeletion(ctx, reque
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestCollectedGraphDataDeletion", reflect.TypeOf((*MockDatabase)(nil).RequestCollectedGraphDataDeletion), ctx, request)
 }
 
+// RequestDatapipeCancel mocks base method.
+func (m *MockDatabase) RequestDatapipeCancel(ctx context.Context, status model.DatapipeStatus) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestDatapipeCancel", ctx, status)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestDatapipeCancel indicates an expected call of RequestDatapipeCancel.
+func (mr *MockDatabaseMockRecorder) RequestDatapipeCancel(ctx, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestDatapipeCancel", reflect.TypeOf((*MockDatabase)(nil).RequestDatapipeCancel), ctx, status)
+}
+
 // SavedQueryBelongsToUser mocks base method.
 func (m *MockDatabase) SavedQueryBelongsToUser(ctx context.Context, userID uuid.UUID, savedQueryID int64) (bool, error) {
 	m.ctrl.T.Helper()
@@ -2077,6 +2302,20 @@ func (mr *MockDatabaseMockRecorder) SetFlag(ctx, value any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetFlag", reflect.TypeOf((*MockDatabase)(nil).SetFlag), ctx, value)
 }
 
+// SetNextScheduledAnalysisTime mocks base method.
+func (m *MockDatabase) SetNextScheduledAnalysisTime(ctx context.Context, nextRun null.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNextScheduledAnalysisTime", ctx, nextRun)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetNextScheduledAnalysisTime indicates an expected call of SetNextScheduledAnalysisTime.
+func (mr *MockDatabaseMockRecorder) SetNextScheduledAnalysisTime(ctx, nextRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNextScheduledAnalysisTime", reflect.TypeOf((*MockDatabase)(nil).SetNextScheduledAnalysisTime), ctx, nextRun)
+}
+
 // SetUserSessionFlag mocks base method.
 func (m *MockDatabase) SetUserSessionFlag(ctx context.Context, userSession *model.UserSession, key model.SessionFlagKey, state bool) error {
 	m.ctrl.T.Helper()
@@ -2129,6 +2368,20 @@ func (mr *MockDatabaseMockRecorder) TerminateUserSessionsBySSOProvider(ctx, ssoP
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TerminateUserSessionsBySSOProvider", reflect.TypeOf((*MockDatabase)(nil).TerminateUserSessionsBySSOProvider), ctx, ssoProvider)
 }
 
+// UpdateAnalysisRun mocks base method.
+func (m *MockDatabase) UpdateAnalysisRun(ctx context.Context, run model.AnalysisRun) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAnalysisRun", ctx, run)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAnalysisRun indicates an expected call of UpdateAnalysisRun.
+func (mr *MockDatabaseMockRecorder) UpdateAnalysisRun(ctx, run any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAnalysisRun", reflect.TypeOf((*MockDatabase)(nil).UpdateAnalysisRun), ctx, run)
+}
+
 // UpdateAssetGroup mocks base method.
 func (m *MockDatabase) UpdateAssetGroup(ctx context.Context, assetGroup model.AssetGroup) error {
 	m.ctrl.T.Helper()
@@ -2245,6 +2498,20 @@ func (mr *MockDatabaseMockRecorder) UpdateIngestJob(ctx, job any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIngestJob", reflect.TypeOf((*MockDatabase)(nil).UpdateIngestJob), ctx, job)
 }
 
+// UpdateIngestTask mocks base method.
+func (m *MockDatabase) UpdateIngestTask(ctx context.Context, task model.IngestTask) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIngestTask", ctx, task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIngestTask indicates an expected call of UpdateIngestTask.
+func (mr *MockDatabaseMockRecorder) UpdateIngestTask(ctx, task any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIngestTask", reflect.TypeOf((*MockDatabase)(nil).UpdateIngestTask), ctx, task)
+}
+
 // UpdateLastAnalysisCompleteTime mocks base method.
 func (m *MockDatabase) UpdateLastAnalysisCompleteTime(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -2333,6 +2600,21 @@ func (mr *MockDatabaseMockRecorder) UpdateSelectorNodesByNodeId(ctx, selectorId,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSelectorNodesByNodeId", reflect.TypeOf((*MockDatabase)(nil).UpdateSelectorNodesByNodeId), ctx, selectorId, certified, certifiedBy, nodeId)
 }
 
+// UpdateSourceKind mocks base method.
+func (m *MockDatabase) UpdateSourceKind(ctx context.Context, sourceKind database.SourceKind) (database.SourceKind, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSourceKind", ctx, sourceKind)
+	ret0, _ := ret[0].(database.SourceKind)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSourceKind indicates an expected call of UpdateSourceKind.
+func (mr *MockDatabaseMockRecorder) UpdateSourceKind(ctx, sourceKind any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSourceKind", reflect.TypeOf((*MockDatabase)(nil).UpdateSourceKind), ctx, sourceKind)
+}
+
 // UpdateUser mocks base method.
 func (m *MockDatabase) UpdateUser(ctx context.Context, user model.User) error {
 	m.ctrl.T.Helper()
@@ -2347,6 +2629,20 @@ func (mr *MockDatabaseMockRecorder) UpdateUser(ctx, user any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockDatabase)(nil).UpdateUser), ctx, user)
 }
 
+// UpsertOpenGraphKinds mocks base method.
+func (m *MockDatabase) UpsertOpenGraphKinds(ctx context.Context, kinds model.OpenGraphKinds) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertOpenGraphKinds", ctx, kinds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertOpenGraphKinds indicates an expected call of UpsertOpenGraphKinds.
+func (mr *MockDatabaseMockRecorder) UpsertOpenGraphKinds(ctx, kinds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertOpenGraphKinds", reflect.TypeOf((*MockDatabase)(nil).UpsertOpenGraphKinds), ctx, kinds)
+}
+
 // Wipe mocks base method.
 func (m *MockDatabase) Wipe(ctx context.Context) error {
 	m.ctrl.T.Helper()
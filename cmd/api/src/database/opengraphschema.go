@@ -0,0 +1,82 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"gorm.io/gorm"
+)
+
+const (
+	openGraphKindTable = "opengraph_kinds"
+)
+
+type OpenGraphSchemaData interface {
+	UpsertOpenGraphKinds(ctx context.Context, kinds model.OpenGraphKinds) error
+	GetOpenGraphKinds(ctx context.Context) (model.OpenGraphKinds, error)
+}
+
+// UpsertOpenGraphKinds stores the kinds declared by an OpenGraph payload, replacing any earlier declaration of the
+// same kind. Icons declared for node kinds are stored as custom node kinds unless one is already configured for the
+// kind, so that icons set by an administrator are not overwritten by ingest.
+func (s *BloodhoundDB) UpsertOpenGraphKinds(ctx context.Context, kinds model.OpenGraphKinds) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, kind := range kinds {
+			if result := tx.Exec(fmt.Sprintf(`
+				INSERT INTO %s (source_kind, kind_name, is_edge, display_name, traversable, properties, created_at, updated_at)
+				VALUES (?, ?, ?, ?, ?, ?, NOW(), NOW())
+				ON CONFLICT (kind_name, is_edge) DO UPDATE SET
+					source_kind = EXCLUDED.source_kind,
+					display_name = EXCLUDED.display_name,
+					traversable = EXCLUDED.traversable,
+					properties = EXCLUDED.properties,
+					updated_at = NOW();`, openGraphKindTable),
+				kind.SourceKind, kind.KindName, kind.IsEdge, kind.DisplayName, kind.Traversable, kind.Properties,
+			); result.Error != nil {
+				return fmt.Errorf("failed to upsert opengraph kind %q: %w", kind.KindName, result.Error)
+			}
+
+			if kind.Icon != nil && !kind.IsEdge {
+				if result := tx.Exec(fmt.Sprintf(`
+					INSERT INTO %s (kind_name, config)
+					VALUES (?, ?)
+					ON CONFLICT (kind_name) DO NOTHING;`, customNodeKindTable),
+					kind.KindName, model.CustomNodeKindConfig{Icon: *kind.Icon},
+				); result.Error != nil {
+					return fmt.Errorf("failed to insert icon for opengraph kind %q: %w", kind.KindName, result.Error)
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *BloodhoundDB) GetOpenGraphKinds(ctx context.Context) (model.OpenGraphKinds, error) {
+	var kinds model.OpenGraphKinds
+
+	result := s.db.WithContext(ctx).Raw(fmt.Sprintf(`
+		SELECT id, source_kind, kind_name, is_edge, display_name, traversable, properties, created_at, updated_at
+		FROM %s
+		ORDER BY is_edge, kind_name;`, openGraphKindTable),
+	).Scan(&kinds)
+
+	return kinds, CheckError(result)
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build integration
+// +build integration
+
+package database_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/test/integration"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpsertOpenGraphKinds(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		dbInst = integration.SetupDB(t)
+		icon   = model.CustomNodeIcon{Type: "font-awesome", Name: "user", Color: "#FFFFFF"}
+	)
+
+	_, err := dbInst.CreateCustomNodeKinds(ctx, model.CustomNodeKinds{{
+		KindName: "GithubRepository",
+		Config:   model.CustomNodeKindConfig{Icon: model.CustomNodeIcon{Type: "font-awesome", Name: "box", Color: "#000000"}},
+	}})
+	require.NoError(t, err)
+
+	require.NoError(t, dbInst.UpsertOpenGraphKinds(ctx, model.OpenGraphKinds{
+		{SourceKind: "GithubBase", KindName: "GithubUser", DisplayName: "GitHub User", Icon: &icon, Properties: model.OpenGraphProperties{{Name: "login", Type: model.OpenGraphPropertyTypeString, Indexed: true}}},
+		{SourceKind: "GithubBase", KindName: "GithubRepository", Icon: &icon},
+		{SourceKind: "GithubBase", KindName: "GithubMemberOf", IsEdge: true, Traversable: false},
+	}))
+
+	// Declaring a kind again replaces the earlier declaration
+	require.NoError(t, dbInst.UpsertOpenGraphKinds(ctx, model.OpenGraphKinds{
+		{SourceKind: "GithubBase", KindName: "GithubMemberOf", IsEdge: true, Traversable: true},
+	}))
+
+	kinds, err := dbInst.GetOpenGraphKinds(ctx)
+	require.NoError(t, err)
+	require.Len(t, kinds, 3)
+
+	require.Equal(t, "GithubRepository", kinds[0].KindName)
+	require.Equal(t, "GithubUser", kinds[1].KindName)
+	require.Equal(t, "GitHub User", kinds[1].DisplayName)
+	require.Equal(t, model.OpenGraphProperties{{Name: "login", Type: model.OpenGraphPropertyTypeString, Indexed: true}}, kinds[1].Properties)
+	require.Equal(t, "GithubMemberOf", kinds[2].KindName)
+	require.True(t, kinds[2].IsEdge)
+	require.True(t, kinds[2].Traversable)
+
+	// The declared icon is stored as a custom node kind but does not replace one that was already configured
+	userKind, err := dbInst.GetCustomNodeKind(ctx, "GithubUser")
+	require.NoError(t, err)
+	require.Equal(t, icon, userKind.Config.Icon)
+
+	repositoryKind, err := dbInst.GetCustomNodeKind(ctx, "GithubRepository")
+	require.NoError(t, err)
+	require.Equal(t, "box", repositoryKind.Config.Icon.Name)
+}
@@ -19,15 +19,24 @@ package database
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/lib/pq"
+	"github.com/specterops/bloodhound/cmd/api/src/database/types/null"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
 	"github.com/specterops/dawgs/graph"
+	"gorm.io/gorm"
 )
 
 type SourceKindsData interface {
 	GetSourceKinds(ctx context.Context) ([]SourceKind, error)
+	GetSourceKindByID(ctx context.Context, id int) (SourceKind, error)
+	CreateSourceKind(ctx context.Context, sourceKind SourceKind) (SourceKind, error)
+	UpdateSourceKind(ctx context.Context, sourceKind SourceKind) (SourceKind, error)
 	DeleteSourceKindsByName(ctx context.Context, kinds graph.Kinds) error
 	RegisterSourceKind(ctx context.Context) func(sourceKind graph.Kind) error
+	MarkSourceKindsIngested(ctx context.Context, kinds graph.Kinds, ingestedAt time.Time) error
 }
 
 // RegisterSourceKind returns a function that inserts a source kind by name,
@@ -55,21 +64,70 @@ func (s *BloodhoundDB) RegisterSourceKind(ctx context.Context) func(sourceKind g
 	}
 }
 
+// SourceKind is a registered source of graph data. Nodes of a source kind with a retention period are purged once they
+// have not been seen by an ingest for that many days; a retention period of 0 keeps them until they are deleted.
+// Version counts the ingests that have written data for the source kind.
 type SourceKind struct {
-	ID   int        `json:"id"`
-	Name graph.Kind `json:"name"`
+	ID             int        `json:"id"`
+	Name           graph.Kind `json:"name"`
+	Description    string     `json:"description"`
+	Owners         []string   `json:"owners"`
+	RetentionDays  int        `json:"retention_days"`
+	Version        int64      `json:"version"`
+	LastIngestedAt null.Time  `json:"last_ingested_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
 }
 
-func (s *BloodhoundDB) GetSourceKinds(ctx context.Context) ([]SourceKind, error) {
-	const query = `
-		SELECT id, name
-		FROM source_kinds;
-	`
+func (s SourceKind) AuditData() model.AuditData {
+	return model.AuditData{
+		"id":             s.ID,
+		"name":           s.Name,
+		"description":    s.Description,
+		"owners":         s.Owners,
+		"retention_days": s.RetentionDays,
+	}
+}
+
+const sourceKindColumns = "id, name, description, owners, retention_days, version, last_ingested_at, created_at, updated_at"
+
+type rawSourceKind struct {
+	ID             int
+	Name           string
+	Description    string
+	Owners         pq.StringArray `gorm:"type:text[]"`
+	RetentionDays  int
+	Version        int64
+	LastIngestedAt null.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (s rawSourceKind) toSourceKind() SourceKind {
+	owners := []string(s.Owners)
+	if owners == nil {
+		owners = []string{}
+	}
 
-	type rawSourceKind struct {
-		ID   int
-		Name string
+	return SourceKind{
+		ID:             s.ID,
+		Name:           graph.StringKind(s.Name),
+		Description:    s.Description,
+		Owners:         owners,
+		RetentionDays:  s.RetentionDays,
+		Version:        s.Version,
+		LastIngestedAt: s.LastIngestedAt,
+		CreatedAt:      s.CreatedAt,
+		UpdatedAt:      s.UpdatedAt,
 	}
+}
+
+func (s *BloodhoundDB) GetSourceKinds(ctx context.Context) ([]SourceKind, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM source_kinds
+		ORDER BY id;
+	`, sourceKindColumns)
 
 	var kinds []rawSourceKind
 	result := s.db.WithContext(ctx).Raw(query).Scan(&kinds)
@@ -79,15 +137,87 @@ func (s *BloodhoundDB) GetSourceKinds(ctx context.Context) ([]SourceKind, error)
 
 	out := make([]SourceKind, len(kinds))
 	for i, k := range kinds {
-		out[i] = SourceKind{
-			ID:   k.ID,
-			Name: graph.StringKind(k.Name),
-		}
+		out[i] = k.toSourceKind()
 	}
 
 	return out, nil
 }
 
+func (s *BloodhoundDB) GetSourceKindByID(ctx context.Context, id int) (SourceKind, error) {
+	var kind rawSourceKind
+
+	result := s.db.WithContext(ctx).Raw(fmt.Sprintf("SELECT %s FROM source_kinds WHERE id = ?;", sourceKindColumns), id).Scan(&kind)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return SourceKind{}, ErrNotFound
+	}
+
+	return kind.toSourceKind(), CheckError(result)
+}
+
+// CreateSourceKind registers a source kind ahead of its first ingest, along with its description, owners and
+// retention period
+func (s *BloodhoundDB) CreateSourceKind(ctx context.Context, sourceKind SourceKind) (SourceKind, error) {
+	var (
+		created    rawSourceKind
+		auditEntry = model.AuditEntry{
+			Action: model.AuditLogActionCreateSourceKind,
+			Model:  &sourceKind,
+		}
+	)
+
+	err := s.AuditableTransaction(ctx, auditEntry, func(tx *gorm.DB) error {
+		result := tx.Raw(fmt.Sprintf(`
+			INSERT INTO source_kinds (name, description, owners, retention_days, created_at, updated_at)
+			VALUES (?, ?, ?, ?, NOW(), NOW())
+			RETURNING %s;
+		`, sourceKindColumns), sourceKind.Name.String(), sourceKind.Description, pq.StringArray(sourceKind.Owners), sourceKind.RetentionDays).Scan(&created)
+
+		if result.Error != nil && strings.Contains(result.Error.Error(), "duplicate key value violates unique constraint \"source_kinds_name_key\"") {
+			return fmt.Errorf("%w: %v", ErrDuplicateSourceKindName, result.Error)
+		}
+
+		return CheckError(result)
+	})
+
+	if err == nil {
+		sourceKind = created.toSourceKind()
+	}
+
+	return sourceKind, err
+}
+
+// UpdateSourceKind updates the description, owners and retention period of a source kind. Its name can not be changed.
+func (s *BloodhoundDB) UpdateSourceKind(ctx context.Context, sourceKind SourceKind) (SourceKind, error) {
+	var (
+		updated    rawSourceKind
+		auditEntry = model.AuditEntry{
+			Action: model.AuditLogActionUpdateSourceKind,
+			Model:  &sourceKind,
+		}
+	)
+
+	err := s.AuditableTransaction(ctx, auditEntry, func(tx *gorm.DB) error {
+		result := tx.Raw(fmt.Sprintf(`
+			UPDATE source_kinds
+			SET description = ?, owners = ?, retention_days = ?, updated_at = NOW()
+			WHERE id = ?
+			RETURNING %s;
+		`, sourceKindColumns), sourceKind.Description, pq.StringArray(sourceKind.Owners), sourceKind.RetentionDays, sourceKind.ID).Scan(&updated)
+
+		if result.Error == nil && result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+
+		return CheckError(result)
+	})
+
+	if err == nil {
+		sourceKind = updated.toSourceKind()
+	}
+
+	return sourceKind, err
+}
+
 func (s *BloodhoundDB) DeleteSourceKindsByName(ctx context.Context, kinds graph.Kinds) error {
 	if len(kinds) == 0 {
 		return nil
@@ -108,3 +238,23 @@ func (s *BloodhoundDB) DeleteSourceKindsByName(ctx context.Context, kinds graph.
 
 	return nil
 }
+
+// MarkSourceKindsIngested records that data was ingested for the source kinds given, bumping their version
+func (s *BloodhoundDB) MarkSourceKindsIngested(ctx context.Context, kinds graph.Kinds, ingestedAt time.Time) error {
+	if len(kinds) == 0 {
+		return nil
+	}
+
+	const query = `
+		UPDATE source_kinds
+		SET last_ingested_at = ?, version = version + 1
+		WHERE name = ANY (?);
+	`
+
+	result := s.db.WithContext(ctx).Exec(query, ingestedAt, pq.Array(kinds.Strings()))
+	if err := result.Error; err != nil {
+		return fmt.Errorf("failed to mark source kinds ingested: %w", err)
+	}
+
+	return nil
+}
@@ -21,8 +21,11 @@ package database_test
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/specterops/bloodhound/cmd/api/src/database"
 	"github.com/specterops/bloodhound/cmd/api/src/test/integration"
 	"github.com/specterops/dawgs/graph"
 	"github.com/stretchr/testify/require"
@@ -57,3 +60,47 @@ func TestDeleteSourceKinds(t *testing.T) {
 	require.Nil(t, err)
 	require.Len(t, sourceKinds, 0)
 }
+
+func TestSourceKindLifecycle(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		dbInst     = integration.SetupDB(t)
+		ingestedAt = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	created, err := dbInst.CreateSourceKind(ctx, database.SourceKind{
+		Name:          graph.StringKind("GithubBase"),
+		Description:   "GitHub organizations",
+		Owners:        []string{"platform"},
+		RetentionDays: 30,
+	})
+	require.Nil(t, err)
+	require.Equal(t, "GithubBase", created.Name.String())
+	require.Equal(t, []string{"platform"}, created.Owners)
+	require.Equal(t, int64(0), created.Version)
+	require.False(t, created.LastIngestedAt.Valid)
+
+	_, err = dbInst.CreateSourceKind(ctx, database.SourceKind{Name: graph.StringKind("GithubBase")})
+	require.True(t, errors.Is(err, database.ErrDuplicateSourceKindName))
+
+	updated, err := dbInst.UpdateSourceKind(ctx, database.SourceKind{ID: created.ID, Description: "GitHub", Owners: []string{}, RetentionDays: 7})
+	require.Nil(t, err)
+	require.Equal(t, "GithubBase", updated.Name.String())
+	require.Equal(t, "GitHub", updated.Description)
+	require.Equal(t, []string{}, updated.Owners)
+	require.Equal(t, 7, updated.RetentionDays)
+
+	_, err = dbInst.UpdateSourceKind(ctx, database.SourceKind{ID: created.ID + 100})
+	require.True(t, errors.Is(err, database.ErrNotFound))
+
+	require.Nil(t, dbInst.MarkSourceKindsIngested(ctx, graph.Kinds{graph.StringKind("GithubBase")}, ingestedAt))
+
+	sourceKind, err := dbInst.GetSourceKindByID(ctx, created.ID)
+	require.Nil(t, err)
+	require.Equal(t, int64(1), sourceKind.Version)
+	require.True(t, sourceKind.LastIngestedAt.Valid)
+	require.True(t, ingestedAt.Equal(sourceKind.LastIngestedAt.Time))
+
+	_, err = dbInst.GetSourceKindByID(ctx, created.ID+100)
+	require.True(t, errors.Is(err, database.ErrNotFound))
+}
@@ -171,3 +171,34 @@ func (s JSONBBoolObject) GormDBDataType(db *gorm.DB, _ *schema.Field) string {
 		panic(fmt.Sprintf("Unsupported database dialect for JSON datatype: %s", dbDialect))
 	}
 }
+
+type JSONBInt64Object map[string]int64
+
+// Scan parses the input value (expected to be JSON) to []byte and then attempts to unmarshal it into the receiver
+func (s *JSONBInt64Object) Scan(value any) error {
+	if bytes, ok := value.([]byte); !ok {
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	} else {
+		if err := json.Unmarshal(bytes, s); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// Value returns the json-marshaled value of the receiver
+func (s JSONBInt64Object) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// GormDBDataType returns JSONB if postgres, otherwise panics due to lack of DB type support
+func (s JSONBInt64Object) GormDBDataType(db *gorm.DB, _ *schema.Field) string {
+	switch dbDialect := db.Name(); dbDialect {
+	case "postgres":
+		return "JSONB"
+
+	default:
+		panic(fmt.Sprintf("Unsupported database dialect for JSON datatype: %s", dbDialect))
+	}
+}
@@ -57,3 +57,17 @@ func TestTypes_Value(t *testing.T) {
 	require.Nil(t, err)
 	require.Equal(t, "value", result.Key)
 }
+
+func TestTypes_JSONBInt64Object(t *testing.T) {
+	object := JSONBInt64Object{}
+
+	jsonInput := []byte(`{"ADCSESC1":12}`)
+
+	err := object.Scan(jsonInput)
+	require.Nil(t, err)
+	require.Equal(t, int64(12), object["ADCSESC1"])
+
+	value, err := object.Value()
+	require.Nil(t, err)
+	require.Equal(t, jsonInput, value)
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metrics holds the Prometheus metrics of the datapipe. They are registered with the default registry and are
+// served by the tools API on the metrics port.
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+)
+
+const namespace = "bloodhound"
+
+// Analysis steps recorded by AnalysisStepDuration, besides post-processing steps which are recorded by name
+const (
+	AnalysisStepAGI         = "agi"
+	AnalysisStepDataQuality = "data_quality"
+)
+
+// Endpoints reported by IngestRelationshipsSkipped as the reason a relationship was skipped
+const (
+	UnresolvedSource = "source"
+	UnresolvedTarget = "target"
+	UnresolvedBoth   = "both"
+)
+
+var (
+	IngestObjectsDecoded = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ingest",
+		Name:      "objects_decoded_total",
+		Help:      "Number of objects decoded from ingest files, by data type.",
+	}, []string{"data_type"})
+
+	IngestBatchFlushDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "ingest",
+		Name:      "batch_flush_duration_seconds",
+		Help:      "Time taken to write a chunk of decoded objects to the graph batch.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 14),
+	})
+
+	IngestRelationshipsSkipped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ingest",
+		Name:      "relationships_skipped_total",
+		Help:      "Number of relationships skipped because their endpoints could not be resolved, by unresolved endpoint.",
+	}, []string{"unresolved"})
+
+	AnalysisStepDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "analysis",
+		Name:      "step_duration_seconds",
+		Help:      "Time taken by each step of analysis.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 16),
+	}, []string{"step"})
+
+	DatapipeStatusTransitions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "datapipe",
+		Name:      "status_transitions_total",
+		Help:      "Number of datapipe status transitions, by previous and new status.",
+	}, []string{"from", "to"})
+
+	DatapipeStatus = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "datapipe",
+		Name:      "status",
+		Help:      "Current datapipe status, set to 1 for the current status and 0 for the others.",
+	}, []string{"status"})
+)
+
+var datapipeStatuses = []model.DatapipeStatus{
+	model.DatapipeStatusIdle,
+	model.DatapipeStatusIngesting,
+	model.DatapipeStatusAnalyzing,
+	model.DatapipeStatusPurging,
+	model.DatapipeStatusPruning,
+	model.DatapipeStatusStarting,
+}
+
+var (
+	currentStatusLock sync.Mutex
+	currentStatus     model.DatapipeStatus
+)
+
+// RecordDatapipeStatus records the transition of the datapipe from its last recorded status to the status given. The
+// first status recorded only sets the current status, as there is no transition to count.
+func RecordDatapipeStatus(status model.DatapipeStatus) {
+	currentStatusLock.Lock()
+	defer currentStatusLock.Unlock()
+
+	if status == currentStatus {
+		return
+	} else if currentStatus != "" {
+		DatapipeStatusTransitions.WithLabelValues(string(currentStatus), string(status)).Inc()
+	}
+
+	currentStatus = status
+
+	for _, known := range datapipeStatuses {
+		if known == status {
+			DatapipeStatus.WithLabelValues(string(known)).Set(1)
+		} else {
+			DatapipeStatus.WithLabelValues(string(known)).Set(0)
+		}
+	}
+}
+
+// MeasureAnalysisStep starts timing the analysis step given. The returned function records the time taken when called.
+func MeasureAnalysisStep(step string) func() {
+	timer := prometheus.NewTimer(AnalysisStepDuration.WithLabelValues(step))
+	return func() {
+		timer.ObserveDuration()
+	}
+}
+
+// ObserveBatchFlush records the objects of a chunk decoded from an ingest file of the data type given, along with the
+// time taken to write them since start
+func ObserveBatchFlush(dataType string, objects int, start time.Time) {
+	IngestObjectsDecoded.WithLabelValues(dataType).Add(float64(objects))
+	IngestBatchFlushDuration.Observe(time.Since(start).Seconds())
+}
+
+// UnresolvedEndpoint names the endpoints of a skipped relationship that could not be resolved
+func UnresolvedEndpoint(sourceResolved, targetResolved bool) string {
+	switch {
+	case !sourceResolved && !targetResolved:
+		return UnresolvedBoth
+	case !sourceResolved:
+		return UnresolvedSource
+	default:
+		return UnresolvedTarget
+	}
+}
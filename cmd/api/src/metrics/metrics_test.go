@@ -0,0 +1,65 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/specterops/bloodhound/cmd/api/src/metrics"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordDatapipeStatus(t *testing.T) {
+	var (
+		startingToIdle  = metrics.DatapipeStatusTransitions.WithLabelValues("starting", "idle")
+		idleToAnalyzing = metrics.DatapipeStatusTransitions.WithLabelValues("idle", "analyzing")
+	)
+
+	metrics.RecordDatapipeStatus(model.DatapipeStatusStarting)
+	metrics.RecordDatapipeStatus(model.DatapipeStatusIdle)
+	metrics.RecordDatapipeStatus(model.DatapipeStatusIdle)
+	metrics.RecordDatapipeStatus(model.DatapipeStatusAnalyzing)
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(startingToIdle))
+	assert.Equal(t, float64(1), testutil.ToFloat64(idleToAnalyzing))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.DatapipeStatus.WithLabelValues("analyzing")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.DatapipeStatus.WithLabelValues("idle")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.DatapipeStatus.WithLabelValues("starting")))
+}
+
+func TestMeasureAnalysisStep(t *testing.T) {
+	done := metrics.MeasureAnalysisStep(metrics.AnalysisStepAGI)
+	done()
+
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.AnalysisStepDuration, "bloodhound_analysis_step_duration_seconds"))
+}
+
+func TestObserveBatchFlush(t *testing.T) {
+	metrics.ObserveBatchFlush("computers", 500, time.Now())
+	metrics.ObserveBatchFlush("computers", 20, time.Now())
+
+	assert.Equal(t, float64(520), testutil.ToFloat64(metrics.IngestObjectsDecoded.WithLabelValues("computers")))
+}
+
+func TestUnresolvedEndpoint(t *testing.T) {
+	assert.Equal(t, metrics.UnresolvedBoth, metrics.UnresolvedEndpoint(false, false))
+	assert.Equal(t, metrics.UnresolvedSource, metrics.UnresolvedEndpoint(false, true))
+	assert.Equal(t, metrics.UnresolvedTarget, metrics.UnresolvedEndpoint(true, false))
+}
@@ -37,4 +37,5 @@ type AnalysisRequest struct {
 	DeleteAllGraph        bool           `json:"delete_all_graph"`                       // Deletes all nodes and edges in the graph
 	DeleteSourcelessGraph bool           `json:"delete_sourceless_graph"`                // Deletes all nodes and edges in the graph that have a type not registered in the source_kinds table
 	DeleteSourceKinds     pq.StringArray `gorm:"type:text[];column:delete_source_kinds"` // Deletes all nodes and edges per kind provided.
+	DeleteEnvironments    pq.StringArray `gorm:"type:text[];column:delete_environments"` // Limits the deletion to the nodes and edges tagged with one of the environments provided.
 }
@@ -0,0 +1,76 @@
+// Copyright 2023 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"time"
+
+	"github.com/specterops/bloodhound/cmd/api/src/database/types"
+	"github.com/specterops/bloodhound/cmd/api/src/database/types/null"
+)
+
+// AnalysisRunStatus is the outcome of an analysis run
+type AnalysisRunStatus string
+
+const (
+	AnalysisRunStatusRunning           AnalysisRunStatus = "running"
+	AnalysisRunStatusComplete          AnalysisRunStatus = "complete"
+	AnalysisRunStatusPartiallyComplete AnalysisRunStatus = "partially_complete"
+	AnalysisRunStatusFailed            AnalysisRunStatus = "failed"
+	AnalysisRunStatusCanceled          AnalysisRunStatus = "canceled"
+)
+
+// AnalysisRunStepStatus is the outcome of a step of an analysis run
+type AnalysisRunStepStatus string
+
+const (
+	AnalysisRunStepStatusComplete AnalysisRunStepStatus = "complete"
+	AnalysisRunStepStatusSkipped  AnalysisRunStepStatus = "skipped"
+	AnalysisRunStepStatusFailed   AnalysisRunStepStatus = "failed"
+)
+
+// AnalysisRun records a run of analysis by the datapipe along with the outcome of each of its steps
+type AnalysisRun struct {
+	StartedAt time.Time         `json:"started_at"`
+	EndedAt   null.Time         `json:"ended_at"`
+	Status    AnalysisRunStatus `json:"status"`
+
+	// Scoped is set if post-processing was limited to the domains and tenants touched by ingest
+	Scoped bool `json:"scoped"`
+
+	Steps AnalysisRunSteps `json:"steps" gorm:"foreignKey:RunID"`
+
+	BigSerial
+}
+
+type AnalysisRuns []AnalysisRun
+
+// AnalysisRunStep records a step of an analysis run. The relationships created and deleted by the step are counted by
+// relationship kind.
+type AnalysisRunStep struct {
+	RunID                int64                  `json:"run_id"`
+	Name                 string                 `json:"name"`
+	Status               AnalysisRunStepStatus  `json:"status"`
+	Error                string                 `json:"error"`
+	DurationMilliseconds int64                  `json:"duration_ms"`
+	RelationshipsCreated types.JSONBInt64Object `json:"relationships_created"`
+	RelationshipsDeleted types.JSONBInt64Object `json:"relationships_deleted"`
+
+	BigSerial
+}
+
+type AnalysisRunSteps []AnalysisRunStep
@@ -23,6 +23,8 @@ import (
 	"fmt"
 	"log/slog"
 	"reflect"
+	"regexp"
+	"slices"
 	"time"
 
 	iso8601 "github.com/channelmeter/iso8601duration"
@@ -31,6 +33,7 @@ import (
 	"github.com/specterops/bloodhound/cmd/api/src/utils"
 	"github.com/specterops/bloodhound/cmd/api/src/utils/validation"
 	"github.com/specterops/dawgs/drivers/neo4j"
+	"github.com/teambition/rrule-go"
 )
 
 type ParameterKey string
@@ -42,6 +45,9 @@ const (
 	CitrixRDPSupportKey      ParameterKey = "analysis.citrix_rdp_support"
 	PruneTTL                 ParameterKey = "prune.ttl"
 	ReconciliationKey        ParameterKey = "analysis.reconciliation"
+	IngestTransformRulesKey  ParameterKey = "ingest.transform_rules"
+	IngestRedactionKey       ParameterKey = "ingest.redaction"
+	PostProcessingKey        ParameterKey = "analysis.post_processing"
 
 	// The below keys are not intended to be user updateable, so should not be added to IsValidKey
 	ScheduledAnalysis          ParameterKey = "analysis.scheduled"
@@ -82,7 +88,7 @@ func (s *Parameter) Map(value any) error {
 
 func (s *Parameter) IsValidKey(parameterKey ParameterKey) bool {
 	switch parameterKey {
-	case PasswordExpirationWindow, Neo4jConfigs, PruneTTL, CitrixRDPSupportKey, ReconciliationKey:
+	case PasswordExpirationWindow, Neo4jConfigs, PruneTTL, CitrixRDPSupportKey, ReconciliationKey, IngestTransformRulesKey, IngestRedactionKey, PostProcessingKey:
 		return true
 	default:
 		return false
@@ -123,6 +129,12 @@ func (s *Parameter) Validate() utils.Errors {
 		v = &CitrixRDPSupport{}
 	case ReconciliationKey:
 		v = &ReconciliationParameter{}
+	case IngestTransformRulesKey:
+		v = &IngestTransformRulesParameter{}
+	case IngestRedactionKey:
+		v = &IngestRedactionParameter{}
+	case PostProcessingKey:
+		v = &PostProcessingParameter{}
 	case TierManagementParameterKey:
 		v = &TieringParameters{}
 	case ScheduledAnalysis:
@@ -334,6 +346,223 @@ func GetReconciliationParameter(ctx context.Context, service ParameterService) b
 	return result.Enabled
 }
 
+// PostProcessing
+
+// PostProcessingParameter lists the steps of post-processing that analysis must not run
+type PostProcessingParameter struct {
+	DisabledSteps []string `json:"disabled_steps"`
+}
+
+func GetPostProcessingParameter(ctx context.Context, service ParameterService) PostProcessingParameter {
+	var result PostProcessingParameter
+
+	if cfg, err := service.GetConfigurationParameter(ctx, PostProcessingKey); err != nil {
+		slog.WarnContext(ctx, "Failed to fetch post-processing configuration; returning default values")
+	} else if err := cfg.Map(&result); err != nil {
+		slog.WarnContext(ctx, fmt.Sprintf("Invalid post-processing configuration supplied, %v. returning default values.", err))
+	}
+
+	return result
+}
+
+// IngestTransformRules
+
+const (
+	IngestTransformRename       = "rename"
+	IngestTransformDrop         = "drop"
+	IngestTransformCoerce       = "coerce"
+	IngestTransformRegexExtract = "regex_extract"
+	IngestTransformDerive       = "derive"
+)
+
+const (
+	IngestTransformTypeString = "string"
+	IngestTransformTypeInt    = "int"
+	IngestTransformTypeFloat  = "float"
+	IngestTransformTypeBool   = "bool"
+)
+
+// IngestTransformRule changes a property of ingested nodes of a kind before they are written to the graph:
+//
+//   - rename moves property to target
+//   - drop removes property
+//   - coerce converts property to type
+//   - regex_extract sets target, or property when target is empty, to the first capture group of pattern found in
+//     property, or to the whole match when pattern has no capture groups
+//   - derive sets target to template with every {name} placeholder replaced by the value of the named property
+type IngestTransformRule struct {
+	Kind     string `json:"kind"`
+	Action   string `json:"action"`
+	Property string `json:"property,omitempty"`
+	Target   string `json:"target,omitempty"`
+	Type     string `json:"type,omitempty"`
+	Pattern  string `json:"pattern,omitempty"`
+	Template string `json:"template,omitempty"`
+}
+
+// Validate checks that the rule has the fields its action requires
+func (s IngestTransformRule) Validate() error {
+	if s.Kind == "" {
+		return errors.New("kind is required")
+	}
+
+	switch s.Action {
+	case IngestTransformRename:
+		if s.Property == "" || s.Target == "" {
+			return errors.New("rename requires property and target")
+		}
+
+	case IngestTransformDrop:
+		if s.Property == "" {
+			return errors.New("drop requires property")
+		}
+
+	case IngestTransformCoerce:
+		if s.Property == "" {
+			return errors.New("coerce requires property")
+		}
+
+		switch s.Type {
+		case IngestTransformTypeString, IngestTransformTypeInt, IngestTransformTypeFloat, IngestTransformTypeBool:
+		default:
+			return fmt.Errorf("coerce type must be one of string, int, float or bool: %q", s.Type)
+		}
+
+	case IngestTransformRegexExtract:
+		if s.Property == "" || s.Pattern == "" {
+			return errors.New("regex_extract requires property and pattern")
+		} else if _, err := regexp.Compile(s.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+
+	case IngestTransformDerive:
+		if s.Target == "" || s.Template == "" {
+			return errors.New("derive requires target and template")
+		}
+
+	default:
+		return fmt.Errorf("unknown action: %q", s.Action)
+	}
+
+	return nil
+}
+
+type IngestTransformRulesParameter struct {
+	Rules []IngestTransformRule `json:"rules"`
+}
+
+// Rules are validated when they are read so that a bad rule is rejected before it is saved
+func (s *IngestTransformRulesParameter) UnmarshalJSON(data []byte) error {
+	var rules struct {
+		Rules []IngestTransformRule `json:"rules"`
+	}
+
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return fmt.Errorf("error unmarshaling data for IngestTransformRulesParameter: %w", err)
+	}
+
+	for idx, rule := range rules.Rules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("invalid ingest transform rule %d: %w", idx, err)
+		}
+	}
+
+	s.Rules = rules.Rules
+	return nil
+}
+
+func GetIngestTransformRules(ctx context.Context, service ParameterService) []IngestTransformRule {
+	var result IngestTransformRulesParameter
+
+	if cfg, err := service.GetConfigurationParameter(ctx, IngestTransformRulesKey); err != nil {
+		slog.WarnContext(ctx, "Failed to fetch ingest transform rules; no rules will be applied")
+	} else if err := cfg.Map(&result); err != nil {
+		slog.WarnContext(ctx, fmt.Sprintf("Invalid ingest transform rules supplied, %v. no rules will be applied.", err))
+	}
+
+	return result.Rules
+}
+
+// IngestRedaction
+
+const (
+	IngestRedactionHash = "hash"
+	IngestRedactionMask = "mask"
+	IngestRedactionDrop = "drop"
+)
+
+// IngestRedactionRule redacts matching properties of ingested nodes and relationships before they are written to the
+// graph. A property matches if its name is one of properties or matches property_pattern, and, when value_pattern is
+// set, its value is a string that matches value_pattern. Property names are matched case-insensitively.
+//
+//   - hash replaces the value with its SHA-256 digest
+//   - mask replaces the value, or only the parts of it matching value_pattern when set, with a fixed mask
+//   - drop removes the property
+type IngestRedactionRule struct {
+	Properties      []string `json:"properties,omitempty"`
+	PropertyPattern string   `json:"property_pattern,omitempty"`
+	ValuePattern    string   `json:"value_pattern,omitempty"`
+	Action          string   `json:"action"`
+}
+
+// Validate checks that the rule selects properties with valid patterns and has a known action
+func (s IngestRedactionRule) Validate() error {
+	if len(s.Properties) == 0 && s.PropertyPattern == "" {
+		return errors.New("properties or property_pattern is required")
+	} else if slices.Contains(s.Properties, "") {
+		return errors.New("properties must not be empty")
+	} else if _, err := regexp.Compile(s.PropertyPattern); err != nil {
+		return fmt.Errorf("invalid property_pattern: %w", err)
+	} else if _, err := regexp.Compile(s.ValuePattern); err != nil {
+		return fmt.Errorf("invalid value_pattern: %w", err)
+	}
+
+	switch s.Action {
+	case IngestRedactionHash, IngestRedactionMask, IngestRedactionDrop:
+		return nil
+	default:
+		return fmt.Errorf("action must be one of hash, mask or drop: %q", s.Action)
+	}
+}
+
+type IngestRedactionParameter struct {
+	Rules []IngestRedactionRule `json:"rules"`
+}
+
+// Rules are validated when they are read so that a bad rule is rejected before it is saved
+func (s *IngestRedactionParameter) UnmarshalJSON(data []byte) error {
+	var rules struct {
+		Rules []IngestRedactionRule `json:"rules"`
+	}
+
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return fmt.Errorf("error unmarshaling data for IngestRedactionParameter: %w", err)
+	}
+
+	for idx, rule := range rules.Rules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("invalid ingest redaction rule %d: %w", idx, err)
+		}
+	}
+
+	s.Rules = rules.Rules
+	return nil
+}
+
+// GetIngestRedactionRules returns the configured redaction rules. Unlike other parameters, an error is returned when
+// the rules can not be read so that ingest does not carry on without redacting the properties it was told to.
+func GetIngestRedactionRules(ctx context.Context, service ParameterService) ([]IngestRedactionRule, error) {
+	var result IngestRedactionParameter
+
+	if cfg, err := service.GetConfigurationParameter(ctx, IngestRedactionKey); err != nil {
+		return nil, fmt.Errorf("failed to fetch ingest redaction rules: %w", err)
+	} else if err := cfg.Map(&result); err != nil {
+		return nil, fmt.Errorf("invalid ingest redaction rules: %w", err)
+	}
+
+	return result.Rules, nil
+}
+
 type ScheduledAnalysisParameter struct {
 	Enabled bool   `json:"enabled,omitempty"`
 	RRule   string `json:"rrule,omitempty" validate:"rrule"`
@@ -351,6 +580,17 @@ func GetScheduledAnalysisParameter(ctx context.Context, service ParameterService
 	return result, nil
 }
 
+// NextRun returns the first occurrence of the schedule strictly after the time given
+func (s ScheduledAnalysisParameter) NextRun(after time.Time) (time.Time, error) {
+	if rule, err := rrule.StrToRRule(s.RRule); err != nil {
+		return time.Time{}, fmt.Errorf("invalid analysis schedule: %w", err)
+	} else if next := rule.After(after, false); next.IsZero() {
+		return time.Time{}, fmt.Errorf("analysis schedule has no occurrence after %s", after.Format(time.RFC3339))
+	} else {
+		return next.UTC(), nil
+	}
+}
+
 type TrustedProxiesParameters struct {
 	TrustedProxies int `json:"trusted_proxies,omitempty"`
 }
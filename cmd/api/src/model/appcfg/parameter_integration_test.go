@@ -131,6 +131,10 @@ func TestParameters_GetReconciliationParameter(t *testing.T) {
 	require.True(t, appcfg.GetReconciliationParameter(context.Background(), integration.SetupDB(t)))
 }
 
+func TestParameters_GetPostProcessingParameter(t *testing.T) {
+	require.Empty(t, appcfg.GetPostProcessingParameter(context.Background(), integration.SetupDB(t)).DisabledSteps)
+}
+
 func TestParameters_GetTieringParameters(t *testing.T) {
 	result := appcfg.TieringParameters{
 		TierLimit:                appcfg.DefaultTierLimit,
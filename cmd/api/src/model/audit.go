@@ -82,6 +82,8 @@ const (
 
 	AuditLogActionMutateGraph AuditLogAction = "MutateGraph"
 
+	AuditLogActionRedactIngestedProperties AuditLogAction = "RedactIngestedProperties"
+
 	AuditLogActionUpdateParameter AuditLogAction = "UpdateParameter"
 
 	AuditLogActionCreateAssetGroupTag         AuditLogAction = "CreateAssetGroupTag"
@@ -95,6 +97,9 @@ const (
 	AuditLogActionUpdateCustomNodeKind AuditLogAction = "UpdateCustomNodeKind"
 	AuditLogActionDeleteCustomNodeKind AuditLogAction = "DeleteCustomNodeKind"
 
+	AuditLogActionCreateSourceKind AuditLogAction = "CreateSourceKind"
+	AuditLogActionUpdateSourceKind AuditLogAction = "UpdateSourceKind"
+
 	AuditLogActionToggleEarlyAccessFeatureFlag AuditLogAction = "ToggleEarlyAccessFeatureFlag"
 
 	AuditLogActionCreateClient       AuditLogAction = "CreateClient"
@@ -16,7 +16,11 @@
 
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/specterops/bloodhound/cmd/api/src/database/types/null"
+)
 
 type DatapipeStatus string
 
@@ -34,4 +38,11 @@ type DatapipeStatusWrapper struct {
 	UpdatedAt              time.Time      `json:"updated_at"`
 	LastCompleteAnalysisAt time.Time      `json:"last_complete_analysis_at"`
 	LastAnalysisRunAt      time.Time      `json:"last_analysis_run_at"`
+
+	// NextScheduledAnalysisAt is the next occurrence of the analysis schedule. It is only set while scheduled analysis
+	// is enabled.
+	NextScheduledAnalysisAt null.Time `json:"next_scheduled_analysis_at"`
+
+	// CancelRequested is set once the step the datapipe is running was asked to stop, until the datapipe status changes
+	CancelRequested bool `json:"cancel_requested"`
 }
@@ -17,7 +17,12 @@
 package model
 
 import (
+	"slices"
+
 	"github.com/specterops/bloodhound/cmd/api/src/database/types/null"
+	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
+	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
+	"github.com/specterops/bloodhound/packages/go/graphschema/azure"
 )
 
 type IngestTask struct {
@@ -26,6 +31,20 @@ type IngestTask struct {
 	JobId       null.Int64 `json:"task_id" gorm:"column:task_id"`
 	FileType    FileType   `json:"file_type"`
 
+	// OriginalFileName is the name the file was uploaded with. FileName is the temporary file it was stored as.
+	OriginalFileName string `json:"original_file_name"`
+
+	// Partial marks a task whose file is still being assembled from chunked uploads. Partial tasks are not
+	// picked up for ingest until their final chunk has been received and the assembled file has been validated.
+	Partial    bool  `json:"partial"`
+	TotalBytes int64 `json:"total_bytes"`
+
+	// CSVMapping describes how the rows of a CSV file are converted to OpenGraph data. It is only set for CSV files.
+	CSVMapping *ingest.CSVMapping `json:"csv_mapping,omitempty" gorm:"column:csv_mapping"`
+
+	// Environment is the environment label of the ingest job the file was uploaded to
+	Environment string `json:"environment"`
+
 	BigSerial
 }
 
@@ -36,4 +55,122 @@ type FileType int
 const (
 	FileTypeJson FileType = iota
 	FileTypeZip
+	FileTypeGzip
+	FileTypeTarGzip
+	FileTypeZstd
+
+	// FileTypeNDJSON is an OpenGraph payload written as newline-delimited JSON, one record per line
+	FileTypeNDJSON
+
+	// FileTypeCSV is a CSV file converted to OpenGraph data with the column mapping stored on its ingest task
+	FileTypeCSV
 )
+
+// IsArchive returns true for file types that must be decompressed before ingest. The contents of archives are not
+// inspected at upload time, so they are schema validated when read for ingest instead.
+func (s FileType) IsArchive() bool {
+	switch s {
+	case FileTypeZip, FileTypeGzip, FileTypeTarGzip, FileTypeZstd:
+		return true
+	default:
+		return false
+	}
+}
+
+// IngestFileResult records the outcome of a single file processed for an ingest job. Files extracted from an archive
+// each get their own result, so that a failure can be traced back to the file in the collection that caused it.
+type IngestFileResult struct {
+	JobID                    int64           `json:"job_id"`
+	FileName                 string          `json:"file_name"`
+	DataType                 ingest.DataType `json:"data_type"`
+	NodeCount                int64           `json:"node_count"`
+	RelationshipCount        int64           `json:"relationship_count"`
+	SkippedRelationshipCount int64           `json:"skipped_relationship_count"`
+	InvalidRelationshipCount int64           `json:"invalid_relationship_count"`
+	DeletedNodeCount         int64           `json:"deleted_node_count"`
+	DeletedRelationshipCount int64           `json:"deleted_relationship_count"`
+	Error                    string          `json:"error"`
+
+	// CollectedDomains and CollectedSourceKinds are the collection scope seen in the file. They are accumulated on the
+	// ingest job rather than stored with the file result.
+	CollectedDomains     []string `json:"-" gorm:"-"`
+	CollectedSourceKinds []string `json:"-" gorm:"-"`
+
+	// RedactedProperties are the names of the properties redacted from the file, accumulated on the ingest job
+	RedactedProperties []string `json:"-" gorm:"-"`
+
+	// TouchedDomains, TouchedTenants and UnscopedObjects record the domains and tenants the file wrote to, accumulated
+	// on the ingest job to limit the analysis that follows
+	TouchedDomains  []string `json:"-" gorm:"-"`
+	TouchedTenants  []string `json:"-" gorm:"-"`
+	UnscopedObjects int64    `json:"-" gorm:"-"`
+
+	BigSerial
+}
+
+// Failed returns true if any part of the file could not be ingested
+func (s IngestFileResult) Failed() bool {
+	return s.Error != ""
+}
+
+type IngestFileResults []IngestFileResult
+
+// Failed returns the number of files that could not be fully ingested
+func (s IngestFileResults) Failed() int {
+	failed := 0
+	for _, result := range s {
+		if result.Failed() {
+			failed++
+		}
+	}
+	return failed
+}
+
+// IngestedSourceKinds returns the source kinds of the data written by the files: the OpenGraph source kinds they
+// collected, and the base kind of the AD or Azure data they hold. Files that wrote nothing are not counted.
+func (s IngestFileResults) IngestedSourceKinds() []string {
+	var sourceKinds []string
+
+	appendSourceKinds := func(kinds ...string) {
+		for _, kind := range kinds {
+			if !slices.Contains(sourceKinds, kind) {
+				sourceKinds = append(sourceKinds, kind)
+			}
+		}
+	}
+
+	for _, result := range s {
+		if result.NodeCount == 0 && result.RelationshipCount == 0 {
+			continue
+		}
+
+		switch result.DataType {
+		case "", ingest.DataTypeOpenGraph:
+		case ingest.DataTypeAzure:
+			appendSourceKinds(azure.Entity.String())
+		case ingest.DataTypeApocExport:
+			// an export of a legacy database may hold both AD and Azure data
+			appendSourceKinds(ad.Entity.String(), azure.Entity.String())
+		default:
+			appendSourceKinds(ad.Entity.String())
+		}
+
+		appendSourceKinds(result.CollectedSourceKinds...)
+	}
+
+	return sourceKinds
+}
+
+// IngestedObject records an object pulled from an S3-compatible bucket for ingest. An object is only pulled again once
+// its ETag changes. Objects that failed validation are recorded with the error and no job.
+type IngestedObject struct {
+	Bucket string     `json:"bucket"`
+	Key    string     `json:"key" gorm:"column:object_key"`
+	ETag   string     `json:"etag" gorm:"column:etag"`
+	JobID  null.Int64 `json:"job_id"`
+	Error  string     `json:"error"`
+
+	BigSerial
+}
+
+type IngestedObjects []IngestedObject
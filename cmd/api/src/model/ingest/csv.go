@@ -0,0 +1,290 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ingest
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"unicode/utf8"
+)
+
+// Types a CSV column can be converted to when it is mapped to a property
+const (
+	CSVPropertyTypeString  = "string"
+	CSVPropertyTypeInteger = "integer"
+	CSVPropertyTypeFloat   = "float"
+	CSVPropertyTypeBoolean = "boolean"
+	CSVPropertyTypeArray   = "array"
+)
+
+// CSVListSeparator separates the values of a single CSV cell that holds a list, such as a kinds column or an array
+// property
+const CSVListSeparator = ";"
+
+// Strategies that can be used to match the endpoints of an edge row, mirroring those of OpenGraph edges
+const (
+	CSVMatchByID       = "id"
+	CSVMatchByName     = "name"
+	CSVMatchByProperty = "property"
+)
+
+// maxCSVNodeKinds is the number of kinds a node may be given by its mapping, matching the limit on the kinds of the
+// nodes of a JSON OpenGraph payload. The source kind is not counted.
+const maxCSVNodeKinds = 3
+
+var (
+	csvPropertyTypes       = []string{CSVPropertyTypeString, CSVPropertyTypeInteger, CSVPropertyTypeFloat, CSVPropertyTypeBoolean, CSVPropertyTypeArray}
+	csvMatchStrategies     = []string{CSVMatchByID, CSVMatchByName, CSVMatchByProperty}
+	csvPropertyNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	ErrCSVMappingMissing = errors.New("a csv mapping is required to ingest a csv file")
+)
+
+// CSVMapping describes how the rows of a CSV file are converted to OpenGraph data. A file holds either nodes or
+// edges; the first row of the file is a header naming its columns.
+type CSVMapping struct {
+	SourceKind string          `json:"source_kind,omitempty"`
+	Delimiter  string          `json:"delimiter,omitempty"`
+	Node       *CSVNodeMapping `json:"node,omitempty"`
+	Edge       *CSVEdgeMapping `json:"edge,omitempty"`
+}
+
+// CSVNodeMapping converts each row to a node. Kinds may be given for every row, read from a column, or both.
+type CSVNodeMapping struct {
+	IDColumn    string               `json:"id_column"`
+	Kinds       []string             `json:"kinds,omitempty"`
+	KindsColumn string               `json:"kinds_column,omitempty"`
+	Properties  []CSVPropertyMapping `json:"properties,omitempty"`
+}
+
+// CSVEdgeMapping converts each row to an edge. The kind is either given for every row or read from a column.
+type CSVEdgeMapping struct {
+	Kind       string               `json:"kind,omitempty"`
+	KindColumn string               `json:"kind_column,omitempty"`
+	Start      CSVEndpointMapping   `json:"start"`
+	End        CSVEndpointMapping   `json:"end"`
+	Properties []CSVPropertyMapping `json:"properties,omitempty"`
+}
+
+// CSVEndpointMapping resolves an endpoint of an edge row from the value of a column, matched by ID (the default), by
+// name or by the property given
+type CSVEndpointMapping struct {
+	Column        string `json:"column"`
+	MatchBy       string `json:"match_by,omitempty"`
+	Property      string `json:"property,omitempty"`
+	Kind          string `json:"kind,omitempty"`
+	CaseSensitive bool   `json:"case_sensitive,omitempty"`
+}
+
+// CSVPropertyMapping maps a column to a property. The property is named after the column unless a name is given, and
+// is a string unless a type is given. Empty cells are not written.
+type CSVPropertyMapping struct {
+	Column string `json:"column"`
+	Name   string `json:"name,omitempty"`
+	Type   string `json:"type,omitempty"`
+}
+
+func (s CSVPropertyMapping) PropertyName() string {
+	if s.Name == "" {
+		return s.Column
+	}
+
+	return s.Name
+}
+
+func (s CSVPropertyMapping) PropertyType() string {
+	if s.Type == "" {
+		return CSVPropertyTypeString
+	}
+
+	return s.Type
+}
+
+func (s CSVEndpointMapping) MatchStrategy() string {
+	if s.MatchBy == "" {
+		return CSVMatchByID
+	}
+
+	return s.MatchBy
+}
+
+// Comma returns the field delimiter of the file, a comma unless another delimiter is given
+func (s CSVMapping) Comma() rune {
+	if s.Delimiter == "" {
+		return ','
+	}
+
+	delimiter, _ := utf8.DecodeRuneInString(s.Delimiter)
+	return delimiter
+}
+
+// Columns returns every column the mapping reads, in the order they are declared
+func (s CSVMapping) Columns() []string {
+	var columns []string
+
+	appendColumn := func(column string) {
+		if column != "" && !slices.Contains(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+
+	if s.Node != nil {
+		appendColumn(s.Node.IDColumn)
+		appendColumn(s.Node.KindsColumn)
+
+		for _, property := range s.Node.Properties {
+			appendColumn(property.Column)
+		}
+	}
+
+	if s.Edge != nil {
+		appendColumn(s.Edge.KindColumn)
+		appendColumn(s.Edge.Start.Column)
+		appendColumn(s.Edge.End.Column)
+
+		for _, property := range s.Edge.Properties {
+			appendColumn(property.Column)
+		}
+	}
+
+	return columns
+}
+
+// Validate returns every problem with the mapping that would prevent a CSV file from being converted with it
+func (s CSVMapping) Validate() []string {
+	var problems []string
+
+	if s.Delimiter != "" && (utf8.RuneCountInString(s.Delimiter) != 1 || s.Delimiter == "\"" || s.Delimiter == "\r" || s.Delimiter == "\n") {
+		problems = append(problems, "delimiter must be a single character other than a quote or line break")
+	}
+
+	switch {
+	case s.Node == nil && s.Edge == nil:
+		problems = append(problems, "mapping must have either a node or an edge section")
+	case s.Node != nil && s.Edge != nil:
+		problems = append(problems, "mapping must have either a node or an edge section, not both")
+	case s.Node != nil:
+		problems = append(problems, s.Node.validate(s.SourceKind != "")...)
+	default:
+		problems = append(problems, s.Edge.validate()...)
+	}
+
+	return problems
+}
+
+func (s CSVNodeMapping) validate(hasSourceKind bool) []string {
+	var problems []string
+
+	if s.IDColumn == "" {
+		problems = append(problems, "node.id_column is required")
+	}
+
+	if len(s.Kinds) == 0 && s.KindsColumn == "" && !hasSourceKind {
+		problems = append(problems, "node must have kinds, a kinds_column or a source_kind")
+	} else if len(s.Kinds) > maxCSVNodeKinds {
+		problems = append(problems, fmt.Sprintf("node may have at most %d kinds", maxCSVNodeKinds))
+	}
+
+	for idx, kind := range s.Kinds {
+		if kind == "" {
+			problems = append(problems, fmt.Sprintf("node.kinds[%d] must not be empty", idx))
+		}
+	}
+
+	return append(problems, validateCSVProperties("node", s.Properties)...)
+}
+
+func (s CSVEdgeMapping) validate() []string {
+	var problems []string
+
+	if (s.Kind == "") == (s.KindColumn == "") {
+		problems = append(problems, "edge must have exactly one of kind or kind_column")
+	}
+
+	problems = append(problems, s.Start.validate("edge.start")...)
+	problems = append(problems, s.End.validate("edge.end")...)
+
+	return append(problems, validateCSVProperties("edge", s.Properties)...)
+}
+
+func (s CSVEndpointMapping) validate(location string) []string {
+	var problems []string
+
+	if s.Column == "" {
+		problems = append(problems, fmt.Sprintf("%s.column is required", location))
+	}
+
+	if !slices.Contains(csvMatchStrategies, s.MatchStrategy()) {
+		problems = append(problems, fmt.Sprintf("%s.match_by must be one of %v", location, csvMatchStrategies))
+	} else if s.MatchStrategy() == CSVMatchByProperty && s.Property == "" {
+		problems = append(problems, fmt.Sprintf("%s.property is required when matching by property", location))
+	} else if s.MatchStrategy() == CSVMatchByProperty && !csvPropertyNamePattern.MatchString(s.Property) {
+		problems = append(problems, fmt.Sprintf("%s.property %q must match %s", location, s.Property, csvPropertyNamePattern))
+	} else if s.MatchStrategy() != CSVMatchByProperty && s.Property != "" {
+		problems = append(problems, fmt.Sprintf("%s.property is only used when matching by property", location))
+	}
+
+	return problems
+}
+
+func validateCSVProperties(location string, properties []CSVPropertyMapping) []string {
+	var (
+		problems []string
+		names    = make([]string, 0, len(properties))
+	)
+
+	for idx, property := range properties {
+		propertyLocation := fmt.Sprintf("%s.properties[%d]", location, idx)
+
+		if property.Column == "" {
+			problems = append(problems, fmt.Sprintf("%s.column is required", propertyLocation))
+		} else if !csvPropertyNamePattern.MatchString(property.PropertyName()) {
+			problems = append(problems, fmt.Sprintf("%s property name %q must match %s", propertyLocation, property.PropertyName(), csvPropertyNamePattern))
+		} else if slices.Contains(names, property.PropertyName()) {
+			problems = append(problems, fmt.Sprintf("%s property %q is mapped more than once", propertyLocation, property.PropertyName()))
+		} else {
+			names = append(names, property.PropertyName())
+		}
+
+		if !slices.Contains(csvPropertyTypes, property.PropertyType()) {
+			problems = append(problems, fmt.Sprintf("%s.type must be one of %v", propertyLocation, csvPropertyTypes))
+		}
+	}
+
+	return problems
+}
+
+func (s *CSVMapping) Scan(value any) error {
+	if value == nil {
+		*s = CSVMapping{}
+		return nil
+	}
+
+	if bytes, ok := value.([]byte); !ok {
+		return errors.New("type assertion to []byte failed for CSVMapping")
+	} else {
+		return json.Unmarshal(bytes, s)
+	}
+}
+
+func (s CSVMapping) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
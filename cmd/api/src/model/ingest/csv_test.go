@@ -0,0 +1,155 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ingest_test
+
+import (
+	"testing"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVMapping_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		mapping  ingest.CSVMapping
+		expected []string
+	}{
+		{
+			name: "valid node mapping",
+			mapping: ingest.CSVMapping{
+				SourceKind: "GithubBase",
+				Delimiter:  ";",
+				Node: &ingest.CSVNodeMapping{
+					IDColumn:    "id",
+					KindsColumn: "kinds",
+					Properties:  []ingest.CSVPropertyMapping{{Column: "login"}, {Column: "repos", Name: "repo_count", Type: ingest.CSVPropertyTypeInteger}},
+				},
+			},
+		},
+		{
+			name: "valid edge mapping",
+			mapping: ingest.CSVMapping{
+				Edge: &ingest.CSVEdgeMapping{
+					Kind:  "GithubMemberOf",
+					Start: ingest.CSVEndpointMapping{Column: "user", MatchBy: ingest.CSVMatchByName},
+					End:   ingest.CSVEndpointMapping{Column: "team", MatchBy: ingest.CSVMatchByProperty, Property: "slug"},
+				},
+			},
+		},
+		{
+			name:     "no section",
+			mapping:  ingest.CSVMapping{},
+			expected: []string{"mapping must have either a node or an edge section"},
+		},
+		{
+			name:     "both sections",
+			mapping:  ingest.CSVMapping{Node: &ingest.CSVNodeMapping{IDColumn: "id", Kinds: []string{"A"}}, Edge: &ingest.CSVEdgeMapping{}},
+			expected: []string{"mapping must have either a node or an edge section, not both"},
+		},
+		{
+			name: "invalid node mapping",
+			mapping: ingest.CSVMapping{
+				Delimiter: "||",
+				Node: &ingest.CSVNodeMapping{
+					Kinds: []string{"A", "", "C", "D"},
+					Properties: []ingest.CSVPropertyMapping{
+						{Column: "login"},
+						{Column: "bad name"},
+						{Column: "user", Name: "login", Type: "date"},
+					},
+				},
+			},
+			expected: []string{
+				"delimiter must be a single character other than a quote or line break",
+				"node.id_column is required",
+				"node may have at most 3 kinds",
+				"node.kinds[1] must not be empty",
+				`node.properties[1] property name "bad name" must match ^[A-Za-z_][A-Za-z0-9_]*$`,
+				`node.properties[2] property "login" is mapped more than once`,
+				"node.properties[2].type must be one of [string integer float boolean array]",
+			},
+		},
+		{
+			name:     "node without kinds",
+			mapping:  ingest.CSVMapping{Node: &ingest.CSVNodeMapping{IDColumn: "id"}},
+			expected: []string{"node must have kinds, a kinds_column or a source_kind"},
+		},
+		{
+			name: "invalid edge mapping",
+			mapping: ingest.CSVMapping{
+				Edge: &ingest.CSVEdgeMapping{
+					Kind:       "GithubMemberOf",
+					KindColumn: "kind",
+					Start:      ingest.CSVEndpointMapping{MatchBy: "email"},
+					End:        ingest.CSVEndpointMapping{Column: "team", MatchBy: ingest.CSVMatchByProperty},
+					Properties: []ingest.CSVPropertyMapping{{}},
+				},
+			},
+			expected: []string{
+				"edge must have exactly one of kind or kind_column",
+				"edge.start.column is required",
+				"edge.start.match_by must be one of [id name property]",
+				"edge.end.property is required when matching by property",
+				"edge.properties[0].column is required",
+			},
+		},
+		{
+			name: "property given without matching by property",
+			mapping: ingest.CSVMapping{
+				Edge: &ingest.CSVEdgeMapping{
+					KindColumn: "kind",
+					Start:      ingest.CSVEndpointMapping{Column: "user", Property: "login"},
+					End:        ingest.CSVEndpointMapping{Column: "team"},
+				},
+			},
+			expected: []string{"edge.start.property is only used when matching by property"},
+		},
+		{
+			name: "invalid property matched by",
+			mapping: ingest.CSVMapping{
+				Edge: &ingest.CSVEdgeMapping{
+					KindColumn: "kind",
+					Start:      ingest.CSVEndpointMapping{Column: "user"},
+					End:        ingest.CSVEndpointMapping{Column: "team", MatchBy: ingest.CSVMatchByProperty, Property: "x') is not null; --"},
+				},
+			},
+			expected: []string{`edge.end.property "x') is not null; --" must match ^[A-Za-z_][A-Za-z0-9_]*$`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.mapping.Validate())
+		})
+	}
+}
+
+func TestCSVMapping_Columns(t *testing.T) {
+	mapping := ingest.CSVMapping{
+		Edge: &ingest.CSVEdgeMapping{
+			KindColumn: "kind",
+			Start:      ingest.CSVEndpointMapping{Column: "user"},
+			End:        ingest.CSVEndpointMapping{Column: "team"},
+			Properties: []ingest.CSVPropertyMapping{{Column: "role"}, {Column: "user", Name: "user_id"}},
+		},
+	}
+
+	assert.Equal(t, []string{"kind", "user", "team", "role"}, mapping.Columns())
+	assert.Equal(t, ',', mapping.Comma())
+	assert.Equal(t, '\t', ingest.CSVMapping{Delimiter: "\t"}.Comma())
+}
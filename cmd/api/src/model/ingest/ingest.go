@@ -19,6 +19,7 @@ package ingest
 import (
 	"encoding/json"
 	"errors"
+	"slices"
 
 	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
 	"github.com/specterops/bloodhound/packages/go/mediatypes"
@@ -31,7 +32,41 @@ var AllowedZipFileUploadTypes = []string{
 	"application/zip-compressed",   // Not currently available in mediatypes
 }
 
-var AllowedFileUploadTypes = append([]string{mediatypes.ApplicationJson.String()}, AllowedZipFileUploadTypes...)
+var AllowedGzipFileUploadTypes = []string{
+	mediatypes.ApplicationGzip.String(),
+	"application/x-gzip", // Not currently available in mediatypes
+}
+
+var AllowedTarGzipFileUploadTypes = []string{
+	"application/x-gtar",           // Not currently available in mediatypes
+	"application/x-tgz",            // Not currently available in mediatypes
+	"application/x-compressed-tar", // Not currently available in mediatypes
+}
+
+var AllowedZstdFileUploadTypes = []string{
+	mediatypes.ApplicationZstd.String(),
+}
+
+// AllowedNDJSONFileUploadTypes are the content types of OpenGraph payloads written as newline-delimited JSON records
+var AllowedNDJSONFileUploadTypes = []string{
+	"application/x-ndjson", // Not currently available in mediatypes
+}
+
+// AllowedCSVFileUploadTypes are the content types of CSV uploads, which are sent as a multipart form holding the column
+// mapping of the file followed by the CSV file itself
+var AllowedCSVFileUploadTypes = []string{
+	mediatypes.MultipartFormData.String(),
+}
+
+var AllowedFileUploadTypes = slices.Concat(
+	[]string{mediatypes.ApplicationJson.String()},
+	AllowedNDJSONFileUploadTypes,
+	AllowedCSVFileUploadTypes,
+	AllowedZipFileUploadTypes,
+	AllowedGzipFileUploadTypes,
+	AllowedTarGzipFileUploadTypes,
+	AllowedZstdFileUploadTypes,
+)
 
 type Metadata struct {
 	Type    DataType         `json:"type"`
@@ -104,6 +139,17 @@ const (
 	DataTypeAzure          DataType = "azure"
 	DataTypeIssuancePolicy DataType = "issuancepolicies"
 	DataTypeOpenGraph      DataType = "opengraph"
+
+	// DataTypeApocExport is a dump of a BloodHound Legacy Neo4j database written by the APOC JSON export procedures
+	DataTypeApocExport DataType = "apoc_export"
+)
+
+// Versions of the SharpHound collection format that changed the layout of collected data. Collections written before
+// LegacyFormatVersionCutoff are converted from their legacy layout; v3 collections also stored their data under a key
+// named after their data type rather than "data".
+const (
+	FormatVersionV3           = 3
+	LegacyFormatVersionCutoff = 5
 )
 
 func AllIngestDataTypes() []DataType {
@@ -222,6 +268,8 @@ var (
 	ErrInvalidDataTag      = errors.New("invalid data tag found")
 	ErrJSONDecoderInternal = errors.New("json decoder internal error")
 	ErrInvalidZipFile      = errors.New("failed to find zip file header")
+	ErrInvalidGzipFile     = errors.New("failed to find gzip file header")
+	ErrInvalidZstdFile     = errors.New("failed to find zstd frame header")
 	ErrMixedIngestFormat   = errors.New("request must use either the classic format (meta/data) or the generic format (graph), not both")
 
 	ErrOpenGraphMetaTagValidation = errors.New("metadata tag is invalid")
@@ -18,10 +18,12 @@ package model
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/gofrs/uuid"
+	"github.com/lib/pq"
 	"github.com/specterops/bloodhound/cmd/api/src/database/types/null"
 )
 
@@ -36,14 +38,91 @@ type IngestJob struct {
 	LastIngest       time.Time   `json:"last_ingest"`
 	TotalFiles       int         `json:"total_files"`
 	FailedFiles      int         `json:"failed_files"`
+
+	// AuthoritativeMode controls what happens to graph data within the scope of the collection that was not seen by
+	// this job. CollectedDomains and CollectedSourceKinds record that scope as files are ingested, and StaleNodes and
+	// StaleRelationships report how many objects were flagged or deleted once ingest finished.
+	AuthoritativeMode    AuthoritativeMode `json:"authoritative_mode"`
+	CollectedDomains     pq.StringArray    `json:"collected_domains" gorm:"type:text[];column:collected_domains"`
+	CollectedSourceKinds pq.StringArray    `json:"collected_source_kinds" gorm:"type:text[];column:collected_source_kinds"`
+	StaleNodes           int64             `json:"stale_nodes"`
+	StaleRelationships   int64             `json:"stale_relationships"`
+
+	// RedactedProperties are the names of the properties redacted by the ingest redaction rules from any file of this
+	// job
+	RedactedProperties pq.StringArray `json:"redacted_properties" gorm:"type:text[];column:redacted_properties"`
+
+	// DeletedNodes and DeletedRelationships count the graph data removed by the deletion directives of the OpenGraph
+	// files of this job
+	DeletedNodes         int64 `json:"deleted_nodes"`
+	DeletedRelationships int64 `json:"deleted_relationships"`
+
+	// Environment is the label of the environment the data of this job belongs to. Every node and relationship
+	// written by the job is tagged with it, and the stale data of an authoritative job is only looked for within it.
+	Environment string `json:"environment"`
+
+	// TouchedDomains and TouchedTenants are the domains and tenants written to by this job. Analysis of the job only
+	// post-processes those, unless UnscopedObjects counts data that could not be attributed to any of them.
+	TouchedDomains  pq.StringArray `json:"touched_domains" gorm:"type:text[];column:touched_domains"`
+	TouchedTenants  pq.StringArray `json:"touched_tenants" gorm:"type:text[];column:touched_tenants"`
+	UnscopedObjects int64          `json:"unscoped_objects"`
+
 	BigSerial
 }
 
+// environmentPattern guards the environment labels ingest jobs are tagged with
+var environmentPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
+// ValidateEnvironment returns an error if the environment label given is not valid. An empty label is valid and
+// means the data is not tagged with an environment.
+func ValidateEnvironment(environment string) error {
+	if environment != "" && !environmentPattern.MatchString(environment) {
+		return fmt.Errorf("invalid environment %q: must match %s", environment, environmentPattern)
+	}
+
+	return nil
+}
+
+// AuthoritativeMode selects how an ingest job treats objects within its collected scope that it did not see
+type AuthoritativeMode string
+
+const (
+	// AuthoritativeModeNone leaves objects that were not seen untouched
+	AuthoritativeModeNone AuthoritativeMode = ""
+
+	// AuthoritativeModeFlag sets the stale property on objects that were not seen
+	AuthoritativeModeFlag AuthoritativeMode = "flag"
+
+	// AuthoritativeModeDelete removes objects that were not seen from the graph
+	AuthoritativeModeDelete AuthoritativeMode = "delete"
+)
+
+// IsValid returns true if the mode is one of the known authoritative modes
+func (s AuthoritativeMode) IsValid() bool {
+	switch s {
+	case AuthoritativeModeNone, AuthoritativeModeFlag, AuthoritativeModeDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// Enabled returns true if the job is authoritative for its collected scope
+func (s AuthoritativeMode) Enabled() bool {
+	return s == AuthoritativeModeFlag || s == AuthoritativeModeDelete
+}
+
 type IngestJobs []IngestJob
 
 func (s IngestJobs) IsSortable(column string) bool {
 	switch column {
 	case "user_email_address",
+		"authoritative_mode",
+		"environment",
+		"stale_nodes",
+		"stale_relationships",
+		"deleted_nodes",
+		"deleted_relationships",
 		"total_files",
 		"failed_files",
 		"status",
@@ -63,25 +142,31 @@ func (s IngestJobs) IsSortable(column string) bool {
 
 func (s IngestJobs) ValidFilters() map[string][]FilterOperator {
 	return map[string][]FilterOperator{
-		"user_id":            {Equals, NotEquals},
-		"user_email_address": {Equals, NotEquals},
-		"status":             {Equals, NotEquals},
-		"status_message":     {Equals, NotEquals},
-		"start_time":         {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
-		"end_time":           {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
-		"last_ingest":        {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
-		"id":                 {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
-		"created_at":         {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
-		"updated_at":         {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
-		"deleted_at":         {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
-		"total_files":        {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
-		"failed_files":       {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
+		"user_id":               {Equals, NotEquals},
+		"user_email_address":    {Equals, NotEquals},
+		"status":                {Equals, NotEquals},
+		"status_message":        {Equals, NotEquals},
+		"start_time":            {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
+		"end_time":              {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
+		"last_ingest":           {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
+		"id":                    {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
+		"created_at":            {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
+		"updated_at":            {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
+		"deleted_at":            {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
+		"total_files":           {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
+		"failed_files":          {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
+		"authoritative_mode":    {Equals, NotEquals},
+		"environment":           {Equals, NotEquals},
+		"stale_nodes":           {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
+		"stale_relationships":   {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
+		"deleted_nodes":         {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
+		"deleted_relationships": {Equals, GreaterThan, GreaterThanOrEquals, LessThan, LessThanOrEquals, NotEquals},
 	}
 }
 
 func (s IngestJobs) IsString(column string) bool {
 	switch column {
-	case "status_message", "user_id", "user_email_address":
+	case "status_message", "user_id", "user_email_address", "authoritative_mode", "environment":
 		return true
 	default:
 		return false
@@ -17,6 +17,7 @@
 package model
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -35,11 +36,37 @@ func TestIngestJobs_IsSortable(t *testing.T) {
 	require.True(t, fuj.IsSortable("created_at"))
 	require.True(t, fuj.IsSortable("updated_at"))
 	require.True(t, fuj.IsSortable("deleted_at"))
+	require.True(t, fuj.IsSortable("authoritative_mode"))
+	require.True(t, fuj.IsSortable("stale_nodes"))
+	require.True(t, fuj.IsSortable("stale_relationships"))
+	require.True(t, fuj.IsSortable("deleted_nodes"))
+	require.True(t, fuj.IsSortable("deleted_relationships"))
+	require.True(t, fuj.IsSortable("environment"))
 	require.False(t, fuj.IsSortable("foobar"))
 }
 
 func TestIngestJobs_ValidFilters(t *testing.T) {
 	fuj := IngestJobs{}
 	columns := fuj.ValidFilters()
-	require.Equal(t, 13, len(columns))
+	require.Equal(t, 19, len(columns))
+}
+
+func TestAuthoritativeMode(t *testing.T) {
+	require.True(t, AuthoritativeModeNone.IsValid())
+	require.True(t, AuthoritativeModeFlag.IsValid())
+	require.True(t, AuthoritativeModeDelete.IsValid())
+	require.False(t, AuthoritativeMode("purge").IsValid())
+
+	require.False(t, AuthoritativeModeNone.Enabled())
+	require.True(t, AuthoritativeModeFlag.Enabled())
+	require.True(t, AuthoritativeModeDelete.Enabled())
+}
+
+func TestValidateEnvironment(t *testing.T) {
+	require.NoError(t, ValidateEnvironment(""))
+	require.NoError(t, ValidateEnvironment("subsidiary-a"))
+	require.NoError(t, ValidateEnvironment("EU_West.2"))
+	require.Error(t, ValidateEnvironment("-subsidiary"))
+	require.Error(t, ValidateEnvironment("subsidiary a"))
+	require.Error(t, ValidateEnvironment(strings.Repeat("a", 65)))
 }
@@ -0,0 +1,132 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"github.com/specterops/dawgs/graph"
+)
+
+// Property types that may be declared for the properties of an OpenGraph kind
+const (
+	OpenGraphPropertyTypeString    = "string"
+	OpenGraphPropertyTypeInteger   = "integer"
+	OpenGraphPropertyTypeFloat     = "float"
+	OpenGraphPropertyTypeBoolean   = "boolean"
+	OpenGraphPropertyTypeTimestamp = "timestamp"
+	OpenGraphPropertyTypeArray     = "array"
+)
+
+// OpenGraphKind is a node or edge kind declared by the schema section of an OpenGraph payload
+type OpenGraphKind struct {
+	ID          int32               `json:"id"`
+	SourceKind  string              `json:"source_kind"`
+	KindName    string              `json:"kind_name"`
+	IsEdge      bool                `json:"is_edge"`
+	DisplayName string              `json:"display_name"`
+	Traversable bool                `json:"traversable"`
+	Properties  OpenGraphProperties `json:"properties"`
+
+	// Icon is only declared for node kinds. It is stored as a custom node kind rather than with the kind.
+	Icon *CustomNodeIcon `json:"-" gorm:"-"`
+
+	Basic
+}
+
+type OpenGraphKinds []OpenGraphKind
+
+// OpenGraphProperty declares the type of a property of an OpenGraph kind and whether the property should be indexed.
+// Edges of a kind are not ingested without the properties declared as required for it.
+type OpenGraphProperty struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Type        string `json:"type"`
+	Indexed     bool   `json:"indexed"`
+	Required    bool   `json:"required"`
+}
+
+type OpenGraphProperties []OpenGraphProperty
+
+func (s *OpenGraphProperties) Scan(value any) error {
+	if value == nil {
+		*s = OpenGraphProperties{}
+		return nil
+	}
+
+	if bytes, ok := value.([]byte); !ok {
+		return errors.New("type assertion to []byte failed for OpenGraphProperties")
+	} else {
+		return json.Unmarshal(bytes, s)
+	}
+}
+
+func (s OpenGraphProperties) Value() (driver.Value, error) {
+	if s == nil {
+		return []byte("[]"), nil
+	}
+
+	return json.Marshal(s)
+}
+
+// TraversableEdgeKinds returns the declared edge kinds that pathfinding may traverse
+func (s OpenGraphKinds) TraversableEdgeKinds() graph.Kinds {
+	var kinds graph.Kinds
+
+	for _, kind := range s {
+		if kind.IsEdge && kind.Traversable {
+			kinds = append(kinds, graph.StringKind(kind.KindName))
+		}
+	}
+
+	return kinds
+}
+
+// NonTraversableEdgeKinds returns the declared edge kinds that pathfinding must not traverse
+func (s OpenGraphKinds) NonTraversableEdgeKinds() graph.Kinds {
+	var kinds graph.Kinds
+
+	for _, kind := range s {
+		if kind.IsEdge && !kind.Traversable {
+			kinds = append(kinds, graph.StringKind(kind.KindName))
+		}
+	}
+
+	return kinds
+}
+
+// IndexedProperties returns the names of the properties declared as indexed, either for node kinds or for edge kinds
+func (s OpenGraphKinds) IndexedProperties(edges bool) []string {
+	var properties []string
+
+	for _, kind := range s {
+		if kind.IsEdge != edges {
+			continue
+		}
+
+		for _, property := range kind.Properties {
+			if property.Indexed && !slices.Contains(properties, property.Name) {
+				properties = append(properties, property.Name)
+			}
+		}
+	}
+
+	return properties
+}
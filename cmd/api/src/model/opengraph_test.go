@@ -0,0 +1,55 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package model
+
+import (
+	"testing"
+
+	"github.com/specterops/dawgs/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenGraphKinds(t *testing.T) {
+	kinds := OpenGraphKinds{
+		{KindName: "GithubUser", Properties: OpenGraphProperties{{Name: "login", Indexed: true}, {Name: "email"}}},
+		{KindName: "GithubTeam", Properties: OpenGraphProperties{{Name: "login", Indexed: true}}},
+		{KindName: "GithubMemberOf", IsEdge: true, Traversable: true, Properties: OpenGraphProperties{{Name: "role", Indexed: true}}},
+		{KindName: "GithubStarred", IsEdge: true},
+	}
+
+	assert.Equal(t, graph.Kinds{graph.StringKind("GithubMemberOf")}, kinds.TraversableEdgeKinds())
+	assert.Equal(t, graph.Kinds{graph.StringKind("GithubStarred")}, kinds.NonTraversableEdgeKinds())
+	assert.Equal(t, []string{"login"}, kinds.IndexedProperties(false))
+	assert.Equal(t, []string{"role"}, kinds.IndexedProperties(true))
+}
+
+func TestOpenGraphProperties_ScanValue(t *testing.T) {
+	properties := OpenGraphProperties{{Name: "login", DisplayName: "Login", Type: OpenGraphPropertyTypeString, Indexed: true}}
+
+	value, err := properties.Value()
+	require.NoError(t, err)
+
+	var scanned OpenGraphProperties
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, properties, scanned)
+
+	require.NoError(t, scanned.Scan(nil))
+	assert.Equal(t, OpenGraphProperties{}, scanned)
+
+	assert.Error(t, scanned.Scan("not bytes"))
+}
@@ -39,6 +39,7 @@ type DomainSelector struct {
 	Name        string `json:"name"`
 	ObjectID    string `json:"id"`
 	Collected   bool   `json:"collected"`
+	Environment string `json:"environment,omitempty"`
 	ImpactValue *int   `json:"impactValue,omitempty"`
 }
 
@@ -56,9 +57,10 @@ func (s DomainSelectors) IsSortable(column string) bool {
 
 func (s DomainSelectors) ValidFilters() map[string][]FilterOperator {
 	return map[string][]FilterOperator{
-		"objectid":  {Equals, NotEquals},
-		"name":      {Equals, NotEquals},
-		"collected": {Equals, NotEquals},
+		"objectid":    {Equals, NotEquals},
+		"name":        {Equals, NotEquals},
+		"collected":   {Equals, NotEquals},
+		"environment": {Equals, NotEquals},
 	}
 }
 
@@ -66,7 +68,8 @@ func (s DomainSelectors) IsString(column string) bool {
 	switch column {
 	case "name",
 		"objectid",
-		"collected":
+		"collected",
+		"environment":
 		return true
 	default:
 		return false
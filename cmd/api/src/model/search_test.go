@@ -35,7 +35,7 @@ func TestDomainSelectors_TestIsSortable(t *testing.T) {
 func TestDomainSelectors_GetFilterableColumns(t *testing.T) {
 	domains := DomainSelectors{}
 	columns := domains.GetFilterableColumns()
-	require.Equal(t, 3, len(columns))
+	require.Equal(t, 4, len(columns))
 }
 
 func TestDomainSelectors_GetValidFilterPredicatesAsStrings(t *testing.T) {
@@ -43,7 +43,7 @@ func TestDomainSelectors_GetValidFilterPredicatesAsStrings(t *testing.T) {
 	_, err := domains.GetValidFilterPredicatesAsStrings("foo")
 	require.Equal(t, ErrResponseDetailsColumnNotFilterable, err.Error())
 
-	columns := []string{"name", "objectid", "collected"}
+	columns := []string{"name", "objectid", "collected", "environment"}
 
 	for _, column := range columns {
 		predicates, err := domains.GetValidFilterPredicatesAsStrings(column)
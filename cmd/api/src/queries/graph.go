@@ -134,6 +134,7 @@ type Graph interface {
 	GetAssetGroupComboNode(ctx context.Context, owningObjectID string, assetGroupTag string) (map[string]any, error)
 	GetAssetGroupNodes(ctx context.Context, assetGroupTag string, isSystemGroup bool) (graph.NodeSet, error)
 	GetAllShortestPaths(ctx context.Context, startNodeID string, endNodeID string, filter graph.Criteria) (graph.PathSet, error)
+	GetTraversableOpenGraphKinds(ctx context.Context) (graph.Kinds, error)
 	SearchNodesByName(ctx context.Context, nodeKinds graph.Kinds, nameQuery string, skip int, limit int) ([]model.SearchResult, error)
 	SearchByNameOrObjectID(ctx context.Context, searchValue string, searchType string) (graph.NodeSet, error)
 	GetADEntityQueryResult(ctx context.Context, params EntityQueryParameters, cacheEnabled bool) (any, int, error)
@@ -150,11 +151,16 @@ type Graph interface {
 	ValidateOUs(ctx context.Context, ous []string) ([]string, error)
 	BatchNodeUpdate(ctx context.Context, nodeUpdate graph.NodeUpdate) error
 	RawCypherQuery(ctx context.Context, pQuery PreparedQuery, includeProperties bool) (model.UnifiedGraph, error)
-	PrepareCypherQuery(rawCypher string, queryComplexityLimit int64) (PreparedQuery, error)
+	PrepareCypherQuery(ctx context.Context, rawCypher string, queryComplexityLimit int64) (PreparedQuery, error)
 	UpdateSelectorTags(ctx context.Context, db agi.AgiData, selectors model.UpdatedAssetGroupSelectors) error
 	FetchNodeByGraphId(ctx context.Context, id graph.ID) (*graph.Node, error)
 }
 
+// OpenGraphSchema provides the node and edge kinds declared by OpenGraph payloads
+type OpenGraphSchema interface {
+	GetOpenGraphKinds(ctx context.Context) (model.OpenGraphKinds, error)
+}
+
 type GraphQuery struct {
 	Graph                        graph.Database
 	Cache                        cache.Cache
@@ -163,6 +169,7 @@ type GraphQuery struct {
 	EnableCypherMutations        bool
 	cypherEmitter                format.Emitter
 	strippedCypherEmitter        format.Emitter
+	openGraphSchema              OpenGraphSchema
 }
 
 func NewGraphQuery(graphDB graph.Database, cache cache.Cache, cfg config.Configuration) *GraphQuery {
@@ -177,6 +184,32 @@ func NewGraphQuery(graphDB graph.Database, cache cache.Cache, cfg config.Configu
 	}
 }
 
+// WithOpenGraphSchema sets the source of declared OpenGraph kinds used to decide which OpenGraph edges are traversed
+// by pathfinding
+func (s *GraphQuery) WithOpenGraphSchema(openGraphSchema OpenGraphSchema) *GraphQuery {
+	s.openGraphSchema = openGraphSchema
+	return s
+}
+
+func (s *GraphQuery) openGraphKinds(ctx context.Context) (model.OpenGraphKinds, error) {
+	if s.openGraphSchema == nil {
+		return nil, nil
+	} else if kinds, err := s.openGraphSchema.GetOpenGraphKinds(ctx); err != nil {
+		return nil, fmt.Errorf("error fetching declared OpenGraph kinds: %w", err)
+	} else {
+		return kinds, nil
+	}
+}
+
+// GetTraversableOpenGraphKinds returns the OpenGraph edge kinds declared as traversable by pathfinding
+func (s *GraphQuery) GetTraversableOpenGraphKinds(ctx context.Context) (graph.Kinds, error) {
+	if kinds, err := s.openGraphKinds(ctx); err != nil {
+		return nil, err
+	} else {
+		return kinds.TraversableEdgeKinds(), nil
+	}
+}
+
 func (s *GraphQuery) GetAssetGroupComboNode(ctx context.Context, owningObjectID string, assetGroupTag string) (map[string]any, error) {
 	var graphData = map[string]any{}
 
@@ -250,6 +283,12 @@ func (s *GraphQuery) GetAllShortestPaths(ctx context.Context, startNodeID string
 
 	var paths graph.PathSet
 
+	// OpenGraph edge kinds declared as not traversable are never part of a path
+	openGraphKinds, err := s.openGraphKinds(ctx)
+	if err != nil {
+		return paths, err
+	}
+
 	return paths, s.Graph.ReadTransaction(ctx, func(tx graph.Transaction) error {
 		if startNode, err := analysis.FetchNodeByObjectID(tx, startNodeID); err != nil {
 			return err
@@ -265,6 +304,10 @@ func (s *GraphQuery) GetAllShortestPaths(ctx context.Context, startNodeID string
 				criteria = append(criteria, filter)
 			}
 
+			if nonTraversableKinds := openGraphKinds.NonTraversableEdgeKinds(); len(nonTraversableKinds) > 0 {
+				criteria = append(criteria, query.Not(query.KindIn(query.Relationship(), nonTraversableKinds...)))
+			}
+
 			return tx.Relationships().Filter(query.And(criteria...)).FetchAllShortestPaths(func(cursor graph.Cursor[graph.Path]) error {
 				for path := range cursor.Chan() {
 					if len(path.Edges) > 0 {
@@ -373,7 +416,7 @@ type PreparedQuery struct {
 	HasMutation   bool
 }
 
-func (s *GraphQuery) PrepareCypherQuery(rawCypher string, queryComplexityLimit int64) (PreparedQuery, error) {
+func (s *GraphQuery) PrepareCypherQuery(ctx context.Context, rawCypher string, queryComplexityLimit int64) (PreparedQuery, error) {
 	var (
 		cypherFilters = []frontend.Visitor{
 			&frontend.ExplicitProcedureInvocationFilter{},
@@ -401,8 +444,11 @@ func (s *GraphQuery) PrepareCypherQuery(rawCypher string, queryComplexityLimit i
 	}
 
 	// Query rewriter targets certain AST elements like relationship types and may rewrite them to add additional
-	// functionality after parsing
-	queryRewriter := NewRewriter()
+	// functionality after parsing. The declared OpenGraph kinds are only fetched for queries that expand
+	// ALL_ATTACK_PATHS.
+	queryRewriter := NewRewriter(func() (graph.Kinds, error) {
+		return s.GetTraversableOpenGraphKinds(ctx)
+	})
 
 	if err = walk.Cypher(queryModel, queryRewriter); err != nil {
 		return graphQuery, err
@@ -27,6 +27,7 @@ import (
 	schema "github.com/specterops/bloodhound/packages/go/graphschema"
 
 	"github.com/specterops/bloodhound/cmd/api/src/api/bloodhoundgraph"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
 	"github.com/specterops/bloodhound/cmd/api/src/queries"
 	"github.com/specterops/bloodhound/cmd/api/src/test/integration"
 	adAnalysis "github.com/specterops/bloodhound/packages/go/analysis/ad"
@@ -378,6 +379,7 @@ func TestGraphQuery_GetAllShortestPaths(t *testing.T) {
 			testContext.NewRelationship(userA, groupA, ad.MemberOf)
 			testContext.NewRelationship(groupA, computer, ad.GenericAll)
 			testContext.NewRelationship(userA, computer, ad.GenericWrite)
+			testContext.NewRelationship(userA, computer, graph.StringKind("OpenGraphShortcut"))
 
 			return nil
 		},
@@ -399,6 +401,22 @@ func TestGraphQuery_GetAllShortestPaths(t *testing.T) {
 
 			require.Nil(t, err)
 			require.Equal(t, 0, len(paths))
+
+			paths, err = graphQuery.GetAllShortestPaths(context.Background(), "A", "C", nil)
+
+			require.Nil(t, err)
+			require.Equal(t, 2, len(paths))
+
+			// OpenGraph edge kinds declared as not traversable are excluded even without a filter
+			graphQuery.WithOpenGraphSchema(stubOpenGraphSchema{
+				kinds: model.OpenGraphKinds{{KindName: "OpenGraphShortcut", IsEdge: true, Traversable: false}},
+			})
+
+			paths, err = graphQuery.GetAllShortestPaths(context.Background(), "A", "C", nil)
+
+			require.Nil(t, err)
+			require.Equal(t, 1, len(paths))
+			require.Equal(t, ad.GenericWrite, paths[0].Edges[0].Kind)
 		})
 }
 
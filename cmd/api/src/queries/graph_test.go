@@ -18,6 +18,7 @@ package queries_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -55,60 +56,112 @@ func TestGraphQuery_PrepareCypherQuery(t *testing.T) {
 	)
 
 	t.Run("invalid cypher", func(t *testing.T) {
-		_, err := gq.PrepareCypherQuery(rawCypherInvalid, queries.DefaultQueryFitnessLowerBoundExplore)
+		_, err := gq.PrepareCypherQuery(context.Background(), rawCypherInvalid, queries.DefaultQueryFitnessLowerBoundExplore)
 		assert.ErrorContains(t, err, "mismatched input 'derp'")
 	})
 
 	t.Run("valid cypher with mutation while mutations disabled", func(t *testing.T) {
-		_, err := gqMutDisable.PrepareCypherQuery(rawCypherMutation, queries.DefaultQueryFitnessLowerBoundExplore)
+		_, err := gqMutDisable.PrepareCypherQuery(context.Background(), rawCypherMutation, queries.DefaultQueryFitnessLowerBoundExplore)
 		assert.ErrorContains(t, err, "not supported")
 	})
 
 	t.Run("valid cypher without mutation", func(t *testing.T) {
-		preparedQuery, err := gq.PrepareCypherQuery(rawCypherRead, queries.DefaultQueryFitnessLowerBoundExplore)
+		preparedQuery, err := gq.PrepareCypherQuery(context.Background(), rawCypherRead, queries.DefaultQueryFitnessLowerBoundExplore)
 		require.Nil(t, err)
 		assert.Equal(t, preparedQuery.HasMutation, false)
 	})
 
 	t.Run("valid cypher with mutation", func(t *testing.T) {
-		preparedQuery, err := gq.PrepareCypherQuery(rawCypherMutation, queries.DefaultQueryFitnessLowerBoundExplore)
+		preparedQuery, err := gq.PrepareCypherQuery(context.Background(), rawCypherMutation, queries.DefaultQueryFitnessLowerBoundExplore)
 		require.Nil(t, err)
 		assert.Equal(t, preparedQuery.HasMutation, true)
 	})
 
 	t.Run("valid cypher pathfinding with expansion", func(t *testing.T) {
-		preparedQuery, err := gq.PrepareCypherQuery(rawCypherPathfindingExpansion, queries.DefaultQueryFitnessLowerBoundExplore)
+		preparedQuery, err := gq.PrepareCypherQuery(context.Background(), rawCypherPathfindingExpansion, queries.DefaultQueryFitnessLowerBoundExplore)
 		require.Nil(t, err)
 		assert.Equal(t, preparedQuery.HasMutation, false)
 	})
 
 	t.Run("valid cypher without mutation with expansion", func(t *testing.T) {
-		preparedQuery, err := gq.PrepareCypherQuery(rawCypherReadExpansion, queries.DefaultQueryFitnessLowerBoundExplore)
+		preparedQuery, err := gq.PrepareCypherQuery(context.Background(), rawCypherReadExpansion, queries.DefaultQueryFitnessLowerBoundExplore)
 		require.Nil(t, err)
 		assert.Equal(t, preparedQuery.HasMutation, false)
 	})
 
 	t.Run("valid cypher with creation and expansion", func(t *testing.T) {
-		_, err := gq.PrepareCypherQuery(rawCypherCreationAndExpansion, queries.DefaultQueryFitnessLowerBoundExplore)
+		_, err := gq.PrepareCypherQuery(context.Background(), rawCypherCreationAndExpansion, queries.DefaultQueryFitnessLowerBoundExplore)
 		assert.ErrorContains(t, err, "not supported")
 	})
 
 	t.Run("valid cypher with deletion and expansion", func(t *testing.T) {
-		_, err := gq.PrepareCypherQuery(rawCypherDeleteAndExpansion, queries.DefaultQueryFitnessLowerBoundExplore)
+		_, err := gq.PrepareCypherQuery(context.Background(), rawCypherDeleteAndExpansion, queries.DefaultQueryFitnessLowerBoundExplore)
 		assert.ErrorContains(t, err, "not supported")
 	})
 	t.Run("valid cypher with updates and expansion", func(t *testing.T) {
-		_, err := gq.PrepareCypherQuery(rawCypherUpdateAndExpansion, queries.DefaultQueryFitnessLowerBoundExplore)
+		_, err := gq.PrepareCypherQuery(context.Background(), rawCypherUpdateAndExpansion, queries.DefaultQueryFitnessLowerBoundExplore)
 		assert.ErrorContains(t, err, "not supported")
 	})
 
 	t.Run("valid cypher without mutation while mutations disabled", func(t *testing.T) {
-		preparedQuery, err := gq.PrepareCypherQuery(rawCypherRead, queries.DefaultQueryFitnessLowerBoundExplore)
+		preparedQuery, err := gq.PrepareCypherQuery(context.Background(), rawCypherRead, queries.DefaultQueryFitnessLowerBoundExplore)
 		require.Nil(t, err)
 		assert.Equal(t, preparedQuery.HasMutation, false)
 	})
 }
 
+type stubOpenGraphSchema struct {
+	kinds model.OpenGraphKinds
+	err   error
+}
+
+func (s stubOpenGraphSchema) GetOpenGraphKinds(context.Context) (model.OpenGraphKinds, error) {
+	return s.kinds, s.err
+}
+
+func TestGraphQuery_PrepareCypherQuery_OpenGraphKinds(t *testing.T) {
+	var (
+		mockCtrl    = gomock.NewController(t)
+		mockGraphDB = graphMocks.NewMockDatabase(mockCtrl)
+		rawCypher   = "MATCH (n:Label)-[r:ALL_ATTACK_PATHS]->() return r"
+	)
+
+	t.Run("traversable OpenGraph edge kinds are included in ALL_ATTACK_PATHS", func(t *testing.T) {
+		gq := queries.NewGraphQuery(mockGraphDB, cache.Cache{}, config.Configuration{}).WithOpenGraphSchema(stubOpenGraphSchema{
+			kinds: model.OpenGraphKinds{
+				{KindName: "GithubMemberOf", IsEdge: true, Traversable: true},
+				{KindName: "GithubStarred", IsEdge: true, Traversable: false},
+				{KindName: "GithubUser"},
+			},
+		})
+
+		preparedQuery, err := gq.PrepareCypherQuery(context.Background(), rawCypher, queries.DefaultQueryFitnessLowerBoundExplore)
+		require.Nil(t, err)
+		assert.Contains(t, preparedQuery.StrippedQuery, "GithubMemberOf")
+		assert.Contains(t, preparedQuery.StrippedQuery, ad.GenericAll.String())
+		assert.NotContains(t, preparedQuery.StrippedQuery, "GithubStarred")
+		assert.NotContains(t, preparedQuery.StrippedQuery, "GithubUser")
+	})
+
+	t.Run("error fetching declared OpenGraph kinds", func(t *testing.T) {
+		gq := queries.NewGraphQuery(mockGraphDB, cache.Cache{}, config.Configuration{}).WithOpenGraphSchema(stubOpenGraphSchema{
+			err: errors.New("database error"),
+		})
+
+		_, err := gq.PrepareCypherQuery(context.Background(), rawCypher, queries.DefaultQueryFitnessLowerBoundExplore)
+		assert.ErrorContains(t, err, "database error")
+	})
+
+	t.Run("declared OpenGraph kinds are not fetched for queries without ALL_ATTACK_PATHS", func(t *testing.T) {
+		gq := queries.NewGraphQuery(mockGraphDB, cache.Cache{}, config.Configuration{}).WithOpenGraphSchema(stubOpenGraphSchema{
+			err: errors.New("database error"),
+		})
+
+		_, err := gq.PrepareCypherQuery(context.Background(), "MATCH (n:Label)-[r:AD_ATTACK_PATHS]->() return r", queries.DefaultQueryFitnessLowerBoundExplore)
+		assert.Nil(t, err)
+	})
+}
+
 func TestGraphQuery_RawCypherQuery(t *testing.T) {
 	var (
 		mockCtrl    = gomock.NewController(t)
@@ -121,14 +174,14 @@ func TestGraphQuery_RawCypherQuery(t *testing.T) {
 
 		// Scenario 1:
 		// Passing query
-		preparedQuery, err := gq.PrepareCypherQuery("match (:Computer)-[:HasSession*..]->(:User)-[:MemberOf*..]->(:Group) return n;", queries.DefaultQueryFitnessLowerBoundExplore)
+		preparedQuery, err := gq.PrepareCypherQuery(context.Background(), "match (:Computer)-[:HasSession*..]->(:User)-[:MemberOf*..]->(:Group) return n;", queries.DefaultQueryFitnessLowerBoundExplore)
 		require.Nil(t, err)
 		_, err = gq.RawCypherQuery(context.Background(), preparedQuery, false)
 		require.Nil(t, err)
 
 		// Scenario 2:
 		// Rejected query
-		_, err = gq.PrepareCypherQuery("match ()-[:HasSession*..]->()-[:MemberOf*..]->() return n;", queries.DefaultQueryFitnessLowerBoundExplore)
+		_, err = gq.PrepareCypherQuery(context.Background(), "match ()-[:HasSession*..]->()-[:MemberOf*..]->() return n;", queries.DefaultQueryFitnessLowerBoundExplore)
 		require.NotNil(t, err)
 	})
 
@@ -136,7 +189,7 @@ func TestGraphQuery_RawCypherQuery(t *testing.T) {
 		mockGraphDB.EXPECT().WriteTransaction(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 		mockGraphDB.EXPECT().ReadTransaction(gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
 
-		preparedQuery, err := gq.PrepareCypherQuery("match (b) where b.name = 'harley' return b;", queries.DefaultQueryFitnessLowerBoundExplore)
+		preparedQuery, err := gq.PrepareCypherQuery(context.Background(), "match (b) where b.name = 'harley' return b;", queries.DefaultQueryFitnessLowerBoundExplore)
 		require.Nil(t, err)
 
 		_, err = gq.RawCypherQuery(context.Background(), preparedQuery, false)
@@ -148,7 +201,7 @@ func TestGraphQuery_RawCypherQuery(t *testing.T) {
 		mockGraphDB.EXPECT().WriteTransaction(gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
 
 		qgWMut := queries.NewGraphQuery(mockGraphDB, cache.Cache{}, config.Configuration{EnableCypherMutations: true})
-		preparedQuery, err := qgWMut.PrepareCypherQuery("match (b) where b.name = 'bruce' remove b.prop return b;", queries.DefaultQueryFitnessLowerBoundExplore)
+		preparedQuery, err := qgWMut.PrepareCypherQuery(context.Background(), "match (b) where b.name = 'bruce' remove b.prop return b;", queries.DefaultQueryFitnessLowerBoundExplore)
 		require.Nil(t, err)
 
 		_, err = qgWMut.RawCypherQuery(context.Background(), preparedQuery, false)
@@ -330,19 +330,34 @@ func (mr *MockGraphMockRecorder) GetPrimaryNodeKindCounts(ctx, kind any, additio
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrimaryNodeKindCounts", reflect.TypeOf((*MockGraph)(nil).GetPrimaryNodeKindCounts), varargs...)
 }
 
+// GetTraversableOpenGraphKinds mocks base method.
+func (m *MockGraph) GetTraversableOpenGraphKinds(ctx context.Context) (graph.Kinds, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTraversableOpenGraphKinds", ctx)
+	ret0, _ := ret[0].(graph.Kinds)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTraversableOpenGraphKinds indicates an expected call of GetTraversableOpenGraphKinds.
+func (mr *MockGraphMockRecorder) GetTraversableOpenGraphKinds(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTraversableOpenGraphKinds", reflect.TypeOf((*MockGraph)(nil).GetTraversableOpenGraphKinds), ctx)
+}
+
 // PrepareCypherQuery mocks base method.
-func (m *MockGraph) PrepareCypherQuery(rawCypher string, queryComplexityLimit int64) (queries.PreparedQuery, error) {
+func (m *MockGraph) PrepareCypherQuery(ctx context.Context, rawCypher string, queryComplexityLimit int64) (queries.PreparedQuery, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PrepareCypherQuery", rawCypher, queryComplexityLimit)
+	ret := m.ctrl.Call(m, "PrepareCypherQuery", ctx, rawCypher, queryComplexityLimit)
 	ret0, _ := ret[0].(queries.PreparedQuery)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // PrepareCypherQuery indicates an expected call of PrepareCypherQuery.
-func (mr *MockGraphMockRecorder) PrepareCypherQuery(rawCypher, queryComplexityLimit any) *gomock.Call {
+func (mr *MockGraphMockRecorder) PrepareCypherQuery(ctx, rawCypher, queryComplexityLimit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrepareCypherQuery", reflect.TypeOf((*MockGraph)(nil).PrepareCypherQuery), rawCypher, queryComplexityLimit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrepareCypherQuery", reflect.TypeOf((*MockGraph)(nil).PrepareCypherQuery), ctx, rawCypher, queryComplexityLimit)
 }
 
 // RawCypherQuery mocks base method.
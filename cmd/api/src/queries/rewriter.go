@@ -18,8 +18,10 @@ package queries
 import (
 	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
 	"github.com/specterops/bloodhound/packages/go/graphschema/azure"
+	"github.com/specterops/bloodhound/packages/go/slicesext"
 	"github.com/specterops/dawgs/cypher/models/cypher"
 	"github.com/specterops/dawgs/cypher/models/walk"
+	"github.com/specterops/dawgs/graph"
 )
 
 const (
@@ -35,14 +37,34 @@ type Rewriter struct {
 
 	HasMutation                 bool
 	HasRelationshipTypeShortcut bool
+
+	// fetchOpenGraphKinds returns the OpenGraph edge kinds declared as traversable, which are included in
+	// ALL_ATTACK_PATHS. It is only called once a query uses ALL_ATTACK_PATHS, and at most once per query.
+	fetchOpenGraphKinds func() (graph.Kinds, error)
+	openGraphKinds      graph.Kinds
+	openGraphKindsSet   bool
 }
 
-func NewRewriter() *Rewriter {
+func NewRewriter(fetchOpenGraphKinds func() (graph.Kinds, error)) *Rewriter {
 	return &Rewriter{
-		Visitor: walk.NewVisitor[cypher.SyntaxNode](),
+		Visitor:             walk.NewVisitor[cypher.SyntaxNode](),
+		fetchOpenGraphKinds: fetchOpenGraphKinds,
 	}
 }
 
+func (s *Rewriter) traversableOpenGraphKinds() (graph.Kinds, error) {
+	if !s.openGraphKindsSet {
+		if kinds, err := s.fetchOpenGraphKinds(); err != nil {
+			return nil, err
+		} else {
+			s.openGraphKinds = kinds
+			s.openGraphKindsSet = true
+		}
+	}
+
+	return s.openGraphKinds, nil
+}
+
 func (s *Rewriter) Enter(node cypher.SyntaxNode) {
 	switch typedNode := node.(type) {
 	case *cypher.UpdatingClause:
@@ -55,7 +77,13 @@ func (s *Rewriter) Enter(node cypher.SyntaxNode) {
 			switch kind.String() {
 			case allAttackPathsRelationshipShortcutType:
 				s.HasRelationshipTypeShortcut = true
-				typedNode.Kinds = append(azure.PathfindingRelationships(), ad.PathfindingRelationships()...)
+
+				if openGraphKinds, err := s.traversableOpenGraphKinds(); err != nil {
+					s.SetError(err)
+					return
+				} else {
+					typedNode.Kinds = slicesext.Concat(azure.PathfindingRelationships(), ad.PathfindingRelationships(), openGraphKinds)
+				}
 
 			case azureAttackPathsRelationshipShortcutType:
 				s.HasRelationshipTypeShortcut = true
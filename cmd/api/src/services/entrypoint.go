@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"time"
 
 	"github.com/specterops/bloodhound/cmd/api/src/api"
@@ -32,10 +33,13 @@ import (
 	"github.com/specterops/bloodhound/cmd/api/src/daemons/api/bhapi"
 	"github.com/specterops/bloodhound/cmd/api/src/daemons/api/toolapi"
 	"github.com/specterops/bloodhound/cmd/api/src/daemons/datapipe"
+	"github.com/specterops/bloodhound/cmd/api/src/daemons/dropfolder"
 	"github.com/specterops/bloodhound/cmd/api/src/daemons/gc"
+	"github.com/specterops/bloodhound/cmd/api/src/daemons/s3ingest"
 	"github.com/specterops/bloodhound/cmd/api/src/database"
 	"github.com/specterops/bloodhound/cmd/api/src/model/appcfg"
 	"github.com/specterops/bloodhound/cmd/api/src/queries"
+	"github.com/specterops/bloodhound/cmd/api/src/services/graphify"
 	"github.com/specterops/bloodhound/cmd/api/src/services/upload"
 	"github.com/specterops/bloodhound/packages/go/cache"
 	schema "github.com/specterops/bloodhound/packages/go/graphschema"
@@ -78,7 +82,9 @@ func Entrypoint(ctx context.Context, cfg config.Configuration, connections boots
 	if !cfg.DisableMigrations {
 		if err := bootstrap.MigrateDB(ctx, cfg, connections.RDMS); err != nil {
 			return nil, fmt.Errorf("rdms migration error: %w", err)
-		} else if err := bootstrap.MigrateGraph(ctx, connections.Graph, schema.DefaultGraphSchema()); err != nil {
+		} else if openGraphKinds, err := connections.RDMS.GetOpenGraphKinds(ctx); err != nil {
+			return nil, fmt.Errorf("error fetching declared OpenGraph kinds: %w", err)
+		} else if err := bootstrap.MigrateGraph(ctx, connections.Graph, graphify.DefaultGraphSchemaWithOpenGraphKinds(openGraphKinds)); err != nil {
 			return nil, fmt.Errorf("graph migration error: %w", err)
 		}
 	} else if err := connections.Graph.SetDefaultGraph(ctx, schema.DefaultGraph()); err != nil {
@@ -108,7 +114,7 @@ func Entrypoint(ctx context.Context, cfg config.Configuration, connections boots
 
 		var (
 			pipeline       = datapipe.NewPipeline(ctx, cfg, connections.RDMS, connections.Graph, graphQueryCache, ingestSchema)
-			graphQuery     = queries.NewGraphQuery(connections.Graph, graphQueryCache, cfg)
+			graphQuery     = queries.NewGraphQuery(connections.Graph, graphQueryCache, cfg).WithOpenGraphSchema(connections.RDMS)
 			authorizer     = auth.NewAuthorizer(connections.RDMS)
 			datapipeDaemon = datapipe.NewDaemon(pipeline, startDelay, time.Duration(cfg.DatapipeInterval)*time.Second, connections.RDMS)
 			routerInst     = router.NewRouter(cfg, authorizer, bootstrap.ContentSecurityPolicy)
@@ -129,10 +135,30 @@ func Entrypoint(ctx context.Context, cfg config.Configuration, connections boots
 			slog.WarnContext(ctx, fmt.Sprintf("failed to request init analysis: %v", err))
 		}
 
-		return []daemons.Daemon{
+		entrypointDaemons := []daemons.Daemon{
 			bhapi.NewDaemon(cfg, routerInst.Handler()),
-			gc.NewDataPruningDaemon(connections.RDMS),
+			gc.NewDataPruningDaemon(connections.RDMS, connections.Graph),
 			datapipeDaemon,
-		}, nil
+		}
+
+		if cfg.DropFolder.Enabled() && !cfg.DisableIngest {
+			if dropFolderDaemon, err := dropfolder.NewDaemon(cfg, connections.RDMS, ingestSchema); err != nil {
+				return nil, fmt.Errorf("failed to create drop folder daemon: %w", err)
+			} else {
+				entrypointDaemons = append(entrypointDaemons, dropFolderDaemon)
+			}
+		}
+
+		if cfg.S3Ingest.Enabled() && !cfg.DisableIngest {
+			s3HTTPClient := &http.Client{Timeout: time.Duration(cfg.S3Ingest.RequestTimeout) * time.Second}
+
+			if s3IngestDaemon, err := s3ingest.NewDaemon(cfg, connections.RDMS, ingestSchema, s3HTTPClient); err != nil {
+				return nil, fmt.Errorf("failed to create S3 ingest daemon: %w", err)
+			} else {
+				entrypointDaemons = append(entrypointDaemons, s3IngestDaemon)
+			}
+		}
+
+		return entrypointDaemons, nil
 	}
 }
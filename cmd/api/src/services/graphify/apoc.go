@@ -0,0 +1,162 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/specterops/bloodhound/packages/go/analysis/hybrid"
+	"github.com/specterops/bloodhound/packages/go/ein"
+	"github.com/specterops/dawgs/graph"
+	"github.com/specterops/dawgs/util"
+)
+
+// apocConvertedData is a chunk of converted APOC export records, split by the base kind of the records as the AD and
+// Azure data of an export are ingested with different base kinds
+type apocConvertedData map[graph.Kind]*ConvertedData
+
+func (s apocConvertedData) get(baseKind graph.Kind) *ConvertedData {
+	if converted, ok := s[baseKind]; ok {
+		return converted
+	}
+
+	converted := &ConvertedData{}
+	s[baseKind] = converted
+	return converted
+}
+
+func (s apocConvertedData) ingest(batch *TimestampedBatch) error {
+	errs := util.NewErrorCollector()
+
+	for baseKind, converted := range s {
+		if err := IngestGenericData(batch, baseKind, *converted); err != nil {
+			errs.Add(err)
+		}
+
+		converted.Clear()
+	}
+
+	return errs.Combined()
+}
+
+// IngestApocExportData ingests the JSON lines export of a BloodHound Legacy Neo4j database written by
+// apoc.export.json.all. The export is read twice: the nodes are ingested first, keeping enough of each to resolve the
+// relationships that reference it by its APOC identifier, then the relationships. Relationships that are derived by
+// post-processing are not ingested, apart from the rights granted by local group membership, which are converted to
+// that membership so that post-processing derives them again.
+func IngestApocExportData(batch *TimestampedBatch, reader io.ReadSeeker) error {
+	var (
+		nodes                = map[ein.ApocID]ein.ApocNode{}
+		postProcessedKinds   = graph.Kinds(hybrid.PostProcessedRelationships())
+		converted            = apocConvertedData{}
+		errs                 = util.NewErrorCollector()
+		resolveApocReference = func(reference ein.ApocNodeReference) (ein.ApocNode, bool) {
+			if node, ok := nodes[reference.ID]; ok {
+				return node, true
+			}
+
+			// Nodes outside of the export may still be identified by the properties written for the reference
+			_, node, ok := ein.ConvertApocNode(ein.ApocRecord{Labels: reference.Labels, Properties: reference.Properties})
+			return node, ok
+		}
+	)
+
+	if err := decodeApocRecords(batch, reader, converted, errs, func(record ein.ApocRecord) {
+		if record.Type != ein.ApocRecordTypeNode {
+			return
+		} else if node, apocNode, ok := ein.ConvertApocNode(record); !ok {
+			slog.Debug(fmt.Sprintf("Skipping APOC node %s with labels %v that is not an AD or Azure node", record.ID, record.Labels))
+		} else {
+			nodes[record.ID] = apocNode
+			converted.get(apocNode.Base).NodeProps = append(converted.get(apocNode.Base).NodeProps, node)
+		}
+	}); err != nil {
+		return err
+	}
+
+	if err := decodeApocRecords(batch, reader, converted, errs, func(record ein.ApocRecord) {
+		if record.Type != ein.ApocRecordTypeRelationship {
+			return
+		} else if start, ok := resolveApocReference(record.Start); !ok {
+			return
+		} else if end, ok := resolveApocReference(record.End); !ok {
+			return
+		} else if parsed, ok := ein.ConvertApocRelationship(record, start, end); !ok {
+			slog.Debug(fmt.Sprintf("Skipping APOC relationship %s with unknown type %s", record.ID, record.Label))
+		} else {
+			data := converted.get(start.Base)
+			data.NodeProps = append(data.NodeProps, parsed.Nodes...)
+
+			for _, relationship := range parsed.Relationships {
+				if !postProcessedKinds.ContainsOneOf(relationship.RelType) {
+					data.RelProps = append(data.RelProps, relationship)
+				}
+			}
+		}
+	}); err != nil {
+		return err
+	}
+
+	return errs.Combined()
+}
+
+// decodeApocRecords rewinds the export and passes each of its records to convertFunc. The records converted are
+// ingested in chunks of IngestCountThreshold records, with ingest errors collected into errs.
+func decodeApocRecords(batch *TimestampedBatch, reader io.ReadSeeker, converted apocConvertedData, errs util.ErrorCollector, convertFunc func(record ein.ApocRecord)) error {
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewind failed: %w", err)
+	}
+
+	var (
+		decoder = json.NewDecoder(reader)
+		count   = 0
+	)
+
+	for decoder.More() {
+		// This variable needs to be initialized here, otherwise the marshaller will cache the map in the struct
+		var record ein.ApocRecord
+		if err := decoder.Decode(&record); err != nil {
+			slog.Error(fmt.Sprintf("Error decoding APOC export record: %v", err))
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+
+		convertFunc(record)
+		count++
+
+		if count == IngestCountThreshold {
+			if err := batch.writeChunk(count, func() error { return converted.ingest(batch) }); err != nil {
+				errs.Add(err)
+			}
+			count = 0
+		}
+	}
+
+	if count > 0 {
+		if err := batch.writeChunk(count, func() error { return converted.ingest(batch) }); err != nil {
+			errs.Add(err)
+		}
+	}
+
+	return nil
+}
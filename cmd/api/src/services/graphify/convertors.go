@@ -62,18 +62,28 @@ func ConvertGenericNode(entity ein.GenericNode, converted *ConvertedData) error
 	return nil
 }
 
+// convertGenericEdgeEndpoint uppercases the value of endpoints matched by ID or name, as object IDs and names are stored
+// uppercase. Property values are kept as given so that they can be compared with case.
+func convertGenericEdgeEndpoint(endpoint ein.EdgeEndpoint) ein.IngestibleEndpoint {
+	converted := ein.IngestibleEndpoint{
+		Value:         endpoint.Value,
+		MatchBy:       ein.IngestMatchStrategy(endpoint.MatchBy),
+		Kind:          graph.StringKind(endpoint.Kind),
+		Property:      endpoint.Property,
+		CaseSensitive: endpoint.CaseSensitive,
+	}
+
+	if converted.MatchBy != ein.MatchByProperty {
+		converted.Value = strings.ToUpper(converted.Value)
+	}
+
+	return converted
+}
+
 func ConvertGenericEdge(entity ein.GenericEdge, converted *ConvertedData) error {
 	ingestibleRel := ein.NewIngestibleRelationship(
-		ein.IngestibleEndpoint{
-			Value:   strings.ToUpper(entity.Start.Value),
-			MatchBy: ein.IngestMatchStrategy(entity.Start.MatchBy),
-			Kind:    graph.StringKind(entity.Start.Kind),
-		},
-		ein.IngestibleEndpoint{
-			Value:   strings.ToUpper(entity.End.Value),
-			MatchBy: ein.IngestMatchStrategy(entity.End.MatchBy),
-			Kind:    graph.StringKind(entity.End.Kind),
-		},
+		convertGenericEdgeEndpoint(entity.Start),
+		convertGenericEdgeEndpoint(entity.End),
 		ein.IngestibleRel{
 			RelProps: entity.Properties,
 			RelType:  graph.StringKind(entity.Kind),
@@ -0,0 +1,106 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
+	"github.com/specterops/bloodhound/cmd/api/src/services/upload"
+	"github.com/specterops/bloodhound/packages/go/ein"
+	"github.com/specterops/dawgs/graph"
+	"github.com/specterops/dawgs/util"
+)
+
+// IngestCSVData ingests a CSV file, converting each of its rows to an OpenGraph node or edge with the mapping given.
+// Rows are ingested in chunks of IngestCountThreshold rows with the source kind of the mapping, if it has one. Rows that
+// can not be converted are skipped and reported in the returned error.
+func IngestCSVData(batch *TimestampedBatch, reader io.ReadSeeker, mapping ingest.CSVMapping, registerSourceKind registrationFn) error {
+	var (
+		sourceKind = graph.EmptyKind
+		errs       = util.NewErrorCollector()
+		nodes      = make([]ein.GenericNode, 0, IngestCountThreshold)
+		edges      = make([]ein.GenericEdge, 0, IngestCountThreshold)
+	)
+
+	if mapping.SourceKind != "" {
+		var err error
+		if sourceKind, err = ingestGenericMetadata(batch, ein.GenericMetadata{SourceKind: mapping.SourceKind}, registerSourceKind); err != nil {
+			return err
+		}
+	}
+
+	var (
+		ingestNodes = genericDataIngester(batch, sourceKind, ConvertGenericNode)
+		ingestEdges = genericDataIngester(batch, sourceKind, ConvertGenericEdge)
+		flush       = func() {
+			if len(nodes)+len(edges) == 0 {
+				return
+			}
+
+			if err := batch.writeChunk(len(nodes)+len(edges), func() error {
+				chunkErrs := util.NewErrorCollector()
+
+				if len(nodes) > 0 {
+					if err := ingestNodes(nodes); err != nil {
+						chunkErrs.Add(err)
+					}
+				}
+
+				if len(edges) > 0 {
+					if err := ingestEdges(edges); err != nil {
+						chunkErrs.Add(err)
+					}
+				}
+
+				return chunkErrs.Combined()
+			}); err != nil {
+				errs.Add(err)
+			}
+
+			nodes = nodes[:0]
+			edges = edges[:0]
+		}
+	)
+
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewind failed: %w", err)
+	}
+
+	if err := upload.ScanCSV(reader, mapping, func(record upload.CSVRecord) error {
+		switch {
+		case record.Err != nil:
+			errs.Add(fmt.Errorf("skipping csv row on line %d: %w", record.Line, record.Err))
+		case record.Node != nil:
+			nodes = append(nodes, *record.Node)
+		case record.Edge != nil:
+			edges = append(edges, *record.Edge)
+		}
+
+		if len(nodes)+len(edges) == IngestCountThreshold {
+			flush()
+		}
+
+		return nil
+	}); err != nil {
+		errs.Add(err)
+	}
+
+	flush()
+	return errs.Combined()
+}
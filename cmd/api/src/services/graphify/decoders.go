@@ -59,7 +59,7 @@ func decodeBasicData[T any](batch *TimestampedBatch, decoder *json.Decoder, conv
 		}
 
 		if count == IngestCountThreshold {
-			if err := IngestBasicData(batch, convertedData); err != nil {
+			if err := batch.writeChunk(count, func() error { return IngestBasicData(batch, convertedData) }); err != nil {
 				errs.Add(err)
 			}
 			convertedData.Clear()
@@ -69,7 +69,7 @@ func decodeBasicData[T any](batch *TimestampedBatch, decoder *json.Decoder, conv
 	}
 
 	if count > 0 {
-		if err := IngestBasicData(batch, convertedData); err != nil {
+		if err := batch.writeChunk(count, func() error { return IngestBasicData(batch, convertedData) }); err != nil {
 			errs.Add(err)
 		}
 	}
@@ -101,7 +101,7 @@ func DecodeGenericData[T any](batch *TimestampedBatch, decoder *json.Decoder, so
 		}
 
 		if count == IngestCountThreshold {
-			if err := IngestGenericData(batch, sourceKind, convertedData); err != nil {
+			if err := batch.writeChunk(count, func() error { return IngestGenericData(batch, sourceKind, convertedData) }); err != nil {
 				errs.Add(err)
 			}
 			convertedData.Clear()
@@ -111,7 +111,7 @@ func DecodeGenericData[T any](batch *TimestampedBatch, decoder *json.Decoder, so
 	}
 
 	if count > 0 {
-		if err := IngestGenericData(batch, sourceKind, convertedData); err != nil {
+		if err := batch.writeChunk(count, func() error { return IngestGenericData(batch, sourceKind, convertedData) }); err != nil {
 			errs.Add(err)
 		}
 	}
@@ -119,7 +119,45 @@ func DecodeGenericData[T any](batch *TimestampedBatch, decoder *json.Decoder, so
 	return errs.Combined()
 }
 
-func decodeGroupData(batch *TimestampedBatch, decoder *json.Decoder) error {
+// DecodeGenericDeletions reads the entries of a deleted_nodes or deleted_edges section of an OpenGraph payload and
+// passes them to deleteFunc in chunks of IngestCountThreshold entries.
+func DecodeGenericDeletions[T any](batch *TimestampedBatch, decoder *json.Decoder, deleteFunc DeleteFunc[T]) error {
+	var (
+		entries = make([]T, 0, IngestCountThreshold)
+		errs    = util.NewErrorCollector()
+	)
+
+	for decoder.More() {
+		var decodeTarget T
+		if err := decoder.Decode(&decodeTarget); err != nil {
+			slog.Error(fmt.Sprintf("Error decoding %T object: %v", decodeTarget, err))
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		} else {
+			entries = append(entries, decodeTarget)
+		}
+
+		if len(entries) == IngestCountThreshold {
+			if err := batch.writeChunk(len(entries), func() error { return deleteFunc(batch, entries) }); err != nil {
+				errs.Add(err)
+			}
+			entries = entries[:0]
+		}
+	}
+
+	if len(entries) > 0 {
+		if err := batch.writeChunk(len(entries), func() error { return deleteFunc(batch, entries) }); err != nil {
+			errs.Add(err)
+		}
+	}
+
+	return errs.Combined()
+}
+
+// decodeGroupData decodes groups of type T, which conversionFunc converts into group data
+func decodeGroupData[T any](batch *TimestampedBatch, decoder *json.Decoder, conversionFunc func(group T, converted *ConvertedGroupData, ingestTime time.Time)) error {
 
 	var (
 		convertedData = ConvertedGroupData{}
@@ -128,7 +166,7 @@ func decodeGroupData(batch *TimestampedBatch, decoder *json.Decoder) error {
 	)
 
 	for decoder.More() {
-		var group ein.Group
+		var group T
 		if err := decoder.Decode(&group); err != nil {
 			slog.Error(fmt.Sprintf("Error decoding group object: %v", err))
 			if errors.Is(err, io.EOF) {
@@ -137,9 +175,9 @@ func decodeGroupData(batch *TimestampedBatch, decoder *json.Decoder) error {
 			return err
 		} else {
 			count++
-			convertGroupData(group, &convertedData, batch.IngestTime)
+			conversionFunc(group, &convertedData, batch.IngestTime)
 			if count == IngestCountThreshold {
-				if err = IngestGroupData(batch, convertedData); err != nil {
+				if err = batch.writeChunk(count, func() error { return IngestGroupData(batch, convertedData) }); err != nil {
 					errs.Add(err)
 				}
 
@@ -150,7 +188,7 @@ func decodeGroupData(batch *TimestampedBatch, decoder *json.Decoder) error {
 	}
 
 	if count > 0 {
-		if err := IngestGroupData(batch, convertedData); err != nil {
+		if err := batch.writeChunk(count, func() error { return IngestGroupData(batch, convertedData) }); err != nil {
 			errs.Add(err)
 		}
 	}
@@ -177,7 +215,7 @@ func decodeSessionData(batch *TimestampedBatch, decoder *json.Decoder) error {
 			count++
 			convertSessionData(session, &convertedData)
 			if count == IngestCountThreshold {
-				if err = IngestSessions(batch, convertedData.SessionProps); err != nil {
+				if err = batch.writeChunk(count, func() error { return IngestSessions(batch, convertedData.SessionProps) }); err != nil {
 					errs.Add(err)
 				}
 				convertedData.Clear()
@@ -187,7 +225,7 @@ func decodeSessionData(batch *TimestampedBatch, decoder *json.Decoder) error {
 	}
 
 	if count > 0 {
-		if err := IngestSessions(batch, convertedData.SessionProps); err != nil {
+		if err := batch.writeChunk(count, func() error { return IngestSessions(batch, convertedData.SessionProps) }); err != nil {
 			errs.Add(err)
 		}
 	}
@@ -215,7 +253,7 @@ func decodeAzureData(batch *TimestampedBatch, decoder *json.Decoder) error {
 			convert(data.Data, &convertedData, batch.IngestTime)
 			count++
 			if count == IngestCountThreshold {
-				if err = IngestAzureData(batch, convertedData); err != nil {
+				if err = batch.writeChunk(count, func() error { return IngestAzureData(batch, convertedData) }); err != nil {
 					errs.Add(err)
 				}
 				convertedData.Clear()
@@ -225,7 +263,7 @@ func decodeAzureData(batch *TimestampedBatch, decoder *json.Decoder) error {
 	}
 
 	if count > 0 {
-		if err := IngestAzureData(batch, convertedData); err != nil {
+		if err := batch.writeChunk(count, func() error { return IngestAzureData(batch, convertedData) }); err != nil {
 			errs.Add(err)
 		}
 	}
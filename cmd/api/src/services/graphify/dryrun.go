@@ -0,0 +1,266 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
+	"github.com/specterops/bloodhound/cmd/api/src/services/upload"
+	"github.com/specterops/bloodhound/packages/go/ein"
+	"github.com/specterops/bloodhound/packages/go/graphschema/common"
+	"github.com/specterops/dawgs/graph"
+)
+
+// MaxDryRunUnresolvedRelationships caps how many unresolved relationships are listed for a single file in a dry run
+// report. The total number skipped is always reported in full.
+const MaxDryRunUnresolvedRelationships = 100
+
+// DryRunReport describes what ingesting an uploaded file would have written to the graph
+type DryRunReport struct {
+	Files []DryRunFileReport `json:"files"`
+
+	// NewSourceKinds lists the OpenGraph source kinds that ingesting the upload would register for the first time
+	NewSourceKinds []string `json:"new_source_kinds"`
+}
+
+// DryRunFileReport describes what ingesting a single file would have written to the graph. Files extracted from an
+// archive each get their own report.
+type DryRunFileReport struct {
+	FileName                 string                   `json:"file_name"`
+	DataType                 ingest.DataType          `json:"data_type"`
+	NodeCounts               map[string]int64         `json:"node_counts"`
+	RelationshipCounts       map[string]int64         `json:"relationship_counts"`
+	SkippedRelationshipCount int64                    `json:"skipped_relationship_count"`
+	InvalidRelationshipCount int64                    `json:"invalid_relationship_count"`
+	UnresolvedRelationships  []UnresolvedRelationship `json:"unresolved_relationships"`
+	SchemaViolations         []string                 `json:"schema_violations"`
+	Error                    string                   `json:"error"`
+}
+
+// UnresolvedRelationship is a relationship that would be skipped because one of its endpoints matches no node, or
+// matches more than one node, by name
+type UnresolvedRelationship struct {
+	Kind           string `json:"kind"`
+	Source         string `json:"source"`
+	SourceResolved bool   `json:"source_resolved"`
+	Target         string `json:"target"`
+	TargetResolved bool   `json:"target_resolved"`
+}
+
+func newDryRunFileReport(fileName string) DryRunFileReport {
+	return DryRunFileReport{
+		FileName:                fileName,
+		NodeCounts:              map[string]int64{},
+		RelationshipCounts:      map[string]int64{},
+		UnresolvedRelationships: []UnresolvedRelationship{},
+		SchemaViolations:        []string{},
+	}
+}
+
+// NewDryRunReportForViolations builds the report for an upload that was rejected by schema validation before any of
+// it could be read
+func NewDryRunReportForViolations(violations upload.ValidationReport) DryRunReport {
+	fileReport := newDryRunFileReport("")
+	fileReport.SchemaViolations = violations.Messages()
+
+	return DryRunReport{
+		Files:          []DryRunFileReport{fileReport},
+		NewSourceKinds: []string{},
+	}
+}
+
+// setError records why a file could not be read. Schema violations are listed individually rather than as one error.
+func (s *DryRunFileReport) setError(err error) {
+	var report upload.ValidationReport
+
+	if errors.As(err, &report) {
+		s.SchemaViolations = report.Messages()
+	} else {
+		s.Error = err.Error()
+	}
+}
+
+// dryRunBatch is a graph.Batch that counts writes by kind instead of performing them. Reads are passed through to a
+// read only transaction so that relationship endpoints matched by name resolve against the real graph. The nodes the
+// file would have written are kept as well, so endpoints can also resolve to nodes from the same payload.
+type dryRunBatch struct {
+	tx     graph.Transaction
+	report *DryRunFileReport
+	nodes  map[string]*graph.Node
+}
+
+func newDryRunBatch(tx graph.Transaction, report *DryRunFileReport) *dryRunBatch {
+	return &dryRunBatch{
+		tx:     tx,
+		report: report,
+		nodes:  map[string]*graph.Node{},
+	}
+}
+
+func (s *dryRunBatch) recordNode(node *graph.Node) {
+	for _, kind := range node.Kinds {
+		s.report.NodeCounts[kind.String()]++
+	}
+
+	// Nodes are upserted by object ID, so writes to the same node accumulate as they would in the graph
+	if objectID, err := node.Properties.Get(common.ObjectID.String()).String(); err != nil || objectID == "" {
+		return
+	} else if existing, found := s.nodes[objectID]; found {
+		existing.Properties.Merge(node.Properties)
+		existing.Kinds = existing.Kinds.Add(node.Kinds...)
+	} else {
+		s.nodes[objectID] = graph.PrepareNode(node.Properties.Clone(), node.Kinds.Copy()...)
+	}
+}
+
+func (s *dryRunBatch) stagedNodes() map[string]*graph.Node {
+	return s.nodes
+}
+
+func (s *dryRunBatch) countRelationship(kind graph.Kind) {
+	s.report.RelationshipCounts[kind.String()]++
+}
+
+func (s *dryRunBatch) WithGraph(graphSchema graph.Graph) graph.Batch {
+	return s
+}
+
+func (s *dryRunBatch) CreateNode(node *graph.Node) error {
+	s.recordNode(node)
+	return nil
+}
+
+func (s *dryRunBatch) DeleteNode(id graph.ID) error {
+	return nil
+}
+
+func (s *dryRunBatch) Nodes() graph.NodeQuery {
+	return s.tx.Nodes()
+}
+
+func (s *dryRunBatch) Relationships() graph.RelationshipQuery {
+	return s.tx.Relationships()
+}
+
+func (s *dryRunBatch) UpdateNodeBy(update graph.NodeUpdate) error {
+	s.recordNode(update.Node)
+	return nil
+}
+
+func (s *dryRunBatch) CreateRelationship(relationship *graph.Relationship) error {
+	s.countRelationship(relationship.Kind)
+	return nil
+}
+
+func (s *dryRunBatch) CreateRelationshipByIDs(startNodeID, endNodeID graph.ID, kind graph.Kind, properties *graph.Properties) error {
+	s.countRelationship(kind)
+	return nil
+}
+
+func (s *dryRunBatch) DeleteRelationship(id graph.ID) error {
+	return nil
+}
+
+func (s *dryRunBatch) UpdateRelationshipBy(update graph.RelationshipUpdate) error {
+	s.countRelationship(update.Relationship.Kind)
+	return nil
+}
+
+func (s *dryRunBatch) Commit() error {
+	return nil
+}
+
+// DryRunIngestFile reads the file at path with the same decoders that ingest uses and reports what would be written
+// to the graph, without writing anything. Source kinds are not registered; any that are not in knownSourceKinds are
+// reported as new instead. CSV files are converted with csvMapping. A file that is not an archive is reported under
+// originalName, the name it was uploaded with. The file at path is removed once it has been read.
+func (s *GraphifyService) DryRunIngestFile(ctx context.Context, path, originalName string, fileType model.FileType, csvMapping *ingest.CSVMapping, knownSourceKinds graph.Kinds) (DryRunReport, error) {
+	var (
+		report = DryRunReport{
+			Files:          []DryRunFileReport{},
+			NewSourceKinds: []string{},
+		}
+		registerSourceKind = func(sourceKind graph.Kind) error {
+			if sourceKind != graph.EmptyKind && !knownSourceKinds.ContainsOneOf(sourceKind) && !slices.Contains(report.NewSourceKinds, sourceKind.String()) {
+				report.NewSourceKinds = append(report.NewSourceKinds, sourceKind.String())
+			}
+			return nil
+		}
+	)
+
+	files, failed, err := s.extractIngestFiles(path, originalName, fileType)
+	if err != nil {
+		fileReport := newDryRunFileReport(uploadName(path, originalName))
+		fileReport.setError(err)
+		report.Files = append(report.Files, fileReport)
+		return report, nil
+	}
+
+	for _, result := range failed {
+		fileReport := newDryRunFileReport(result.FileName)
+		fileReport.Error = result.Error
+		report.Files = append(report.Files, fileReport)
+	}
+
+	edgePropertySchema := s.loadEdgePropertySchema(ctx)
+
+	return report, s.graphdb.ReadTransaction(ctx, func(tx graph.Transaction) error {
+		for _, file := range files {
+			var (
+				fileReport       = newDryRunFileReport(file.Name)
+				timestampedBatch = NewTimestampedBatch(newDryRunBatch(tx, &fileReport), time.Now().UTC())
+				readOpts         = ReadOptions{
+					IngestSchema:       s.schema,
+					FileType:           fileType,
+					RegisterSourceKind: registerSourceKind,
+					CSVMapping:         csvMapping,
+				}
+			)
+
+			// Each file gets its own copy, as the declarations of an OpenGraph payload only apply to that payload
+			timestampedBatch.EdgePropertySchema = maps.Clone(edgePropertySchema)
+			timestampedBatch.OnSkippedRelationship = func(rel ein.IngestibleRelationship, sourceResolved, targetResolved bool) {
+				if len(fileReport.UnresolvedRelationships) < MaxDryRunUnresolvedRelationships {
+					fileReport.UnresolvedRelationships = append(fileReport.UnresolvedRelationships, UnresolvedRelationship{
+						Kind:           rel.RelType.String(),
+						Source:         rel.Source.Value,
+						SourceResolved: sourceResolved,
+						Target:         rel.Target.Value,
+						TargetResolved: targetResolved,
+					})
+				}
+			}
+
+			if err := processSingleFile(ctx, file.Path, timestampedBatch, readOpts); err != nil {
+				fileReport.setError(err)
+			}
+
+			fileReport.DataType = timestampedBatch.Stats.DataType
+			fileReport.SkippedRelationshipCount = timestampedBatch.Stats.SkippedRelationships
+			fileReport.InvalidRelationshipCount = timestampedBatch.Stats.InvalidRelationships
+			report.Files = append(report.Files, fileReport)
+		}
+
+		return nil
+	})
+}
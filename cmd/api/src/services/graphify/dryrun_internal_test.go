@@ -0,0 +1,87 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/services/upload"
+	graphmocks "github.com/specterops/bloodhound/cmd/api/src/vendormocks/dawgs/graph"
+	"github.com/specterops/dawgs/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGraphifyService_DryRunIngestFile_ResolvesPayloadNodes(t *testing.T) {
+	var (
+		mockCtrl      = gomock.NewController(t)
+		mockGraphDB   = graphmocks.NewMockDatabase(mockCtrl)
+		mockTx        = graphmocks.NewMockTransaction(mockCtrl)
+		mockNodeQuery = graphmocks.NewMockNodeQuery(mockCtrl)
+		path          = filepath.Join(t.TempDir(), "opengraph.json")
+		payload       = `{"graph": {
+			"nodes": [
+				{"id": "1", "kinds": ["Person"], "properties": {"name": "alice"}},
+				{"id": "2", "kinds": ["Person"], "properties": {"name": "bob"}}
+			],
+			"edges": [
+				{"kind": "Knows", "start": {"value": "alice", "match_by": "name"}, "end": {"value": "bob", "match_by": "name", "kind": "Person"}},
+				{"kind": "Knows", "start": {"value": "alice", "match_by": "name"}, "end": {"value": "carol", "match_by": "name"}}
+			]
+		}}`
+	)
+
+	schema, err := upload.LoadIngestSchema()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte(payload), 0600))
+
+	service := &GraphifyService{
+		ctx:     context.Background(),
+		db:      stubGraphifyData{},
+		graphdb: mockGraphDB,
+		schema:  schema,
+	}
+
+	// The graph is empty, so only the nodes of the payload itself can resolve the endpoints
+	mockGraphDB.EXPECT().ReadTransaction(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, delegate graph.TransactionDelegate, _ ...graph.TransactionOption) error {
+		return delegate(mockTx)
+	})
+	mockTx.EXPECT().Nodes().Return(mockNodeQuery).AnyTimes()
+	mockNodeQuery.EXPECT().Filter(gomock.Any()).Return(mockNodeQuery).AnyTimes()
+	mockNodeQuery.EXPECT().Fetch(gomock.Any()).Return(nil).AnyTimes()
+
+	report, err := service.DryRunIngestFile(context.Background(), path, "opengraph.json", model.FileTypeJson, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, report.Files, 1)
+
+	fileReport := report.Files[0]
+	assert.Equal(t, int64(2), fileReport.NodeCounts["Person"])
+	assert.Equal(t, int64(1), fileReport.RelationshipCounts["Knows"])
+	assert.Equal(t, int64(1), fileReport.SkippedRelationshipCount)
+	assert.Equal(t, []UnresolvedRelationship{{
+		Kind:           "Knows",
+		Source:         "ALICE",
+		SourceResolved: true,
+		Target:         "CAROL",
+		TargetResolved: false,
+	}}, fileReport.UnresolvedRelationships)
+}
@@ -0,0 +1,232 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/packages/go/ein"
+	"github.com/specterops/dawgs/graph"
+	"github.com/specterops/dawgs/util"
+)
+
+// EdgePropertySchema holds the properties declared for edge kinds by the schema sections of OpenGraph payloads, keyed
+// by edge kind. The properties of ingested edges of a declared kind are checked against, and converted to, the types
+// declared for them, and edges missing a property declared as required are not written.
+type EdgePropertySchema map[string]model.OpenGraphProperties
+
+// NewEdgePropertySchema returns the edge property declarations of the kinds given. Node kinds are ignored.
+func NewEdgePropertySchema(kinds model.OpenGraphKinds) EdgePropertySchema {
+	schema := EdgePropertySchema{}
+	schema.declare(kinds)
+	return schema
+}
+
+// declare adds the edge kinds given to the schema, replacing any earlier declaration of the same kind
+func (s EdgePropertySchema) declare(kinds model.OpenGraphKinds) {
+	for _, kind := range kinds {
+		if kind.IsEdge {
+			s[kind.KindName] = kind.Properties
+		}
+	}
+}
+
+// validate checks the properties of an edge of the given kind, returning every problem found. Values must be strings,
+// numbers, booleans or arrays holding a single one of those types, as the OpenGraph JSON schema requires of node and
+// edge properties. Properties declared for the kind are converted to their declared type in place.
+func (s EdgePropertySchema) validate(kind graph.Kind, properties map[string]any) []string {
+	var problems []string
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if problem := validateEdgePropertyValue(properties[name]); problem != "" {
+			problems = append(problems, fmt.Sprintf("property %q %s", name, problem))
+		}
+	}
+
+	if kind == nil {
+		return problems
+	}
+
+	for _, declared := range s[kind.String()] {
+		if value, ok := properties[declared.Name]; !ok || value == nil {
+			if declared.Required {
+				problems = append(problems, fmt.Sprintf("missing required property %q", declared.Name))
+			}
+		} else if typed, ok := convertDeclaredProperty(declared.Type, value); !ok {
+			problems = append(problems, fmt.Sprintf("property %q must be of type %s", declared.Name, declared.Type))
+		} else {
+			properties[declared.Name] = typed
+		}
+	}
+
+	return problems
+}
+
+// primitiveTypeOf returns the OpenGraph primitive type of a value: string, number or boolean
+func primitiveTypeOf(value any) (string, bool) {
+	switch value.(type) {
+	case string:
+		return "string", true
+	case bool:
+		return "boolean", true
+	case json.Number, float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "number", true
+	default:
+		return "", false
+	}
+}
+
+// validateEdgePropertyValue describes why a property value is not allowed, or returns an empty string if it is
+func validateEdgePropertyValue(value any) string {
+	if _, ok := primitiveTypeOf(value); ok {
+		return ""
+	} else if value == nil {
+		return "must not be null"
+	}
+
+	switch reflected := reflect.ValueOf(value); reflected.Kind() {
+	case reflect.Slice, reflect.Array:
+		var arrayType string
+
+		for idx := 0; idx < reflected.Len(); idx++ {
+			if itemType, ok := primitiveTypeOf(reflected.Index(idx).Interface()); !ok {
+				return "is an array that contains something other than strings, numbers or booleans"
+			} else if arrayType == "" {
+				arrayType = itemType
+			} else if itemType != arrayType {
+				return "contains a mixed-type array"
+			}
+		}
+
+		return ""
+	case reflect.Map, reflect.Struct:
+		return "must not be an object"
+	default:
+		return fmt.Sprintf("has an unsupported type %T", value)
+	}
+}
+
+// convertDeclaredProperty converts a valid property value to the type declared for it, returning false if it can not
+// be represented as that type. Timestamps are given as RFC 3339 strings.
+func convertDeclaredProperty(propertyType string, value any) (any, bool) {
+	switch propertyType {
+	case model.OpenGraphPropertyTypeString:
+		_, ok := value.(string)
+		return value, ok
+
+	case model.OpenGraphPropertyTypeBoolean:
+		_, ok := value.(bool)
+		return value, ok
+
+	case model.OpenGraphPropertyTypeFloat:
+		return toFloat64(value)
+
+	case model.OpenGraphPropertyTypeInteger:
+		if number, ok := toFloat64(value); !ok || number != math.Trunc(number) || number < math.MinInt64 || number >= math.MaxInt64 {
+			return nil, false
+		} else if integer, ok := value.(int64); ok {
+			// Integers are returned as given so that large values do not lose precision through float64
+			return integer, true
+		} else {
+			return int64(number), true
+		}
+
+	case model.OpenGraphPropertyTypeTimestamp:
+		if rawTimestamp, ok := value.(string); !ok {
+			return nil, false
+		} else if timestamp, err := time.Parse(time.RFC3339Nano, rawTimestamp); err != nil {
+			return nil, false
+		} else {
+			return timestamp.UTC(), true
+		}
+
+	case model.OpenGraphPropertyTypeArray:
+		kind := reflect.ValueOf(value).Kind()
+		return value, kind == reflect.Slice || kind == reflect.Array
+
+	default:
+		return value, true
+	}
+}
+
+func toFloat64(value any) (float64, bool) {
+	switch number := value.(type) {
+	case json.Number:
+		parsed, err := number.Float64()
+		return parsed, err == nil
+	case float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return reflect.ValueOf(number).Convert(reflect.TypeOf(float64(0))).Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// declareEdgeProperties adds the edge kinds declared by the schema section of a payload to the batch, replacing the
+// stored declarations of the same kinds for the rest of the file
+func (s *TimestampedBatch) declareEdgeProperties(kinds model.OpenGraphKinds) {
+	if s.EdgePropertySchema == nil {
+		s.EdgePropertySchema = EdgePropertySchema{}
+	}
+
+	s.EdgePropertySchema.declare(kinds)
+}
+
+// validateRelationshipProperties drops the relationships whose properties are not valid, returning the rest along with
+// an error describing each relationship dropped
+func (s *TimestampedBatch) validateRelationshipProperties(relationships []ein.IngestibleRelationship) ([]ein.IngestibleRelationship, error) {
+	var (
+		valid = make([]ein.IngestibleRelationship, 0, len(relationships))
+		errs  = util.NewErrorCollector()
+	)
+
+	for _, rel := range relationships {
+		if problems := s.EdgePropertySchema.validate(rel.RelType, rel.RelProps); len(problems) > 0 {
+			s.Stats.InvalidRelationships++
+			errs.Add(fmt.Errorf("skipping invalid relationship %s from %s to %s: %s", rel.RelType, rel.Source.Value, rel.Target.Value, strings.Join(problems, "; ")))
+		} else {
+			valid = append(valid, rel)
+		}
+	}
+
+	return valid, errs.Combined()
+}
+
+// loadEdgePropertySchema returns the edge property declarations stored from earlier OpenGraph payloads. Edges are
+// still checked against the declarations of the payload being read if the stored declarations can not be loaded.
+func (s *GraphifyService) loadEdgePropertySchema(ctx context.Context) EdgePropertySchema {
+	if kinds, err := s.db.GetOpenGraphKinds(ctx); err != nil {
+		slog.WarnContext(ctx, fmt.Sprintf("Stored OpenGraph edge property declarations will not be applied: %v", err))
+		return EdgePropertySchema{}
+	} else {
+		return NewEdgePropertySchema(kinds)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/packages/go/ein"
+	"github.com/specterops/dawgs/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEdgePropertySchema_Validate(t *testing.T) {
+	schema := NewEdgePropertySchema(model.OpenGraphKinds{
+		{KindName: "GithubUser", Properties: model.OpenGraphProperties{{Name: "risk_score", Type: model.OpenGraphPropertyTypeFloat, Required: true}}},
+		{KindName: "GithubMemberOf", IsEdge: true, Properties: model.OpenGraphProperties{
+			{Name: "risk_score", Type: model.OpenGraphPropertyTypeFloat, Required: true},
+			{Name: "evidence", Type: model.OpenGraphPropertyTypeArray},
+			{Name: "collected_at", Type: model.OpenGraphPropertyTypeTimestamp},
+			{Name: "rank", Type: model.OpenGraphPropertyTypeInteger},
+		}},
+	})
+
+	testCases := []struct {
+		name       string
+		kind       graph.Kind
+		properties map[string]any
+		problems   []string
+		typed      map[string]any
+	}{
+		{
+			name:       "primitive values and homogeneous arrays are allowed",
+			kind:       graph.StringKind("GithubStarred"),
+			properties: map[string]any{"name": "repo", "count": float64(3), "public": true, "tags": []any{"a", "b"}, "scores": []int64{1, 2}, "empty": []any{}},
+		},
+		{
+			name:       "objects are not allowed",
+			kind:       graph.StringKind("GithubStarred"),
+			properties: map[string]any{"evidence": map[string]any{"source": "api"}},
+			problems:   []string{`property "evidence" must not be an object`},
+		},
+		{
+			name:       "null values are not allowed",
+			kind:       graph.StringKind("GithubStarred"),
+			properties: map[string]any{"evidence": nil},
+			problems:   []string{`property "evidence" must not be null`},
+		},
+		{
+			name:       "arrays may only hold primitive values of a single type",
+			kind:       graph.StringKind("GithubStarred"),
+			properties: map[string]any{"mixed": []any{"a", float64(1)}, "nested": []any{map[string]any{}}},
+			problems:   []string{`property "mixed" contains a mixed-type array`, `property "nested" is an array that contains something other than strings, numbers or booleans`},
+		},
+		{
+			name:       "node kind declarations are not applied to edges",
+			kind:       graph.StringKind("GithubUser"),
+			properties: map[string]any{},
+		},
+		{
+			name:       "required properties must be present",
+			kind:       graph.StringKind("GithubMemberOf"),
+			properties: map[string]any{"rank": float64(1)},
+			problems:   []string{`missing required property "risk_score"`},
+		},
+		{
+			name:       "declared properties must have their declared type",
+			kind:       graph.StringKind("GithubMemberOf"),
+			properties: map[string]any{"risk_score": "high", "evidence": "audit log", "collected_at": "yesterday", "rank": 1.5},
+			problems: []string{
+				`property "risk_score" must be of type float`,
+				`property "evidence" must be of type array`,
+				`property "collected_at" must be of type timestamp`,
+				`property "rank" must be of type integer`,
+			},
+		},
+		{
+			name:       "declared properties are converted to their declared type",
+			kind:       graph.StringKind("GithubMemberOf"),
+			properties: map[string]any{"risk_score": float64(7), "evidence": []string{"audit log"}, "collected_at": "2025-06-01T12:00:00+02:00", "rank": float64(2)},
+			typed:      map[string]any{"risk_score": float64(7), "evidence": []string{"audit log"}, "collected_at": time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC), "rank": int64(2)},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.problems, schema.validate(testCase.kind, testCase.properties))
+
+			if testCase.typed != nil {
+				assert.Equal(t, testCase.typed, testCase.properties)
+			}
+		})
+	}
+}
+
+func TestTimestampedBatch_ValidateRelationshipProperties(t *testing.T) {
+	var (
+		batch         = NewTimestampedBatch(nil, time.Now().UTC())
+		relationships = []ein.IngestibleRelationship{
+			ein.NewIngestibleRelationship(ein.IngestibleEndpoint{Value: "1"}, ein.IngestibleEndpoint{Value: "2"}, ein.IngestibleRel{
+				RelType:  graph.StringKind("GithubMemberOf"),
+				RelProps: map[string]any{"risk_score": float64(1)},
+			}),
+			ein.NewIngestibleRelationship(ein.IngestibleEndpoint{Value: "3"}, ein.IngestibleEndpoint{Value: "4"}, ein.IngestibleRel{
+				RelType:  graph.StringKind("GithubMemberOf"),
+				RelProps: map[string]any{},
+			}),
+		}
+	)
+
+	batch.declareEdgeProperties(model.OpenGraphKinds{
+		{KindName: "GithubMemberOf", IsEdge: true, Properties: model.OpenGraphProperties{{Name: "risk_score", Type: model.OpenGraphPropertyTypeFloat, Required: true}}},
+	})
+
+	valid, err := batch.validateRelationshipProperties(relationships)
+	require.EqualError(t, err, `skipping invalid relationship GithubMemberOf from 3 to 4: missing required property "risk_score"`)
+	assert.Equal(t, relationships[:1], valid)
+	assert.Equal(t, int64(1), batch.Stats.InvalidRelationships)
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/specterops/bloodhound/packages/go/ein"
+	"github.com/specterops/bloodhound/packages/go/graphschema/common"
+	"github.com/specterops/dawgs/graph"
+	"github.com/specterops/dawgs/ops"
+	"github.com/specterops/dawgs/query"
+)
+
+// DeleteFunc deletes what a chunk of decoded deletion entries (of type T) match from the graph through the batch given
+type DeleteFunc[T any] func(batch *TimestampedBatch, entries []T) error
+
+// deleteNode deletes a single node, counting it toward the batch stats if it succeeds
+func (s *TimestampedBatch) deleteNode(id graph.ID) error {
+	if err := s.Batch.DeleteNode(id); err != nil {
+		return err
+	}
+
+	s.Stats.DeletedNodes++
+	return nil
+}
+
+// deleteRelationship deletes a single relationship, counting it toward the batch stats if it succeeds
+func (s *TimestampedBatch) deleteRelationship(id graph.ID) error {
+	if err := s.Batch.DeleteRelationship(id); err != nil {
+		return err
+	}
+
+	s.Stats.DeletedRelationships++
+	return nil
+}
+
+// queryableEndpoint reports whether the property an endpoint is matched by, if any, is a plain name that can be written
+// into the translated graph query
+func queryableEndpoint(endpoint ein.IngestibleEndpoint) bool {
+	return endpoint.MatchBy != ein.MatchByProperty || indexablePropertyName.MatchString(endpoint.Property)
+}
+
+// endpointCriteria matches the node referenced by an endpoint that has been resolved to the object ID given. The
+// optional kind of the endpoint is also required of the node, including for endpoints matched by ID.
+func endpointCriteria(reference graph.Criteria, endpoint ein.IngestibleEndpoint, objectID string) graph.Criteria {
+	criteria := query.Equals(query.Property(reference, common.ObjectID.String()), objectID)
+
+	if endpoint.Kind == nil || endpoint.Kind == graph.EmptyKind {
+		return criteria
+	}
+
+	return query.And(criteria, query.Kind(reference, endpoint.Kind))
+}
+
+// DeleteGenericNodes deletes the nodes matched by the entries of the deleted_nodes section of an OpenGraph payload.
+// Entries are resolved the same way as edge endpoints. Entries that can not be resolved, or that match no node, are
+// skipped since the node may already have been deleted, and entries matched by a property name that is not a plain name
+// are rejected before any criteria is built for them. The edges of a deleted node are deleted along with it and are
+// not counted separately.
+func DeleteGenericNodes(batch *TimestampedBatch, entries []ein.EdgeEndpoint) error {
+	endpoints := make([]ein.IngestibleEndpoint, 0, len(entries))
+	for _, entry := range entries {
+		if endpoint := convertGenericEdgeEndpoint(entry); !queryableEndpoint(endpoint) {
+			slog.Warn("skipping node deletion matched by an invalid property name", slog.String("property", endpoint.Property))
+		} else {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	cache, err := resolveEndpoints(batch.Batch, endpoints)
+	if err != nil {
+		return err
+	}
+
+	var filters []graph.Criteria
+	for _, endpoint := range endpoints {
+		if objectID, ok := resolveEndpointID(endpoint, cache); !ok {
+			slog.Warn("skipping unresolved node deletion", slog.String("value", endpoint.Value))
+		} else {
+			filters = append(filters, endpointCriteria(query.Node(), endpoint, objectID))
+		}
+	}
+
+	if len(filters) == 0 {
+		return nil
+	}
+
+	if ids, err := ops.FetchNodeIDs(batch.Batch.Nodes().Filter(query.Or(filters...))); err != nil {
+		return fmt.Errorf("failed to fetch nodes to delete: %w", err)
+	} else {
+		for _, id := range ids {
+			if err := batch.deleteNode(id); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// DeleteGenericEdges deletes the edges matched by the entries of the deleted_edges section of an OpenGraph payload.
+// The start and end of each entry are resolved the same way as edge endpoints, and only edges of the entry's kind
+// between the two resolved nodes are deleted. Entries that can not be resolved, or that match no edge, are skipped, and
+// entries with an endpoint matched by a property name that is not a plain name are rejected before any criteria is built.
+func DeleteGenericEdges(batch *TimestampedBatch, entries []ein.GenericDeletedEdge) error {
+	rels := make([]ein.IngestibleRelationship, 0, len(entries))
+	for _, entry := range entries {
+		var (
+			start = convertGenericEdgeEndpoint(entry.Start)
+			end   = convertGenericEdgeEndpoint(entry.End)
+		)
+
+		if !queryableEndpoint(start) || !queryableEndpoint(end) {
+			slog.Warn("skipping relationship deletion matched by an invalid property name",
+				slog.String("start_property", start.Property),
+				slog.String("end_property", end.Property))
+			continue
+		}
+
+		rels = append(rels, ein.NewIngestibleRelationship(start, end, ein.IngestibleRel{RelType: graph.StringKind(entry.Kind)}))
+	}
+
+	cache, err := resolveAllEndpoints(batch.Batch, rels)
+	if err != nil {
+		return err
+	}
+
+	var filters []graph.Criteria
+	for _, rel := range rels {
+		srcID, srcOK := resolveEndpointID(rel.Source, cache)
+		targetID, targetOK := resolveEndpointID(rel.Target, cache)
+
+		if !srcOK || !targetOK {
+			slog.Warn("skipping unresolved relationship deletion",
+				slog.String("source", rel.Source.Value),
+				slog.String("target", rel.Target.Value),
+				slog.Bool("resolved_source", srcOK),
+				slog.Bool("resolved_target", targetOK))
+			continue
+		}
+
+		filters = append(filters, query.And(
+			endpointCriteria(query.Start(), rel.Source, srcID),
+			endpointCriteria(query.End(), rel.Target, targetID),
+			query.Kind(query.Relationship(), rel.RelType),
+		))
+	}
+
+	if len(filters) == 0 {
+		return nil
+	}
+
+	if ids, err := ops.FetchRelationshipIDs(batch.Batch.Relationships().Filter(query.Or(filters...))); err != nil {
+		return fmt.Errorf("failed to fetch relationships to delete: %w", err)
+	} else {
+		for _, id := range ids {
+			if err := batch.deleteRelationship(id); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build serial_integration
+// +build serial_integration
+
+package graphify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/specterops/bloodhound/cmd/api/src/test/integration"
+	"github.com/specterops/bloodhound/packages/go/ein"
+	"github.com/specterops/bloodhound/packages/go/graphschema"
+	"github.com/specterops/bloodhound/packages/go/graphschema/common"
+	"github.com/specterops/dawgs/graph"
+	"github.com/specterops/dawgs/query"
+	"github.com/stretchr/testify/require"
+)
+
+func countNodesWithObjectID(t *testing.T, testContext *integration.GraphTestContext, db graph.Database, objectID string) int64 {
+	var count int64
+
+	require.Nil(t, db.ReadTransaction(testContext.Context(), func(tx graph.Transaction) error {
+		var err error
+		count, err = tx.Nodes().Filter(query.Equals(query.NodeProperty(common.ObjectID.String()), objectID)).Count()
+		return err
+	}))
+
+	return count
+}
+
+func Test_DeleteGenericNodes(t *testing.T) {
+	testContext := integration.NewGraphTestContext(t, graphschema.DefaultGraphSchema())
+
+	testContext.DatabaseTestWithSetup(
+		func(harness *integration.HarnessDetails) error {
+			harness.GenericIngest.Setup(testContext)
+			return nil
+		},
+		func(harness integration.HarnessDetails, db graph.Database) {
+			var timestampedBatch *TimestampedBatch
+
+			err := db.BatchOperation(testContext.Context(), func(batch graph.Batch) error {
+				timestampedBatch = NewTimestampedBatch(batch, time.Now().UTC())
+
+				return DeleteGenericNodes(timestampedBatch, []ein.EdgeEndpoint{
+					{Value: "1234"},
+					{Value: "bobby", MatchBy: string(ein.MatchByName), Kind: "KindB"},
+					{Value: "server-01", MatchBy: string(ein.MatchByName), Kind: "KindA"}, // kind does not match
+					{Value: "same name", MatchBy: string(ein.MatchByName)},                // ambiguous
+					{Value: "not a node"},
+				})
+			})
+			require.Nil(t, err)
+
+			require.Equal(t, int64(2), timestampedBatch.Stats.DeletedNodes)
+			require.Zero(t, countNodesWithObjectID(t, testContext, db, "1234"))
+			require.Zero(t, countNodesWithObjectID(t, testContext, db, "5678"))
+			require.Equal(t, int64(1), countNodesWithObjectID(t, testContext, db, "0001"))
+		})
+}
+
+func Test_DeleteGenericEdges(t *testing.T) {
+	var (
+		testContext = integration.NewGraphTestContext(t, graphschema.DefaultGraphSchema())
+		connectedTo = graph.StringKind("ConnectedTo")
+		managedBy   = graph.StringKind("ManagedBy")
+	)
+
+	testContext.DatabaseTestWithSetup(
+		func(harness *integration.HarnessDetails) error {
+			harness.GenericIngest.Setup(testContext)
+			testContext.NewRelationship(harness.GenericIngest.Node9, harness.GenericIngest.Node10, connectedTo)
+			testContext.NewRelationship(harness.GenericIngest.Node9, harness.GenericIngest.Node10, managedBy)
+			return nil
+		},
+		func(harness integration.HarnessDetails, db graph.Database) {
+			var timestampedBatch *TimestampedBatch
+
+			err := db.BatchOperation(testContext.Context(), func(batch graph.Batch) error {
+				timestampedBatch = NewTimestampedBatch(batch, time.Now().UTC())
+
+				return DeleteGenericEdges(timestampedBatch, []ein.GenericDeletedEdge{
+					{
+						Start: ein.EdgeEndpoint{Value: "server-01", MatchBy: string(ein.MatchByName)},
+						End:   ein.EdgeEndpoint{Value: "0002"},
+						Kind:  connectedTo.String(),
+					},
+					{
+						Start: ein.EdgeEndpoint{Value: "not a node", MatchBy: string(ein.MatchByName)},
+						End:   ein.EdgeEndpoint{Value: "0002"},
+						Kind:  managedBy.String(),
+					},
+				})
+			})
+			require.Nil(t, err)
+
+			require.Equal(t, int64(1), timestampedBatch.Stats.DeletedRelationships)
+
+			require.Nil(t, db.ReadTransaction(testContext.Context(), func(tx graph.Transaction) error {
+				if count, err := tx.Relationships().Filter(query.Kind(query.Relationship(), connectedTo)).Count(); err != nil {
+					return err
+				} else {
+					require.Zero(t, count)
+				}
+
+				if count, err := tx.Relationships().Filter(query.Kind(query.Relationship(), managedBy)).Count(); err != nil {
+					return err
+				} else {
+					require.Equal(t, int64(1), count)
+				}
+
+				return nil
+			}))
+		})
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify
+
+import (
+	"testing"
+	"time"
+
+	graphmocks "github.com/specterops/bloodhound/cmd/api/src/vendormocks/dawgs/graph"
+	"github.com/specterops/bloodhound/packages/go/ein"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const unsafePropertyName = "x') is not null; drop table users; --"
+
+func TestDeleteGenericNodes_RejectsInvalidPropertyName(t *testing.T) {
+	var (
+		mockCtrl         = gomock.NewController(t)
+		timestampedBatch = NewTimestampedBatch(graphmocks.NewMockBatch(mockCtrl), time.Now().UTC())
+	)
+
+	// The batch has no expectations set, so any query built for the entry fails the test
+	require.NoError(t, DeleteGenericNodes(timestampedBatch, []ein.EdgeEndpoint{
+		{Value: "alice@example.com", MatchBy: string(ein.MatchByProperty), Property: unsafePropertyName},
+	}))
+	require.Zero(t, timestampedBatch.Stats.DeletedNodes)
+}
+
+func TestDeleteGenericEdges_RejectsInvalidPropertyName(t *testing.T) {
+	var (
+		mockCtrl         = gomock.NewController(t)
+		timestampedBatch = NewTimestampedBatch(graphmocks.NewMockBatch(mockCtrl), time.Now().UTC())
+	)
+
+	// The batch has no expectations set, so any query built for the entry fails the test
+	require.NoError(t, DeleteGenericEdges(timestampedBatch, []ein.GenericDeletedEdge{
+		{
+			Start: ein.EdgeEndpoint{Value: "1234"},
+			End:   ein.EdgeEndpoint{Value: "alice@example.com", MatchBy: string(ein.MatchByProperty), Property: unsafePropertyName},
+			Kind:  "Knows",
+		},
+	}))
+	require.Zero(t, timestampedBatch.Stats.DeletedRelationships)
+}
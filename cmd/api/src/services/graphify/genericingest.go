@@ -19,55 +19,144 @@ package graphify
 import (
 	"fmt"
 	"log/slog"
+	"maps"
 	"strings"
 
 	"github.com/specterops/bloodhound/packages/go/ein"
 	"github.com/specterops/bloodhound/packages/go/graphschema/common"
+	"github.com/specterops/dawgs/cypher/models/cypher"
 	"github.com/specterops/dawgs/graph"
 	"github.com/specterops/dawgs/query"
 	"github.com/specterops/dawgs/util"
 )
 
+// stringFolding is how endpoint values and node properties are normalized before they are compared
+type stringFolding int
+
+const (
+	foldNone stringFolding = iota
+	foldUpper
+	foldLower
+)
+
+func (s stringFolding) apply(value string) string {
+	switch s {
+	case foldUpper:
+		return strings.ToUpper(value)
+	case foldLower:
+		return strings.ToLower(value)
+	default:
+		return value
+	}
+}
+
+// endpointLookup is a node property that endpoints are resolved by. Names are stored uppercase, so they are matched
+// against an uppercase value. Other properties are matched as given, or with both sides lowercased when the match is
+// not case-sensitive.
+type endpointLookup struct {
+	Property string
+	Folding  stringFolding
+}
+
+var nameLookup = endpointLookup{Property: common.Name.String(), Folding: foldUpper}
+
+// endpointLookupFor returns the lookup the endpoint is resolved by, or false if the endpoint is matched by ID
+func endpointLookupFor(endpoint ein.IngestibleEndpoint) (endpointLookup, bool) {
+	switch endpoint.MatchBy {
+	case ein.MatchByName:
+		return nameLookup, true
+
+	case ein.MatchByProperty:
+		// The property name is written into the translated query as is, so anything but a plain name is refused
+		if !indexablePropertyName.MatchString(endpoint.Property) {
+			return endpointLookup{}, false
+		} else if endpoint.CaseSensitive {
+			return endpointLookup{Property: endpoint.Property, Folding: foldNone}, true
+		} else {
+			return endpointLookup{Property: endpoint.Property, Folding: foldLower}, true
+		}
+
+	default:
+		return endpointLookup{}, false
+	}
+}
+
+// criteria matches nodes whose property has the value given, which has already been folded
+func (s endpointLookup) criteria(value string) graph.Criteria {
+	if s.Folding == foldLower {
+		return query.Equals(cypher.NewSimpleFunctionInvocation(cypher.ToLowerFunction, query.NodeProperty(s.Property)), value)
+	}
+
+	return query.Equals(query.NodeProperty(s.Property), value)
+}
+
 type endpointKey struct {
-	Name string
-	Kind string
+	Lookup endpointLookup
+	Value  string
+	Kind   string
 }
 
-func addKey(endpoint ein.IngestibleEndpoint, cache map[endpointKey]struct{}) {
-	if endpoint.MatchBy != ein.MatchByName {
-		return
+func newEndpointKey(endpoint ein.IngestibleEndpoint) (endpointKey, bool) {
+	lookup, ok := endpointLookupFor(endpoint)
+	if !ok {
+		return endpointKey{}, false
 	}
+
 	key := endpointKey{
-		Name: strings.ToUpper(endpoint.Value),
+		Lookup: lookup,
+		Value:  lookup.Folding.apply(endpoint.Value),
 	}
 	if endpoint.Kind != nil {
 		key.Kind = endpoint.Kind.String()
 	}
-	cache[key] = struct{}{}
+	return key, true
+}
+
+func addKey(endpoint ein.IngestibleEndpoint, cache map[endpointKey]struct{}) {
+	if key, ok := newEndpointKey(endpoint); ok {
+		cache[key] = struct{}{}
+	}
 }
 
-// resolveAllEndpointsByName attempts to resolve all unique source and target
-// endpoints from a list of ingestible relationships into their corresponding object IDs.
+// resolveAllEndpoints attempts to resolve all unique source and target endpoints that are matched by name or by
+// property from a list of ingestible relationships into their corresponding object IDs.
 //
-// Each endpoint is identified by a Name, (optional) Kind pair. A single batch query is
+// Each endpoint is identified by a Lookup, Value, (optional) Kind triple. A single batch query is
 // used to resolve all endpoints in one round trip.
 //
-// If multiple nodes match a given Name, Kind pair with conflicting object IDs,
-// the match is considered ambiguous and excluded from the result. This can happen because there are no
-// uniqueness guarantees on a node's `Name` property.
+// If multiple nodes match a given triple with conflicting object IDs, the match is considered ambiguous and
+// excluded from the result. This can happen because there are no uniqueness guarantees on a node's `Name` property,
+// or on any other property matched by.
 //
 // Returns a map of resolved object IDs. If no matches are found or the input is empty, an empty map is returned.
-func resolveAllEndpointsByName(batch graph.Batch, rels []ein.IngestibleRelationship) (map[endpointKey]string, error) {
-	// seen deduplicates Name:Kind pairs from the input batch to ensure that each Name:Kind pairs is resolved once.
+func resolveAllEndpoints(batch graph.Batch, rels []ein.IngestibleRelationship) (map[endpointKey]string, error) {
+	endpoints := make([]ein.IngestibleEndpoint, 0, len(rels)*2)
+
+	for _, rel := range rels {
+		endpoints = append(endpoints, rel.Source, rel.Target)
+	}
+
+	return resolveEndpoints(batch, endpoints)
+}
+
+// stagedNodeReader is implemented by batches that hold node writes which are not visible to their Nodes query, such
+// as the batch used for dry runs. Endpoints resolve against these nodes, keyed by object ID, as well as the graph.
+type stagedNodeReader interface {
+	stagedNodes() map[string]*graph.Node
+}
+
+// resolveEndpoints resolves all unique endpoints that are matched by name or by property into their corresponding
+// object IDs, in the same way as resolveAllEndpoints
+func resolveEndpoints(batch graph.Batch, endpoints []ein.IngestibleEndpoint) (map[endpointKey]string, error) {
+	// seen deduplicates keys from the input batch to ensure that each key is resolved once.
 	seen := map[endpointKey]struct{}{}
 
-	if len(rels) == 0 {
+	if len(endpoints) == 0 {
 		return map[endpointKey]string{}, nil
 	}
 
-	for _, rel := range rels {
-		addKey(rel.Source, seen)
-		addKey(rel.Target, seen)
+	for _, endpoint := range endpoints {
+		addKey(endpoint, seen)
 	}
 	// if nothing to filter, return early
 	if len(seen) == 0 {
@@ -76,10 +165,11 @@ func resolveAllEndpointsByName(batch graph.Batch, rels []ein.IngestibleRelations
 
 	var (
 		filters     = make([]graph.Criteria, 0, len(seen))
+		lookups     = map[endpointLookup]struct{}{}
 		buildFilter = func(key endpointKey) graph.Criteria {
 			var criteria []graph.Criteria
 
-			criteria = append(criteria, query.Equals(query.NodeProperty(common.Name.String()), key.Name))
+			criteria = append(criteria, key.Lookup.criteria(key.Value))
 			if key.Kind != "" {
 				criteria = append(criteria, query.Kind(query.Node(), graph.StringKind(key.Kind)))
 			}
@@ -87,42 +177,67 @@ func resolveAllEndpointsByName(batch graph.Batch, rels []ein.IngestibleRelations
 		}
 	)
 
-	// aggregate all Name:Kind pairs in 1 DAWGs query for 1 round trip
+	// aggregate all keys in 1 DAWGs query for 1 round trip
 	for key := range seen {
 		filters = append(filters, buildFilter(key))
+		lookups[key.Lookup] = struct{}{}
 	}
 
 	var (
 		resolved  = map[endpointKey]string{}
 		ambiguous = map[endpointKey]bool{}
+		staged    = map[string]*graph.Node{}
+		matchNode = func(objectID string, properties *graph.Properties, kinds graph.Kinds) {
+			// edge case: resolve an empty key to match endpoints that provide no Kind filter
+			kinds = kinds.Copy().Add(graph.EmptyKind)
+
+			// resolve all values found for the properties looked up to objectids,
+			// record ambiguous matches (when more than one match is found, we cannot disambiguate the requested node and must skip the update)
+			for lookup := range lookups {
+				value, err := properties.Get(lookup.Property).String()
+				if err != nil {
+					continue
+				}
+
+				for _, kind := range kinds {
+					key := endpointKey{Lookup: lookup, Value: lookup.Folding.apply(value), Kind: kind.String()}
+					if existingID, exists := resolved[key]; exists && existingID != objectID {
+						ambiguous[key] = true
+					} else {
+						resolved[key] = objectID
+					}
+				}
+			}
+		}
 	)
 
+	if reader, ok := batch.(stagedNodeReader); ok {
+		staged = maps.Clone(reader.stagedNodes())
+	}
+
 	if err := batch.Nodes().Filter(query.Or(filters...)).Fetch(
 		func(cursor graph.Cursor[*graph.Node]) error {
 
 			for node := range cursor.Chan() {
-				nameVal, _ := node.Properties.Get(common.Name.String()).String()
 				objectID, err := node.Properties.Get(string(common.ObjectID)).String()
 				if err != nil || objectID == "" {
+					nameVal, _ := node.Properties.Get(common.Name.String()).String()
 					slog.Warn("matched node missing objectid",
 						slog.String("name", nameVal),
 						slog.Any("kinds", node.Kinds))
 					continue
 				}
 
-				// edge case: resolve an empty key to match endpoints that provide no Kind filter
-				node.Kinds = append(node.Kinds, graph.EmptyKind)
+				if stagedNode, found := staged[objectID]; found {
+					// the staged write upserts this node, so it is matched as it will be once the write lands
+					properties := node.Properties.Clone()
+					properties.Merge(stagedNode.Properties)
 
-				// resolve all names found to objectids,
-				// record ambiguous matches (when more than one match is found, we cannot disambiguate the requested node and must skip the update)
-				for _, kind := range node.Kinds {
-					key := endpointKey{Name: strings.ToUpper(nameVal), Kind: kind.String()}
-					if existingID, exists := resolved[key]; exists && existingID != objectID {
-						ambiguous[key] = true
-					} else {
-						resolved[key] = objectID
-					}
+					staged[objectID] = graph.PrepareNode(properties, node.Kinds.Copy().Add(stagedNode.Kinds...)...)
+					continue
 				}
+
+				matchNode(objectID, node.Properties, node.Kinds)
 			}
 
 			return nil
@@ -131,6 +246,10 @@ func resolveAllEndpointsByName(batch graph.Batch, rels []ein.IngestibleRelations
 		return nil, err
 	}
 
+	for objectID, node := range staged {
+		matchNode(objectID, node.Properties, node.Kinds)
+	}
+
 	// remove ambiguous matches
 	for key := range ambiguous {
 		delete(resolved, key)
@@ -144,7 +263,7 @@ func resolveAllEndpointsByName(batch graph.Batch, rels []ein.IngestibleRelations
 // graph database.
 //
 // The function resolves all source and target endpoints to their corresponding
-// object IDs if MatchByName is set on an endpoint. Relationships with unresolved
+// object IDs if MatchByName or MatchByProperty is set on an endpoint. Relationships with unresolved
 // or ambiguous endpoints are skipped and logged with a warning.
 //
 // The identityKind parameter determines the identity kind used for both start
@@ -154,7 +273,7 @@ func resolveAllEndpointsByName(batch graph.Batch, rels []ein.IngestibleRelations
 //
 // Returns a slice of valid relationship updates or an error if resolution fails.
 func resolveRelationships(batch *TimestampedBatch, rels []ein.IngestibleRelationship, sourceKind graph.Kind) ([]graph.RelationshipUpdate, error) {
-	if cache, err := resolveAllEndpointsByName(batch.Batch, rels); err != nil {
+	if cache, err := resolveAllEndpoints(batch.Batch, rels); err != nil {
 		return nil, err
 	} else {
 		var (
@@ -172,12 +291,14 @@ func resolveRelationships(batch *TimestampedBatch, rels []ein.IngestibleRelation
 					slog.String("target", rel.Target.Value),
 					slog.Bool("resolved_source", srcOK),
 					slog.Bool("resolved_target", targetOK))
+				batch.skipRelationship(rel, srcOK, targetOK)
 				errs.Add(
 					fmt.Errorf("skipping invalid relationship. unable to resolve endpoints. source: %s, target: %s", rel.Source.Value, rel.Target.Value),
 				)
 				continue
 			}
 
+			batch.redactProperties(rel.RelProps)
 			rel.RelProps[common.LastSeen.String()] = batch.IngestTime
 
 			startKinds := MergeNodeKinds(sourceKind, rel.Source.Kind)
@@ -207,16 +328,13 @@ func resolveRelationships(batch *TimestampedBatch, rels []ein.IngestibleRelation
 }
 
 func resolveEndpointID(endpoint ein.IngestibleEndpoint, cache map[endpointKey]string) (string, bool) {
-	if endpoint.MatchBy == ein.MatchByName {
-		key := endpointKey{
-			Name: strings.ToUpper(endpoint.Value),
-			Kind: "",
-		}
-		if endpoint.Kind != nil {
-			key.Kind = endpoint.Kind.String()
+	if endpoint.MatchBy == ein.MatchByName || endpoint.MatchBy == ein.MatchByProperty {
+		if key, ok := newEndpointKey(endpoint); !ok {
+			return "", false
+		} else {
+			id, ok := cache[key]
+			return id, ok
 		}
-		id, ok := cache[key]
-		return id, ok
 	}
 
 	// Fallback to raw value if matching by ID
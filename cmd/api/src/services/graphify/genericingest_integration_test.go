@@ -520,13 +520,14 @@ func Test_ResolveRelationships(t *testing.T) {
 	})
 }
 
-func Test_ResolveAllEndpointsByName(t *testing.T) {
+func Test_ResolveAllEndpoints(t *testing.T) {
 	testContext := integration.NewGraphTestContext(t, graphschema.DefaultGraphSchema())
 
 	generateKey := func(name, kind string) endpointKey {
 		return endpointKey{
-			Name: name,
-			Kind: kind,
+			Lookup: nameLookup,
+			Value:  name,
+			Kind:   kind,
 		}
 	}
 	t.Run("Single match. One node with name and kind found, and valid objectid returned.", func(t *testing.T) {
@@ -544,7 +545,7 @@ func Test_ResolveAllEndpointsByName(t *testing.T) {
 
 				rels := []ein.IngestibleRelationship{rel} // simulate a "batch"
 
-				cache, err := resolveAllEndpointsByName(batch, rels)
+				cache, err := resolveAllEndpoints(batch, rels)
 				require.Nil(t, err)
 				require.Len(t, cache, 3) // cache has keys for 'User' and 'Base' and ""
 
@@ -574,7 +575,7 @@ func Test_ResolveAllEndpointsByName(t *testing.T) {
 
 				rels := []ein.IngestibleRelationship{rel} // simulate a "batch"
 
-				cache, err := resolveAllEndpointsByName(batch, rels)
+				cache, err := resolveAllEndpoints(batch, rels)
 				require.Nil(t, err)
 				require.Len(t, cache, 0)
 
@@ -600,7 +601,7 @@ func Test_ResolveAllEndpointsByName(t *testing.T) {
 
 				rels := []ein.IngestibleRelationship{rel} // simulate a "batch"
 
-				cache, err := resolveAllEndpointsByName(batch, rels)
+				cache, err := resolveAllEndpoints(batch, rels)
 				require.Nil(t, err)
 				require.Len(t, cache, 0)
 
@@ -626,7 +627,7 @@ func Test_ResolveAllEndpointsByName(t *testing.T) {
 
 				rels := []ein.IngestibleRelationship{rel} // simulate a "batch"
 
-				cache, err := resolveAllEndpointsByName(batch, rels)
+				cache, err := resolveAllEndpoints(batch, rels)
 				require.Nil(t, err)
 				require.Len(t, cache, 5) // Alice node has keys for 'User' and 'Base' and "". Bob just has GenericBase and ""
 
@@ -645,6 +646,66 @@ func Test_ResolveAllEndpointsByName(t *testing.T) {
 		})
 	})
 
+	t.Run("Property match. Values are compared without case unless case-sensitive is set.", func(t *testing.T) {
+		testContext.DatabaseTestWithSetup(func(harness *integration.HarnessDetails) error {
+			harness.ResolveEndpointsByName.Setup(testContext)
+			return nil
+		}, func(harness integration.HarnessDetails, db graph.Database) {
+
+			err := db.BatchOperation(testContext.Context(), func(batch graph.Batch) error {
+				var (
+					insensitive = ein.IngestibleEndpoint{Value: "bob@example.com", Kind: graph.StringKind("GenericDevice"), MatchBy: ein.MatchByProperty, Property: common.Email.String()}
+					sensitive   = ein.IngestibleEndpoint{Value: "bob@example.com", MatchBy: ein.MatchByProperty, Property: common.Email.String(), CaseSensitive: true}
+					rels        = []ein.IngestibleRelationship{ein.NewIngestibleRelationship(insensitive, sensitive, ein.IngestibleRel{})}
+				)
+
+				cache, err := resolveAllEndpoints(batch, rels)
+				require.Nil(t, err)
+
+				id, ok := resolveEndpointID(insensitive, cache)
+				require.True(t, ok)
+				require.Equal(t, "1234", id)
+
+				_, ok = resolveEndpointID(sensitive, cache)
+				require.False(t, ok)
+
+				sensitive.Value = "Bob@Example.com"
+				cache, err = resolveAllEndpoints(batch, []ein.IngestibleRelationship{ein.NewIngestibleRelationship(sensitive, ein.IngestibleEndpoint{}, ein.IngestibleRel{})})
+				require.Nil(t, err)
+
+				id, ok = resolveEndpointID(sensitive, cache)
+				require.True(t, ok)
+				require.Equal(t, "1234", id)
+
+				return nil
+			})
+
+			require.Nil(t, err)
+		})
+	})
+
+	t.Run("Ambiguous property match. Two nodes with the same property value. Skipped from result map.", func(t *testing.T) {
+		testContext.DatabaseTestWithSetup(func(harness *integration.HarnessDetails) error {
+			harness.ResolveEndpointsByName.Setup(testContext)
+			return nil
+		}, func(harness integration.HarnessDetails, db graph.Database) {
+
+			err := db.BatchOperation(testContext.Context(), func(batch graph.Batch) error {
+				endpoint := ein.IngestibleEndpoint{Value: "same name", Kind: ad.Computer, MatchBy: ein.MatchByProperty, Property: common.Name.String()}
+
+				cache, err := resolveAllEndpoints(batch, []ein.IngestibleRelationship{ein.NewIngestibleRelationship(endpoint, ein.IngestibleEndpoint{}, ein.IngestibleRel{})})
+				require.Nil(t, err)
+
+				_, ok := resolveEndpointID(endpoint, cache)
+				require.False(t, ok)
+
+				return nil
+			})
+
+			require.Nil(t, err)
+		})
+	})
+
 	t.Run("Empty input.	Empty result map returned.", func(t *testing.T) {
 		testContext.DatabaseTestWithSetup(func(harness *integration.HarnessDetails) error {
 			harness.ResolveEndpointsByName.Setup(testContext)
@@ -654,7 +715,7 @@ func Test_ResolveAllEndpointsByName(t *testing.T) {
 			err := db.BatchOperation(testContext.Context(), func(batch graph.Batch) error {
 				rels := []ein.IngestibleRelationship{} // simulate a "batch"
 
-				cache, err := resolveAllEndpointsByName(batch, rels)
+				cache, err := resolveAllEndpoints(batch, rels)
 				require.Nil(t, err)
 				require.Len(t, cache, 0)
 
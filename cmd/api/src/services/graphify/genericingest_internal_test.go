@@ -0,0 +1,104 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify
+
+import (
+	"testing"
+
+	"github.com/specterops/bloodhound/packages/go/ein"
+	"github.com/specterops/dawgs/graph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEndpointKey(t *testing.T) {
+	testCases := []struct {
+		name     string
+		endpoint ein.IngestibleEndpoint
+		expected endpointKey
+		ok       bool
+	}{
+		{
+			name:     "matched by ID",
+			endpoint: ein.IngestibleEndpoint{Value: "1234", MatchBy: ein.MatchByID},
+		},
+		{
+			name:     "matched by name",
+			endpoint: ein.IngestibleEndpoint{Value: "alice", MatchBy: ein.MatchByName, Kind: graph.StringKind("User")},
+			expected: endpointKey{Lookup: nameLookup, Value: "ALICE", Kind: "User"},
+			ok:       true,
+		},
+		{
+			name:     "matched by property without case",
+			endpoint: ein.IngestibleEndpoint{Value: "Alice@Example.com", MatchBy: ein.MatchByProperty, Property: "email"},
+			expected: endpointKey{Lookup: endpointLookup{Property: "email", Folding: foldLower}, Value: "alice@example.com"},
+			ok:       true,
+		},
+		{
+			name:     "matched by property with case",
+			endpoint: ein.IngestibleEndpoint{Value: "Alice@Example.com", MatchBy: ein.MatchByProperty, Property: "email", CaseSensitive: true},
+			expected: endpointKey{Lookup: endpointLookup{Property: "email", Folding: foldNone}, Value: "Alice@Example.com"},
+			ok:       true,
+		},
+		{
+			name:     "matched by property without a property",
+			endpoint: ein.IngestibleEndpoint{Value: "Alice@Example.com", MatchBy: ein.MatchByProperty},
+		},
+		{
+			name:     "matched by property with a quote in the property name",
+			endpoint: ein.IngestibleEndpoint{Value: "Alice@Example.com", MatchBy: ein.MatchByProperty, Property: "x') is not null; drop table users; --"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			key, ok := newEndpointKey(testCase.endpoint)
+			assert.Equal(t, testCase.ok, ok)
+			assert.Equal(t, testCase.expected, key)
+		})
+	}
+}
+
+func TestResolveEndpointID(t *testing.T) {
+	var (
+		endpoint = ein.IngestibleEndpoint{Value: "CN=ALICE,DC=EXAMPLE,DC=COM", MatchBy: ein.MatchByProperty, Property: "distinguishedname"}
+		key, _   = newEndpointKey(endpoint)
+	)
+
+	id, ok := resolveEndpointID(endpoint, map[endpointKey]string{key: "1234"})
+	assert.True(t, ok)
+	assert.Equal(t, "1234", id)
+
+	_, ok = resolveEndpointID(endpoint, map[endpointKey]string{})
+	assert.False(t, ok)
+
+	id, ok = resolveEndpointID(ein.IngestibleEndpoint{Value: "5678"}, nil)
+	assert.True(t, ok)
+	assert.Equal(t, "5678", id)
+}
+
+func TestConvertGenericEdge_PropertyValuesKeepCase(t *testing.T) {
+	var converted ConvertedData
+
+	assert.NoError(t, ConvertGenericEdge(ein.GenericEdge{
+		Start: ein.EdgeEndpoint{Value: "alice", MatchBy: string(ein.MatchByName)},
+		End:   ein.EdgeEndpoint{Value: "Bob@Example.com", MatchBy: string(ein.MatchByProperty), Property: "email", CaseSensitive: true},
+		Kind:  "Knows",
+	}, &converted))
+
+	assert.Equal(t, ein.IngestibleEndpoint{Value: "ALICE", MatchBy: ein.MatchByName, Kind: graph.StringKind("")}, converted.RelProps[0].Source)
+	assert.Equal(t, ein.IngestibleEndpoint{Value: "Bob@Example.com", MatchBy: ein.MatchByProperty, Kind: graph.StringKind(""), Property: "email", CaseSensitive: true}, converted.RelProps[0].Target)
+}
@@ -23,9 +23,11 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/specterops/bloodhound/cmd/api/src/metrics"
 	"github.com/specterops/bloodhound/cmd/api/src/model"
 	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
 	"github.com/specterops/bloodhound/cmd/api/src/services/upload"
@@ -41,6 +43,7 @@ import (
 const (
 	IngestCountThreshold = 500
 	ReconcileProperty    = "reconcile"
+	StaleProperty        = "stale"
 )
 
 // registrationFn persists a kind encountered in the ingest payload, if it hasn't already been registered in the source_kinds table.
@@ -48,14 +51,75 @@ const (
 type registrationFn func(kind graph.Kind) error
 
 type ReadOptions struct {
-	FileType           model.FileType // JSON or ZIP
+	FileType           model.FileType // JSON, NDJSON, CSV or one of the archive types
 	IngestSchema       upload.IngestSchema
 	RegisterSourceKind registrationFn
+	CSVMapping         *ingest.CSVMapping // Only set for CSV files
 }
 
 type TimestampedBatch struct {
 	Batch      graph.Batch
 	IngestTime time.Time
+	Stats      IngestStats
+
+	// PropertyTransformer, if set, applies the configured ingest transform rules to nodes before they are written
+	PropertyTransformer *PropertyTransformer
+
+	// PropertyRedactor, if set, applies the configured ingest redaction rules to nodes and relationships before they
+	// are written. Node properties are redacted after the transform rules have been applied.
+	PropertyRedactor *PropertyRedactor
+
+	// OnSkippedRelationship, if set, is called with every relationship dropped because one of its endpoints could
+	// not be resolved to a node
+	OnSkippedRelationship func(rel ein.IngestibleRelationship, sourceResolved, targetResolved bool)
+
+	// EdgePropertySchema holds the property declarations that the edges of generic data are checked against. The
+	// declarations of the schema section of an OpenGraph payload are added to it as the payload is read.
+	EdgePropertySchema EdgePropertySchema
+
+	// Environment, if set, is written as the environment property of every node and relationship ingested. The nodes
+	// created to stand in for the unresolved endpoints of a relationship are not tagged, as they may belong to
+	// another environment.
+	Environment string
+
+	// RecordMetrics, if set, reports the objects decoded, the chunks written and the relationships skipped through the
+	// batch to the ingest metrics. Dry runs leave it unset as they write nothing.
+	RecordMetrics bool
+}
+
+// IngestStats tallies what was written through a TimestampedBatch so the outcome of each ingested file can be reported
+type IngestStats struct {
+	DataType             ingest.DataType
+	Nodes                int64
+	Relationships        int64
+	SkippedRelationships int64
+
+	// InvalidRelationships counts the relationships of generic data that were not written because their properties
+	// failed validation
+	InvalidRelationships int64
+
+	// DeletedNodes and DeletedRelationships count what was removed by the deleted_nodes and deleted_edges sections of
+	// an OpenGraph file. Relationships removed along with a deleted node are not counted.
+	DeletedNodes         int64
+	DeletedRelationships int64
+
+	// Domains and SourceKinds are the collection scope covered by the file: the SIDs of the domains collected and the
+	// OpenGraph source kinds ingested
+	Domains     []string
+	SourceKinds []string
+
+	// RedactedProperties are the names of the properties redacted from the file
+	RedactedProperties []string
+
+	// TouchedDomains and TouchedTenants are the SIDs of the domains and the IDs of the tenants of the Active Directory
+	// and Azure nodes written from the file. UnscopedObjects counts the Active Directory and Azure objects written
+	// from generic data that could not be attributed to a domain or tenant.
+	TouchedDomains  []string
+	TouchedTenants  []string
+	UnscopedObjects int64
+
+	// OpenGraphKinds are the node and edge kinds declared by the schema section of an OpenGraph file
+	OpenGraphKinds model.OpenGraphKinds
 }
 
 func NewTimestampedBatch(batch graph.Batch, ingestTime time.Time) *TimestampedBatch {
@@ -65,14 +129,130 @@ func NewTimestampedBatch(batch graph.Batch, ingestTime time.Time) *TimestampedBa
 	}
 }
 
+// skipRelationship records a relationship dropped because one of its endpoints could not be resolved
+func (s *TimestampedBatch) skipRelationship(rel ein.IngestibleRelationship, sourceResolved, targetResolved bool) {
+	s.Stats.SkippedRelationships++
+
+	if s.RecordMetrics {
+		metrics.IngestRelationshipsSkipped.WithLabelValues(metrics.UnresolvedEndpoint(sourceResolved, targetResolved)).Inc()
+	}
+
+	if s.OnSkippedRelationship != nil {
+		s.OnSkippedRelationship(rel, sourceResolved, targetResolved)
+	}
+}
+
+// writeChunk writes a chunk of objects decoded from the file through writeFunc, recording the objects and the time
+// taken to write them when the batch records metrics
+func (s *TimestampedBatch) writeChunk(objects int, writeFunc func() error) error {
+	if !s.RecordMetrics {
+		return writeFunc()
+	}
+
+	defer metrics.ObserveBatchFlush(string(s.Stats.DataType), objects, time.Now())
+	return writeFunc()
+}
+
+// redactProperties applies the redaction rules of the batch to properties and records the properties redacted
+func (s *TimestampedBatch) redactProperties(properties map[string]any) {
+	for _, redacted := range s.PropertyRedactor.Redact(properties) {
+		if !slices.Contains(s.Stats.RedactedProperties, redacted) {
+			s.Stats.RedactedProperties = append(s.Stats.RedactedProperties, redacted)
+		}
+	}
+}
+
+// collectDomain records that the file contains the collection of the domain with the given SID
+func (s *TimestampedBatch) collectDomain(domainSID string) {
+	if domainSID = strings.ToUpper(domainSID); domainSID != "" && !slices.Contains(s.Stats.Domains, domainSID) {
+		s.Stats.Domains = append(s.Stats.Domains, domainSID)
+	}
+}
+
+// collectSourceKind records that the file contains OpenGraph data for the given source kind
+func (s *TimestampedBatch) collectSourceKind(sourceKind graph.Kind) {
+	if sourceKind != graph.EmptyKind && !slices.Contains(s.Stats.SourceKinds, sourceKind.String()) {
+		s.Stats.SourceKinds = append(s.Stats.SourceKinds, sourceKind.String())
+	}
+}
+
+// touchScope records the domain or tenant of a node written through the batch, so that analysis can be limited to the
+// domains and tenants touched by ingest. Nodes of generic data are attributed from their properties only when they
+// carry Active Directory or Azure kinds.
+func (s *TimestampedBatch) touchScope(baseKind graph.Kind, nodeKinds graph.Kinds, properties *graph.Properties) {
+	var (
+		generic = baseKind != ad.Entity && baseKind != azure.Entity
+		isAD    = baseKind == ad.Entity || (generic && nodeKinds.ContainsOneOf(ad.NodeKinds()...))
+		isAzure = baseKind == azure.Entity || (generic && nodeKinds.ContainsOneOf(azure.NodeKinds()...))
+	)
+
+	if isAD {
+		if domainSID, _ := properties.GetOrDefault(ad.DomainSID.String(), "").String(); domainSID != "" {
+			s.Stats.TouchedDomains = appendMissingID(s.Stats.TouchedDomains, domainSID)
+		} else if objectID, _ := properties.GetOrDefault(common.ObjectID.String(), "").String(); nodeKinds.ContainsOneOf(ad.Domain) && objectID != "" {
+			s.Stats.TouchedDomains = appendMissingID(s.Stats.TouchedDomains, objectID)
+		} else if generic {
+			s.Stats.UnscopedObjects++
+		}
+	} else if isAzure {
+		if tenantID, _ := properties.GetOrDefault(azure.TenantID.String(), "").String(); tenantID != "" {
+			s.Stats.TouchedTenants = appendMissingID(s.Stats.TouchedTenants, tenantID)
+		} else if generic {
+			s.Stats.UnscopedObjects++
+		}
+	}
+}
+
+// touchUnscopedRelationships counts the relationships of generic data that carry Active Directory or Azure kinds, as
+// the domain or tenant they affect is not known
+func (s *TimestampedBatch) touchUnscopedRelationships(baseKind graph.Kind, relationships []ein.IngestibleRelationship) {
+	if baseKind == ad.Entity || baseKind == azure.Entity {
+		return
+	}
+
+	for _, relationship := range relationships {
+		if slices.Contains(ad.Relationships(), relationship.RelType) || slices.Contains(azure.Relationships(), relationship.RelType) {
+			s.Stats.UnscopedObjects++
+		}
+	}
+}
+
+// appendMissingID appends the upper-cased ID given to ids unless it is already present
+func appendMissingID(ids []string, id string) []string {
+	if id = strings.ToUpper(id); slices.Contains(ids, id) {
+		return ids
+	}
+
+	return append(ids, id)
+}
+
+// tagEnvironment sets the environment property of the batch on the properties given
+func (s *TimestampedBatch) tagEnvironment(properties *graph.Properties) {
+	if s.Environment != "" {
+		properties.Set(common.Environment.String(), s.Environment)
+	}
+}
+
+// updateRelationshipBy writes a single relationship update, counting it toward the batch stats if it succeeds
+func (s *TimestampedBatch) updateRelationshipBy(update graph.RelationshipUpdate) error {
+	s.tagEnvironment(update.Relationship.Properties)
+
+	if err := s.Batch.UpdateRelationshipBy(update); err != nil {
+		return err
+	}
+
+	s.Stats.Relationships++
+	return nil
+}
+
 // ReadFileForIngest orchestrates the ingestion of a file into the graph database,
 // performing any necessary metadata validation and schema enforcement before
 // delegating to the core ingest logic.
 //
-// If the file type is ZIP, additional validation is performed using JSON Schema,
-// and the full stream is consumed to enable downstream readers to function correctly.
-// Zip files are validated here and not at file upload time because it would be expensive to
-// decompress the entire zip into memory.
+// If the file came out of an archive (ZIP, GZIP, TAR.GZ or ZSTD), additional validation is performed
+// using JSON Schema, and the full stream is consumed to enable downstream readers to function correctly.
+// Archives are validated here and not at file upload time because it would be expensive to
+// decompress the entire archive into memory.
 // Files that fail this validation step will not be processed further.
 //
 // Returns an error if metadata validation or ingestion fails.
@@ -82,12 +262,27 @@ func ReadFileForIngest(batch *TimestampedBatch, reader io.ReadSeeker, options Re
 		shouldValidateGraph = false
 	)
 
+	// NDJSON payloads are always OpenGraph data and were validated line by line at file upload time
+	if options.FileType == model.FileTypeNDJSON {
+		batch.Stats.DataType = ingest.DataTypeOpenGraph
+		return IngestNDJSONData(batch, reader, options.RegisterSourceKind)
+	}
+
+	// CSV files are converted to OpenGraph data with the mapping they were uploaded with
+	if options.FileType == model.FileTypeCSV {
+		batch.Stats.DataType = ingest.DataTypeOpenGraph
+		if options.CSVMapping == nil {
+			return ingest.ErrCSVMappingMissing
+		}
+		return IngestCSVData(batch, reader, *options.CSVMapping, options.RegisterSourceKind)
+	}
+
 	// TODO: Should this be moved into the upload service. The comment here is helpful, but more
 	// discovery required.
-	// if filetype == ZIP, we need to validate against jsonschema because
+	// if filetype is an archive (ZIP, GZIP, etc.), we need to validate against jsonschema because
 	// the archive bypassed validation controls at file upload time, as opposed to JSON files,
 	// which were validated at file upload time
-	if options.FileType == model.FileTypeZip {
+	if options.FileType.IsArchive() {
 		shouldValidateGraph = true
 	}
 
@@ -99,6 +294,8 @@ func ReadFileForIngest(batch *TimestampedBatch, reader io.ReadSeeker, options Re
 		if _, err := reader.Seek(0, io.SeekStart); err != nil {
 			return fmt.Errorf("rewind failed: %w", err)
 		}
+
+		batch.Stats.DataType = meta.Type
 		return IngestWrapper(batch, reader, meta, options)
 	}
 }
@@ -124,6 +321,8 @@ func IngestBasicData(batch *TimestampedBatch, converted ConvertedData) error {
 // graph.EmptyKind to the node and relationship ingestion functions. This indicates that no
 // base kind should be applied uniformly to all ingested entities, and instead the kind(s)
 // defined directly on each node or edge (if any) are used as-is.
+//
+// Relationships whose properties fail validation against the EdgePropertySchema of the batch are not written.
 func IngestGenericData(batch *TimestampedBatch, sourceKind graph.Kind, converted ConvertedData) error {
 	errs := util.NewErrorCollector()
 
@@ -131,7 +330,14 @@ func IngestGenericData(batch *TimestampedBatch, sourceKind graph.Kind, converted
 		errs.Add(err)
 	}
 
-	if err := IngestRelationships(batch, sourceKind, converted.RelProps); err != nil {
+	relationships, err := batch.validateRelationshipProperties(converted.RelProps)
+	if err != nil {
+		errs.Add(err)
+	}
+
+	batch.touchUnscopedRelationships(sourceKind, relationships)
+
+	if err := IngestRelationships(batch, sourceKind, relationships); err != nil {
 		errs.Add(err)
 	}
 
@@ -185,6 +391,11 @@ func IngestWrapper(batch *TimestampedBatch, reader io.ReadSeeker, meta ingest.Me
 		return handler(batch, reader, meta, readOpts.RegisterSourceKind)
 	}
 
+	// Collections written before the current format are converted from their legacy layout
+	if handler, ok := legacyHandlers[meta.Type]; ok && meta.Version < ingest.LegacyFormatVersionCutoff {
+		return handler(batch, reader, meta)
+	}
+
 	// Basic handler
 	if handler, ok := basicHandlers[meta.Type]; ok {
 		return handler(batch, reader, meta)
@@ -212,20 +423,12 @@ func defaultBasicHandler[T any](conversionFunc ConversionFuncWithTime[T]) basicI
 }
 
 var basicHandlers = map[ingest.DataType]basicIngestHandler{
-	ingest.DataTypeComputer: func(batch *TimestampedBatch, reader io.ReadSeeker, meta ingest.Metadata) error {
-		if decoder, err := getDefaultDecoder(reader); err != nil {
-			return err
-		} else if meta.Version >= 5 {
-			return decodeBasicData(batch, decoder, convertComputerData)
-		} else {
-			return nil
-		}
-	},
+	ingest.DataTypeComputer: defaultBasicHandler(convertComputerData),
 	ingest.DataTypeGroup: func(batch *TimestampedBatch, reader io.ReadSeeker, meta ingest.Metadata) error {
 		if decoder, err := getDefaultDecoder(reader); err != nil {
 			return err
 		} else {
-			return decodeGroupData(batch, decoder)
+			return decodeGroupData(batch, decoder, convertGroupData)
 		}
 	},
 	ingest.DataTypeSession: func(batch *TimestampedBatch, reader io.ReadSeeker, meta ingest.Metadata) error {
@@ -242,8 +445,19 @@ var basicHandlers = map[ingest.DataType]basicIngestHandler{
 			return decodeAzureData(batch, decoder)
 		}
 	},
+	ingest.DataTypeDomain: func(batch *TimestampedBatch, reader io.ReadSeeker, meta ingest.Metadata) error {
+		if decoder, err := getDefaultDecoder(reader); err != nil {
+			return err
+		} else {
+			// Only the domains collected by this file are in scope. Trusted domains are also converted to nodes but
+			// were not collected.
+			return decodeBasicData(batch, decoder, func(domain ein.Domain, converted *ConvertedData, ingestTime time.Time) {
+				batch.collectDomain(domain.ObjectIdentifier)
+				convertDomainData(domain, converted, ingestTime)
+			})
+		}
+	},
 	ingest.DataTypeUser:           defaultBasicHandler(convertUserData),
-	ingest.DataTypeDomain:         defaultBasicHandler(convertDomainData),
 	ingest.DataTypeGPO:            defaultBasicHandler(convertGPOData),
 	ingest.DataTypeOU:             defaultBasicHandler(convertOUData),
 	ingest.DataTypeContainer:      defaultBasicHandler(convertContainerData),
@@ -253,6 +467,9 @@ var basicHandlers = map[ingest.DataType]basicIngestHandler{
 	ingest.DataTypeNTAuthStore:    defaultBasicHandler(convertNTAuthStoreData),
 	ingest.DataTypeCertTemplate:   defaultBasicHandler(convertCertTemplateData),
 	ingest.DataTypeIssuancePolicy: defaultBasicHandler(convertIssuancePolicy),
+	ingest.DataTypeApocExport: func(batch *TimestampedBatch, reader io.ReadSeeker, meta ingest.Metadata) error {
+		return IngestApocExportData(batch, reader)
+	},
 }
 
 var sourceKindHandlers = map[ingest.DataType]sourceKindIngestHandler{
@@ -269,11 +486,8 @@ var sourceKindHandlers = map[ingest.DataType]sourceKindIngestHandler{
 			var meta ein.GenericMetadata
 			if err := decoder.Decode(&meta); err != nil {
 				return fmt.Errorf("failed to parse opengraph metadata tag: %w", err)
-			}
-
-			sourceKind = graph.StringKind(meta.SourceKind)
-			if err := registerSourceKind(sourceKind); err != nil {
-				return fmt.Errorf("failed to register sourceKind: %w", err)
+			} else if sourceKind, err = ingestGenericMetadata(batch, meta, registerSourceKind); err != nil {
+				return err
 			}
 		}
 
@@ -287,20 +501,63 @@ var sourceKindHandlers = map[ingest.DataType]sourceKindIngestHandler{
 			return err
 		}
 
+		// edges that can not be resolved do not prevent the deletions below from being applied
+		errs := util.NewErrorCollector()
+
 		// decode edges, if present
 		if decoder, err := CreateIngestDecoder(reader, "edges", 2); err != nil {
 			if !errors.Is(err, ingest.ErrDataTagNotFound) {
 				return err
 			}
-			slog.Debug("no edges found in opengraph payload")
-		} else {
-			return DecodeGenericData(batch, decoder, sourceKind, ConvertGenericEdge)
+			slog.Debug("no edges found in opengraph payload; continuing to deletions")
+		} else if err := DecodeGenericData(batch, decoder, sourceKind, ConvertGenericEdge); err != nil {
+			errs.Add(err)
 		}
 
-		return nil
+		// apply node deletions, if present. deletions are resolved against the graph as it is before the writes of
+		// this payload are committed, so a payload should not both write and delete the same node or edge.
+		if decoder, err := CreateIngestDecoder(reader, "deleted_nodes", 2); err != nil {
+			if !errors.Is(err, ingest.ErrDataTagNotFound) {
+				return err
+			}
+			slog.Debug("no deleted nodes found in opengraph payload; continuing to deleted edges")
+		} else if err := DecodeGenericDeletions(batch, decoder, DeleteGenericNodes); err != nil {
+			errs.Add(err)
+		}
+
+		// apply edge deletions, if present
+		if decoder, err := CreateIngestDecoder(reader, "deleted_edges", 2); err != nil {
+			if !errors.Is(err, ingest.ErrDataTagNotFound) {
+				return err
+			}
+			slog.Debug("no deleted edges found in opengraph payload")
+		} else if err := DecodeGenericDeletions(batch, decoder, DeleteGenericEdges); err != nil {
+			errs.Add(err)
+		}
+
+		return errs.Combined()
 	},
 }
 
+// ingestGenericMetadata registers the source kind of an OpenGraph payload and collects the kinds its schema declares,
+// returning the source kind that the nodes of the payload are written with
+func ingestGenericMetadata(batch *TimestampedBatch, meta ein.GenericMetadata, registerSourceKind registrationFn) (graph.Kind, error) {
+	sourceKind := graph.StringKind(meta.SourceKind)
+	if err := registerSourceKind(sourceKind); err != nil {
+		return graph.EmptyKind, fmt.Errorf("failed to register sourceKind: %w", err)
+	}
+
+	batch.collectSourceKind(sourceKind)
+
+	if meta.Schema != nil {
+		declared := ConvertGenericSchema(meta.SourceKind, *meta.Schema)
+		batch.collectOpenGraphKinds(declared)
+		batch.declareEdgeProperties(declared)
+	}
+
+	return sourceKind, nil
+}
+
 func getDefaultDecoder(reader io.ReadSeeker) (*json.Decoder, error) {
 	return CreateIngestDecoder(reader, "data", 1)
 }
@@ -343,8 +600,16 @@ func NormalizeEinNodeProperties(properties map[string]any, objectID string, inge
 
 func IngestNode(batch *TimestampedBatch, baseKind graph.Kind, nextNode ein.IngestibleNode) error {
 	var (
-		nodeKinds            = MergeNodeKinds(baseKind, nextNode.Labels...)
-		normalizedProperties = NormalizeEinNodeProperties(nextNode.PropertyMap, nextNode.ObjectID, batch.IngestTime)
+		nodeKinds   = MergeNodeKinds(baseKind, nextNode.Labels...)
+		transformed = batch.PropertyTransformer.Transform(nodeKinds, nextNode.PropertyMap)
+	)
+
+	// Redaction runs on the transformed properties so that a value renamed, extracted or derived by a transform rule
+	// can not slip past the redaction rules
+	batch.redactProperties(transformed)
+
+	var (
+		normalizedProperties = NormalizeEinNodeProperties(transformed, nextNode.ObjectID, batch.IngestTime)
 		nodeUpdate           = graph.NodeUpdate{
 			Node:         graph.PrepareNode(graph.AsProperties(normalizedProperties), nodeKinds...),
 			IdentityKind: baseKind,
@@ -354,6 +619,8 @@ func IngestNode(batch *TimestampedBatch, baseKind graph.Kind, nextNode ein.Inges
 		}
 	)
 
+	batch.tagEnvironment(nodeUpdate.Node.Properties)
+
 	if len(nodeKinds) == 0 {
 		slog.Warn("skipping node with no kinds",
 			slog.String("objectid", nextNode.ObjectID),
@@ -367,8 +634,12 @@ func IngestNode(batch *TimestampedBatch, baseKind graph.Kind, nextNode ein.Inges
 			slog.String("kinds", strings.Join(graph.Kinds(nodeKinds).Strings(), ", ")),
 		)
 		return fmt.Errorf("node %s has too many kinds (%d); max allowed is 3", nextNode.ObjectID, len(nodeKinds))
+	} else if err := batch.Batch.UpdateNodeBy(nodeUpdate); err != nil {
+		return err
 	} else {
-		return batch.Batch.UpdateNodeBy(nodeUpdate)
+		batch.Stats.Nodes++
+		batch.touchScope(baseKind, nodeKinds, nodeUpdate.Node.Properties)
+		return nil
 	}
 }
 
@@ -403,7 +674,7 @@ func IngestRelationships(batch *TimestampedBatch, baseKind graph.Kind, relations
 	}
 
 	for _, update := range updates {
-		if err := batch.Batch.UpdateRelationshipBy(update); err != nil {
+		if err := batch.updateRelationshipBy(update); err != nil {
 			errs.Add(err)
 		}
 	}
@@ -412,11 +683,12 @@ func IngestRelationships(batch *TimestampedBatch, baseKind graph.Kind, relations
 }
 
 func ingestDNRelationship(batch *TimestampedBatch, nextRel ein.IngestibleRelationship) error {
+	batch.redactProperties(nextRel.RelProps)
 	nextRel.RelProps[common.LastSeen.String()] = batch.IngestTime
 	nextRel.Source.Value = strings.ToUpper(nextRel.Source.Value)
 	nextRel.Target.Value = strings.ToUpper(nextRel.Target.Value)
 
-	return batch.Batch.UpdateRelationshipBy(graph.RelationshipUpdate{
+	return batch.updateRelationshipBy(graph.RelationshipUpdate{
 		Relationship: graph.PrepareRelationship(graph.AsProperties(nextRel.RelProps), nextRel.RelType),
 
 		Start: graph.PrepareNode(graph.AsProperties(graph.PropertyMap{
@@ -457,7 +729,7 @@ func ingestSession(batch *TimestampedBatch, nextSession ein.IngestibleSession) e
 	nextSession.Target = strings.ToUpper(nextSession.Target)
 	nextSession.Source = strings.ToUpper(nextSession.Source)
 
-	return batch.Batch.UpdateRelationshipBy(graph.RelationshipUpdate{
+	return batch.updateRelationshipBy(graph.RelationshipUpdate{
 		Relationship: graph.PrepareRelationship(graph.AsProperties(graph.PropertyMap{
 			common.LastSeen: batch.IngestTime,
 			ad.LogonType:    nextSession.LogonType,
@@ -17,13 +17,26 @@
 package graphify_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/specterops/bloodhound/cmd/api/src/metrics"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/model/appcfg"
+	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
 	"github.com/specterops/bloodhound/cmd/api/src/services/graphify"
+	"github.com/specterops/bloodhound/cmd/api/src/services/upload"
+	graphmocks "github.com/specterops/bloodhound/cmd/api/src/vendormocks/dawgs/graph"
+	"github.com/specterops/bloodhound/packages/go/ein"
 	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
+	"github.com/specterops/bloodhound/packages/go/graphschema/azure"
 	"github.com/specterops/bloodhound/packages/go/graphschema/common"
+	"github.com/specterops/dawgs/graph"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
 )
 
 func TestNormalizeEinNodeProperties(t *testing.T) {
@@ -46,3 +59,475 @@ func TestNormalizeEinNodeProperties(t *testing.T) {
 	assert.Equal(t, "DISTINGUISHED-NAME", normalizedProperties[ad.DistinguishedName.String()])
 	assert.Equal(t, "TEMPLE", normalizedProperties[common.OperatingSystem.String()])
 }
+
+func TestIngestStats(t *testing.T) {
+	var (
+		mockCtrl  = gomock.NewController(t)
+		mockBatch = graphmocks.NewMockBatch(mockCtrl)
+		batch     = graphify.NewTimestampedBatch(mockBatch, time.Now().UTC())
+		nodes     = []ein.IngestibleNode{
+			{ObjectID: "1", PropertyMap: map[string]any{}, Labels: []graph.Kind{ad.User}},
+			{ObjectID: "2", PropertyMap: map[string]any{}, Labels: []graph.Kind{ad.Computer}},
+			{ObjectID: "3", PropertyMap: map[string]any{}},
+		}
+		relationships = []ein.IngestibleRelationship{
+			ein.NewIngestibleRelationship(
+				ein.IngestibleEndpoint{Value: "1", MatchBy: ein.MatchByID},
+				ein.IngestibleEndpoint{Value: "2", MatchBy: ein.MatchByID},
+				ein.IngestibleRel{RelType: ad.AdminTo},
+			),
+			ein.NewIngestibleRelationship(
+				ein.IngestibleEndpoint{Value: "1", MatchBy: ein.MatchByID},
+				ein.IngestibleEndpoint{MatchBy: ein.MatchByID},
+				ein.IngestibleRel{RelType: ad.AdminTo},
+			),
+		}
+	)
+
+	mockBatch.EXPECT().UpdateNodeBy(gomock.Any()).Return(nil).Times(2)
+	mockBatch.EXPECT().UpdateRelationshipBy(gomock.Any()).Return(nil).Times(1)
+
+	assert.Error(t, graphify.IngestNodes(batch, graph.EmptyKind, nodes))
+	assert.Error(t, graphify.IngestRelationships(batch, ad.Entity, relationships))
+
+	assert.Equal(t, int64(2), batch.Stats.Nodes)
+	assert.Equal(t, int64(1), batch.Stats.Relationships)
+	assert.Equal(t, int64(1), batch.Stats.SkippedRelationships)
+}
+
+func TestIngestEnvironment(t *testing.T) {
+	var (
+		mockCtrl  = gomock.NewController(t)
+		mockBatch = graphmocks.NewMockBatch(mockCtrl)
+		batch     = graphify.NewTimestampedBatch(mockBatch, time.Now().UTC())
+		rels      = []ein.IngestibleRelationship{
+			ein.NewIngestibleRelationship(
+				ein.IngestibleEndpoint{Value: "1", MatchBy: ein.MatchByID},
+				ein.IngestibleEndpoint{Value: "2", MatchBy: ein.MatchByID},
+				ein.IngestibleRel{RelType: ad.AdminTo},
+			),
+		}
+	)
+
+	batch.Environment = "subsidiary-a"
+
+	mockBatch.EXPECT().UpdateNodeBy(gomock.Any()).DoAndReturn(func(update graph.NodeUpdate) error {
+		assert.Equal(t, "subsidiary-a", update.Node.Properties.Get(common.Environment.String()).Any())
+		return nil
+	})
+	mockBatch.EXPECT().UpdateRelationshipBy(gomock.Any()).DoAndReturn(func(update graph.RelationshipUpdate) error {
+		assert.Equal(t, "subsidiary-a", update.Relationship.Properties.Get(common.Environment.String()).Any())
+		assert.False(t, update.Start.Properties.Exists(common.Environment.String()))
+		assert.False(t, update.End.Properties.Exists(common.Environment.String()))
+		return nil
+	})
+
+	require.NoError(t, graphify.IngestNode(batch, ad.Entity, ein.IngestibleNode{ObjectID: "1", PropertyMap: map[string]any{}, Labels: []graph.Kind{ad.User}}))
+	require.NoError(t, graphify.IngestRelationships(batch, ad.Entity, rels))
+}
+
+func TestIngestRedaction_AfterTransform(t *testing.T) {
+	var (
+		mockCtrl  = gomock.NewController(t)
+		mockBatch = graphmocks.NewMockBatch(mockCtrl)
+		batch     = graphify.NewTimestampedBatch(mockBatch, time.Now().UTC())
+		rels      = []ein.IngestibleRelationship{
+			ein.NewIngestibleRelationship(
+				ein.IngestibleEndpoint{Value: "1", MatchBy: ein.MatchByID},
+				ein.IngestibleEndpoint{Value: "2", MatchBy: ein.MatchByID},
+				ein.IngestibleRel{RelType: ad.AdminTo, RelProps: map[string]any{"userpassword": "hunter2"}},
+			),
+		}
+	)
+
+	transformer, err := graphify.NewPropertyTransformer([]appcfg.IngestTransformRule{
+		{Kind: ad.User.String(), Action: appcfg.IngestTransformRename, Property: "pwd", Target: "userpassword"},
+	})
+	require.NoError(t, err)
+
+	redactor, err := graphify.NewPropertyRedactor([]appcfg.IngestRedactionRule{
+		{Properties: []string{"userpassword"}, Action: appcfg.IngestRedactionDrop},
+	})
+	require.NoError(t, err)
+
+	batch.PropertyTransformer = transformer
+	batch.PropertyRedactor = redactor
+
+	// The property only gets the redacted name once the transform rule has renamed it
+	mockBatch.EXPECT().UpdateNodeBy(gomock.Any()).DoAndReturn(func(update graph.NodeUpdate) error {
+		assert.False(t, update.Node.Properties.Exists("pwd"))
+		assert.False(t, update.Node.Properties.Exists("userpassword"))
+		return nil
+	})
+	mockBatch.EXPECT().UpdateRelationshipBy(gomock.Any()).DoAndReturn(func(update graph.RelationshipUpdate) error {
+		assert.False(t, update.Relationship.Properties.Exists("userpassword"))
+		return nil
+	})
+
+	require.NoError(t, graphify.IngestNode(batch, ad.Entity, ein.IngestibleNode{ObjectID: "1", PropertyMap: map[string]any{"pwd": "hunter2"}, Labels: []graph.Kind{ad.User}}))
+	require.NoError(t, graphify.IngestRelationships(batch, ad.Entity, rels))
+
+	assert.Equal(t, []string{"userpassword"}, batch.Stats.RedactedProperties)
+}
+
+func TestIngestStats_CollectedScope(t *testing.T) {
+	ingestSchema, err := upload.LoadIngestSchema()
+	require.NoError(t, err)
+
+	var (
+		readOptions = graphify.ReadOptions{
+			IngestSchema:       ingestSchema,
+			FileType:           model.FileTypeJson,
+			RegisterSourceKind: func(k graph.Kind) error { return nil },
+		}
+		newBatch = func(t *testing.T) *graphify.TimestampedBatch {
+			mockBatch := graphmocks.NewMockBatch(gomock.NewController(t))
+			mockBatch.EXPECT().UpdateNodeBy(gomock.Any()).Return(nil).AnyTimes()
+			mockBatch.EXPECT().UpdateRelationshipBy(gomock.Any()).Return(nil).AnyTimes()
+
+			return graphify.NewTimestampedBatch(mockBatch, time.Now().UTC())
+		}
+	)
+
+	t.Run("collected domains exclude trusted domains", func(t *testing.T) {
+		var (
+			batch  = newBatch(t)
+			reader = strings.NewReader(`{"meta": {"type": "domains", "version": 6, "count": 1}, "data": [{
+				"ObjectIdentifier": "s-1-5-21-1",
+				"Properties": {"name": "EXAMPLE.LOCAL"},
+				"Trusts": [{"TargetDomainSid": "S-1-5-21-2", "TargetDomainName": "OTHER.LOCAL", "TrustDirection": "Bidirectional", "TrustType": "ParentChild", "TrustAttributes": 32}]
+			}]}`)
+		)
+
+		require.NoError(t, graphify.ReadFileForIngest(batch, reader, readOptions))
+		assert.Equal(t, []string{"S-1-5-21-1"}, batch.Stats.Domains)
+		assert.Empty(t, batch.Stats.SourceKinds)
+	})
+
+	t.Run("collected source kinds come from opengraph metadata", func(t *testing.T) {
+		var (
+			batch  = newBatch(t)
+			reader = strings.NewReader(`{"metadata": {"source_kind": "GithubBase"}, "graph": {"nodes": [{"id": "1", "kinds": ["GithubUser"]}]}}`)
+		)
+
+		require.NoError(t, graphify.ReadFileForIngest(batch, reader, readOptions))
+		assert.Equal(t, []string{"GithubBase"}, batch.Stats.SourceKinds)
+		assert.Empty(t, batch.Stats.Domains)
+	})
+}
+
+func TestIngestStats_TouchedScope(t *testing.T) {
+	newBatch := func(t *testing.T) *graphify.TimestampedBatch {
+		mockBatch := graphmocks.NewMockBatch(gomock.NewController(t))
+		mockBatch.EXPECT().UpdateNodeBy(gomock.Any()).Return(nil).AnyTimes()
+		mockBatch.EXPECT().UpdateRelationshipBy(gomock.Any()).Return(nil).AnyTimes()
+
+		return graphify.NewTimestampedBatch(mockBatch, time.Now().UTC())
+	}
+
+	t.Run("nodes are attributed to their domain or tenant", func(t *testing.T) {
+		var (
+			batch = newBatch(t)
+			nodes = []ein.IngestibleNode{
+				{ObjectID: "1", PropertyMap: map[string]any{ad.DomainSID.String(): "s-1-5-21-1"}, Labels: []graph.Kind{ad.User}},
+				{ObjectID: "2", PropertyMap: map[string]any{ad.DomainSID.String(): "S-1-5-21-1"}, Labels: []graph.Kind{ad.Computer}},
+				{ObjectID: "S-1-5-21-2", PropertyMap: map[string]any{}, Labels: []graph.Kind{ad.Domain}},
+				{ObjectID: "3", PropertyMap: map[string]any{}, Labels: []graph.Kind{ad.User}},
+			}
+		)
+
+		require.NoError(t, graphify.IngestNodes(batch, ad.Entity, nodes))
+		require.NoError(t, graphify.IngestNode(batch, azure.Entity, ein.IngestibleNode{ObjectID: "4", PropertyMap: map[string]any{azure.TenantID.String(): "TENANT-1"}, Labels: []graph.Kind{azure.User}}))
+
+		assert.Equal(t, []string{"S-1-5-21-1", "S-1-5-21-2"}, batch.Stats.TouchedDomains)
+		assert.Equal(t, []string{"TENANT-1"}, batch.Stats.TouchedTenants)
+		assert.Zero(t, batch.Stats.UnscopedObjects)
+	})
+
+	t.Run("generic data with Active Directory kinds that cannot be attributed is unscoped", func(t *testing.T) {
+		var (
+			batch     = newBatch(t)
+			converted = graphify.ConvertedData{
+				NodeProps: []ein.IngestibleNode{
+					{ObjectID: "1", PropertyMap: map[string]any{ad.DomainSID.String(): "S-1-5-21-1"}, Labels: []graph.Kind{ad.User}},
+					{ObjectID: "2", PropertyMap: map[string]any{}, Labels: []graph.Kind{ad.Computer}},
+					{ObjectID: "3", PropertyMap: map[string]any{}, Labels: []graph.Kind{graph.StringKind("GithubUser")}},
+				},
+				RelProps: []ein.IngestibleRelationship{
+					ein.NewIngestibleRelationship(
+						ein.IngestibleEndpoint{Value: "1", MatchBy: ein.MatchByID},
+						ein.IngestibleEndpoint{Value: "2", MatchBy: ein.MatchByID},
+						ein.IngestibleRel{RelType: ad.MemberOf, RelProps: map[string]any{}},
+					),
+					ein.NewIngestibleRelationship(
+						ein.IngestibleEndpoint{Value: "3", MatchBy: ein.MatchByID},
+						ein.IngestibleEndpoint{Value: "1", MatchBy: ein.MatchByID},
+						ein.IngestibleRel{RelType: graph.StringKind("GithubOwns"), RelProps: map[string]any{}},
+					),
+				},
+			}
+		)
+
+		require.NoError(t, graphify.IngestGenericData(batch, graph.StringKind("GithubBase"), converted))
+
+		assert.Equal(t, []string{"S-1-5-21-1"}, batch.Stats.TouchedDomains)
+		assert.Equal(t, int64(2), batch.Stats.UnscopedObjects)
+	})
+}
+
+func TestIngestStats_OpenGraphKinds(t *testing.T) {
+	ingestSchema, err := upload.LoadIngestSchema()
+	require.NoError(t, err)
+
+	var (
+		mockBatch   = graphmocks.NewMockBatch(gomock.NewController(t))
+		batch       = graphify.NewTimestampedBatch(mockBatch, time.Now().UTC())
+		readOptions = graphify.ReadOptions{
+			IngestSchema:       ingestSchema,
+			FileType:           model.FileTypeJson,
+			RegisterSourceKind: func(k graph.Kind) error { return nil },
+		}
+		reader = strings.NewReader(`{"metadata": {"source_kind": "GithubBase", "schema": {
+			"node_kinds": [{"name": "GithubUser", "icon": {"type": "font-awesome", "name": "user"}, "properties": [{"name": "login", "type": "string", "indexed": true}]}],
+			"edge_kinds": [{"name": "GithubMemberOf", "traversable": true}]
+		}}, "graph": {"nodes": [{"id": "1", "kinds": ["GithubUser"]}]}}`)
+	)
+
+	mockBatch.EXPECT().UpdateNodeBy(gomock.Any()).Return(nil).AnyTimes()
+
+	require.NoError(t, graphify.ReadFileForIngest(batch, reader, readOptions))
+	assert.Equal(t, model.OpenGraphKinds{
+		{
+			SourceKind: "GithubBase",
+			KindName:   "GithubUser",
+			Icon:       &model.CustomNodeIcon{Type: "font-awesome", Name: "user", Color: "#FFFFFF"},
+			Properties: model.OpenGraphProperties{{Name: "login", Type: model.OpenGraphPropertyTypeString, Indexed: true}},
+		},
+		{
+			SourceKind:  "GithubBase",
+			KindName:    "GithubMemberOf",
+			IsEdge:      true,
+			Traversable: true,
+			Properties:  model.OpenGraphProperties{},
+		},
+	}, batch.Stats.OpenGraphKinds)
+}
+
+func TestIngestStats_NDJSON(t *testing.T) {
+	var (
+		mockBatch       = graphmocks.NewMockBatch(gomock.NewController(t))
+		batch           = graphify.NewTimestampedBatch(mockBatch, time.Now().UTC())
+		registeredKinds graph.Kinds
+		readOptions     = graphify.ReadOptions{
+			FileType: model.FileTypeNDJSON,
+			RegisterSourceKind: func(k graph.Kind) error {
+				registeredKinds = append(registeredKinds, k)
+				return nil
+			},
+		}
+		reader = strings.NewReader(`{"metadata": {"source_kind": "GithubBase", "schema": {"edge_kinds": [{"name": "GithubMemberOf"}]}}}
+{"node": {"id": "1", "kinds": ["GithubUser"]}}
+
+{"node": {"id": "2", "kinds": ["GithubTeam"]}}`)
+	)
+
+	mockBatch.EXPECT().UpdateNodeBy(gomock.Any()).Return(nil).Times(2)
+
+	require.NoError(t, graphify.ReadFileForIngest(batch, reader, readOptions))
+	assert.Equal(t, ingest.DataTypeOpenGraph, batch.Stats.DataType)
+	assert.Equal(t, int64(2), batch.Stats.Nodes)
+	assert.Equal(t, graph.Kinds{graph.StringKind("GithubBase")}, registeredKinds)
+	assert.Equal(t, []string{"GithubBase"}, batch.Stats.SourceKinds)
+	assert.Len(t, batch.Stats.OpenGraphKinds, 1)
+}
+
+func TestIngestMetrics(t *testing.T) {
+	var (
+		mockBatch   = graphmocks.NewMockBatch(gomock.NewController(t))
+		batch       = graphify.NewTimestampedBatch(mockBatch, time.Now().UTC())
+		readOptions = graphify.ReadOptions{FileType: model.FileTypeNDJSON}
+		reader      = strings.NewReader(`{"node": {"id": "1", "kinds": ["GithubUser"]}}
+{"node": {"id": "2", "kinds": ["GithubTeam"]}}
+{"edge": {"start": {"value": "1"}, "end": {"value": ""}, "kind": "GithubMemberOf"}}`)
+
+		decoded = metrics.IngestObjectsDecoded.WithLabelValues(string(ingest.DataTypeOpenGraph))
+		skipped = metrics.IngestRelationshipsSkipped.WithLabelValues(metrics.UnresolvedTarget)
+
+		decodedBefore = testutil.ToFloat64(decoded)
+		skippedBefore = testutil.ToFloat64(skipped)
+	)
+
+	batch.RecordMetrics = true
+
+	mockBatch.EXPECT().UpdateNodeBy(gomock.Any()).Return(nil).Times(2)
+
+	assert.Error(t, graphify.ReadFileForIngest(batch, reader, readOptions))
+	assert.Equal(t, float64(3), testutil.ToFloat64(decoded)-decodedBefore)
+	assert.Equal(t, float64(1), testutil.ToFloat64(skipped)-skippedBefore)
+}
+
+func TestIngestMetrics_NotRecorded(t *testing.T) {
+	var (
+		mockBatch   = graphmocks.NewMockBatch(gomock.NewController(t))
+		batch       = graphify.NewTimestampedBatch(mockBatch, time.Now().UTC())
+		readOptions = graphify.ReadOptions{FileType: model.FileTypeNDJSON}
+		reader      = strings.NewReader(`{"node": {"id": "1", "kinds": ["GithubUser"]}}`)
+
+		decoded       = metrics.IngestObjectsDecoded.WithLabelValues(string(ingest.DataTypeOpenGraph))
+		decodedBefore = testutil.ToFloat64(decoded)
+	)
+
+	mockBatch.EXPECT().UpdateNodeBy(gomock.Any()).Return(nil)
+
+	require.NoError(t, graphify.ReadFileForIngest(batch, reader, readOptions))
+	assert.Equal(t, decodedBefore, testutil.ToFloat64(decoded))
+}
+
+func TestIngestStats_EdgeProperties(t *testing.T) {
+	var (
+		mockBatch     = graphmocks.NewMockBatch(gomock.NewController(t))
+		batch         = graphify.NewTimestampedBatch(mockBatch, time.Now().UTC())
+		relationships []graph.RelationshipUpdate
+		readOptions   = graphify.ReadOptions{
+			FileType:           model.FileTypeNDJSON,
+			RegisterSourceKind: func(k graph.Kind) error { return nil },
+		}
+		reader = strings.NewReader(`{"metadata": {"source_kind": "GithubBase", "schema": {"edge_kinds": [{"name": "GithubMemberOf", "traversable": true, "properties": [{"name": "risk_score", "type": "integer", "required": true}, {"name": "collected_at", "type": "timestamp"}]}]}}}
+{"edge": {"start": {"value": "1"}, "end": {"value": "2"}, "kind": "GithubMemberOf", "properties": {"risk_score": 7, "collected_at": "2025-06-01T00:00:00Z"}}}
+{"edge": {"start": {"value": "1"}, "end": {"value": "3"}, "kind": "GithubMemberOf", "properties": {"collected_at": "2025-06-01T00:00:00Z"}}}
+{"edge": {"start": {"value": "1"}, "end": {"value": "4"}, "kind": "GithubStarred", "properties": {"evidence": {"source": "api"}}}}`)
+	)
+
+	mockBatch.EXPECT().UpdateRelationshipBy(gomock.Any()).DoAndReturn(func(update graph.RelationshipUpdate) error {
+		relationships = append(relationships, update)
+		return nil
+	}).Times(1)
+
+	err := graphify.ReadFileForIngest(batch, reader, readOptions)
+	require.ErrorContains(t, err, `skipping invalid relationship GithubMemberOf from 1 to 3: missing required property "risk_score"`)
+	require.ErrorContains(t, err, `skipping invalid relationship GithubStarred from 1 to 4: property "evidence" must not be an object`)
+
+	assert.Equal(t, int64(1), batch.Stats.Relationships)
+	assert.Equal(t, int64(2), batch.Stats.InvalidRelationships)
+
+	require.Len(t, relationships, 1)
+	assert.Equal(t, int64(7), relationships[0].Relationship.Properties.Get("risk_score").Any())
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), relationships[0].Relationship.Properties.Get("collected_at").Any())
+}
+
+func TestIngestStats_LegacyCollection(t *testing.T) {
+	ingestSchema, err := upload.LoadIngestSchema()
+	require.NoError(t, err)
+
+	var (
+		mockBatch     = graphmocks.NewMockBatch(gomock.NewController(t))
+		batch         = graphify.NewTimestampedBatch(mockBatch, time.Now().UTC())
+		relationships []graph.RelationshipUpdate
+		readOptions   = graphify.ReadOptions{
+			IngestSchema: ingestSchema,
+			FileType:     model.FileTypeJson,
+		}
+		reader = strings.NewReader(`{"computers": [{
+			"Properties": {"objectid": "S-1-5-21-1-1000", "name": "WS01.TESTLAB.LOCAL"},
+			"LocalAdmins": [{"MemberId": "S-1-5-21-1-512", "MemberType": "Group"}]
+		}], "meta": {"type": "computers", "count": 1, "version": 3}}`)
+	)
+
+	mockBatch.EXPECT().UpdateNodeBy(gomock.Any()).Return(nil).AnyTimes()
+	mockBatch.EXPECT().UpdateRelationshipBy(gomock.Any()).DoAndReturn(func(update graph.RelationshipUpdate) error {
+		relationships = append(relationships, update)
+		return nil
+	}).AnyTimes()
+
+	require.NoError(t, graphify.ReadFileForIngest(batch, reader, readOptions))
+	assert.Equal(t, ingest.DataTypeComputer, batch.Stats.DataType)
+
+	var memberships []string
+	for _, update := range relationships {
+		if update.Relationship.Kind.Is(ad.MemberOfLocalGroup) {
+			memberships = append(memberships, update.Start.Properties.Get(common.ObjectID.String()).Any().(string)+" -> "+update.End.Properties.Get(common.ObjectID.String()).Any().(string))
+		}
+	}
+	assert.Equal(t, []string{"S-1-5-21-1-512 -> S-1-5-21-1-1000-544"}, memberships)
+}
+
+func TestIngestStats_ApocExport(t *testing.T) {
+	ingestSchema, err := upload.LoadIngestSchema()
+	require.NoError(t, err)
+
+	var (
+		mockBatch     = graphmocks.NewMockBatch(gomock.NewController(t))
+		batch         = graphify.NewTimestampedBatch(mockBatch, time.Now().UTC())
+		relationships graph.Kinds
+		readOptions   = graphify.ReadOptions{
+			IngestSchema: ingestSchema,
+			FileType:     model.FileTypeJson,
+		}
+		reader = strings.NewReader(`{"type":"node","id":"0","labels":["Base","User"],"properties":{"objectid":"S-1-5-21-1-1104","name":"BOB@TESTLAB.LOCAL"}}
+{"type":"node","id":"1","labels":["Base","Computer"],"properties":{"objectid":"S-1-5-21-1-1000","name":"WS01.TESTLAB.LOCAL"}}
+{"type":"node","id":"2","labels":["AZBase","AZUser"],"properties":{"objectid":"8A1C6C5D-0000-0000-0000-000000000000"}}
+{"type":"node","id":"3","labels":["Unknown"],"properties":{"objectid":"ignored"}}
+{"type":"relationship","id":"0","label":"AdminTo","start":{"id":"0","labels":["Base","User"]},"end":{"id":"1","labels":["Base","Computer"]}}
+{"type":"relationship","id":"1","label":"Owns","properties":{"isacl":true},"start":{"id":"0","labels":["Base","User"]},"end":{"id":"1","labels":["Base","Computer"]}}
+{"type":"relationship","id":"2","label":"DCSync","start":{"id":"0","labels":["Base","User"]},"end":{"id":"1","labels":["Base","Computer"]}}
+{"type":"relationship","id":"3","label":"HasSession","start":{"id":"1","labels":["Base","Computer"]},"end":{"id":"0","labels":["Base","User"]}}`)
+	)
+
+	mockBatch.EXPECT().UpdateNodeBy(gomock.Any()).Return(nil).AnyTimes()
+	mockBatch.EXPECT().UpdateRelationshipBy(gomock.Any()).DoAndReturn(func(update graph.RelationshipUpdate) error {
+		relationships = append(relationships, update.Relationship.Kind)
+		return nil
+	}).AnyTimes()
+
+	require.NoError(t, graphify.ReadFileForIngest(batch, reader, readOptions))
+	assert.Equal(t, ingest.DataTypeApocExport, batch.Stats.DataType)
+	// the user, the computer, the Azure user and the local group the AdminTo relationship is converted to
+	assert.Equal(t, int64(4), batch.Stats.Nodes)
+	assert.ElementsMatch(t, graph.Kinds{ad.LocalToComputer, ad.MemberOfLocalGroup, ad.OwnsRaw, ad.HasSession}, relationships)
+}
+
+func TestIngestStats_CSV(t *testing.T) {
+	var (
+		mockBatch       = graphmocks.NewMockBatch(gomock.NewController(t))
+		batch           = graphify.NewTimestampedBatch(mockBatch, time.Now().UTC())
+		registeredKinds graph.Kinds
+		nodeKinds       []graph.Kinds
+		readOptions     = graphify.ReadOptions{
+			FileType: model.FileTypeCSV,
+			RegisterSourceKind: func(k graph.Kind) error {
+				registeredKinds = append(registeredKinds, k)
+				return nil
+			},
+			CSVMapping: &ingest.CSVMapping{
+				SourceKind: "GithubBase",
+				Node: &ingest.CSVNodeMapping{
+					IDColumn:    "id",
+					Kinds:       []string{"GithubUser"},
+					KindsColumn: "roles",
+					Properties:  []ingest.CSVPropertyMapping{{Column: "repos", Type: ingest.CSVPropertyTypeInteger}},
+				},
+			},
+		}
+		reader = strings.NewReader("id,roles,repos\n1,GithubAdmin,3\n2,,\n3,,many\n")
+	)
+
+	mockBatch.EXPECT().UpdateNodeBy(gomock.Any()).DoAndReturn(func(update graph.NodeUpdate) error {
+		nodeKinds = append(nodeKinds, update.Node.Kinds)
+		return nil
+	}).Times(2)
+
+	err := graphify.ReadFileForIngest(batch, reader, readOptions)
+	assert.ErrorContains(t, err, `skipping csv row on line 4: column "repos" is not a valid integer: "many"`)
+	assert.Equal(t, ingest.DataTypeOpenGraph, batch.Stats.DataType)
+	assert.Equal(t, int64(2), batch.Stats.Nodes)
+	assert.Equal(t, graph.Kinds{graph.StringKind("GithubBase")}, registeredKinds)
+	assert.Equal(t, []graph.Kinds{
+		{graph.StringKind("GithubBase"), graph.StringKind("GithubUser"), graph.StringKind("GithubAdmin")},
+		{graph.StringKind("GithubBase"), graph.StringKind("GithubUser")},
+	}, nodeKinds)
+
+	t.Run("requires a mapping", func(t *testing.T) {
+		batch := graphify.NewTimestampedBatch(mockBatch, time.Now().UTC())
+		assert.ErrorIs(t, graphify.ReadFileForIngest(batch, strings.NewReader("id\n1\n"), graphify.ReadOptions{FileType: model.FileTypeCSV}), ingest.ErrCSVMappingMissing)
+	})
+}
@@ -0,0 +1,248 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
+	"github.com/specterops/bloodhound/cmd/api/src/services/upload"
+	"github.com/specterops/bloodhound/packages/go/bhlog/measure"
+	"github.com/specterops/dawgs/graph"
+	"github.com/specterops/dawgs/util"
+)
+
+// ingestWorkerStats tallies the work done by a single ingest worker. It is resolved when logged, so the measurement
+// logged when a worker finishes reports its final totals.
+type ingestWorkerStats struct {
+	Files         int
+	FailedFiles   int
+	Nodes         int64
+	Relationships int64
+}
+
+func (s *ingestWorkerStats) add(result model.IngestFileResult) {
+	s.Files++
+	s.Nodes += result.NodeCount
+	s.Relationships += result.RelationshipCount
+
+	if result.Failed() {
+		s.FailedFiles++
+	}
+}
+
+func (s *ingestWorkerStats) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("files", s.Files),
+		slog.Int("failed_files", s.FailedFiles),
+		slog.Int64("nodes", s.Nodes),
+		slog.Int64("relationships", s.Relationships),
+	)
+}
+
+// ingestFilesConcurrently ingests files with a pool of workers that read and transform files at the same time. Their
+// writes all go through a single batch, one at a time: files of every data type upsert the endpoints of their
+// relationships by object ID, and concurrent transactions upserting the same object ID would each create a node for
+// it. A file that fails part way keeps what it wrote, as the other files share its transaction, while a failure to
+// commit the batch fails every file written through it. OpenGraph files are held back until the batch has been
+// committed and are then ingested one at a time in their original order: their relationships may match endpoints by
+// name, which only resolves against nodes already committed to the graph.
+func (s *GraphifyService) ingestFilesConcurrently(ctx context.Context, fileType model.FileType, files []ingestFile, ingestTime time.Time) (model.IngestFileResults, error) {
+	var (
+		results   = make(model.IngestFileResults, len(files))
+		errs      = util.NewErrorCollector()
+		unordered = make([]int, 0, len(files))
+		ordered   = make([]int, 0)
+	)
+
+	for idx, file := range files {
+		if s.peekIngestDataType(file.Path) == ingest.DataTypeOpenGraph {
+			ordered = append(ordered, idx)
+		} else {
+			unordered = append(unordered, idx)
+		}
+	}
+
+	if len(unordered) > 0 {
+		if err := s.graphdb.BatchOperation(ctx, func(batch graph.Batch) error {
+			s.runIngestWorkers(ctx, &sharedBatch{Batch: batch, lock: &sync.Mutex{}}, fileType, files, unordered, ingestTime, results, errs)
+			return nil
+		}); err != nil {
+			errs.Add(err)
+
+			for _, idx := range unordered {
+				if !results[idx].Failed() {
+					results[idx].FileName = files[idx].Name
+					results[idx].Error = err.Error()
+				}
+			}
+		}
+	}
+
+	for _, idx := range ordered {
+		if err := s.ingestFileInOwnBatch(ctx, fileType, files[idx], ingestTime, &results[idx]); err != nil {
+			errs.Add(err)
+		}
+	}
+
+	return results, errs.Combined()
+}
+
+// runIngestWorkers ingests the files at the given indexes into the shared batch with up to IngestConcurrency workers,
+// writing each result to the same index of results
+func (s *GraphifyService) runIngestWorkers(ctx context.Context, batch *sharedBatch, fileType model.FileType, files []ingestFile, indexes []int, ingestTime time.Time, results model.IngestFileResults, errs util.ErrorCollector) {
+	var (
+		queue     = make(chan int)
+		waitGroup = sync.WaitGroup{}
+	)
+
+	for worker := 0; worker < min(s.cfg.IngestConcurrency, len(indexes)); worker++ {
+		waitGroup.Add(1)
+
+		go func(worker int) {
+			defer waitGroup.Done()
+
+			stats := &ingestWorkerStats{}
+			defer measure.ContextLogAndMeasure(ctx, slog.LevelInfo, "ingest worker", slog.Int("worker", worker), slog.Any("stats", stats))()
+
+			for idx := range queue {
+				var err error
+				if results[idx], err = s.ingestFile(ctx, batch, fileType, files[idx], ingestTime); err != nil {
+					errs.Add(err)
+				}
+
+				stats.add(results[idx])
+			}
+		}(worker)
+	}
+
+	for _, idx := range indexes {
+		queue <- idx
+	}
+
+	close(queue)
+	waitGroup.Wait()
+}
+
+// ingestFileInOwnBatch ingests a single file and commits it on its own. A failure to commit the batch is recorded on
+// the file's result, since the file itself may have been read without error.
+func (s *GraphifyService) ingestFileInOwnBatch(ctx context.Context, fileType model.FileType, file ingestFile, ingestTime time.Time, result *model.IngestFileResult) error {
+	err := s.graphdb.BatchOperation(ctx, func(batch graph.Batch) error {
+		var err error
+		*result, err = s.ingestFile(ctx, batch, fileType, file, ingestTime)
+		return err
+	})
+
+	if err != nil && !result.Failed() {
+		result.FileName = file.Name
+		result.Error = err.Error()
+	}
+
+	return err
+}
+
+// sharedBatch lets the ingest workers write to the same batch, applying their writes one at a time. Reads of the graph
+// through Nodes and Relationships are not guarded, which is why files that read the graph while ingesting, OpenGraph
+// files, are not ingested through a shared batch.
+type sharedBatch struct {
+	graph.Batch
+	lock *sync.Mutex
+}
+
+func (s *sharedBatch) WithGraph(graphSchema graph.Graph) graph.Batch {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return &sharedBatch{Batch: s.Batch.WithGraph(graphSchema), lock: s.lock}
+}
+
+func (s *sharedBatch) CreateNode(node *graph.Node) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.Batch.CreateNode(node)
+}
+
+func (s *sharedBatch) DeleteNode(id graph.ID) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.Batch.DeleteNode(id)
+}
+
+func (s *sharedBatch) UpdateNodeBy(update graph.NodeUpdate) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.Batch.UpdateNodeBy(update)
+}
+
+func (s *sharedBatch) CreateRelationship(relationship *graph.Relationship) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.Batch.CreateRelationship(relationship)
+}
+
+func (s *sharedBatch) CreateRelationshipByIDs(startNodeID, endNodeID graph.ID, kind graph.Kind, properties *graph.Properties) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.Batch.CreateRelationshipByIDs(startNodeID, endNodeID, kind, properties)
+}
+
+func (s *sharedBatch) DeleteRelationship(id graph.ID) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.Batch.DeleteRelationship(id)
+}
+
+func (s *sharedBatch) UpdateRelationshipBy(update graph.RelationshipUpdate) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.Batch.UpdateRelationshipBy(update)
+}
+
+func (s *sharedBatch) Commit() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.Batch.Commit()
+}
+
+// peekIngestDataType reads the metadata of an extracted file without ingesting it. A file whose metadata cannot be
+// read reports an empty data type; it fails with a descriptive error once a worker tries to ingest it.
+func (s *GraphifyService) peekIngestDataType(path string) ingest.DataType {
+	if file, err := os.Open(path); err != nil {
+		return ""
+	} else {
+		defer file.Close()
+
+		if meta, err := upload.ParseAndValidatePayload(file, s.schema, false, false); err != nil {
+			return ""
+		} else {
+			return meta.Type
+		}
+	}
+}
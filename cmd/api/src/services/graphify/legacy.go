@@ -0,0 +1,88 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
+	"github.com/specterops/bloodhound/packages/go/ein"
+)
+
+// legacyModel is a model of a legacy SharpHound collection that upgrades to the current model T
+type legacyModel[T any] interface {
+	Upgrade() T
+}
+
+// upgradeLegacy converts legacy models of type L by upgrading them and converting the result with conversionFunc
+func upgradeLegacy[L legacyModel[T], T any](conversionFunc ConversionFuncWithTime[T]) ConversionFuncWithTime[L] {
+	return func(decoded L, converted *ConvertedData, ingestTime time.Time) {
+		conversionFunc(decoded.Upgrade(), converted, ingestTime)
+	}
+}
+
+// getLegacyDecoder positions the decoder at the objects of a legacy collection
+func getLegacyDecoder(reader io.ReadSeeker, meta ingest.Metadata) (*json.Decoder, error) {
+	if meta.Version <= ingest.FormatVersionV3 {
+		return CreateIngestDecoder(reader, string(meta.Type), 1)
+	}
+
+	return getDefaultDecoder(reader)
+}
+
+func legacyBasicHandler[T any](conversionFunc ConversionFuncWithTime[T]) basicIngestHandler {
+	return func(batch *TimestampedBatch, reader io.ReadSeeker, meta ingest.Metadata) error {
+		if decoder, err := getLegacyDecoder(reader, meta); err != nil {
+			return err
+		} else {
+			return decodeBasicData(batch, decoder, conversionFunc)
+		}
+	}
+}
+
+// legacyHandlers ingest the data types that SharpHound collected before v5 of the collection format. Their objects are
+// decoded with the legacy models of ein and upgraded to the current models before they are converted, so that legacy
+// collections produce the same graph as current ones.
+var legacyHandlers = map[ingest.DataType]basicIngestHandler{
+	ingest.DataTypeComputer:  legacyBasicHandler(upgradeLegacy[ein.LegacyComputer](convertComputerData)),
+	ingest.DataTypeUser:      legacyBasicHandler(upgradeLegacy[ein.LegacyUser](convertUserData)),
+	ingest.DataTypeGPO:       legacyBasicHandler(upgradeLegacy[ein.LegacyGPO](convertGPOData)),
+	ingest.DataTypeOU:        legacyBasicHandler(upgradeLegacy[ein.LegacyOU](convertOUData)),
+	ingest.DataTypeContainer: legacyBasicHandler(upgradeLegacy[ein.LegacyContainer](convertContainerData)),
+	ingest.DataTypeGroup: func(batch *TimestampedBatch, reader io.ReadSeeker, meta ingest.Metadata) error {
+		if decoder, err := getLegacyDecoder(reader, meta); err != nil {
+			return err
+		} else {
+			return decodeGroupData(batch, decoder, func(group ein.LegacyGroup, converted *ConvertedGroupData, ingestTime time.Time) {
+				convertGroupData(group.Upgrade(), converted, ingestTime)
+			})
+		}
+	},
+	ingest.DataTypeDomain: func(batch *TimestampedBatch, reader io.ReadSeeker, meta ingest.Metadata) error {
+		if decoder, err := getLegacyDecoder(reader, meta); err != nil {
+			return err
+		} else {
+			return decodeBasicData(batch, decoder, func(legacyDomain ein.LegacyDomain, converted *ConvertedData, ingestTime time.Time) {
+				domain := legacyDomain.Upgrade()
+				batch.collectDomain(domain.ObjectIdentifier)
+				convertDomainData(domain, converted, ingestTime)
+			})
+		}
+	},
+}
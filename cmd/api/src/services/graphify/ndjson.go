@@ -0,0 +1,152 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/specterops/bloodhound/cmd/api/src/services/upload"
+	"github.com/specterops/bloodhound/packages/go/ein"
+	"github.com/specterops/dawgs/graph"
+	"github.com/specterops/dawgs/util"
+)
+
+// IngestNDJSONData ingests an NDJSON OpenGraph payload. Records may appear in any order after the metadata record, so
+// the payload is read once per record type to write them in the same order as the sections of a JSON payload: nodes,
+// then edges, then node and edge deletions. Each record type is ingested in chunks of IngestCountThreshold records.
+func IngestNDJSONData(batch *TimestampedBatch, reader io.ReadSeeker, registerSourceKind registrationFn) error {
+	sourceKind := graph.EmptyKind
+
+	// the metadata record, if present, is always the first record
+	if err := scanNDJSON(reader, func(record upload.NDJSONRecord) error {
+		if record.Type != upload.NDJSONMetadata {
+			return upload.ErrStopNDJSONScan
+		}
+
+		var meta ein.GenericMetadata
+		if err := json.Unmarshal(record.Value, &meta); err != nil {
+			return fmt.Errorf("failed to parse opengraph metadata record on line %d: %w", record.Line, err)
+		} else if sourceKind, err = ingestGenericMetadata(batch, meta, registerSourceKind); err != nil {
+			return err
+		}
+
+		return upload.ErrStopNDJSONScan
+	}); err != nil {
+		return err
+	}
+
+	if err := decodeNDJSONRecords(batch, reader, upload.NDJSONNode, genericDataIngester(batch, sourceKind, ConvertGenericNode)); err != nil {
+		return err
+	}
+
+	// edges that can not be resolved do not prevent the deletions below from being applied
+	errs := util.NewErrorCollector()
+
+	if err := decodeNDJSONRecords(batch, reader, upload.NDJSONEdge, genericDataIngester(batch, sourceKind, ConvertGenericEdge)); err != nil {
+		errs.Add(err)
+	}
+
+	if err := decodeNDJSONRecords(batch, reader, upload.NDJSONDeletedNode, func(entries []ein.EdgeEndpoint) error {
+		return DeleteGenericNodes(batch, entries)
+	}); err != nil {
+		errs.Add(err)
+	}
+
+	if err := decodeNDJSONRecords(batch, reader, upload.NDJSONDeletedEdge, func(entries []ein.GenericDeletedEdge) error {
+		return DeleteGenericEdges(batch, entries)
+	}); err != nil {
+		errs.Add(err)
+	}
+
+	return errs.Combined()
+}
+
+// genericDataIngester converts a chunk of decoded OpenGraph records and ingests them with the source kind given
+func genericDataIngester[T any](batch *TimestampedBatch, sourceKind graph.Kind, conversionFunc ConversionFunc[T]) func(entries []T) error {
+	return func(entries []T) error {
+		var (
+			convertedData ConvertedData
+			errs          = util.NewErrorCollector()
+		)
+
+		for _, entry := range entries {
+			if err := conversionFunc(entry, &convertedData); err != nil {
+				errs.Add(err)
+			}
+		}
+
+		if err := IngestGenericData(batch, sourceKind, convertedData); err != nil {
+			errs.Add(err)
+		}
+
+		return errs.Combined()
+	}
+}
+
+// decodeNDJSONRecords reads every record of the type given from an NDJSON payload and passes them to ingestFunc in
+// chunks of IngestCountThreshold records. A record that can not be decoded stops the read, while errors returned by
+// ingestFunc are collected.
+func decodeNDJSONRecords[T any](batch *TimestampedBatch, reader io.ReadSeeker, recordType string, ingestFunc func(entries []T) error) error {
+	var (
+		entries = make([]T, 0, IngestCountThreshold)
+		errs    = util.NewErrorCollector()
+	)
+
+	if err := scanNDJSON(reader, func(record upload.NDJSONRecord) error {
+		if record.Type != recordType {
+			return nil
+		}
+
+		// This variable needs to be initialized here, otherwise the marshaller will cache the map in the struct
+		var decodeTarget T
+		if err := json.Unmarshal(record.Value, &decodeTarget); err != nil {
+			return fmt.Errorf("error decoding %s record on line %d: %w", recordType, record.Line, err)
+		}
+
+		entries = append(entries, decodeTarget)
+
+		if len(entries) == IngestCountThreshold {
+			if err := batch.writeChunk(len(entries), func() error { return ingestFunc(entries) }); err != nil {
+				errs.Add(err)
+			}
+			entries = entries[:0]
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if len(entries) > 0 {
+		if err := batch.writeChunk(len(entries), func() error { return ingestFunc(entries) }); err != nil {
+			errs.Add(err)
+		}
+	}
+
+	return errs.Combined()
+}
+
+// scanNDJSON rewinds the payload and passes each of its records to delegate
+func scanNDJSON(reader io.ReadSeeker, delegate func(record upload.NDJSONRecord) error) error {
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewind failed: %w", err)
+	}
+
+	return upload.ScanNDJSON(reader, delegate)
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/packages/go/ein"
+	"github.com/specterops/bloodhound/packages/go/graphschema"
+	"github.com/specterops/dawgs/graph"
+)
+
+// indexablePropertyName guards the property names used for declared indexes, which become part of the index names, and
+// the property names endpoints are matched by, which are written into the translated graph query
+var indexablePropertyName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ConvertGenericSchema converts the schema section of an OpenGraph payload into the kinds it declares
+func ConvertGenericSchema(sourceKind string, schema ein.GenericSchema) model.OpenGraphKinds {
+	kinds := make(model.OpenGraphKinds, 0, len(schema.NodeKinds)+len(schema.EdgeKinds))
+
+	for _, nodeKind := range schema.NodeKinds {
+		kind := model.OpenGraphKind{
+			SourceKind:  sourceKind,
+			KindName:    nodeKind.Name,
+			DisplayName: nodeKind.DisplayName,
+			Properties:  convertGenericPropertyTypes(nodeKind.Properties),
+		}
+
+		if nodeKind.Icon != nil {
+			kind.Icon = &model.CustomNodeIcon{
+				Type:  nodeKind.Icon.Type,
+				Name:  nodeKind.Icon.Name,
+				Color: nodeKind.Icon.Color,
+			}
+
+			if kind.Icon.Color == "" {
+				kind.Icon.Color = "#FFFFFF"
+			}
+		}
+
+		kinds = append(kinds, kind)
+	}
+
+	for _, edgeKind := range schema.EdgeKinds {
+		kinds = append(kinds, model.OpenGraphKind{
+			SourceKind:  sourceKind,
+			KindName:    edgeKind.Name,
+			IsEdge:      true,
+			DisplayName: edgeKind.DisplayName,
+			Traversable: edgeKind.Traversable,
+			Properties:  convertGenericPropertyTypes(edgeKind.Properties),
+		})
+	}
+
+	return kinds
+}
+
+func convertGenericPropertyTypes(propertyTypes []ein.GenericPropertyType) model.OpenGraphProperties {
+	properties := make(model.OpenGraphProperties, 0, len(propertyTypes))
+
+	for _, propertyType := range propertyTypes {
+		properties = append(properties, model.OpenGraphProperty{
+			Name:        propertyType.Name,
+			DisplayName: propertyType.DisplayName,
+			Type:        propertyType.Type,
+			Indexed:     propertyType.Indexed,
+			Required:    propertyType.Required,
+		})
+	}
+
+	return properties
+}
+
+// collectOpenGraphKinds records the kinds declared by the file so they can be stored once the file is ingested
+func (s *TimestampedBatch) collectOpenGraphKinds(kinds model.OpenGraphKinds) {
+	s.Stats.OpenGraphKinds = append(s.Stats.OpenGraphKinds, kinds...)
+}
+
+// storeOpenGraphKinds persists the kinds declared by an ingested file. Failing to store them does not fail the file
+// since its graph data has already been written.
+func (s *GraphifyService) storeOpenGraphKinds(ctx context.Context, fileName string, kinds model.OpenGraphKinds) {
+	if len(kinds) == 0 {
+		return
+	} else if err := s.db.UpsertOpenGraphKinds(ctx, kinds); err != nil {
+		slog.ErrorContext(ctx, fmt.Sprintf("Error storing OpenGraph kinds declared by file %s: %v", fileName, err))
+	}
+}
+
+// DefaultGraphSchemaWithOpenGraphKinds returns the default graph schema extended with an index for every property
+// declared as indexed by an OpenGraph payload. Properties that are already indexed by the default schema are left as
+// they are.
+func DefaultGraphSchemaWithOpenGraphKinds(kinds model.OpenGraphKinds) graph.Schema {
+	schema := graphschema.DefaultGraphSchema()
+
+	schema.DefaultGraph.NodeIndexes = appendPropertyIndexes(schema.DefaultGraph.NodeIndexes, schema.DefaultGraph.NodeConstraints, kinds.IndexedProperties(false))
+	schema.DefaultGraph.EdgeIndexes = appendPropertyIndexes(schema.DefaultGraph.EdgeIndexes, schema.DefaultGraph.EdgeConstraints, kinds.IndexedProperties(true))
+	schema.Graphs = []graph.Graph{schema.DefaultGraph}
+
+	return schema
+}
+
+func appendPropertyIndexes(indexes []graph.Index, constraints []graph.Constraint, properties []string) []graph.Index {
+	for _, property := range properties {
+		isIndexed := func(index graph.Index) bool { return index.Field == property }
+		isConstrained := func(constraint graph.Constraint) bool { return constraint.Field == property }
+
+		if !indexablePropertyName.MatchString(property) || slices.ContainsFunc(indexes, isIndexed) || slices.ContainsFunc(constraints, isConstrained) {
+			continue
+		}
+
+		indexes = append(indexes, graph.Index{
+			Field: property,
+			Type:  graph.BTreeIndex,
+		})
+	}
+
+	return indexes
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify_test
+
+import (
+	"testing"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/services/graphify"
+	"github.com/specterops/bloodhound/packages/go/graphschema"
+	"github.com/specterops/bloodhound/packages/go/graphschema/common"
+	"github.com/specterops/dawgs/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultGraphSchemaWithOpenGraphKinds(t *testing.T) {
+	var (
+		defaultSchema = graphschema.DefaultGraphSchema()
+		schema        = graphify.DefaultGraphSchemaWithOpenGraphKinds(model.OpenGraphKinds{
+			{
+				KindName: "GithubUser",
+				Properties: model.OpenGraphProperties{
+					{Name: "login", Type: model.OpenGraphPropertyTypeString, Indexed: true},
+					{Name: "created_at", Type: model.OpenGraphPropertyTypeTimestamp},
+					{Name: common.Name.String(), Type: model.OpenGraphPropertyTypeString, Indexed: true},
+					{Name: common.ObjectID.String(), Type: model.OpenGraphPropertyTypeString, Indexed: true},
+					{Name: "login; DROP TABLE node", Type: model.OpenGraphPropertyTypeString, Indexed: true},
+				},
+			},
+			{
+				KindName:   "GithubRepository",
+				Properties: model.OpenGraphProperties{{Name: "login", Type: model.OpenGraphPropertyTypeString, Indexed: true}},
+			},
+			{
+				KindName:   "GithubMemberOf",
+				IsEdge:     true,
+				Properties: model.OpenGraphProperties{{Name: "role", Type: model.OpenGraphPropertyTypeString, Indexed: true}},
+			},
+		})
+	)
+
+	require.Len(t, schema.Graphs, 1)
+	assert.Equal(t, schema.DefaultGraph.Name, schema.Graphs[0].Name)
+
+	// Only the new, valid property names are indexed and each is indexed once
+	assert.Equal(t, append(defaultSchema.DefaultGraph.NodeIndexes, graph.Index{Field: "login", Type: graph.BTreeIndex}), schema.DefaultGraph.NodeIndexes)
+	assert.Equal(t, append(defaultSchema.DefaultGraph.EdgeIndexes, graph.Index{Field: "role", Type: graph.BTreeIndex}), schema.DefaultGraph.EdgeIndexes)
+	assert.Equal(t, defaultSchema.DefaultGraph.NodeConstraints, schema.DefaultGraph.NodeConstraints)
+}
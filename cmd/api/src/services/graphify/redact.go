@@ -0,0 +1,148 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model/appcfg"
+)
+
+const (
+	// RedactedMask replaces masked property values
+	RedactedMask = "********"
+
+	// RedactedHashPrefix is prepended to the hex encoded digest of hashed property values
+	RedactedHashPrefix = "sha256:"
+)
+
+type propertyRedaction struct {
+	appcfg.IngestRedactionRule
+
+	properties      []string
+	propertyPattern *regexp.Regexp
+	valuePattern    *regexp.Regexp
+}
+
+// PropertyRedactor applies the configured ingest redaction rules to the properties of nodes and relationships before
+// they are written to the graph. A nil PropertyRedactor leaves properties unchanged.
+type PropertyRedactor struct {
+	redactions []propertyRedaction
+}
+
+// NewPropertyRedactor validates and compiles the rules given. Rules are applied in the order given.
+func NewPropertyRedactor(rules []appcfg.IngestRedactionRule) (*PropertyRedactor, error) {
+	redactor := &PropertyRedactor{}
+
+	for idx, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid ingest redaction rule %d: %w", idx, err)
+		}
+
+		redaction := propertyRedaction{IngestRedactionRule: rule}
+
+		for _, property := range rule.Properties {
+			redaction.properties = append(redaction.properties, strings.ToLower(property))
+		}
+
+		// The patterns were compiled once already by Validate, so these can not fail
+		if rule.PropertyPattern != "" {
+			redaction.propertyPattern = regexp.MustCompile("(?i)" + rule.PropertyPattern)
+		}
+
+		if rule.ValuePattern != "" {
+			redaction.valuePattern = regexp.MustCompile(rule.ValuePattern)
+		}
+
+		redactor.redactions = append(redactor.redactions, redaction)
+	}
+
+	return redactor, nil
+}
+
+// loadPropertyRedactor builds a PropertyRedactor from the rules stored in the app config
+func (s *GraphifyService) loadPropertyRedactor(ctx context.Context) (*PropertyRedactor, error) {
+	if rules, err := appcfg.GetIngestRedactionRules(ctx, s.db); err != nil {
+		return nil, err
+	} else {
+		return NewPropertyRedactor(rules)
+	}
+}
+
+// Redact applies every rule to properties, which is modified in place. The names of the properties redacted are
+// returned.
+func (s *PropertyRedactor) Redact(properties map[string]any) []string {
+	var redacted []string
+
+	if s == nil {
+		return nil
+	}
+
+	for _, redaction := range s.redactions {
+		for name, value := range properties {
+			if redaction.apply(properties, name, value) && !slices.Contains(redacted, name) {
+				redacted = append(redacted, name)
+			}
+		}
+	}
+
+	return redacted
+}
+
+func (s propertyRedaction) matchesProperty(name string) bool {
+	return slices.Contains(s.properties, strings.ToLower(name)) || (s.propertyPattern != nil && s.propertyPattern.MatchString(name))
+}
+
+// apply redacts the property given if it matches the rule and returns true if it was redacted
+func (s propertyRedaction) apply(properties map[string]any, name string, value any) bool {
+	if value == nil || !s.matchesProperty(name) {
+		return false
+	}
+
+	if s.valuePattern != nil {
+		if str, isString := value.(string); !isString || !s.valuePattern.MatchString(str) {
+			return false
+		}
+	}
+
+	switch s.Action {
+	case appcfg.IngestRedactionHash:
+		digest := sha256.Sum256([]byte(fmt.Sprint(value)))
+		properties[name] = RedactedHashPrefix + hex.EncodeToString(digest[:])
+
+	case appcfg.IngestRedactionMask:
+		if s.valuePattern != nil {
+			properties[name] = s.valuePattern.ReplaceAllLiteralString(value.(string), RedactedMask)
+		} else {
+			properties[name] = RedactedMask
+		}
+
+	case appcfg.IngestRedactionDrop:
+		delete(properties, name)
+
+	default:
+		return false
+	}
+
+	return true
+}
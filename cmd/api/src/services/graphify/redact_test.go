@@ -0,0 +1,107 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model/appcfg"
+	"github.com/specterops/bloodhound/cmd/api/src/services/graphify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropertyRedactor_Redact(t *testing.T) {
+	digest := sha256.Sum256([]byte("hunter2"))
+
+	testCases := []struct {
+		name       string
+		rules      []appcfg.IngestRedactionRule
+		properties map[string]any
+		expected   map[string]any
+		redacted   []string
+	}{
+		{
+			name:       "denylisted properties are dropped regardless of case",
+			rules:      []appcfg.IngestRedactionRule{{Properties: []string{"userPassword", "unixpassword"}, Action: appcfg.IngestRedactionDrop}},
+			properties: map[string]any{"userpassword": "hunter2", "name": "A"},
+			expected:   map[string]any{"name": "A"},
+			redacted:   []string{"userpassword"},
+		},
+		{
+			name:       "property pattern hashes values",
+			rules:      []appcfg.IngestRedactionRule{{PropertyPattern: "password$", Action: appcfg.IngestRedactionHash}},
+			properties: map[string]any{"unixpassword": "hunter2", "passwordlastset": int64(1)},
+			expected:   map[string]any{"unixpassword": graphify.RedactedHashPrefix + hex.EncodeToString(digest[:]), "passwordlastset": int64(1)},
+			redacted:   []string{"unixpassword"},
+		},
+		{
+			name:       "mask replaces the whole value",
+			rules:      []appcfg.IngestRedactionRule{{Properties: []string{"description"}, Action: appcfg.IngestRedactionMask}},
+			properties: map[string]any{"description": "anything"},
+			expected:   map[string]any{"description": graphify.RedactedMask},
+			redacted:   []string{"description"},
+		},
+		{
+			name:       "mask with a value pattern replaces only the matches",
+			rules:      []appcfg.IngestRedactionRule{{Properties: []string{"description"}, ValuePattern: `(?i)pw:\s*\S+`, Action: appcfg.IngestRedactionMask}},
+			properties: map[string]any{"description": "svc account pw: hunter2 do not change"},
+			expected:   map[string]any{"description": "svc account " + graphify.RedactedMask + " do not change"},
+			redacted:   []string{"description"},
+		},
+		{
+			name:       "values that do not match the value pattern are left alone",
+			rules:      []appcfg.IngestRedactionRule{{Properties: []string{"description"}, ValuePattern: `(?i)pw:`, Action: appcfg.IngestRedactionDrop}},
+			properties: map[string]any{"description": "file server"},
+			expected:   map[string]any{"description": "file server"},
+		},
+		{
+			name:       "nil values are left alone",
+			rules:      []appcfg.IngestRedactionRule{{Properties: []string{"description"}, Action: appcfg.IngestRedactionMask}},
+			properties: map[string]any{"description": nil},
+			expected:   map[string]any{"description": nil},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			redactor, err := graphify.NewPropertyRedactor(testCase.rules)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.redacted, redactor.Redact(testCase.properties))
+			assert.Equal(t, testCase.expected, testCase.properties)
+		})
+	}
+
+	t.Run("nil redactor leaves properties unchanged", func(t *testing.T) {
+		var redactor *graphify.PropertyRedactor
+
+		properties := map[string]any{"userpassword": "hunter2"}
+		assert.Empty(t, redactor.Redact(properties))
+		assert.Equal(t, map[string]any{"userpassword": "hunter2"}, properties)
+	})
+}
+
+func TestNewPropertyRedactor_InvalidRule(t *testing.T) {
+	_, err := graphify.NewPropertyRedactor([]appcfg.IngestRedactionRule{
+		{Properties: []string{"userpassword"}, Action: appcfg.IngestRedactionDrop},
+		{PropertyPattern: "password(", Action: appcfg.IngestRedactionDrop},
+	})
+
+	assert.ErrorContains(t, err, "invalid ingest redaction rule 1")
+}
@@ -33,6 +33,11 @@ type GraphifyData interface {
 	GetFlagByKey(context.Context, string) (appcfg.FeatureFlag, error)
 
 	RegisterSourceKind(context.Context) func(sourceKind graph.Kind) error
+	UpsertOpenGraphKinds(ctx context.Context, kinds model.OpenGraphKinds) error
+	GetOpenGraphKinds(ctx context.Context) (model.OpenGraphKinds, error)
+
+	// Ingest transform rules are read from the app config
+	appcfg.ParameterService
 }
 
 type GraphifyService struct {
@@ -0,0 +1,162 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/packages/go/analysis/hybrid"
+	"github.com/specterops/bloodhound/packages/go/bhlog/measure"
+	adSchema "github.com/specterops/bloodhound/packages/go/graphschema/ad"
+	"github.com/specterops/bloodhound/packages/go/graphschema/common"
+	"github.com/specterops/dawgs/graph"
+	"github.com/specterops/dawgs/query"
+)
+
+// StaleDataCounts are the number of nodes and relationships that were flagged or deleted by SweepStaleData
+type StaleDataCounts struct {
+	Nodes         int64
+	Relationships int64
+}
+
+// collectedScope matches the nodes referenced that fall within the scope collected by the job: AD objects of the
+// collected domains and OpenGraph nodes of the collected source kinds. The scope of a job tagged with an environment
+// only covers the nodes of that environment.
+func collectedScope(reference graph.Criteria, domainSID graph.Criteria, environment graph.Criteria, job model.IngestJob) graph.Criteria {
+	var scope []graph.Criteria
+
+	if len(job.CollectedDomains) > 0 {
+		scope = append(scope, query.And(
+			query.Kind(reference, adSchema.Entity),
+			query.In(domainSID, []string(job.CollectedDomains)),
+		))
+	}
+
+	if len(job.CollectedSourceKinds) > 0 {
+		scope = append(scope, query.KindIn(reference, graph.StringsToKinds(job.CollectedSourceKinds)...))
+	}
+
+	if job.Environment != "" {
+		return query.And(query.Or(scope...), query.Equals(environment, job.Environment))
+	}
+
+	return query.Or(scope...)
+}
+
+func nodesInScope(job model.IngestJob) graph.Criteria {
+	return collectedScope(query.Node(), query.NodeProperty(adSchema.DomainSID.String()), query.NodeProperty(common.Environment.String()), job)
+}
+
+// relationshipsInScope matches ingested relationships between two nodes in scope. A relationship that crosses into
+// data that was not collected by the job may have been collected from the other side, so it is left alone.
+// Relationships created by post-processing are rebuilt by analysis and are never in scope.
+func relationshipsInScope(job model.IngestJob) graph.Criteria {
+	return query.And(
+		collectedScope(query.Start(), query.StartProperty(adSchema.DomainSID.String()), query.StartProperty(common.Environment.String()), job),
+		collectedScope(query.End(), query.EndProperty(adSchema.DomainSID.String()), query.EndProperty(common.Environment.String()), job),
+		query.Not(query.KindIn(query.Relationship(), hybrid.PostProcessedRelationships()...)),
+	)
+}
+
+// SweepStaleData acts on the graph data within the scope collected by an authoritative ingest job that was not seen
+// by the job. Anything in scope that was last seen before the job started is either flagged with the stale property
+// or deleted, depending on the authoritative mode of the job. When flagging, objects flagged by an earlier job that
+// have since been seen again have the flag removed.
+func (s *GraphifyService) SweepStaleData(ctx context.Context, job model.IngestJob) (StaleDataCounts, error) {
+	var counts StaleDataCounts
+
+	if !job.AuthoritativeMode.Enabled() || (len(job.CollectedDomains) == 0 && len(job.CollectedSourceKinds) == 0) {
+		return counts, nil
+	}
+
+	defer measure.ContextLogAndMeasure(ctx, slog.LevelInfo, "Sweep stale graph data", slog.Int64("job_id", job.ID))()
+
+	var (
+		staleNodes = func() graph.Criteria {
+			return query.And(
+				nodesInScope(job),
+				query.LessThan(query.NodeProperty(common.LastSeen.String()), job.StartTime),
+			)
+		}
+		staleRelationships = func() graph.Criteria {
+			return query.And(
+				relationshipsInScope(job),
+				query.LessThan(query.RelationshipProperty(common.LastSeen.String()), job.StartTime),
+			)
+		}
+	)
+
+	return counts, s.graphdb.WriteTransaction(ctx, func(tx graph.Transaction) error {
+		var err error
+
+		if counts.Relationships, err = tx.Relationships().Filterf(staleRelationships).Count(); err != nil {
+			return fmt.Errorf("error counting stale relationships: %w", err)
+		} else if counts.Nodes, err = tx.Nodes().Filterf(staleNodes).Count(); err != nil {
+			return fmt.Errorf("error counting stale nodes: %w", err)
+		}
+
+		switch job.AuthoritativeMode {
+		case model.AuthoritativeModeDelete:
+			if err := tx.Relationships().Filterf(staleRelationships).Delete(); err != nil {
+				return fmt.Errorf("error deleting stale relationships: %w", err)
+			} else if err := tx.Nodes().Filterf(staleNodes).Delete(); err != nil {
+				return fmt.Errorf("error deleting stale nodes: %w", err)
+			}
+
+		case model.AuthoritativeModeFlag:
+			return flagStaleData(tx, job, staleNodes, staleRelationships)
+		}
+
+		return nil
+	})
+}
+
+func flagStaleData(tx graph.Transaction, job model.IngestJob, staleNodes, staleRelationships graph.CriteriaProvider) error {
+	var (
+		flagged   = graph.NewProperties().Set(StaleProperty, true)
+		unflagged = graph.NewProperties().Delete(StaleProperty)
+		seenNodes = func() graph.Criteria {
+			return query.And(
+				nodesInScope(job),
+				query.Equals(query.NodeProperty(StaleProperty), true),
+				query.GreaterThanOrEquals(query.NodeProperty(common.LastSeen.String()), job.StartTime),
+			)
+		}
+		seenRelationships = func() graph.Criteria {
+			return query.And(
+				relationshipsInScope(job),
+				query.Equals(query.RelationshipProperty(StaleProperty), true),
+				query.GreaterThanOrEquals(query.RelationshipProperty(common.LastSeen.String()), job.StartTime),
+			)
+		}
+	)
+
+	if err := tx.Relationships().Filterf(staleRelationships).Update(flagged); err != nil {
+		return fmt.Errorf("error flagging stale relationships: %w", err)
+	} else if err := tx.Nodes().Filterf(staleNodes).Update(flagged); err != nil {
+		return fmt.Errorf("error flagging stale nodes: %w", err)
+	} else if err := tx.Relationships().Filterf(seenRelationships).Update(unflagged); err != nil {
+		return fmt.Errorf("error clearing stale flag from relationships: %w", err)
+	} else if err := tx.Nodes().Filterf(seenNodes).Update(unflagged); err != nil {
+		return fmt.Errorf("error clearing stale flag from nodes: %w", err)
+	}
+
+	return nil
+}
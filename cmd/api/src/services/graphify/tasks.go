@@ -17,7 +17,10 @@
 package graphify
 
 import (
+	"archive/tar"
 	"archive/zip"
+	"bufio"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
@@ -25,20 +28,46 @@ import (
 	"io/fs"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
 	"github.com/specterops/bloodhound/packages/go/bhlog/measure"
 	"github.com/specterops/bloodhound/packages/go/bomenc"
 	"github.com/specterops/dawgs/graph"
 	"github.com/specterops/dawgs/util"
 )
 
+const (
+	tarMagic       = "ustar"
+	tarMagicOffset = 257
+)
+
 // UpdateJobFunc is passed to the graphify service to let it tell us about the tasks as they are processed
 //
 // The datapipe doesn't know or care about tasks, and the graphify service doesn't know or care about jobs.
 // Instead, this func is provided as an abstraction for graphify.
-type UpdateJobFunc func(jobId int64, totalFiles int, totalFailed int)
+type UpdateJobFunc func(jobId int64, fileResults model.IngestFileResults)
+
+// TaskContextFunc is passed to the graphify service to provide the context each task is processed with, derived from
+// the context given. This lets the datapipe stop the tasks of jobs that were canceled without graphify knowing about
+// jobs. A task whose context is already done when it is picked up is discarded without being processed.
+type TaskContextFunc func(ctx context.Context, task model.IngestTask) (context.Context, context.CancelFunc)
+
+// ingestFile is a file ready to be read for ingest. Name is the name the file had inside its archive, or the name
+// the file was uploaded with when it was not an archive, and is what gets reported in the job's file results.
+type ingestFile struct {
+	Name string
+	Path string
+
+	// CSVMapping is the column mapping of a CSV file, taken from its ingest task
+	CSVMapping *ingest.CSVMapping
+
+	// Environment is the environment label the data of the file is tagged with, taken from its ingest task
+	Environment string
+}
 
 // clearFileTask removes a generic ingest task for ingested data.
 func (s *GraphifyService) clearFileTask(ingestTask model.IngestTask) {
@@ -47,28 +76,62 @@ func (s *GraphifyService) clearFileTask(ingestTask model.IngestTask) {
 	}
 }
 
-// extractIngestFiles will take a path and extract zips if necessary, returning the paths for files to process
-// along with any errors and the number of failed files (in the case of a zip archive)
-func (s *GraphifyService) extractIngestFiles(path string, fileType model.FileType) ([]string, int, error) {
-	if fileType == model.FileTypeJson {
-		//If this isn't a zip file, just return a slice with the path in it and let stuff process as normal
-		return []string{path}, 0, nil
-	} else if archive, err := zip.OpenReader(path); err != nil {
-		return []string{}, 0, err
+// uploadName is the name a file was uploaded with, falling back to the name of the file at path when it is not known
+func uploadName(path, originalName string) string {
+	if originalName != "" {
+		return originalName
+	}
+
+	return filepath.Base(path)
+}
+
+// extractIngestFiles will take a path and extract archives if necessary, returning the files to process along with a
+// failed result for each file in the archive that could not be extracted. An error is only returned when the archive
+// itself could not be read. Files that are not archives, and failures that concern the whole archive, are reported
+// under originalName, the name the file was uploaded with.
+func (s *GraphifyService) extractIngestFiles(path, originalName string, fileType model.FileType) ([]ingestFile, model.IngestFileResults, error) {
+	switch fileType {
+	case model.FileTypeJson, model.FileTypeNDJSON, model.FileTypeCSV:
+		//If this isn't an archive, just return a slice with the path in it and let stuff process as normal
+		return []ingestFile{{Name: uploadName(path, originalName), Path: path}}, nil, nil
+	case model.FileTypeGzip, model.FileTypeZstd:
+		return s.extractCompressedFile(path, originalName, fileType)
+	case model.FileTypeTarGzip:
+		return s.extractTarGzipArchive(path, originalName)
+	default:
+		return s.extractZipArchive(path)
+	}
+}
+
+// removeArchive deletes an archive once its contents have been extracted
+func (s *GraphifyService) removeArchive(path string) {
+	if err := os.Remove(path); err != nil {
+		slog.ErrorContext(s.ctx, fmt.Sprintf("Error deleting archive %s: %v", path, err))
+	}
+}
+
+// failedFileResult builds the result reported for a file that could not be extracted from its archive
+func failedFileResult(name string, err error) model.IngestFileResult {
+	return model.IngestFileResult{
+		FileName: name,
+		Error:    err.Error(),
+	}
+}
+
+func (s *GraphifyService) extractZipArchive(path string) ([]ingestFile, model.IngestFileResults, error) {
+	if archive, err := zip.OpenReader(path); err != nil {
+		return nil, nil, err
 	} else {
 		var (
-			errs      = util.NewErrorCollector()
-			failed    = 0
-			filePaths = make([]string, 0, len(archive.File))
+			failed = model.IngestFileResults{}
+			files  = make([]ingestFile, 0, len(archive.File))
 		)
 
 		defer func() {
 			if err := archive.Close(); err != nil {
 				slog.ErrorContext(s.ctx, fmt.Sprintf("Error closing archive %s: %v", path, err))
 			}
-			if err := os.Remove(path); err != nil {
-				slog.ErrorContext(s.ctx, fmt.Sprintf("Error deleting archive %s: %v", path, err))
-			}
+			s.removeArchive(path)
 		}()
 
 		for _, f := range archive.File {
@@ -77,20 +140,131 @@ func (s *GraphifyService) extractIngestFiles(path string, fileType model.FileTyp
 				continue
 			}
 
-			fileName, err := s.extractToTempFile(f)
+			fileName, err := s.extractZipEntryToTempFile(f)
 			if err != nil {
-				failed++
-				errs.Add(err)
+				failed = append(failed, failedFileResult(f.Name, err))
 			} else {
-				filePaths = append(filePaths, fileName)
+				files = append(files, ingestFile{Name: f.Name, Path: fileName})
 			}
 		}
 
-		return filePaths, failed, errs.Combined()
+		return files, failed, nil
+	}
+}
+
+// extractTarGzipArchive extracts every regular file in a gzip compressed tarball
+func (s *GraphifyService) extractTarGzipArchive(path, originalName string) ([]ingestFile, model.IngestFileResults, error) {
+	archiveFile, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		archiveFile.Close()
+		s.removeArchive(path)
+	}()
+
+	if gzipReader, err := gzip.NewReader(archiveFile); err != nil {
+		return nil, nil, err
+	} else {
+		defer gzipReader.Close()
+		return s.extractTarEntries(uploadName(path, originalName), tar.NewReader(gzipReader))
+	}
+}
+
+// extractTarEntries extracts every regular file in a tarball. Unlike zip, a tarball can only be read front to back,
+// so a corrupt entry header ends extraction and is reported as a single failed result under the archive's name.
+func (s *GraphifyService) extractTarEntries(archiveName string, tarReader *tar.Reader) ([]ingestFile, model.IngestFileResults, error) {
+	var (
+		failed = model.IngestFileResults{}
+		files  = make([]ingestFile, 0)
+	)
+
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			failed = append(failed, failedFileResult(archiveName, fmt.Errorf("error reading tar entry: %w", err)))
+			break
+		}
+
+		//skip directories, links and other special entries
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if fileName, err := s.extractToTempFile(tarReader); err != nil {
+			failed = append(failed, failedFileResult(header.Name, err))
+		} else {
+			files = append(files, ingestFile{Name: header.Name, Path: fileName})
+		}
+	}
+
+	return files, failed, nil
+}
+
+// isTarStream peeks at the ustar magic of the first tar header, without consuming any of the stream
+func isTarStream(reader *bufio.Reader) bool {
+	if header, err := reader.Peek(tarMagicOffset + len(tarMagic)); err != nil {
+		return false
+	} else {
+		return string(header[tarMagicOffset:]) == tarMagic
+	}
+}
+
+// extractCompressedFile decompresses a single gzip or zstd compressed file. Browsers commonly report tarballs as
+// plain gzip, so a compressed stream that turns out to hold a tarball is extracted entry by entry instead.
+func (s *GraphifyService) extractCompressedFile(path, originalName string, fileType model.FileType) ([]ingestFile, model.IngestFileResults, error) {
+	compressedFile, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		compressedFile.Close()
+		s.removeArchive(path)
+	}()
+
+	var (
+		archiveName  = uploadName(path, originalName)
+		decompressed *bufio.Reader
+	)
+
+	if fileType == model.FileTypeZstd {
+		if zstdReader, err := zstd.NewReader(compressedFile); err != nil {
+			return nil, nil, err
+		} else {
+			defer zstdReader.Close()
+			decompressed = bufio.NewReader(zstdReader)
+		}
+	} else if gzipReader, err := gzip.NewReader(compressedFile); err != nil {
+		return nil, nil, err
+	} else {
+		defer gzipReader.Close()
+		decompressed = bufio.NewReader(gzipReader)
+	}
+
+	if isTarStream(decompressed) {
+		return s.extractTarEntries(archiveName, tar.NewReader(decompressed))
+	} else if fileName, err := s.extractToTempFile(decompressed); err != nil {
+		return nil, model.IngestFileResults{failedFileResult(archiveName, err)}, nil
+	} else {
+		return []ingestFile{{Name: archiveName, Path: fileName}}, nil, nil
 	}
 }
 
-func (s *GraphifyService) extractToTempFile(f *zip.File) (string, error) {
+func (s *GraphifyService) extractZipEntryToTempFile(f *zip.File) (string, error) {
+	srcFile, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer srcFile.Close()
+
+	return s.extractToTempFile(srcFile)
+}
+
+func (s *GraphifyService) extractToTempFile(srcFile io.Reader) (string, error) {
 	// Given a single artifact in an archive, extract it out to a temporary file
 	tempFile, err := os.CreateTemp(s.cfg.TempDirectory(), "bh")
 	if err != nil {
@@ -107,12 +281,6 @@ func (s *GraphifyService) extractToTempFile(f *zip.File) (string, error) {
 		}
 	}()
 
-	srcFile, err := f.Open()
-	if err != nil {
-		return "", err
-	}
-	defer srcFile.Close()
-
 	// this creates a normalized file to feed to the copy
 	if normFile, err := bomenc.NormalizeToUTF8(srcFile); err != nil {
 		return "", err
@@ -126,36 +294,108 @@ func (s *GraphifyService) extractToTempFile(f *zip.File) (string, error) {
 	}
 }
 
-// ProcessIngestFile reads the files at the path supplied, and returns the total number of files in the
-// archive, the number of files that failed to ingest as JSON, and an error
-func (s *GraphifyService) ProcessIngestFile(ctx context.Context, task model.IngestTask, ingestTime time.Time) (int, int, error) {
-	// Try to pre-process the file. If any of them fail, stop processing and return the error
-	if paths, failedExtracting, err := s.extractIngestFiles(task.FileName, task.FileType); err != nil {
-		return 0, failedExtracting, err
+// ProcessIngestFile reads the files at the path supplied and returns a result for every file found, whether it was
+// ingested or not. Files that failed to extract or ingest are marked as failed in their result, and their errors are
+// combined into the returned error.
+func (s *GraphifyService) ProcessIngestFile(ctx context.Context, task model.IngestTask, ingestTime time.Time) (model.IngestFileResults, error) {
+	files, results, err := s.extractIngestFiles(task.FileName, task.OriginalFileName, task.FileType)
+	if err != nil {
+		// The archive could not be read at all, so report it as a single failed file
+		return model.IngestFileResults{failedFileResult(uploadName(task.FileName, task.OriginalFileName), err)}, err
+	}
+
+	for idx := range files {
+		files[idx].CSVMapping = task.CSVMapping
+		files[idx].Environment = task.Environment
+	}
+
+	errs := util.NewErrorCollector()
+	for _, result := range results {
+		errs.Add(fmt.Errorf("error extracting %s: %s", result.FileName, result.Error))
+	}
+
+	var fileResults model.IngestFileResults
+	if s.cfg.IngestConcurrency > 1 && len(files) > 1 {
+		fileResults, err = s.ingestFilesConcurrently(ctx, task.FileType, files, ingestTime)
 	} else {
+		fileResults, err = s.ingestFiles(ctx, task.FileType, files, ingestTime)
+	}
+
+	if err != nil {
+		errs.Add(err)
+	}
+
+	return append(results, fileResults...), errs.Combined()
+}
 
-		failedIngestion := 0
+// ingestFiles ingests files one at a time, in order, through a single batch
+func (s *GraphifyService) ingestFiles(ctx context.Context, fileType model.FileType, files []ingestFile, ingestTime time.Time) (model.IngestFileResults, error) {
+	results := make(model.IngestFileResults, 0, len(files))
 
+	return results, s.graphdb.BatchOperation(ctx, func(batch graph.Batch) error {
 		errs := util.NewErrorCollector()
-		return len(paths), failedIngestion, s.graphdb.BatchOperation(ctx, func(batch graph.Batch) error {
-			timestampedBatch := NewTimestampedBatch(batch, ingestTime)
-
-			for _, filePath := range paths {
-				readOpts := ReadOptions{
-					IngestSchema:       s.schema,
-					FileType:           task.FileType,
-					RegisterSourceKind: s.db.RegisterSourceKind(s.ctx)}
-
-				if err := processSingleFile(ctx, filePath, timestampedBatch, readOpts); err != nil {
-					failedIngestion++
-					errs.Add(err) // util.NewErrorCollector at fn scope
-					continue      // keep ingesting the rest
-				}
+
+		for _, file := range files {
+			result, err := s.ingestFile(ctx, batch, fileType, file, ingestTime)
+			if err != nil {
+				errs.Add(err) // keep ingesting the rest
 			}
 
-			return errs.Combined()
-		})
+			results = append(results, result)
+		}
+
+		return errs.Combined()
+	})
+}
+
+// ingestFile reads a single extracted file into the batch given and reports what was written for it
+func (s *GraphifyService) ingestFile(ctx context.Context, batch graph.Batch, fileType model.FileType, file ingestFile, ingestTime time.Time) (model.IngestFileResult, error) {
+	var (
+		timestampedBatch = NewTimestampedBatch(batch, ingestTime)
+		readOpts         = ReadOptions{
+			IngestSchema:       s.schema,
+			FileType:           fileType,
+			RegisterSourceKind: s.db.RegisterSourceKind(s.ctx),
+			CSVMapping:         file.CSVMapping,
+		}
+		result = model.IngestFileResult{FileName: file.Name}
+	)
+
+	timestampedBatch.Environment = file.Environment
+	timestampedBatch.RecordMetrics = true
+	timestampedBatch.PropertyTransformer = s.loadPropertyTransformer(ctx)
+	timestampedBatch.EdgePropertySchema = s.loadEdgePropertySchema(ctx)
+
+	// Nothing is ingested if the redaction rules can not be loaded, otherwise properties that should never reach the
+	// graph could be written
+	var err error
+	if timestampedBatch.PropertyRedactor, err = s.loadPropertyRedactor(ctx); err != nil {
+		slog.ErrorContext(ctx, fmt.Sprintf("Error loading ingest redaction rules for file %s: %v", file.Name, err))
+		removeIngestFile(ctx, file.Path)
+	} else {
+		err = processSingleFile(ctx, file.Path, timestampedBatch, readOpts)
+		s.storeOpenGraphKinds(ctx, file.Name, timestampedBatch.Stats.OpenGraphKinds)
 	}
+
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	result.DataType = timestampedBatch.Stats.DataType
+	result.NodeCount = timestampedBatch.Stats.Nodes
+	result.RelationshipCount = timestampedBatch.Stats.Relationships
+	result.SkippedRelationshipCount = timestampedBatch.Stats.SkippedRelationships
+	result.InvalidRelationshipCount = timestampedBatch.Stats.InvalidRelationships
+	result.DeletedNodeCount = timestampedBatch.Stats.DeletedNodes
+	result.DeletedRelationshipCount = timestampedBatch.Stats.DeletedRelationships
+	result.CollectedDomains = timestampedBatch.Stats.Domains
+	result.CollectedSourceKinds = timestampedBatch.Stats.SourceKinds
+	result.RedactedProperties = timestampedBatch.Stats.RedactedProperties
+	result.TouchedDomains = timestampedBatch.Stats.TouchedDomains
+	result.TouchedTenants = timestampedBatch.Stats.TouchedTenants
+	result.UnscopedObjects = timestampedBatch.Stats.UnscopedObjects
+
+	return result, err
 }
 
 func processSingleFile(ctx context.Context, filePath string, batch *TimestampedBatch, readOpts ReadOptions) error {
@@ -170,9 +410,7 @@ func processSingleFile(ctx context.Context, filePath string, batch *TimestampedB
 	defer func() {
 		file.Close()
 		// Always remove the file after attempting to ingest it. Even if it failed
-		if err := os.Remove(filePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
-			slog.ErrorContext(ctx, fmt.Sprintf("Error removing ingest file %s: %v", filePath, err))
-		}
+		removeIngestFile(ctx, filePath)
 	}()
 
 	if err := ReadFileForIngest(batch, file, readOpts); err != nil {
@@ -183,6 +421,12 @@ func processSingleFile(ctx context.Context, filePath string, batch *TimestampedB
 	return nil
 }
 
+func removeIngestFile(ctx context.Context, filePath string) {
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		slog.ErrorContext(ctx, fmt.Sprintf("Error removing ingest file %s: %v", filePath, err))
+	}
+}
+
 func (s *GraphifyService) getAllTasks() model.IngestTasks {
 	tasks, err := s.db.GetAllIngestTasks(s.ctx)
 	if err != nil {
@@ -192,7 +436,7 @@ func (s *GraphifyService) getAllTasks() model.IngestTasks {
 	return tasks
 }
 
-func (s *GraphifyService) ProcessTasks(updateJob UpdateJobFunc) {
+func (s *GraphifyService) ProcessTasks(updateJob UpdateJobFunc, taskContext TaskContextFunc) {
 
 	for _, task := range s.getAllTasks() {
 		// Check the context to see if we should continue processing ingest tasks. This has to be explicit since error
@@ -205,15 +449,37 @@ func (s *GraphifyService) ProcessTasks(updateJob UpdateJobFunc) {
 			slog.WarnContext(s.ctx, "Skipped processing of ingestTasks due to config flag.")
 			return
 		}
-		total, failed, err := s.ProcessIngestFile(s.ctx, task, time.Now().UTC())
 
-		if errors.Is(err, fs.ErrNotExist) {
-			slog.WarnContext(s.ctx, fmt.Sprintf("Did not process ingest task %d with file %s: %v", task.ID, task.FileName, err))
-		} else if err != nil {
-			slog.ErrorContext(s.ctx, fmt.Sprintf("Failed processing ingest task %d with file %s: %v", task.ID, task.FileName, err))
+		// Chunked uploads are still being assembled and will be processed once their final chunk has been received
+		if task.Partial {
+			continue
 		}
 
-		updateJob(task.JobId.ValueOrZero(), total, failed)
+		s.processTask(task, updateJob, taskContext)
+	}
+}
+
+func (s *GraphifyService) processTask(task model.IngestTask, updateJob UpdateJobFunc, taskContext TaskContextFunc) {
+	ctx, cancel := taskContext(s.ctx, task)
+	defer cancel()
+
+	if ctx.Err() != nil {
+		slog.WarnContext(s.ctx, fmt.Sprintf("Discarded ingest task %d with file %s: %v", task.ID, task.FileName, context.Cause(ctx)))
+		removeIngestFile(s.ctx, task.FileName)
 		s.clearFileTask(task)
+		return
 	}
+
+	results, err := s.ProcessIngestFile(ctx, task, time.Now().UTC())
+
+	if errors.Is(err, fs.ErrNotExist) {
+		slog.WarnContext(s.ctx, fmt.Sprintf("Did not process ingest task %d with file %s: %v", task.ID, task.FileName, err))
+	} else if ctx.Err() != nil && s.ctx.Err() == nil {
+		slog.WarnContext(s.ctx, fmt.Sprintf("Stopped processing ingest task %d with file %s: %v", task.ID, task.FileName, context.Cause(ctx)))
+	} else if err != nil {
+		slog.ErrorContext(s.ctx, fmt.Sprintf("Failed processing ingest task %d with file %s: %v", task.ID, task.FileName, err))
+	}
+
+	updateJob(task.JobId.ValueOrZero(), results)
+	s.clearFileTask(task)
 }
@@ -24,8 +24,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/specterops/bloodhound/cmd/api/src/config"
 	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/services/graphify"
+	"github.com/specterops/bloodhound/cmd/api/src/services/upload"
+	"github.com/specterops/bloodhound/packages/go/graphschema/common"
 	"github.com/specterops/bloodhound/packages/go/lab/generic"
+	"github.com/specterops/dawgs/graph"
+	"github.com/specterops/dawgs/ops"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -53,10 +60,10 @@ func TestVersion5IngestJSON(t *testing.T) {
 	defer teardownIntegrationTestSuite(t, &testSuite)
 
 	for _, file := range files {
-		total, failed, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeJson}, time.Now())
+		results, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeJson}, time.Now())
 		require.NoError(t, err)
-		require.Zero(t, failed)
-		require.Equal(t, 1, total)
+		require.Zero(t, results.Failed())
+		require.Len(t, results, 1)
 	}
 
 	expected, err := generic.LoadGraphFromFile(os.DirFS(path.Join("fixtures", "Version5JSON", "ingest")), "ingested.json")
@@ -81,10 +88,10 @@ func TestVersion5IngestZIP(t *testing.T) {
 	defer teardownIntegrationTestSuite(t, &testSuite)
 
 	for _, file := range files {
-		total, failed, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeZip}, time.Now())
+		results, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeZip}, time.Now())
 		require.NoError(t, err)
-		require.Zero(t, failed)
-		require.Equal(t, 8, total)
+		require.Zero(t, results.Failed())
+		require.Len(t, results, 8)
 	}
 
 	expected, err := generic.LoadGraphFromFile(os.DirFS(path.Join("fixtures", "Version5ZIP", "ingest")), "ingested.json")
@@ -121,10 +128,10 @@ func TestVersion6ADCSJSON(t *testing.T) {
 	defer teardownIntegrationTestSuite(t, &testSuite)
 
 	for _, file := range files {
-		total, failed, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeJson}, time.Now())
+		results, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeJson}, time.Now())
 		require.NoError(t, err)
-		require.Zero(t, failed)
-		require.Equal(t, 1, total)
+		require.Zero(t, results.Failed())
+		require.Len(t, results, 1)
 	}
 
 	expected, err := generic.LoadGraphFromFile(os.DirFS(path.Join("fixtures", "Version6ADCSJSON", "ingest")), "ingested.json")
@@ -149,10 +156,10 @@ func TestVersion6ADCSZIP(t *testing.T) {
 	defer teardownIntegrationTestSuite(t, &testSuite)
 
 	for _, file := range files {
-		total, failed, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeZip}, time.Now())
+		results, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeZip}, time.Now())
 		require.NoError(t, err)
-		require.Zero(t, failed)
-		require.Equal(t, 13, total)
+		require.Zero(t, results.Failed())
+		require.Len(t, results, 13)
 	}
 
 	expected, err := generic.LoadGraphFromFile(os.DirFS(path.Join("fixtures", "Version6ADCSZIP", "ingest")), "ingested.json")
@@ -189,10 +196,10 @@ func TestVersion6AllJSON(t *testing.T) {
 	defer teardownIntegrationTestSuite(t, &testSuite)
 
 	for _, file := range files {
-		total, failed, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeJson}, time.Now())
+		results, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeJson}, time.Now())
 		require.NoError(t, err)
-		require.Zero(t, failed)
-		require.Equal(t, 1, total)
+		require.Zero(t, results.Failed())
+		require.Len(t, results, 1)
 	}
 
 	expected, err := generic.LoadGraphFromFile(os.DirFS(path.Join("fixtures", "Version6AllJSON", "ingest")), "ingested.json")
@@ -217,10 +224,10 @@ func TestVersion6AllZIP(t *testing.T) {
 	defer teardownIntegrationTestSuite(t, &testSuite)
 
 	for _, file := range files {
-		total, failed, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeZip}, time.Now())
+		results, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeZip}, time.Now())
 		require.NoError(t, err)
-		require.Zero(t, failed)
-		require.Equal(t, 13, total)
+		require.Zero(t, results.Failed())
+		require.Len(t, results, 13)
 	}
 
 	expected, err := generic.LoadGraphFromFile(os.DirFS(path.Join("fixtures", "Version6AllZIP", "ingest")), "ingested.json")
@@ -252,10 +259,10 @@ func TestVersion6IngestJSON(t *testing.T) {
 	defer teardownIntegrationTestSuite(t, &testSuite)
 
 	for _, file := range files {
-		total, failed, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeJson}, time.Now())
+		results, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeJson}, time.Now())
 		require.NoError(t, err)
-		require.Zero(t, failed)
-		require.Equal(t, 1, total)
+		require.Zero(t, results.Failed())
+		require.Len(t, results, 1)
 	}
 
 	expected, err := generic.LoadGraphFromFile(os.DirFS(path.Join("fixtures", "Version6JSON", "ingest")), "ingested.json")
@@ -280,13 +287,62 @@ func TestVersion6IngestZIP(t *testing.T) {
 	defer teardownIntegrationTestSuite(t, &testSuite)
 
 	for _, file := range files {
-		total, failed, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeZip}, time.Now())
+		results, err := testSuite.GraphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: file, FileType: model.FileTypeZip}, time.Now())
 		require.NoError(t, err)
-		require.Zero(t, failed)
-		require.Equal(t, 8, total)
+		require.Zero(t, results.Failed())
+		require.Len(t, results, 8)
 	}
 
 	expected, err := generic.LoadGraphFromFile(os.DirFS(path.Join("fixtures", "Version6ZIP", "ingest")), "ingested.json")
 	require.NoError(t, err)
 	generic.AssertDatabaseGraph(t, ctx, testSuite.GraphDB, &expected)
 }
+
+func TestVersion6IngestZIP_Concurrent(t *testing.T) {
+	t.Parallel()
+	var (
+		ctx = context.Background()
+
+		fixturesPath = path.Join("fixtures", "Version6ZIP", "raw")
+
+		testSuite = setupIntegrationTestSuite(t, fixturesPath)
+	)
+
+	defer teardownIntegrationTestSuite(t, &testSuite)
+
+	ingestSchema, err := upload.LoadIngestSchema()
+	require.NoError(t, err)
+
+	// The files of the archive share the endpoints of their relationships, which ingest workers running at the same
+	// time must not each create
+	graphifyService := graphify.NewGraphifyService(ctx, testSuite.BHDatabase, testSuite.GraphDB, config.Configuration{WorkDir: testSuite.WorkDir, IngestConcurrency: 4}, ingestSchema)
+
+	results, err := graphifyService.ProcessIngestFile(ctx, model.IngestTask{FileName: path.Join(testSuite.WorkDir, "archive.zip"), FileType: model.FileTypeZip}, time.Now())
+	require.NoError(t, err)
+	require.Zero(t, results.Failed())
+	require.Len(t, results, 8)
+
+	expected, err := generic.LoadGraphFromFile(os.DirFS(path.Join("fixtures", "Version6ZIP", "ingest")), "ingested.json")
+	require.NoError(t, err)
+	generic.AssertDatabaseGraph(t, ctx, testSuite.GraphDB, &expected)
+
+	require.NoError(t, testSuite.GraphDB.ReadTransaction(ctx, func(tx graph.Transaction) error {
+		nodes, err := ops.FetchNodes(tx.Nodes())
+		if err != nil {
+			return err
+		}
+
+		objectIDs := map[string]int{}
+		for _, node := range nodes {
+			if objectID, err := node.Properties.Get(common.ObjectID.String()).String(); err == nil {
+				objectIDs[objectID]++
+			}
+		}
+
+		for objectID, count := range objectIDs {
+			assert.Equal(t, 1, count, "object ID %s was written to more than one node", objectID)
+		}
+
+		return nil
+	}))
+}
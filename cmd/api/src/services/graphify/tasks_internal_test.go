@@ -0,0 +1,379 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/specterops/bloodhound/cmd/api/src/config"
+	"github.com/specterops/bloodhound/cmd/api/src/database"
+	"github.com/specterops/bloodhound/cmd/api/src/database/types"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/model/appcfg"
+	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
+	graphmocks "github.com/specterops/bloodhound/cmd/api/src/vendormocks/dawgs/graph"
+	"github.com/specterops/dawgs/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const testIngestPayload = `{"meta": {"type": "domains", "version": 6, "count": 0}, "data": []}`
+
+// utf16BOMPayload is testIngestPayload encoded as UTF-16LE with a byte order mark, to verify that extracted files are
+// normalized the same way regardless of the archive format they came from
+func utf16BOMPayload() []byte {
+	encoded := []byte{0xff, 0xfe}
+	for _, r := range testIngestPayload {
+		encoded = append(encoded, byte(r), 0x00)
+	}
+	return encoded
+}
+
+func newTestGraphifyService(t *testing.T) *GraphifyService {
+	t.Helper()
+
+	cfg := config.Configuration{WorkDir: t.TempDir()}
+	require.NoError(t, os.Mkdir(cfg.TempDirectory(), 0755))
+
+	return &GraphifyService{ctx: context.Background(), cfg: cfg}
+}
+
+func writeArchive(t *testing.T, content []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "archive")
+	require.NoError(t, os.WriteFile(path, content, 0600))
+	return path
+}
+
+func gzipBytes(t *testing.T, content []byte) []byte {
+	t.Helper()
+
+	var (
+		buffer bytes.Buffer
+		writer = gzip.NewWriter(&buffer)
+	)
+
+	_, err := writer.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func tarBytes(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+
+	var (
+		buffer bytes.Buffer
+		writer = tar.NewWriter(&buffer)
+	)
+
+	require.NoError(t, writer.WriteHeader(&tar.Header{Name: "collection/", Typeflag: tar.TypeDir, Mode: 0755}))
+	for name, content := range files {
+		require.NoError(t, writer.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0600, Size: int64(len(content))}))
+		_, err := writer.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func assertExtractedPayloads(t *testing.T, files []ingestFile, names ...string) {
+	t.Helper()
+
+	require.Len(t, files, len(names))
+	for _, file := range files {
+		assert.Contains(t, names, file.Name)
+
+		content, err := os.ReadFile(file.Path)
+		require.NoError(t, err)
+		assert.Equal(t, testIngestPayload, string(content))
+	}
+}
+
+func TestGraphifyService_ExtractIngestFiles(t *testing.T) {
+	t.Run("gzip", func(t *testing.T) {
+		var (
+			service = newTestGraphifyService(t)
+			path    = writeArchive(t, gzipBytes(t, utf16BOMPayload()))
+		)
+
+		files, failed, err := service.extractIngestFiles(path, "", model.FileTypeGzip)
+		require.NoError(t, err)
+		assert.Empty(t, failed)
+		assertExtractedPayloads(t, files, "archive")
+		assert.NoFileExists(t, path)
+	})
+
+	t.Run("gzip is reported under the name it was uploaded with", func(t *testing.T) {
+		var (
+			service = newTestGraphifyService(t)
+			path    = writeArchive(t, gzipBytes(t, []byte(testIngestPayload)))
+		)
+
+		files, failed, err := service.extractIngestFiles(path, "computers.json.gz", model.FileTypeGzip)
+		require.NoError(t, err)
+		assert.Empty(t, failed)
+		assertExtractedPayloads(t, files, "computers.json.gz")
+	})
+
+	t.Run("json is reported under the name it was uploaded with", func(t *testing.T) {
+		var (
+			service = newTestGraphifyService(t)
+			path    = writeArchive(t, []byte(testIngestPayload))
+		)
+
+		files, failed, err := service.extractIngestFiles(path, "computers.json", model.FileTypeJson)
+		require.NoError(t, err)
+		assert.Empty(t, failed)
+		assertExtractedPayloads(t, files, "computers.json")
+	})
+
+	t.Run("corrupt gzip", func(t *testing.T) {
+		var (
+			service    = newTestGraphifyService(t)
+			compressed = gzipBytes(t, []byte(testIngestPayload))
+			path       = writeArchive(t, compressed[:len(compressed)-8])
+		)
+
+		files, failed, err := service.extractIngestFiles(path, "", model.FileTypeGzip)
+		require.NoError(t, err)
+		require.Len(t, failed, 1)
+		assert.Equal(t, "archive", failed[0].FileName)
+		assert.True(t, failed[0].Failed())
+		assert.Empty(t, files)
+	})
+
+	t.Run("tar.gz", func(t *testing.T) {
+		var (
+			service = newTestGraphifyService(t)
+			path    = writeArchive(t, gzipBytes(t, tarBytes(t, map[string][]byte{
+				"collection/domains.json":   []byte(testIngestPayload),
+				"collection/computers.json": utf16BOMPayload(),
+			})))
+		)
+
+		files, failed, err := service.extractIngestFiles(path, "", model.FileTypeTarGzip)
+		require.NoError(t, err)
+		assert.Empty(t, failed)
+		assertExtractedPayloads(t, files, "collection/domains.json", "collection/computers.json")
+		assert.NoFileExists(t, path)
+	})
+
+	t.Run("tar.gz uploaded as gzip", func(t *testing.T) {
+		var (
+			service = newTestGraphifyService(t)
+			path    = writeArchive(t, gzipBytes(t, tarBytes(t, map[string][]byte{
+				"collection/domains.json": []byte(testIngestPayload),
+			})))
+		)
+
+		files, failed, err := service.extractIngestFiles(path, "", model.FileTypeGzip)
+		require.NoError(t, err)
+		assert.Empty(t, failed)
+		assertExtractedPayloads(t, files, "collection/domains.json")
+	})
+
+	t.Run("truncated tar.gz", func(t *testing.T) {
+		var (
+			service = newTestGraphifyService(t)
+			archive = tarBytes(t, map[string][]byte{"collection/domains.json": []byte(testIngestPayload)})
+			path    = writeArchive(t, gzipBytes(t, archive[:600]))
+		)
+
+		files, failed, err := service.extractIngestFiles(path, "", model.FileTypeTarGzip)
+		require.NoError(t, err)
+		assert.Empty(t, files)
+		require.Len(t, failed, 1)
+		assert.Equal(t, "archive", failed[0].FileName)
+	})
+
+	t.Run("unreadable archive", func(t *testing.T) {
+		var (
+			service = newTestGraphifyService(t)
+			path    = writeArchive(t, []byte("not a gzip stream"))
+		)
+
+		_, _, err := service.extractIngestFiles(path, "", model.FileTypeTarGzip)
+		assert.Error(t, err)
+	})
+
+	t.Run("zstd", func(t *testing.T) {
+		encoder, err := zstd.NewWriter(nil)
+		require.NoError(t, err)
+
+		var (
+			service = newTestGraphifyService(t)
+			path    = writeArchive(t, encoder.EncodeAll(utf16BOMPayload(), nil))
+		)
+
+		files, failed, err := service.extractIngestFiles(path, "", model.FileTypeZstd)
+		require.NoError(t, err)
+		assert.Empty(t, failed)
+		assertExtractedPayloads(t, files, "archive")
+		assert.NoFileExists(t, path)
+	})
+}
+
+// stubGraphifyData satisfies GraphifyData for tests that only need source kind registration. No app config is stored.
+type stubGraphifyData struct{}
+
+func (stubGraphifyData) GetAllIngestTasks(context.Context) (model.IngestTasks, error) {
+	return model.IngestTasks{}, nil
+}
+
+func (stubGraphifyData) DeleteIngestTask(context.Context, model.IngestTask) error {
+	return nil
+}
+
+func (stubGraphifyData) GetFlagByKey(context.Context, string) (appcfg.FeatureFlag, error) {
+	return appcfg.FeatureFlag{}, nil
+}
+
+func (stubGraphifyData) RegisterSourceKind(context.Context) func(sourceKind graph.Kind) error {
+	return func(graph.Kind) error { return nil }
+}
+
+func (stubGraphifyData) UpsertOpenGraphKinds(context.Context, model.OpenGraphKinds) error {
+	return nil
+}
+
+func (stubGraphifyData) GetOpenGraphKinds(context.Context) (model.OpenGraphKinds, error) {
+	return model.OpenGraphKinds{}, nil
+}
+
+func (stubGraphifyData) GetAllConfigurationParameters(context.Context) (appcfg.Parameters, error) {
+	return appcfg.Parameters{}, nil
+}
+
+func (stubGraphifyData) GetConfigurationParameter(_ context.Context, key appcfg.ParameterKey) (appcfg.Parameter, error) {
+	// Ingest refuses to run without redaction rules, so an empty set is always configured
+	if key == appcfg.IngestRedactionKey {
+		value, err := types.NewJSONBObject(appcfg.IngestRedactionParameter{})
+		return appcfg.Parameter{Key: key, Value: value}, err
+	}
+
+	return appcfg.Parameter{}, database.ErrNotFound
+}
+
+func (stubGraphifyData) SetConfigurationParameter(context.Context, appcfg.Parameter) error {
+	return nil
+}
+
+func TestGraphifyService_IngestFilesConcurrently(t *testing.T) {
+	var (
+		mockCtrl    = gomock.NewController(t)
+		mockGraphDB = graphmocks.NewMockDatabase(mockCtrl)
+		mockBatch   = graphmocks.NewMockBatch(mockCtrl)
+		workDir     = t.TempDir()
+		committed   = atomic.Int32{}
+		service     = &GraphifyService{
+			ctx:     context.Background(),
+			db:      stubGraphifyData{},
+			graphdb: mockGraphDB,
+			cfg:     config.Configuration{IngestConcurrency: 2},
+		}
+		writeFile = func(name, content string) ingestFile {
+			path := filepath.Join(workDir, name)
+			require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+			return ingestFile{Name: name, Path: path}
+		}
+		files = []ingestFile{
+			writeFile("opengraph.json", `{"graph": {"nodes": [{"id": "1", "kinds": ["Person"], "properties": {}}]}}`),
+			writeFile("domains.json", testIngestPayload),
+			writeFile("invalid.json", `{"meta": {"type": "domains", "version": 6, "count": 0}, "data": [`),
+			writeFile("computers.json", `{"meta": {"type": "computers", "version": 6, "count": 0}, "data": []}`),
+		}
+	)
+
+	// Every file but the OpenGraph one is written through a single shared batch, and the OpenGraph file through its own
+	mockGraphDB.EXPECT().BatchOperation(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, delegate graph.BatchDelegate) error {
+		defer committed.Add(1)
+		return delegate(mockBatch)
+	}).Times(2)
+
+	// The OpenGraph node must only be written once the shared batch has been committed
+	mockBatch.EXPECT().UpdateNodeBy(gomock.Any()).DoAndReturn(func(graph.NodeUpdate) error {
+		assert.Equal(t, int32(1), committed.Load())
+		return nil
+	})
+
+	results, err := service.ingestFilesConcurrently(context.Background(), model.FileTypeJson, files, time.Now().UTC())
+	assert.Error(t, err)
+
+	require.Len(t, results, len(files))
+	for idx, result := range results {
+		assert.Equal(t, files[idx].Name, result.FileName)
+	}
+
+	assert.Equal(t, ingest.DataTypeOpenGraph, results[0].DataType)
+	assert.Equal(t, int64(1), results[0].NodeCount)
+	assert.False(t, results[0].Failed())
+	assert.Equal(t, ingest.DataTypeDomain, results[1].DataType)
+	assert.False(t, results[1].Failed())
+	assert.True(t, results[2].Failed())
+	assert.Equal(t, ingest.DataTypeComputer, results[3].DataType)
+	assert.False(t, results[3].Failed())
+}
+
+func TestGraphifyService_IngestFilesConcurrently_CommitFailure(t *testing.T) {
+	var (
+		mockCtrl    = gomock.NewController(t)
+		mockGraphDB = graphmocks.NewMockDatabase(mockCtrl)
+		mockBatch   = graphmocks.NewMockBatch(mockCtrl)
+		workDir     = t.TempDir()
+		service     = &GraphifyService{
+			ctx:     context.Background(),
+			db:      stubGraphifyData{},
+			graphdb: mockGraphDB,
+			cfg:     config.Configuration{IngestConcurrency: 2},
+		}
+		files = []ingestFile{
+			{Name: "domains.json", Path: filepath.Join(workDir, "domains.json")},
+			{Name: "computers.json", Path: filepath.Join(workDir, "computers.json")},
+		}
+	)
+
+	require.NoError(t, os.WriteFile(files[0].Path, []byte(testIngestPayload), 0600))
+	require.NoError(t, os.WriteFile(files[1].Path, []byte(`{"meta": {"type": "computers", "version": 6, "count": 0}, "data": []}`), 0600))
+
+	mockGraphDB.EXPECT().BatchOperation(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, delegate graph.BatchDelegate) error {
+		require.NoError(t, delegate(mockBatch))
+		return errors.New("commit failed")
+	})
+
+	results, err := service.ingestFilesConcurrently(context.Background(), model.FileTypeJson, files, time.Now().UTC())
+	assert.Error(t, err)
+
+	// Files written through the shared batch all fail when it can not be committed
+	require.Len(t, results, len(files))
+	for idx, result := range results {
+		assert.Equal(t, files[idx].Name, result.FileName)
+		assert.Equal(t, "commit failed", result.Error)
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model/appcfg"
+	"github.com/specterops/dawgs/graph"
+)
+
+var templatePlaceholder = regexp.MustCompile(`\{([^{}]+)\}`)
+
+type propertyTransform struct {
+	appcfg.IngestTransformRule
+
+	kind    graph.Kind
+	pattern *regexp.Regexp
+}
+
+// PropertyTransformer applies the configured ingest transform rules to the properties of nodes before they are
+// written to the graph. A nil PropertyTransformer leaves properties unchanged.
+type PropertyTransformer struct {
+	transforms []propertyTransform
+}
+
+// NewPropertyTransformer validates and compiles the rules given. Rules are applied in the order given.
+func NewPropertyTransformer(rules []appcfg.IngestTransformRule) (*PropertyTransformer, error) {
+	transformer := &PropertyTransformer{}
+
+	for idx, rule := range rules {
+		transform := propertyTransform{
+			IngestTransformRule: rule,
+			kind:                graph.StringKind(rule.Kind),
+		}
+
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid ingest transform rule %d: %w", idx, err)
+		} else if rule.Action == appcfg.IngestTransformRegexExtract {
+			// The pattern was compiled once already by Validate, so this can not fail
+			transform.pattern = regexp.MustCompile(rule.Pattern)
+		}
+
+		transformer.transforms = append(transformer.transforms, transform)
+	}
+
+	return transformer, nil
+}
+
+// loadPropertyTransformer builds a PropertyTransformer from the rules stored in the app config. Ingest carries on
+// without any rules if they can not be loaded.
+func (s *GraphifyService) loadPropertyTransformer(ctx context.Context) *PropertyTransformer {
+	if transformer, err := NewPropertyTransformer(appcfg.GetIngestTransformRules(ctx, s.db)); err != nil {
+		slog.WarnContext(ctx, fmt.Sprintf("Ingest transform rules will not be applied: %v", err))
+		return nil
+	} else {
+		return transformer
+	}
+}
+
+// Transform applies every rule for one of the node kinds given to properties, which is modified in place
+func (s *PropertyTransformer) Transform(kinds graph.Kinds, properties map[string]any) map[string]any {
+	if s == nil || len(s.transforms) == 0 {
+		return properties
+	}
+
+	if properties == nil {
+		properties = map[string]any{}
+	}
+
+	for _, transform := range s.transforms {
+		if kinds.ContainsOneOf(transform.kind) {
+			transform.apply(properties)
+		}
+	}
+
+	return properties
+}
+
+func (s propertyTransform) apply(properties map[string]any) {
+	value, found := properties[s.Property]
+
+	switch s.Action {
+	case appcfg.IngestTransformRename:
+		if found {
+			delete(properties, s.Property)
+			properties[s.Target] = value
+		}
+
+	case appcfg.IngestTransformDrop:
+		delete(properties, s.Property)
+
+	case appcfg.IngestTransformCoerce:
+		if !found || value == nil {
+			return
+		} else if coerced, err := coerceProperty(value, s.Type); err != nil {
+			slog.Debug(fmt.Sprintf("Ingest transform could not coerce property %s to %s: %v", s.Property, s.Type, err))
+		} else {
+			properties[s.Property] = coerced
+		}
+
+	case appcfg.IngestTransformRegexExtract:
+		if str, isString := value.(string); found && isString {
+			if match := s.pattern.FindStringSubmatch(str); len(match) > 1 {
+				properties[s.targetOrProperty()] = match[1]
+			} else if len(match) == 1 {
+				properties[s.targetOrProperty()] = match[0]
+			}
+		}
+
+	case appcfg.IngestTransformDerive:
+		if derived, ok := deriveProperty(s.Template, properties); ok {
+			properties[s.Target] = derived
+		}
+	}
+}
+
+func (s propertyTransform) targetOrProperty() string {
+	if s.Target != "" {
+		return s.Target
+	}
+
+	return s.Property
+}
+
+// deriveProperty fills in every {name} placeholder in template with the value of the named property. Nothing is
+// derived if any of the properties referenced are missing.
+func deriveProperty(template string, properties map[string]any) (string, bool) {
+	var missing bool
+
+	derived := templatePlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		if value, found := properties[placeholder[1:len(placeholder)-1]]; !found || value == nil {
+			missing = true
+			return ""
+		} else {
+			return fmt.Sprint(value)
+		}
+	})
+
+	return derived, !missing
+}
+
+func coerceProperty(value any, propertyType string) (any, error) {
+	switch propertyType {
+	case appcfg.IngestTransformTypeString:
+		if str, isString := value.(string); isString {
+			return str, nil
+		}
+
+		return fmt.Sprint(value), nil
+
+	case appcfg.IngestTransformTypeInt:
+		switch typed := value.(type) {
+		case string:
+			return strconv.ParseInt(strings.TrimSpace(typed), 10, 64)
+		case float64:
+			if typed != float64(int64(typed)) {
+				return nil, fmt.Errorf("%v is not a whole number", typed)
+			}
+			return int64(typed), nil
+		case int, int32, int64:
+			return typed, nil
+		case bool:
+			if typed {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		}
+
+	case appcfg.IngestTransformTypeFloat:
+		switch typed := value.(type) {
+		case string:
+			return strconv.ParseFloat(strings.TrimSpace(typed), 64)
+		case float64:
+			return typed, nil
+		case int:
+			return float64(typed), nil
+		case int64:
+			return float64(typed), nil
+		}
+
+	case appcfg.IngestTransformTypeBool:
+		switch typed := value.(type) {
+		case string:
+			return strconv.ParseBool(strings.TrimSpace(typed))
+		case bool:
+			return typed, nil
+		case float64:
+			return typed != 0, nil
+		case int:
+			return typed != 0, nil
+		case int64:
+			return typed != 0, nil
+		}
+	}
+
+	return nil, fmt.Errorf("can not convert %T to %s", value, propertyType)
+}
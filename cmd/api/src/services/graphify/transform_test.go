@@ -0,0 +1,148 @@
+// Copyright 2024 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package graphify_test
+
+import (
+	"testing"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model/appcfg"
+	"github.com/specterops/bloodhound/cmd/api/src/services/graphify"
+	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
+	"github.com/specterops/dawgs/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropertyTransformer_Transform(t *testing.T) {
+	testCases := []struct {
+		name       string
+		rules      []appcfg.IngestTransformRule
+		kinds      graph.Kinds
+		properties map[string]any
+		expected   map[string]any
+	}{
+		{
+			name:       "rename moves the value",
+			rules:      []appcfg.IngestTransformRule{{Kind: "User", Action: appcfg.IngestTransformRename, Property: "mail", Target: "email"}},
+			kinds:      graph.Kinds{ad.User},
+			properties: map[string]any{"mail": "a@example.com"},
+			expected:   map[string]any{"email": "a@example.com"},
+		},
+		{
+			name:       "rules for other kinds are not applied",
+			rules:      []appcfg.IngestTransformRule{{Kind: "Computer", Action: appcfg.IngestTransformDrop, Property: "mail"}},
+			kinds:      graph.Kinds{ad.User},
+			properties: map[string]any{"mail": "a@example.com"},
+			expected:   map[string]any{"mail": "a@example.com"},
+		},
+		{
+			name:       "drop removes the property",
+			rules:      []appcfg.IngestTransformRule{{Kind: "User", Action: appcfg.IngestTransformDrop, Property: "extensionattribute1"}},
+			kinds:      graph.Kinds{ad.Entity, ad.User},
+			properties: map[string]any{"extensionattribute1": "secret", "name": "A"},
+			expected:   map[string]any{"name": "A"},
+		},
+		{
+			name: "coerce converts values",
+			rules: []appcfg.IngestTransformRule{
+				{Kind: "User", Action: appcfg.IngestTransformCoerce, Property: "count", Type: appcfg.IngestTransformTypeInt},
+				{Kind: "User", Action: appcfg.IngestTransformCoerce, Property: "enabled", Type: appcfg.IngestTransformTypeBool},
+				{Kind: "User", Action: appcfg.IngestTransformCoerce, Property: "score", Type: appcfg.IngestTransformTypeFloat},
+				{Kind: "User", Action: appcfg.IngestTransformCoerce, Property: "employeeid", Type: appcfg.IngestTransformTypeString},
+			},
+			kinds:      graph.Kinds{ad.User},
+			properties: map[string]any{"count": " 12 ", "enabled": "true", "score": "1.5", "employeeid": float64(1234)},
+			expected:   map[string]any{"count": int64(12), "enabled": true, "score": 1.5, "employeeid": "1234"},
+		},
+		{
+			name:       "failed coercion leaves the value unchanged",
+			rules:      []appcfg.IngestTransformRule{{Kind: "User", Action: appcfg.IngestTransformCoerce, Property: "count", Type: appcfg.IngestTransformTypeInt}},
+			kinds:      graph.Kinds{ad.User},
+			properties: map[string]any{"count": "twelve"},
+			expected:   map[string]any{"count": "twelve"},
+		},
+		{
+			name:       "regex extract uses the first capture group",
+			rules:      []appcfg.IngestTransformRule{{Kind: "User", Action: appcfg.IngestTransformRegexExtract, Property: "description", Target: "owner", Pattern: `owner:\s*(\S+)`}},
+			kinds:      graph.Kinds{ad.User},
+			properties: map[string]any{"description": "service account owner: alice"},
+			expected:   map[string]any{"description": "service account owner: alice", "owner": "alice"},
+		},
+		{
+			name:       "regex extract without a capture group replaces the property with the match",
+			rules:      []appcfg.IngestTransformRule{{Kind: "User", Action: appcfg.IngestTransformRegexExtract, Property: "description", Pattern: `[A-Z]{3}-\d+`}},
+			kinds:      graph.Kinds{ad.User},
+			properties: map[string]any{"description": "ticket ABC-123 approved"},
+			expected:   map[string]any{"description": "ABC-123"},
+		},
+		{
+			name:       "regex extract without a match leaves properties unchanged",
+			rules:      []appcfg.IngestTransformRule{{Kind: "User", Action: appcfg.IngestTransformRegexExtract, Property: "description", Target: "owner", Pattern: `owner:\s*(\S+)`}},
+			kinds:      graph.Kinds{ad.User},
+			properties: map[string]any{"description": "no owner"},
+			expected:   map[string]any{"description": "no owner"},
+		},
+		{
+			name:       "derive fills in the template",
+			rules:      []appcfg.IngestTransformRule{{Kind: "User", Action: appcfg.IngestTransformDerive, Target: "upn", Template: "{samaccountname}@{domain}"}},
+			kinds:      graph.Kinds{ad.User},
+			properties: map[string]any{"samaccountname": "alice", "domain": "EXAMPLE.COM"},
+			expected:   map[string]any{"samaccountname": "alice", "domain": "EXAMPLE.COM", "upn": "alice@EXAMPLE.COM"},
+		},
+		{
+			name:       "derive is skipped when a property is missing",
+			rules:      []appcfg.IngestTransformRule{{Kind: "User", Action: appcfg.IngestTransformDerive, Target: "upn", Template: "{samaccountname}@{domain}"}},
+			kinds:      graph.Kinds{ad.User},
+			properties: map[string]any{"samaccountname": "alice"},
+			expected:   map[string]any{"samaccountname": "alice"},
+		},
+		{
+			name: "rules are applied in order",
+			rules: []appcfg.IngestTransformRule{
+				{Kind: "User", Action: appcfg.IngestTransformRename, Property: "mail", Target: "email"},
+				{Kind: "User", Action: appcfg.IngestTransformDerive, Target: "contact", Template: "mailto:{email}"},
+			},
+			kinds:      graph.Kinds{ad.User},
+			properties: map[string]any{"mail": "a@example.com"},
+			expected:   map[string]any{"email": "a@example.com", "contact": "mailto:a@example.com"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			transformer, err := graphify.NewPropertyTransformer(testCase.rules)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, transformer.Transform(testCase.kinds, testCase.properties))
+		})
+	}
+
+	t.Run("nil transformer leaves properties unchanged", func(t *testing.T) {
+		var transformer *graphify.PropertyTransformer
+
+		properties := map[string]any{"name": "A"}
+		assert.Equal(t, properties, transformer.Transform(graph.Kinds{ad.User}, properties))
+	})
+}
+
+func TestNewPropertyTransformer_InvalidRule(t *testing.T) {
+	_, err := graphify.NewPropertyTransformer([]appcfg.IngestTransformRule{
+		{Kind: "User", Action: appcfg.IngestTransformDrop, Property: "mail"},
+		{Kind: "User", Action: "uppercase", Property: "name"},
+	})
+
+	assert.ErrorContains(t, err, "invalid ingest transform rule 1")
+}
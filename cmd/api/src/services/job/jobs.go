@@ -18,8 +18,11 @@ package job
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
+	"os"
 	"time"
 
 	"github.com/specterops/bloodhound/cmd/api/src/model"
@@ -27,6 +30,10 @@ import (
 
 const jobActivityTimeout = time.Minute * 20
 
+// FinishIngestJobFunc is called with every ingest job whose tasks have all been ingested, before the job moves on to
+// analysis. The job returned is the one that is saved.
+type FinishIngestJobFunc func(job model.IngestJob) model.IngestJob
+
 func updateIngestJobStatus(ctx context.Context, db JobData, job model.IngestJob, status model.JobStatus, message string) error {
 	job.Status = status
 	job.StatusMessage = message
@@ -47,6 +54,34 @@ func timeOutIngestJob(ctx context.Context, db JobData, jobID int64, message stri
 	}
 }
 
+// clearPartialIngestTasks removes any unfinished chunked uploads for a job. Partial tasks are never picked up for
+// ingest, so once their job can no longer receive chunks they would otherwise be left behind forever.
+func clearPartialIngestTasks(ctx context.Context, db JobData, jobID int64) error {
+	if tasks, err := db.GetIngestTasksForJob(ctx, jobID); err != nil {
+		return err
+	} else {
+		for _, task := range tasks {
+			if !task.Partial {
+				continue
+			}
+
+			if err := removeIngestTask(ctx, db, task); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// removeIngestTask deletes an ingest task along with the file it would have ingested
+func removeIngestTask(ctx context.Context, db JobData, task model.IngestTask) error {
+	if err := os.Remove(task.FileName); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		slog.ErrorContext(ctx, fmt.Sprintf("Error removing ingest file %s: %v", task.FileName, err))
+	}
+
+	return db.DeleteIngestTask(ctx, task)
+}
+
 // ProcessStaleIngestJobs fetches all runnings ingest jobs and transitions them to a timed out state if the job has been inactive for too long.
 func (s *JobService) ProcessStaleIngestJobs() {
 	// Because our database interfaces do not yet accept contexts this is a best-effort check to ensure that we do not
@@ -72,6 +107,8 @@ func (s *JobService) ProcessStaleIngestJobs() {
 
 				if err := timeOutIngestJob(s.ctx, s.db, job.ID, fmt.Sprintf("Ingest timeout: No ingest activity observed in %f minutes. Upload incomplete.", now.Sub(threshold).Minutes())); err != nil {
 					slog.ErrorContext(s.ctx, fmt.Sprintf("Error marking ingest job %d as timed out: %v", job.ID, err))
+				} else if err := clearPartialIngestTasks(s.ctx, s.db, job.ID); err != nil {
+					slog.ErrorContext(s.ctx, fmt.Sprintf("Error clearing partial ingest tasks for timed out ingest job %d: %v", job.ID, err))
 				}
 			}
 		}
@@ -79,13 +116,18 @@ func (s *JobService) ProcessStaleIngestJobs() {
 }
 
 func (s *JobService) HasIngestJobsWaitingForAnalysis() (bool, error) {
-	if ingestJobsUnderAnalysis, err := s.db.GetIngestJobsWithStatus(s.ctx, model.JobStatusAnalyzing); err != nil {
+	if ingestJobsUnderAnalysis, err := s.GetIngestJobsWaitingForAnalysis(); err != nil {
 		return false, err
 	} else {
 		return len(ingestJobsUnderAnalysis) > 0, nil
 	}
 }
 
+// GetIngestJobsWaitingForAnalysis returns the ingest jobs that finished ingesting and await the next analysis
+func (s *JobService) GetIngestJobsWaitingForAnalysis() ([]model.IngestJob, error) {
+	return s.db.GetIngestJobsWithStatus(s.ctx, model.JobStatusAnalyzing)
+}
+
 func (s *JobService) FailAnalyzedIngestJobs() {
 	// Because our database interfaces do not yet accept contexts this is a best-effort check to ensure that we do not
 	// commit state transitions when we are shutting down.
@@ -122,6 +164,25 @@ func (s *JobService) PartialCompleteIngestJobs() {
 	}
 }
 
+// CancelAnalyzedIngestJobs transitions the jobs under analysis to a canceled state once their analysis was canceled
+func (s *JobService) CancelAnalyzedIngestJobs() {
+	// Because our database interfaces do not yet accept contexts this is a best-effort check to ensure that we do not
+	// commit state transitions when we are shutting down.
+	if s.ctx.Err() != nil {
+		return
+	}
+
+	if ingestJobsUnderAnalysis, err := s.db.GetIngestJobsWithStatus(s.ctx, model.JobStatusAnalyzing); err != nil {
+		slog.ErrorContext(s.ctx, fmt.Sprintf("Failed to load ingest jobs under analysis: %v", err))
+	} else {
+		for _, job := range ingestJobsUnderAnalysis {
+			if err := updateIngestJobStatus(s.ctx, s.db, job, model.JobStatusCanceled, "Analysis canceled"); err != nil {
+				slog.ErrorContext(s.ctx, fmt.Sprintf("Failed updating ingest job %d to canceled status: %v", job.ID, err))
+			}
+		}
+	}
+}
+
 func (s *JobService) CompleteAnalyzedIngestJobs() {
 	// Because our database interfaces do not yet accept contexts this is a best-effort check to ensure that we do not
 	// commit state transitions when we are shutting down.
@@ -155,8 +216,9 @@ func (s *JobService) CompleteAnalyzedIngestJobs() {
 	}
 }
 
-// ProcessFinishedIngestJobs transitions all jobs in an ingesting state to an analyzing state, if there are no further tasks associated with the job in question
-func (s *JobService) ProcessFinishedIngestJobs() {
+// ProcessFinishedIngestJobs transitions all jobs in an ingesting state to an analyzing state, if there are no further tasks associated with the job in question.
+// Each of the finishJobs is called in order before the job is saved.
+func (s *JobService) ProcessFinishedIngestJobs(finishJobs ...FinishIngestJobFunc) {
 	// Because our database interfaces do not yet accept contexts this is a best-effort check to ensure that we do not
 	// commit state transitions when shutting down.
 	if s.ctx.Err() != nil {
@@ -170,6 +232,10 @@ func (s *JobService) ProcessFinishedIngestJobs() {
 			if remainingIngestTasks, err := s.db.GetIngestTasksForJob(s.ctx, job.ID); err != nil {
 				slog.ErrorContext(s.ctx, fmt.Sprintf("Failed looking up remaining ingest tasks for ingest job %d: %v", job.ID, err))
 			} else if len(remainingIngestTasks) == 0 {
+				for _, finishJob := range finishJobs {
+					job = finishJob(job)
+				}
+
 				if err := updateIngestJobStatus(s.ctx, s.db, job, model.JobStatusAnalyzing, "Analyzing"); err != nil {
 					slog.ErrorContext(s.ctx, fmt.Sprintf("Error updating ingest job %d: %v", job.ID, err))
 				}
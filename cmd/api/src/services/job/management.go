@@ -18,12 +18,16 @@ package job
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/specterops/bloodhound/cmd/api/src/model"
 )
 
+// ErrIngestJobNotCancelable is returned when canceling a job that is no longer receiving or ingesting files
+var ErrIngestJobNotCancelable = errors.New("job must be running or ingesting to be canceled")
+
 // NOTE: These methods are all called by the frontend/http handler to do stuff. We might want to consider moving
 //       that to the service model too, but I'm not sure if that's important yet.
 
@@ -35,13 +39,19 @@ func GetAllIngestJobs(ctx context.Context, db JobData, skip int, limit int, orde
 	return db.GetAllIngestJobs(ctx, skip, limit, order, filter)
 }
 
-func StartIngestJob(ctx context.Context, db JobData, user model.User) (model.IngestJob, error) {
+func GetIngestFileResultsForJob(ctx context.Context, db JobData, jobID int64, skip int, limit int) (model.IngestFileResults, int, error) {
+	return db.GetIngestFileResultsForJob(ctx, jobID, skip, limit)
+}
+
+func StartIngestJob(ctx context.Context, db JobData, user model.User, authoritativeMode model.AuthoritativeMode, environment string) (model.IngestJob, error) {
 	job := model.IngestJob{
-		UserID:     user.ID,
-		User:       user,
-		Status:     model.JobStatusRunning,
-		StartTime:  time.Now().UTC(),
-		LastIngest: time.Now().UTC(),
+		UserID:            user.ID,
+		User:              user,
+		Status:            model.JobStatusRunning,
+		StartTime:         time.Now().UTC(),
+		LastIngest:        time.Now().UTC(),
+		AuthoritativeMode: authoritativeMode,
+		Environment:       environment,
 	}
 	return db.CreateIngestJob(ctx, job)
 }
@@ -51,6 +61,20 @@ func TouchIngestJobLastIngest(ctx context.Context, db JobData, job model.IngestJ
 	return db.UpdateIngestJob(ctx, job)
 }
 
+// HasPartialIngestTasks reports whether any chunked uploads for the job are still waiting on more chunks
+func HasPartialIngestTasks(ctx context.Context, db JobData, jobID int64) (bool, error) {
+	if tasks, err := db.GetIngestTasksForJob(ctx, jobID); err != nil {
+		return false, err
+	} else {
+		for _, task := range tasks {
+			if task.Partial {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
 func EndIngestJob(ctx context.Context, db JobData, job model.IngestJob) error {
 	job.Status = model.JobStatusIngesting
 
@@ -60,3 +84,29 @@ func EndIngestJob(ctx context.Context, db JobData, job model.IngestJob) error {
 
 	return nil
 }
+
+// CancelIngestJob cancels a job that is still receiving or ingesting files, discarding the files it has left to
+// ingest. A file of the job being ingested when it is canceled is stopped by the datapipe.
+func CancelIngestJob(ctx context.Context, db JobData, job model.IngestJob) (model.IngestJob, error) {
+	if job.Status != model.JobStatusRunning && job.Status != model.JobStatusIngesting {
+		return job, ErrIngestJobNotCancelable
+	}
+
+	job.Status = model.JobStatusCanceled
+	job.StatusMessage = "Canceled"
+	job.EndTime = time.Now().UTC()
+
+	if err := db.UpdateIngestJob(ctx, job); err != nil {
+		return job, fmt.Errorf("error canceling ingest job: %w", err)
+	} else if tasks, err := db.GetIngestTasksForJob(ctx, job.ID); err != nil {
+		return job, fmt.Errorf("error fetching tasks of canceled ingest job: %w", err)
+	} else {
+		for _, task := range tasks {
+			if err := removeIngestTask(ctx, db, task); err != nil {
+				return job, fmt.Errorf("error removing task of canceled ingest job: %w", err)
+			}
+		}
+	}
+
+	return job, nil
+}
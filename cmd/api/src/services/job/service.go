@@ -29,6 +29,7 @@ type JobData interface {
 	CreateCompositionInfo(ctx context.Context, nodes model.EdgeCompositionNodes, edges model.EdgeCompositionEdges) (model.EdgeCompositionNodes, model.EdgeCompositionEdges, error)
 
 	GetIngestTasksForJob(ctx context.Context, jobID int64) (model.IngestTasks, error)
+	DeleteIngestTask(ctx context.Context, ingestTask model.IngestTask) error
 	// Job handlers
 	CreateIngestJob(ctx context.Context, job model.IngestJob) (model.IngestJob, error)
 	UpdateIngestJob(ctx context.Context, job model.IngestJob) error
@@ -37,6 +38,7 @@ type JobData interface {
 	GetIngestJobsWithStatus(ctx context.Context, status model.JobStatus) ([]model.IngestJob, error)
 	DeleteAllIngestJobs(ctx context.Context) error
 	CancelAllIngestJobs(ctx context.Context) error
+	GetIngestFileResultsForJob(ctx context.Context, jobID int64, skip int, limit int) (model.IngestFileResults, int, error)
 }
 
 type JobService struct {
@@ -0,0 +1,204 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package objectstoretest provides an in-memory S3-compatible server for tests. It serves a single bucket with path
+// style addressing, the same way a local MinIO instance is usually run.
+package objectstoretest
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Server struct {
+	*httptest.Server
+
+	// PageSize limits the number of objects returned per listing so that continuation can be exercised
+	PageSize int
+
+	bucket  string
+	lock    sync.Mutex
+	objects map[string][]byte
+	tags    map[string]map[string]string
+}
+
+// NewServer starts a server holding an empty bucket. Call Close when done.
+func NewServer(bucket string) *Server {
+	server := &Server{
+		PageSize: 1000,
+		bucket:   bucket,
+		objects:  map[string][]byte{},
+		tags:     map[string]map[string]string{},
+	}
+
+	server.Server = httptest.NewServer(http.HandlerFunc(server.handle))
+	return server
+}
+
+// ETag returns the ETag the server reports for content
+func ETag(content []byte) string {
+	digest := md5.Sum(content)
+	return hex.EncodeToString(digest[:])
+}
+
+// PutObject stores an object, replacing any previous content and tags
+func (s *Server) PutObject(key string, content []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.objects[key] = content
+	delete(s.tags, key)
+}
+
+// HasObject reports whether the object exists
+func (s *Server) HasObject(key string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, found := s.objects[key]
+	return found
+}
+
+// Tags returns the tags set on an object
+func (s *Server) Tags(key string) map[string]string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.tags[key]
+}
+
+type listBucketResult struct {
+	XMLName               xml.Name       `xml:"ListBucketResult"`
+	Name                  string         `xml:"Name"`
+	Prefix                string         `xml:"Prefix"`
+	IsTruncated           bool           `xml:"IsTruncated"`
+	NextContinuationToken string         `xml:"NextContinuationToken,omitempty"`
+	Contents              []listContents `xml:"Contents"`
+}
+
+type listContents struct {
+	Key          string `xml:"Key"`
+	ETag         string `xml:"ETag"`
+	Size         int    `xml:"Size"`
+	LastModified string `xml:"LastModified"`
+}
+
+type tagging struct {
+	TagSet []struct {
+		Key   string `xml:"Key"`
+		Value string `xml:"Value"`
+	} `xml:"TagSet>Tag"`
+}
+
+func writeError(response http.ResponseWriter, status int, code string) {
+	response.WriteHeader(status)
+	fmt.Fprintf(response, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+func (s *Server) handle(response http.ResponseWriter, request *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !strings.HasPrefix(request.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+		writeError(response, http.StatusForbidden, "AccessDenied")
+		return
+	}
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(request.URL.Path, "/"), "/")
+	if bucket != s.bucket {
+		writeError(response, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	switch {
+	case key == "" && request.Method == http.MethodGet:
+		s.list(response, request)
+
+	case request.Method == http.MethodGet:
+		if content, found := s.objects[key]; !found {
+			writeError(response, http.StatusNotFound, "NoSuchKey")
+		} else {
+			response.Header().Set("ETag", strconv.Quote(ETag(content)))
+			response.Write(content)
+		}
+
+	case request.Method == http.MethodDelete:
+		delete(s.objects, key)
+		delete(s.tags, key)
+		response.WriteHeader(http.StatusNoContent)
+
+	case request.Method == http.MethodPut && request.URL.Query().Has("tagging"):
+		var body tagging
+
+		if _, found := s.objects[key]; !found {
+			writeError(response, http.StatusNotFound, "NoSuchKey")
+		} else if content, err := io.ReadAll(request.Body); err != nil || xml.Unmarshal(content, &body) != nil {
+			writeError(response, http.StatusBadRequest, "MalformedXML")
+		} else {
+			s.tags[key] = map[string]string{}
+			for _, tag := range body.TagSet {
+				s.tags[key][tag.Key] = tag.Value
+			}
+		}
+
+	default:
+		writeError(response, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+func (s *Server) list(response http.ResponseWriter, request *http.Request) {
+	var (
+		prefix = request.URL.Query().Get("prefix")
+		after  = request.URL.Query().Get("continuation-token")
+		keys   []string
+		result = listBucketResult{Name: s.bucket, Prefix: prefix}
+	)
+
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) && key > after {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	if len(keys) > s.PageSize {
+		keys = keys[:s.PageSize]
+		result.IsTruncated = true
+		result.NextContinuationToken = keys[len(keys)-1]
+	}
+
+	for _, key := range keys {
+		result.Contents = append(result.Contents, listContents{
+			Key:          key,
+			ETag:         strconv.Quote(ETag(s.objects[key])),
+			Size:         len(s.objects[key]),
+			LastModified: time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+
+	response.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(response).Encode(result)
+}
@@ -0,0 +1,259 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package objectstore is a minimal client for the parts of the S3 API needed to pull collection files from a bucket.
+// It works against AWS S3 and S3-compatible servers such as MinIO.
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var ErrObjectStoreRequest = errors.New("object store request failed")
+
+// Credentials are the static access keys used to sign requests
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// Config locates a bucket. Endpoint defaults to AWS S3 for the region; set UsePathStyle for S3-compatible servers that
+// do not support virtual hosted buckets.
+type Config struct {
+	Endpoint     string
+	Region       string
+	Bucket       string
+	UsePathStyle bool
+	Credentials  Credentials
+}
+
+// Object is an entry returned by ListObjects. ETag is stored without the surrounding quotes S3 returns it with.
+type Object struct {
+	Key          string
+	ETag         string
+	Size         int64
+	LastModified time.Time
+}
+
+// S3Client makes signed requests against a single bucket
+type S3Client struct {
+	cfg        Config
+	endpoint   *url.URL
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewS3Client creates a client for the bucket described by cfg
+func NewS3Client(cfg Config, httpClient *http.Client) (*S3Client, error) {
+	rawEndpoint := cfg.Endpoint
+	if rawEndpoint == "" {
+		rawEndpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+	}
+
+	if endpoint, err := url.Parse(rawEndpoint); err != nil {
+		return nil, fmt.Errorf("invalid object store endpoint %s: %w", rawEndpoint, err)
+	} else if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid object store endpoint %s: scheme and host are required", rawEndpoint)
+	} else if cfg.Bucket == "" {
+		return nil, errors.New("object store bucket is required")
+	} else {
+		return &S3Client{
+			cfg:        cfg,
+			endpoint:   endpoint,
+			httpClient: httpClient,
+			now:        time.Now,
+		}, nil
+	}
+}
+
+// Bucket returns the name of the bucket the client reads from
+func (s *S3Client) Bucket() string {
+	return s.cfg.Bucket
+}
+
+func (s *S3Client) objectURL(key string, query map[string]string) *url.URL {
+	objectURL := *s.endpoint
+	escapedPath := strings.TrimSuffix(objectURL.EscapedPath(), "/")
+
+	if s.cfg.UsePathStyle {
+		escapedPath += "/" + uriEncode(s.cfg.Bucket, false)
+	} else {
+		objectURL.Host = s.cfg.Bucket + "." + objectURL.Host
+	}
+
+	// Bucket level requests against a path style endpoint address the bucket without a trailing slash
+	if key != "" || !s.cfg.UsePathStyle {
+		escapedPath += "/" + uriEncode(key, true)
+	}
+
+	objectURL.RawPath = escapedPath
+	objectURL.Path, _ = url.PathUnescape(escapedPath)
+	objectURL.RawQuery = canonicalQuery(query)
+
+	return &objectURL
+}
+
+func (s *S3Client) do(ctx context.Context, method, key string, query map[string]string, body []byte, headers http.Header) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, method, s.objectURL(key, query).String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	// Go re-derives the URL on parse; keep the exact escaping that will be signed
+	request.URL = s.objectURL(key, query)
+	request.ContentLength = int64(len(body))
+
+	for name, values := range headers {
+		request.Header[name] = values
+	}
+
+	signRequest(request, s.cfg.Credentials, s.cfg.Region, hashSHA256(body), s.now())
+
+	if response, err := s.httpClient.Do(request); err != nil {
+		return nil, fmt.Errorf("%w: %s %s: %w", ErrObjectStoreRequest, method, key, err)
+	} else if response.StatusCode >= http.StatusMultipleChoices {
+		defer response.Body.Close()
+		return nil, fmt.Errorf("%w: %s %s: %s", ErrObjectStoreRequest, method, key, readErrorResponse(response))
+	} else {
+		return response, nil
+	}
+}
+
+type errorResponse struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func readErrorResponse(response *http.Response) string {
+	var errResponse errorResponse
+
+	if content, err := io.ReadAll(io.LimitReader(response.Body, 64*1024)); err != nil || xml.Unmarshal(content, &errResponse) != nil || errResponse.Code == "" {
+		return response.Status
+	}
+
+	return fmt.Sprintf("%s: %s: %s", response.Status, errResponse.Code, errResponse.Message)
+}
+
+type listBucketResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key          string    `xml:"Key"`
+		ETag         string    `xml:"ETag"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+}
+
+// ListObjects lists every object in the bucket whose key begins with prefix, following continuation tokens until the
+// listing is complete
+func (s *S3Client) ListObjects(ctx context.Context, prefix string) ([]Object, error) {
+	var (
+		objects []Object
+		query   = map[string]string{"list-type": "2", "prefix": prefix}
+	)
+
+	for {
+		var result listBucketResult
+
+		if response, err := s.do(ctx, http.MethodGet, "", query, nil, nil); err != nil {
+			return nil, err
+		} else if err := decodeAndClose(response, &result); err != nil {
+			return nil, fmt.Errorf("error decoding object listing: %w", err)
+		}
+
+		for _, content := range result.Contents {
+			objects = append(objects, Object{
+				Key:          content.Key,
+				ETag:         strings.Trim(content.ETag, `"`),
+				Size:         content.Size,
+				LastModified: content.LastModified,
+			})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+
+		query["continuation-token"] = result.NextContinuationToken
+	}
+}
+
+func decodeAndClose(response *http.Response, target any) error {
+	defer response.Body.Close()
+	return xml.NewDecoder(response.Body).Decode(target)
+}
+
+// GetObject opens the content of an object. The caller must close the returned reader.
+func (s *S3Client) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	if response, err := s.do(ctx, http.MethodGet, key, nil, nil, nil); err != nil {
+		return nil, err
+	} else {
+		return response.Body, nil
+	}
+}
+
+// DeleteObject removes an object from the bucket
+func (s *S3Client) DeleteObject(ctx context.Context, key string) error {
+	if response, err := s.do(ctx, http.MethodDelete, key, nil, nil, nil); err != nil {
+		return err
+	} else {
+		return response.Body.Close()
+	}
+}
+
+type tagging struct {
+	XMLName xml.Name `xml:"Tagging"`
+	TagSet  []tag    `xml:"TagSet>Tag"`
+}
+
+type tag struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+// PutObjectTagging replaces the tags of an object
+func (s *S3Client) PutObjectTagging(ctx context.Context, key string, tags map[string]string) error {
+	var body tagging
+
+	for tagKey, tagValue := range tags {
+		body.TagSet = append(body.TagSet, tag{Key: tagKey, Value: tagValue})
+	}
+
+	if content, err := xml.Marshal(body); err != nil {
+		return err
+	} else {
+		digest := md5.Sum(content)
+		headers := http.Header{"Content-Md5": []string{base64.StdEncoding.EncodeToString(digest[:])}}
+
+		if response, err := s.do(ctx, http.MethodPut, key, map[string]string{"tagging": ""}, content, headers); err != nil {
+			return err
+		} else {
+			return response.Body.Close()
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package objectstore
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	signingService   = "s3"
+	amzDateFormat    = "20060102T150405Z"
+	amzDayFormat     = "20060102"
+
+	headerAmzDate          = "X-Amz-Date"
+	headerAmzContentSHA256 = "X-Amz-Content-Sha256"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty request body
+var emptyPayloadHash = hashSHA256(nil)
+
+func hashSHA256(content []byte) string {
+	digest := sha256.Sum256(content)
+	return hex.EncodeToString(digest[:])
+}
+
+func hmacSHA256(key []byte, content string) []byte {
+	digest := hmac.New(sha256.New, key)
+	digest.Write([]byte(content))
+	return digest.Sum(nil)
+}
+
+// uriEncode percent-encodes everything but the RFC 3986 unreserved characters, as required for SigV4 canonical
+// requests. Slashes are kept when encoding an object key for use in a path.
+func uriEncode(value string, keepSlash bool) string {
+	var builder strings.Builder
+
+	for _, char := range []byte(value) {
+		switch {
+		case 'A' <= char && char <= 'Z', 'a' <= char && char <= 'z', '0' <= char && char <= '9', char == '-', char == '_', char == '.', char == '~':
+			builder.WriteByte(char)
+		case char == '/' && keepSlash:
+			builder.WriteByte(char)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", char)
+		}
+	}
+
+	return builder.String()
+}
+
+// canonicalQuery sorts and encodes query parameters the way SigV4 expects. The same string is used as the raw query of
+// the request so that what is signed is exactly what is sent.
+func canonicalQuery(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, uriEncode(key, false)+"="+uriEncode(params[key], false))
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// signRequest adds AWS Signature Version 4 headers to a request. Every header already set on the request is signed
+// along with the host.
+func signRequest(request *http.Request, credentials Credentials, region, payloadHash string, now time.Time) {
+	var (
+		amzDate = now.UTC().Format(amzDateFormat)
+		scope   = strings.Join([]string{now.UTC().Format(amzDayFormat), region, signingService, "aws4_request"}, "/")
+		headers = map[string]string{"host": request.URL.Host}
+		names   = []string{"host"}
+	)
+
+	request.Header.Set(headerAmzDate, amzDate)
+	request.Header.Set(headerAmzContentSHA256, payloadHash)
+
+	for name, values := range request.Header {
+		lowerName := strings.ToLower(name)
+		headers[lowerName] = strings.TrimSpace(strings.Join(values, ","))
+		names = append(names, lowerName)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+
+	var (
+		signedHeaders    = strings.Join(names, ";")
+		canonicalRequest = strings.Join([]string{
+			request.Method,
+			request.URL.EscapedPath(),
+			request.URL.RawQuery,
+			canonicalHeaders.String(),
+			signedHeaders,
+			payloadHash,
+		}, "\n")
+		stringToSign = strings.Join([]string{signingAlgorithm, amzDate, scope, hashSHA256([]byte(canonicalRequest))}, "\n")
+		signingKey   = hmacSHA256(hmacSHA256(hmacSHA256(hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), now.UTC().Format(amzDayFormat)), region), signingService), "aws4_request")
+		signature    = hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+	)
+
+	request.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", signingAlgorithm, credentials.AccessKeyID, scope, signedHeaders, signature))
+}
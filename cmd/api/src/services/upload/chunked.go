@@ -0,0 +1,243 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package upload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/specterops/bloodhound/cmd/api/src/database/types/null"
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+)
+
+const contentRangeUnit = "bytes"
+
+var (
+	ErrInvalidContentRange = errors.New("invalid content range")
+	ErrChunkOffsetMismatch = errors.New("chunk offset does not match the number of bytes received")
+	ErrChunkTotalMismatch  = errors.New("chunk total size does not match the size of the upload")
+	ErrUploadNotPartial    = errors.New("ingest task is not an in-progress chunked upload")
+)
+
+// chunkedUploads serializes every read and write of a partial file so concurrent requests for the same upload cannot
+// both pass the offset check, interleave their bytes or finalize the upload twice
+var chunkedUploads = &chunkedUploadLocks{entries: map[string]*chunkedUploadLock{}}
+
+type chunkedUploadLock struct {
+	sync.Mutex
+	refs int
+}
+
+// chunkedUploadLocks hands out one mutex per partial file, dropping it once nobody holds or waits on it
+type chunkedUploadLocks struct {
+	lock    sync.Mutex
+	entries map[string]*chunkedUploadLock
+}
+
+func (s *chunkedUploadLocks) acquire(fileName string) func() {
+	s.lock.Lock()
+	entry, found := s.entries[fileName]
+	if !found {
+		entry = &chunkedUploadLock{}
+		s.entries[fileName] = entry
+	}
+	entry.refs++
+	s.lock.Unlock()
+
+	entry.Lock()
+
+	return func() {
+		entry.Unlock()
+
+		s.lock.Lock()
+		if entry.refs--; entry.refs == 0 {
+			delete(s.entries, fileName)
+		}
+		s.lock.Unlock()
+	}
+}
+
+// ContentRange is a parsed `Content-Range: bytes <start>-<end>/<total>` header. Start and End are inclusive byte
+// offsets into the complete file, matching RFC 9110 semantics.
+type ContentRange struct {
+	Start int64
+	End   int64
+	Total int64
+}
+
+// Size returns the number of bytes the range covers
+func (s ContentRange) Size() int64 {
+	return s.End - s.Start + 1
+}
+
+// ParseContentRange parses a Content-Range header value. Unsatisfied ranges (`bytes */<total>`) and unknown totals
+// (`bytes <start>-<end>/*`) are rejected, since the server must know exactly where each chunk belongs.
+func ParseContentRange(value string) (ContentRange, error) {
+	var (
+		contentRange ContentRange
+		err          error
+	)
+
+	if unit, byteRange, found := strings.Cut(strings.TrimSpace(value), " "); !found || unit != contentRangeUnit {
+		return contentRange, fmt.Errorf("%w: expected unit %q", ErrInvalidContentRange, contentRangeUnit)
+	} else if span, total, found := strings.Cut(byteRange, "/"); !found {
+		return contentRange, fmt.Errorf("%w: missing total size", ErrInvalidContentRange)
+	} else if start, end, found := strings.Cut(span, "-"); !found {
+		return contentRange, fmt.Errorf("%w: missing byte span", ErrInvalidContentRange)
+	} else if contentRange.Start, err = strconv.ParseInt(start, 10, 64); err != nil {
+		return contentRange, fmt.Errorf("%w: invalid start offset", ErrInvalidContentRange)
+	} else if contentRange.End, err = strconv.ParseInt(end, 10, 64); err != nil {
+		return contentRange, fmt.Errorf("%w: invalid end offset", ErrInvalidContentRange)
+	} else if contentRange.Total, err = strconv.ParseInt(total, 10, 64); err != nil {
+		return contentRange, fmt.Errorf("%w: invalid total size", ErrInvalidContentRange)
+	} else if contentRange.Start < 0 || contentRange.End < contentRange.Start || contentRange.End >= contentRange.Total {
+		return contentRange, fmt.Errorf("%w: byte span %d-%d is out of bounds for total size %d", ErrInvalidContentRange, contentRange.Start, contentRange.End, contentRange.Total)
+	}
+
+	return contentRange, nil
+}
+
+// ChunkedUploadStatus reports how much of a chunked upload the server has received
+type ChunkedUploadStatus struct {
+	TaskID        int64 `json:"task_id"`
+	ReceivedBytes int64 `json:"received_bytes"`
+	TotalBytes    int64 `json:"total_bytes"`
+	Complete      bool  `json:"complete"`
+}
+
+// GetChunkedUploadStatus reads the number of bytes written so far for an ingest task. The partial file on disk is
+// the source of truth, so a chunk interrupted part way through still counts the bytes that made it to disk.
+func GetChunkedUploadStatus(task model.IngestTask) (ChunkedUploadStatus, error) {
+	status := ChunkedUploadStatus{
+		TaskID:     task.ID,
+		TotalBytes: task.TotalBytes,
+		Complete:   !task.Partial,
+	}
+
+	if !task.Partial {
+		status.ReceivedBytes = task.TotalBytes
+	} else if info, err := os.Stat(task.FileName); err != nil {
+		return status, fmt.Errorf("error reading partial ingest file: %w", err)
+	} else {
+		status.ReceivedBytes = info.Size()
+	}
+
+	return status, nil
+}
+
+// StartChunkedUpload creates an empty partial file in location and a partial ingest task that tracks it. Chunks are
+// appended to the file with WriteIngestChunk until the upload is complete.
+func StartChunkedUpload(ctx context.Context, db UploadData, location string, params IngestTaskParams) (model.IngestTask, error) {
+	tempFile, err := os.CreateTemp(location, "bh")
+	if err != nil {
+		return model.IngestTask{}, fmt.Errorf("error creating partial ingest file: %w", err)
+	} else if err := tempFile.Close(); err != nil {
+		slog.ErrorContext(ctx, fmt.Sprintf("Error closing partial ingest file %s: %v", tempFile.Name(), err))
+	}
+
+	task, err := db.CreateIngestTask(ctx, model.IngestTask{
+		FileName:         tempFile.Name(),
+		RequestGUID:      params.RequestID,
+		JobId:            null.Int64From(params.JobID),
+		FileType:         params.FileType,
+		OriginalFileName: params.OriginalFilename,
+		Partial:          true,
+		Environment:      params.Environment,
+	})
+
+	if err != nil {
+		if removeErr := os.Remove(tempFile.Name()); removeErr != nil {
+			slog.ErrorContext(ctx, fmt.Sprintf("Error deleting partial ingest file %s: %v", tempFile.Name(), removeErr))
+		}
+	}
+
+	return task, err
+}
+
+// WriteIngestChunk writes a chunk to the partial file of an ingest task. The chunk must start exactly where the
+// previously received bytes end, otherwise ErrChunkOffsetMismatch is returned and nothing is written. Concurrent
+// writes to the same upload are serialized, so only one of several requests for the same range succeeds. If the
+// upload was finalized by another request in the meantime ErrUploadNotPartial is returned. The returned status
+// reflects the bytes on disk after the write, including any partial chunk written before a read error.
+func WriteIngestChunk(task model.IngestTask, contentRange ContentRange, chunk io.Reader) (ChunkedUploadStatus, error) {
+	if !task.Partial {
+		return ChunkedUploadStatus{}, ErrUploadNotPartial
+	} else if task.TotalBytes != 0 && task.TotalBytes != contentRange.Total {
+		return ChunkedUploadStatus{}, ErrChunkTotalMismatch
+	}
+
+	// The first chunk fixes the size of the upload; the caller is responsible for persisting it on the task
+	task.TotalBytes = contentRange.Total
+
+	unlock := chunkedUploads.acquire(task.FileName)
+	defer unlock()
+
+	if status, err := GetChunkedUploadStatus(task); errors.Is(err, os.ErrNotExist) {
+		return status, ErrUploadNotPartial
+	} else if err != nil {
+		return status, err
+	} else if status.ReceivedBytes != contentRange.Start {
+		return status, ErrChunkOffsetMismatch
+	} else if partialFile, err := os.OpenFile(task.FileName, os.O_WRONLY, 0); err != nil {
+		return status, fmt.Errorf("error opening partial ingest file: %w", err)
+	} else {
+		written, copyErr := io.CopyN(io.NewOffsetWriter(partialFile, contentRange.Start), chunk, contentRange.Size())
+
+		if closeErr := partialFile.Close(); closeErr != nil && copyErr == nil {
+			copyErr = closeErr
+		}
+
+		status.ReceivedBytes += written
+		if copyErr != nil {
+			return status, fmt.Errorf("error writing ingest chunk: %w", copyErr)
+		}
+
+		return status, nil
+	}
+}
+
+// FinalizeChunkedUpload runs the validator for the task's file type over the assembled partial file, exactly as
+// SaveIngestFile does for single request uploads. The partial file is removed whether validation passes or not; on
+// success the name of the validated file is returned so the task can be handed to the ingest pipeline. Finalization
+// is exclusive with WriteIngestChunk; if another request already finalized the upload ErrUploadNotPartial is returned.
+func FinalizeChunkedUpload(location string, task model.IngestTask, validator IngestValidator) (string, error) {
+	unlock := chunkedUploads.acquire(task.FileName)
+	defer unlock()
+
+	partialFile, err := os.Open(task.FileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrUploadNotPartial
+	} else if err != nil {
+		return "", fmt.Errorf("error opening partial ingest file: %w", err)
+	}
+
+	defer func() {
+		partialFile.Close()
+		if err := os.Remove(task.FileName); err != nil {
+			slog.Error(fmt.Sprintf("Error deleting partial ingest file %s: %v", task.FileName, err))
+		}
+	}()
+
+	return WriteAndValidateFile(partialFile, location, validator.FileValidatorFor(task.FileType))
+}
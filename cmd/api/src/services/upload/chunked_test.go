@@ -0,0 +1,235 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package upload
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected ContentRange
+		wantErr  bool
+	}{
+		{name: "valid range", value: "bytes 0-99/1000", expected: ContentRange{Start: 0, End: 99, Total: 1000}},
+		{name: "valid final range", value: "bytes 900-999/1000", expected: ContentRange{Start: 900, End: 999, Total: 1000}},
+		{name: "single byte", value: "bytes 0-0/1", expected: ContentRange{Start: 0, End: 0, Total: 1}},
+		{name: "empty header", value: "", wantErr: true},
+		{name: "wrong unit", value: "items 0-99/1000", wantErr: true},
+		{name: "unknown total", value: "bytes 0-99/*", wantErr: true},
+		{name: "unsatisfied range", value: "bytes */1000", wantErr: true},
+		{name: "end before start", value: "bytes 99-0/1000", wantErr: true},
+		{name: "end past total", value: "bytes 0-1000/1000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contentRange, err := ParseContentRange(tt.value)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidContentRange)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, contentRange)
+			}
+		})
+	}
+}
+
+func TestWriteIngestChunk(t *testing.T) {
+	var (
+		payload = `{"meta": {"type": "domains", "version": 5, "count": 1}, "data": [{"domain": "example.com"}]}`
+		total   = int64(len(payload))
+		half    = total / 2
+	)
+
+	newPartialTask := func(t *testing.T) model.IngestTask {
+		t.Helper()
+		fileName := filepath.Join(t.TempDir(), "partial")
+		require.NoError(t, os.WriteFile(fileName, nil, 0600))
+		return model.IngestTask{FileName: fileName, Partial: true}
+	}
+
+	t.Run("chunks are appended in order", func(t *testing.T) {
+		task := newPartialTask(t)
+
+		status, err := WriteIngestChunk(task, ContentRange{Start: 0, End: half - 1, Total: total}, strings.NewReader(payload[:half]))
+		require.NoError(t, err)
+		assert.Equal(t, half, status.ReceivedBytes)
+		assert.Equal(t, total, status.TotalBytes)
+
+		task.TotalBytes = total
+		status, err = WriteIngestChunk(task, ContentRange{Start: half, End: total - 1, Total: total}, strings.NewReader(payload[half:]))
+		require.NoError(t, err)
+		assert.Equal(t, total, status.ReceivedBytes)
+
+		content, err := os.ReadFile(task.FileName)
+		require.NoError(t, err)
+		assert.Equal(t, payload, string(content))
+	})
+
+	t.Run("chunk at the wrong offset is rejected", func(t *testing.T) {
+		task := newPartialTask(t)
+
+		status, err := WriteIngestChunk(task, ContentRange{Start: half, End: total - 1, Total: total}, strings.NewReader(payload[half:]))
+		assert.ErrorIs(t, err, ErrChunkOffsetMismatch)
+		assert.Equal(t, int64(0), status.ReceivedBytes)
+	})
+
+	t.Run("chunk with a different total is rejected", func(t *testing.T) {
+		task := newPartialTask(t)
+		task.TotalBytes = total
+
+		_, err := WriteIngestChunk(task, ContentRange{Start: 0, End: half - 1, Total: total + 1}, strings.NewReader(payload[:half]))
+		assert.ErrorIs(t, err, ErrChunkTotalMismatch)
+	})
+
+	t.Run("interrupted chunk keeps the bytes received", func(t *testing.T) {
+		task := newPartialTask(t)
+
+		status, err := WriteIngestChunk(task, ContentRange{Start: 0, End: total - 1, Total: total}, strings.NewReader(payload[:half]))
+		assert.Error(t, err)
+		assert.Equal(t, half, status.ReceivedBytes)
+	})
+
+	t.Run("concurrent chunks for the same range are written once", func(t *testing.T) {
+		var (
+			task      = newPartialTask(t)
+			waitGroup sync.WaitGroup
+			errs      = make([]error, 8)
+		)
+
+		for idx := range errs {
+			waitGroup.Add(1)
+			go func() {
+				defer waitGroup.Done()
+				_, errs[idx] = WriteIngestChunk(task, ContentRange{Start: 0, End: half - 1, Total: total}, strings.NewReader(payload[:half]))
+			}()
+		}
+		waitGroup.Wait()
+
+		succeeded := 0
+		for _, err := range errs {
+			if err == nil {
+				succeeded++
+			} else {
+				assert.ErrorIs(t, err, ErrChunkOffsetMismatch)
+			}
+		}
+		assert.Equal(t, 1, succeeded)
+
+		content, err := os.ReadFile(task.FileName)
+		require.NoError(t, err)
+		assert.Equal(t, payload[:half], string(content))
+	})
+
+	t.Run("chunk for a finalized upload is rejected", func(t *testing.T) {
+		task := newPartialTask(t)
+		require.NoError(t, os.Remove(task.FileName))
+
+		_, err := WriteIngestChunk(task, ContentRange{Start: 0, End: half - 1, Total: total}, strings.NewReader(payload[:half]))
+		assert.ErrorIs(t, err, ErrUploadNotPartial)
+	})
+
+	t.Run("completed task is rejected", func(t *testing.T) {
+		_, err := WriteIngestChunk(model.IngestTask{}, ContentRange{Start: 0, End: 0, Total: 1}, strings.NewReader("a"))
+		assert.ErrorIs(t, err, ErrUploadNotPartial)
+	})
+}
+
+func TestFinalizeChunkedUpload(t *testing.T) {
+	schema, err := LoadIngestSchema()
+	require.NoError(t, err)
+
+	validator := NewIngestValidator(schema)
+
+	t.Run("valid assembled file is kept", func(t *testing.T) {
+		var (
+			location    = t.TempDir()
+			partialName = filepath.Join(location, "partial")
+			payload     = `{"meta": {"type": "domains", "version": 5, "count": 1}, "data": [{"domain": "example.com"}]}`
+		)
+
+		require.NoError(t, os.WriteFile(partialName, []byte(payload), 0600))
+
+		fileName, err := FinalizeChunkedUpload(location, model.IngestTask{FileName: partialName, FileType: model.FileTypeJson, Partial: true}, validator)
+		require.NoError(t, err)
+		assert.NoFileExists(t, partialName)
+
+		content, err := os.ReadFile(fileName)
+		require.NoError(t, err)
+		assert.Equal(t, payload, string(content))
+	})
+
+	t.Run("invalid assembled file is removed", func(t *testing.T) {
+		var (
+			location    = t.TempDir()
+			partialName = filepath.Join(location, "partial")
+		)
+
+		require.NoError(t, os.WriteFile(partialName, []byte("not a zip"), 0600))
+
+		_, err := FinalizeChunkedUpload(location, model.IngestTask{FileName: partialName, FileType: model.FileTypeZip, Partial: true}, validator)
+		assert.Error(t, err)
+
+		entries, err := os.ReadDir(location)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+	t.Run("upload is finalized only once", func(t *testing.T) {
+		var (
+			location    = t.TempDir()
+			partialName = filepath.Join(location, "partial")
+			payload     = `{"meta": {"type": "domains", "version": 5, "count": 1}, "data": [{"domain": "example.com"}]}`
+			task        = model.IngestTask{FileName: partialName, FileType: model.FileTypeJson, Partial: true}
+			waitGroup   sync.WaitGroup
+			fileNames   = make([]string, 4)
+			errs        = make([]error, 4)
+		)
+
+		require.NoError(t, os.WriteFile(partialName, []byte(payload), 0600))
+
+		for idx := range errs {
+			waitGroup.Add(1)
+			go func() {
+				defer waitGroup.Done()
+				fileNames[idx], errs[idx] = FinalizeChunkedUpload(location, task, validator)
+			}()
+		}
+		waitGroup.Wait()
+
+		finalized := 0
+		for idx, err := range errs {
+			if err == nil {
+				finalized++
+				assert.FileExists(t, fileNames[idx])
+			} else {
+				assert.ErrorIs(t, err, ErrUploadNotPartial)
+			}
+		}
+		assert.Equal(t, 1, finalized)
+	})
+}
@@ -0,0 +1,371 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package upload
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
+	"github.com/specterops/bloodhound/packages/go/bomenc"
+	"github.com/specterops/bloodhound/packages/go/ein"
+)
+
+// Fields of the multipart form a CSV file is uploaded with. The mapping field must come before the file field so that
+// the file can be validated against its mapping as it is written to disk.
+const (
+	CSVFormFieldMapping = "mapping"
+	CSVFormFieldFile    = "file"
+)
+
+var ErrInvalidCSVUpload = errors.New("invalid csv upload")
+
+// CSVRecord is a single row of a CSV file converted with its mapping. Exactly one of Node and Edge is set, unless the
+// row could not be converted, in which case Err is.
+type CSVRecord struct {
+	Line int
+	Node *ein.GenericNode
+	Edge *ein.GenericEdge
+	Err  error
+}
+
+// ScanCSV reads a CSV file and passes each of its rows, converted with the mapping given, to delegate in order. The
+// header must name every column the mapping reads. Reading stops at the first row that is not valid CSV, or at the
+// first error returned by delegate; rows that are valid CSV but can not be converted are passed with their error.
+func ScanCSV(reader io.Reader, mapping ingest.CSVMapping, delegate func(record CSVRecord) error) error {
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma = mapping.Comma()
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("csv file is empty. a header row is required")
+	} else if err != nil {
+		return fmt.Errorf("error reading csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for idx, column := range header {
+		columns[strings.TrimSpace(column)] = idx
+	}
+
+	var missing []string
+	for _, column := range mapping.Columns() {
+		if _, ok := columns[column]; !ok {
+			missing = append(missing, column)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("csv header is missing mapped columns: %s", strings.Join(missing, ", "))
+	}
+
+	for {
+		row, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("error reading csv: %w", err)
+		}
+
+		line, _ := csvReader.FieldPos(0)
+		if err := delegate(convertCSVRow(mapping, columns, line, row)); err != nil {
+			return err
+		}
+	}
+}
+
+// ValidateCSV validates a CSV file against its mapping. Each row is converted and validated against the same JSON
+// Schema as the nodes or edges of a JSON OpenGraph payload, along with the property constraints enforced by
+// ValidateGraph. At least one row is required.
+//
+// The file is always read to the end so that it can be teed to disk while it is validated. If any row is invalid a
+// ValidationReport is returned as an error.
+func ValidateCSV(reader io.Reader, mapping ingest.CSVMapping, schema IngestSchema) error {
+	var (
+		v = &validator{
+			maxErrors: 15,
+		}
+		rowCount = 0
+	)
+
+	if err := ScanCSV(reader, mapping, func(record CSVRecord) error {
+		rowCount++
+
+		if len(v.validationErrors) >= v.maxErrors {
+			return nil
+		}
+
+		var (
+			location = fmt.Sprintf("line %d", record.Line)
+			value    any
+			item     map[string]any
+		)
+
+		switch {
+		case record.Err != nil:
+			v.reportValidation(record.Line, fmt.Sprintf("%s %s", location, record.Err))
+			return nil
+		case record.Node != nil:
+			value = csvNodeDocument(*record.Node)
+			location += " (node)"
+		default:
+			value = csvEdgeDocument(*record.Edge)
+			location += " (edge)"
+		}
+
+		if content, err := json.Marshal(value); err != nil {
+			v.reportValidation(record.Line, fmt.Sprintf("%s %s", location, err))
+		} else if err := json.Unmarshal(content, &item); err != nil {
+			v.reportValidation(record.Line, fmt.Sprintf("%s %s", location, err))
+		} else if record.Node != nil {
+			if err := schema.NodeSchema.Validate(item); err != nil {
+				v.reportValidation(record.Line, formatSchemaValidationError(location, err))
+			}
+		} else if err := schema.EdgeSchema.Validate(item); err != nil {
+			v.reportValidation(record.Line, formatSchemaValidationError(location, err))
+		}
+
+		v.validateProperties(location, record.Line, item)
+		return nil
+	}); err != nil {
+		// the rest of the file still has to be written to disk
+		if _, copyErr := io.Copy(io.Discard, reader); copyErr != nil {
+			return copyErr
+		}
+
+		v.reportCritical(0, err.Error())
+	} else if rowCount == 0 {
+		v.reportCritical(0, "csv file has no rows. at least one row after the header is required")
+	}
+
+	return v.report()
+}
+
+// CSVFileValidator returns a FileValidator for CSV files that validates them against the mapping given
+func (s *IngestValidator) CSVFileValidator(mapping ingest.CSVMapping) FileValidator {
+	return func(src io.Reader, dst io.Writer) (ingest.Metadata, error) {
+		normalizedContent, err := bomenc.NormalizeToUTF8(src)
+		if err != nil {
+			return ingest.Metadata{}, err
+		}
+
+		tr := io.TeeReader(normalizedContent, dst)
+		return ingest.Metadata{Type: ingest.DataTypeOpenGraph}, ValidateCSV(tr, mapping, s.IngestSchema)
+	}
+}
+
+// SaveCSVIngestFile reads a CSV upload, a multipart form holding the mapping of the file followed by the file itself.
+// The mapping is validated before the file is written to disk and validated against it.
+func SaveCSVIngestFile(location string, request *http.Request, validator IngestValidator) (IngestTaskParams, error) {
+	var mapping *ingest.CSVMapping
+
+	multipartReader, err := request.MultipartReader()
+	if err != nil {
+		return IngestTaskParams{}, fmt.Errorf("%w: %v", ErrInvalidCSVUpload, err)
+	}
+
+	for {
+		part, err := multipartReader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return IngestTaskParams{}, fmt.Errorf("%w: the %s field is required", ErrInvalidCSVUpload, CSVFormFieldFile)
+		} else if err != nil {
+			return IngestTaskParams{}, fmt.Errorf("%w: %v", ErrInvalidCSVUpload, err)
+		}
+
+		switch part.FormName() {
+		case CSVFormFieldMapping:
+			mapping = &ingest.CSVMapping{}
+			if err := json.NewDecoder(part).Decode(mapping); err != nil {
+				return IngestTaskParams{}, fmt.Errorf("%w: the %s field is not a valid csv mapping: %v", ErrInvalidCSVUpload, CSVFormFieldMapping, err)
+			} else if problems := mapping.Validate(); len(problems) > 0 {
+				report := ValidationReport{}
+				for _, problem := range problems {
+					report.CriticalErrors = append(report.CriticalErrors, validationError{Message: "mapping " + problem})
+				}
+
+				return IngestTaskParams{}, report
+			}
+
+		case CSVFormFieldFile:
+			if mapping == nil {
+				return IngestTaskParams{}, fmt.Errorf("%w: the %s field must come before the %s field", ErrInvalidCSVUpload, CSVFormFieldMapping, CSVFormFieldFile)
+			} else if tempFileName, err := WriteAndValidateFile(part, location, validator.CSVFileValidator(*mapping)); err != nil {
+				return IngestTaskParams{}, err
+			} else {
+				return IngestTaskParams{
+					Filename:         tempFileName,
+					FileType:         model.FileTypeCSV,
+					OriginalFilename: uploadedFileName(part.FileName()),
+					CSVMapping:       mapping,
+				}, nil
+			}
+		}
+	}
+}
+
+// convertCSVRow converts a row of a CSV file to the node or edge its mapping describes
+func convertCSVRow(mapping ingest.CSVMapping, columns map[string]int, line int, row []string) CSVRecord {
+	var (
+		record = CSVRecord{Line: line}
+		cell   = func(column string) string {
+			if idx, ok := columns[column]; ok && idx < len(row) {
+				return strings.TrimSpace(row[idx])
+			}
+
+			return ""
+		}
+	)
+
+	if mapping.Node != nil {
+		node := ein.GenericNode{
+			ID:    cell(mapping.Node.IDColumn),
+			Kinds: append([]string{}, mapping.Node.Kinds...),
+		}
+
+		if node.ID == "" {
+			record.Err = fmt.Errorf("column %q holding the node id is empty", mapping.Node.IDColumn)
+			return record
+		} else if mapping.Node.KindsColumn != "" {
+			node.Kinds = append(node.Kinds, splitCSVList(cell(mapping.Node.KindsColumn))...)
+		}
+
+		if node.Properties, record.Err = convertCSVProperties(mapping.Node.Properties, cell); record.Err == nil {
+			record.Node = &node
+		}
+	} else if mapping.Edge != nil {
+		edge := ein.GenericEdge{
+			Start: convertCSVEndpoint(mapping.Edge.Start, cell),
+			End:   convertCSVEndpoint(mapping.Edge.End, cell),
+			Kind:  mapping.Edge.Kind,
+		}
+
+		if mapping.Edge.KindColumn != "" {
+			edge.Kind = cell(mapping.Edge.KindColumn)
+		}
+
+		if edge.Start.Value == "" {
+			record.Err = fmt.Errorf("column %q holding the start of the edge is empty", mapping.Edge.Start.Column)
+		} else if edge.End.Value == "" {
+			record.Err = fmt.Errorf("column %q holding the end of the edge is empty", mapping.Edge.End.Column)
+		} else if edge.Kind == "" {
+			record.Err = fmt.Errorf("column %q holding the kind of the edge is empty", mapping.Edge.KindColumn)
+		} else if edge.Properties, record.Err = convertCSVProperties(mapping.Edge.Properties, cell); record.Err == nil {
+			record.Edge = &edge
+		}
+	}
+
+	return record
+}
+
+func convertCSVEndpoint(mapping ingest.CSVEndpointMapping, cell func(column string) string) ein.EdgeEndpoint {
+	return ein.EdgeEndpoint{
+		Value:         cell(mapping.Column),
+		Kind:          mapping.Kind,
+		MatchBy:       mapping.MatchStrategy(),
+		Property:      mapping.Property,
+		CaseSensitive: mapping.CaseSensitive,
+	}
+}
+
+func convertCSVProperties(mappings []ingest.CSVPropertyMapping, cell func(column string) string) (map[string]any, error) {
+	properties := make(map[string]any, len(mappings))
+
+	for _, mapping := range mappings {
+		value := cell(mapping.Column)
+		if value == "" {
+			continue
+		}
+
+		var err error
+		switch mapping.PropertyType() {
+		case ingest.CSVPropertyTypeInteger:
+			properties[mapping.PropertyName()], err = strconv.ParseInt(value, 10, 64)
+		case ingest.CSVPropertyTypeFloat:
+			properties[mapping.PropertyName()], err = strconv.ParseFloat(value, 64)
+		case ingest.CSVPropertyTypeBoolean:
+			properties[mapping.PropertyName()], err = strconv.ParseBool(value)
+		case ingest.CSVPropertyTypeArray:
+			properties[mapping.PropertyName()] = splitCSVList(value)
+		default:
+			properties[mapping.PropertyName()] = value
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("column %q is not a valid %s: %q", mapping.Column, mapping.PropertyType(), value)
+		}
+	}
+
+	return properties, nil
+}
+
+// splitCSVList splits a cell holding a list of values, dropping empty values
+func splitCSVList(value string) []string {
+	var values []string
+
+	for _, item := range strings.Split(value, ingest.CSVListSeparator) {
+		if trimmed := strings.TrimSpace(item); trimmed != "" {
+			values = append(values, trimmed)
+		}
+	}
+
+	return values
+}
+
+// csvNodeDocument and csvEdgeDocument write a converted row in the shape of the nodes and edges of a JSON OpenGraph
+// payload, so that they can be validated against the same schemas
+func csvNodeDocument(node ein.GenericNode) map[string]any {
+	return map[string]any{
+		"id":         node.ID,
+		"kinds":      node.Kinds,
+		"properties": node.Properties,
+	}
+}
+
+func csvEdgeDocument(edge ein.GenericEdge) map[string]any {
+	endpoint := func(endpoint ein.EdgeEndpoint) map[string]any {
+		document := map[string]any{
+			"value":    endpoint.Value,
+			"match_by": endpoint.MatchBy,
+		}
+
+		if endpoint.Kind != "" {
+			document["kind"] = endpoint.Kind
+		}
+
+		if endpoint.Property != "" {
+			document["property"] = endpoint.Property
+			document["case_sensitive"] = endpoint.CaseSensitive
+		}
+
+		return document
+	}
+
+	return map[string]any{
+		"start":      endpoint(edge.Start),
+		"end":        endpoint(edge.End),
+		"kind":       edge.Kind,
+		"properties": edge.Properties,
+	}
+}
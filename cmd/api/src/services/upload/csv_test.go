@@ -0,0 +1,265 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package upload
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
+	"github.com/specterops/bloodhound/packages/go/ein"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testCSVNodeMapping = ingest.CSVMapping{
+		SourceKind: "GithubBase",
+		Node: &ingest.CSVNodeMapping{
+			IDColumn:    "id",
+			Kinds:       []string{"GithubUser"},
+			KindsColumn: "roles",
+			Properties: []ingest.CSVPropertyMapping{
+				{Column: "login"},
+				{Column: "repos", Name: "repo_count", Type: ingest.CSVPropertyTypeInteger},
+				{Column: "admin", Type: ingest.CSVPropertyTypeBoolean},
+				{Column: "emails", Type: ingest.CSVPropertyTypeArray},
+			},
+		},
+	}
+	testCSVEdgeMapping = ingest.CSVMapping{
+		Delimiter: "\t",
+		Edge: &ingest.CSVEdgeMapping{
+			KindColumn: "kind",
+			Start:      ingest.CSVEndpointMapping{Column: "user"},
+			End:        ingest.CSVEndpointMapping{Column: "team", MatchBy: ingest.CSVMatchByProperty, Property: "slug", Kind: "GithubTeam"},
+		},
+	}
+)
+
+func csvValidationMessages(t *testing.T, err error) ([]string, []string) {
+	report, ok := err.(ValidationReport)
+	require.True(t, ok, "expected a ValidationReport, got %v", err)
+
+	var critical, validation []string
+	for _, criticalErr := range report.CriticalErrors {
+		critical = append(critical, criticalErr.Message)
+	}
+	for _, valErr := range report.ValidationErrors {
+		validation = append(validation, valErr.Message)
+	}
+
+	return critical, validation
+}
+
+func TestScanCSV(t *testing.T) {
+	var records []CSVRecord
+
+	err := ScanCSV(strings.NewReader("id, login,repos,admin,emails,roles\n1,bob,3,true,a@x.com; b@x.com,Admin\n2,,,,,\n3,eve,many,,,\n"), testCSVNodeMapping, func(record CSVRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	assert.Equal(t, CSVRecord{Line: 2, Node: &ein.GenericNode{
+		ID:    "1",
+		Kinds: []string{"GithubUser", "Admin"},
+		Properties: map[string]any{
+			"login":      "bob",
+			"repo_count": int64(3),
+			"admin":      true,
+			"emails":     []string{"a@x.com", "b@x.com"},
+		},
+	}}, records[0])
+	assert.Equal(t, CSVRecord{Line: 3, Node: &ein.GenericNode{ID: "2", Kinds: []string{"GithubUser"}, Properties: map[string]any{}}}, records[1])
+	assert.Equal(t, 4, records[2].Line)
+	assert.EqualError(t, records[2].Err, `column "repos" is not a valid integer: "many"`)
+
+	t.Run("edges", func(t *testing.T) {
+		var records []CSVRecord
+
+		require.NoError(t, ScanCSV(strings.NewReader("user\tteam\tkind\n1\tcore\tGithubMemberOf\n"), testCSVEdgeMapping, func(record CSVRecord) error {
+			records = append(records, record)
+			return nil
+		}))
+		require.Len(t, records, 1)
+		assert.Equal(t, &ein.GenericEdge{
+			Start:      ein.EdgeEndpoint{Value: "1", MatchBy: ingest.CSVMatchByID},
+			End:        ein.EdgeEndpoint{Value: "core", MatchBy: ingest.CSVMatchByProperty, Property: "slug", Kind: "GithubTeam"},
+			Kind:       "GithubMemberOf",
+			Properties: map[string]any{},
+		}, records[0].Edge)
+	})
+
+	t.Run("fails when the header is missing mapped columns", func(t *testing.T) {
+		err := ScanCSV(strings.NewReader("id,login\n1,bob\n"), testCSVNodeMapping, func(record CSVRecord) error { return nil })
+		assert.EqualError(t, err, "csv header is missing mapped columns: roles, repos, admin, emails")
+	})
+
+	t.Run("fails on an empty file", func(t *testing.T) {
+		err := ScanCSV(strings.NewReader(""), testCSVNodeMapping, func(record CSVRecord) error { return nil })
+		assert.EqualError(t, err, "csv file is empty. a header row is required")
+	})
+}
+
+func TestValidateCSV(t *testing.T) {
+	schema, err := LoadIngestSchema()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		mapping          ingest.CSVMapping
+		payload          string
+		expectedCritical []string
+		expectedErrors   []string
+	}{
+		{
+			name:    "valid nodes",
+			mapping: testCSVNodeMapping,
+			payload: "id,login,repos,admin,emails,roles\n1,bob,3,true,a@x.com,\n2,alice,,false,,Owner\n",
+		},
+		{
+			name:    "valid edges",
+			mapping: testCSVEdgeMapping,
+			payload: "user\tteam\tkind\n1\tcore\tGithubMemberOf\n2\tcore\tGithubMaintains\n",
+		},
+		{
+			name:    "invalid rows are reported by line",
+			mapping: testCSVNodeMapping,
+			payload: "id,login,repos,admin,emails,roles\n,bob,,,,\n2,alice,,maybe,,\n3,eve,,,,Admin;Owner;Member\n",
+			expectedErrors: []string{
+				`line 2 column "id" holding the node id is empty`,
+				`line 3 column "admin" is not a valid boolean: "maybe"`,
+				"line 4 (node) schema validation failed with 1 error(s): [at '/kinds': maxItems: got 4, want 3]",
+			},
+		},
+		{
+			name:    "empty edge kind",
+			mapping: testCSVEdgeMapping,
+			payload: "user\tteam\tkind\n1\tcore\t\n",
+			expectedErrors: []string{
+				`line 2 column "kind" holding the kind of the edge is empty`,
+			},
+		},
+		{
+			name:             "header only",
+			mapping:          testCSVNodeMapping,
+			payload:          "id,login,repos,admin,emails,roles\n",
+			expectedCritical: []string{"csv file has no rows. at least one row after the header is required"},
+		},
+		{
+			name:             "missing columns",
+			mapping:          testCSVEdgeMapping,
+			payload:          "user\tteam\n1\tcore\n",
+			expectedCritical: []string{"csv header is missing mapped columns: kind"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCSV(strings.NewReader(tt.payload), tt.mapping, schema)
+			if len(tt.expectedCritical) == 0 && len(tt.expectedErrors) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			critical, validation := csvValidationMessages(t, err)
+			assert.Equal(t, tt.expectedCritical, critical)
+			assert.Equal(t, tt.expectedErrors, validation)
+		})
+	}
+}
+
+func newCSVUploadRequest(t *testing.T, fields ...[2]string) *http.Request {
+	var (
+		body   = &bytes.Buffer{}
+		writer = multipart.NewWriter(body)
+	)
+
+	for _, field := range fields {
+		require.NoError(t, writer.WriteField(field[0], field[1]))
+	}
+	require.NoError(t, writer.Close())
+
+	request := httptest.NewRequest(http.MethodPost, "/api/v2/file-upload/1", body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	return request
+}
+
+func TestSaveCSVIngestFile(t *testing.T) {
+	schema, err := LoadIngestSchema()
+	require.NoError(t, err)
+
+	var (
+		validator = NewIngestValidator(schema)
+		mapping   = `{"source_kind": "GithubBase", "node": {"id_column": "id", "kinds": ["GithubUser"]}}`
+		payload   = "id\n1\n2\n"
+	)
+
+	t.Run("saves a valid file with its mapping", func(t *testing.T) {
+		params, err := SaveCSVIngestFile(t.TempDir(), newCSVUploadRequest(t, [2]string{CSVFormFieldMapping, mapping}, [2]string{CSVFormFieldFile, payload}), validator)
+		require.NoError(t, err)
+		assert.Equal(t, model.FileTypeCSV, params.FileType)
+		assert.Equal(t, &ingest.CSVMapping{SourceKind: "GithubBase", Node: &ingest.CSVNodeMapping{IDColumn: "id", Kinds: []string{"GithubUser"}}}, params.CSVMapping)
+
+		content, err := os.ReadFile(params.Filename)
+		require.NoError(t, err)
+		assert.Equal(t, payload, string(content))
+	})
+
+	t.Run("rejects a file sent before its mapping", func(t *testing.T) {
+		_, err := SaveCSVIngestFile(t.TempDir(), newCSVUploadRequest(t, [2]string{CSVFormFieldFile, payload}, [2]string{CSVFormFieldMapping, mapping}), validator)
+		assert.ErrorIs(t, err, ErrInvalidCSVUpload)
+		assert.ErrorContains(t, err, "the mapping field must come before the file field")
+	})
+
+	t.Run("rejects a request without a file", func(t *testing.T) {
+		_, err := SaveCSVIngestFile(t.TempDir(), newCSVUploadRequest(t, [2]string{CSVFormFieldMapping, mapping}), validator)
+		assert.ErrorIs(t, err, ErrInvalidCSVUpload)
+	})
+
+	t.Run("reports an invalid mapping without writing the file", func(t *testing.T) {
+		location := t.TempDir()
+
+		_, err := SaveCSVIngestFile(location, newCSVUploadRequest(t, [2]string{CSVFormFieldMapping, `{"node": {}}`}, [2]string{CSVFormFieldFile, payload}), validator)
+		critical, _ := csvValidationMessages(t, err)
+		assert.Equal(t, []string{"mapping node.id_column is required", "mapping node must have kinds, a kinds_column or a source_kind"}, critical)
+
+		entries, err := os.ReadDir(location)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("reports invalid rows", func(t *testing.T) {
+		_, err := SaveCSVIngestFile(t.TempDir(), newCSVUploadRequest(t, [2]string{CSVFormFieldMapping, mapping}, [2]string{CSVFormFieldFile, "id\n\n"}), validator)
+		critical, _ := csvValidationMessages(t, err)
+		assert.Equal(t, []string{"csv file has no rows. at least one row after the header is required"}, critical)
+	})
+}
+
+func TestFileTypeFromHeader_CSV(t *testing.T) {
+	fileType, err := FileTypeFromHeader(http.Header{"Content-Type": []string{"multipart/form-data; boundary=abc"}})
+	require.NoError(t, err)
+	assert.Equal(t, model.FileTypeCSV, fileType)
+}
@@ -19,6 +19,7 @@ package upload
 import (
 	"io"
 
+	"github.com/specterops/bloodhound/cmd/api/src/model"
 	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
 	"github.com/specterops/bloodhound/packages/go/bomenc"
 )
@@ -27,7 +28,7 @@ import (
 // It receives a source reader (src) and a destination writer (dst).
 // Implementations are responsible for validating the input stream,
 // while simultaneously copying it to the destination for persistence.
-// This abstraction supports format-agnostic payloads (e.g., JSON, ZIP)
+// This abstraction supports format-agnostic payloads (e.g., JSON, ZIP, GZIP)
 type FileValidator func(src io.Reader, dst io.Writer) (ingest.Metadata, error)
 
 // WriteAndValidateZIP implements FileValidator for ZIP ingest files.
@@ -36,6 +37,18 @@ func WriteAndValidateZip(src io.Reader, dst io.Writer) (ingest.Metadata, error)
 	return ingest.Metadata{}, ValidateZipFile(tr)
 }
 
+// WriteAndValidateGzip implements FileValidator for gzip and tar.gz ingest files.
+func WriteAndValidateGzip(src io.Reader, dst io.Writer) (ingest.Metadata, error) {
+	tr := io.TeeReader(src, dst)
+	return ingest.Metadata{}, ValidateGzipFile(tr)
+}
+
+// WriteAndValidateZstd implements FileValidator for zstd ingest files.
+func WriteAndValidateZstd(src io.Reader, dst io.Writer) (ingest.Metadata, error) {
+	tr := io.TeeReader(src, dst)
+	return ingest.Metadata{}, ValidateZstdFile(tr)
+}
+
 // IngestValidator encapsulates precompiled JSON schemas used to validate
 // graph ingest payloads, including node and edge definitions.
 //
@@ -51,6 +64,27 @@ func NewIngestValidator(schema IngestSchema) IngestValidator {
 	}
 }
 
+// FileValidatorFor returns the FileValidator responsible for the given ingest file type.
+func (s *IngestValidator) FileValidatorFor(fileType model.FileType) FileValidator {
+	switch fileType {
+	case model.FileTypeZip:
+		return WriteAndValidateZip
+	case model.FileTypeGzip, model.FileTypeTarGzip:
+		return WriteAndValidateGzip
+	case model.FileTypeZstd:
+		return WriteAndValidateZstd
+	case model.FileTypeNDJSON:
+		return s.WriteAndValidateNDJSON
+	case model.FileTypeCSV:
+		// CSV files can only be validated against the mapping they are uploaded with, see CSVFileValidator
+		return func(src io.Reader, dst io.Writer) (ingest.Metadata, error) {
+			return ingest.Metadata{}, ingest.ErrCSVMappingMissing
+		}
+	default:
+		return s.WriteAndValidateJSON
+	}
+}
+
 // WriteAndValidateJSON implements FileValidator for JSON ingest files.
 // It streams JSON through a validator while simultaneously writing it to disk.
 //
@@ -66,3 +100,15 @@ func (s *IngestValidator) WriteAndValidateJSON(src io.Reader, dst io.Writer) (in
 
 	return metatag, err
 }
+
+// WriteAndValidateNDJSON implements FileValidator for NDJSON ingest files. Every record is validated against the same
+// node and edge schemas as a JSON payload while the file is written to disk.
+func (s *IngestValidator) WriteAndValidateNDJSON(src io.Reader, dst io.Writer) (ingest.Metadata, error) {
+	normalizedContent, err := bomenc.NormalizeToUTF8(src)
+	if err != nil {
+		return ingest.Metadata{}, err
+	}
+
+	tr := io.TeeReader(normalizedContent, dst)
+	return ingest.Metadata{Type: ingest.DataTypeOpenGraph}, ValidateNDJSON(tr, s.IngestSchema)
+}
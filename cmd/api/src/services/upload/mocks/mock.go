@@ -87,6 +87,20 @@ func (mr *MockUploadDataMockRecorder) CreateCompositionInfo(ctx, nodes, edges an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCompositionInfo", reflect.TypeOf((*MockUploadData)(nil).CreateCompositionInfo), ctx, nodes, edges)
 }
 
+// CreateIngestFileResults mocks base method.
+func (m *MockUploadData) CreateIngestFileResults(ctx context.Context, results model.IngestFileResults) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIngestFileResults", ctx, results)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateIngestFileResults indicates an expected call of CreateIngestFileResults.
+func (mr *MockUploadDataMockRecorder) CreateIngestFileResults(ctx, results any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIngestFileResults", reflect.TypeOf((*MockUploadData)(nil).CreateIngestFileResults), ctx, results)
+}
+
 // CreateIngestJob mocks base method.
 func (m *MockUploadData) CreateIngestJob(ctx context.Context, job model.IngestJob) (model.IngestJob, error) {
 	m.ctrl.T.Helper()
@@ -161,6 +175,22 @@ func (mr *MockUploadDataMockRecorder) GetAllIngestJobs(ctx, skip, limit, order,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllIngestJobs", reflect.TypeOf((*MockUploadData)(nil).GetAllIngestJobs), ctx, skip, limit, order, filter)
 }
 
+// GetIngestFileResultsForJob mocks base method.
+func (m *MockUploadData) GetIngestFileResultsForJob(ctx context.Context, jobID int64, skip, limit int) (model.IngestFileResults, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngestFileResultsForJob", ctx, jobID, skip, limit)
+	ret0, _ := ret[0].(model.IngestFileResults)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetIngestFileResultsForJob indicates an expected call of GetIngestFileResultsForJob.
+func (mr *MockUploadDataMockRecorder) GetIngestFileResultsForJob(ctx, jobID, skip, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngestFileResultsForJob", reflect.TypeOf((*MockUploadData)(nil).GetIngestFileResultsForJob), ctx, jobID, skip, limit)
+}
+
 // GetIngestJob mocks base method.
 func (m *MockUploadData) GetIngestJob(ctx context.Context, id int64) (model.IngestJob, error) {
 	m.ctrl.T.Helper()
@@ -191,6 +221,21 @@ func (mr *MockUploadDataMockRecorder) GetIngestJobsWithStatus(ctx, status any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngestJobsWithStatus", reflect.TypeOf((*MockUploadData)(nil).GetIngestJobsWithStatus), ctx, status)
 }
 
+// GetIngestTask mocks base method.
+func (m *MockUploadData) GetIngestTask(ctx context.Context, id int64) (model.IngestTask, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngestTask", ctx, id)
+	ret0, _ := ret[0].(model.IngestTask)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIngestTask indicates an expected call of GetIngestTask.
+func (mr *MockUploadDataMockRecorder) GetIngestTask(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngestTask", reflect.TypeOf((*MockUploadData)(nil).GetIngestTask), ctx, id)
+}
+
 // UpdateIngestJob mocks base method.
 func (m *MockUploadData) UpdateIngestJob(ctx context.Context, job model.IngestJob) error {
 	m.ctrl.T.Helper()
@@ -204,3 +249,17 @@ func (mr *MockUploadDataMockRecorder) UpdateIngestJob(ctx, job any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIngestJob", reflect.TypeOf((*MockUploadData)(nil).UpdateIngestJob), ctx, job)
 }
+
+// UpdateIngestTask mocks base method.
+func (m *MockUploadData) UpdateIngestTask(ctx context.Context, task model.IngestTask) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateIngestTask", ctx, task)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateIngestTask indicates an expected call of UpdateIngestTask.
+func (mr *MockUploadDataMockRecorder) UpdateIngestTask(ctx, task any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIngestTask", reflect.TypeOf((*MockUploadData)(nil).UpdateIngestTask), ctx, task)
+}
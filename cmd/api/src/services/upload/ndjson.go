@@ -0,0 +1,177 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package upload
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// Record types of an NDJSON OpenGraph payload. Every line of the payload is a JSON object with exactly one of these
+// keys, holding the same object that would be an element of the matching section of a JSON payload, e.g.:
+//
+//	{"metadata": {"source_kind": "GithubBase"}}
+//	{"node": {"id": "1", "kinds": ["GithubUser"], "properties": {"login": "bob"}}}
+//	{"edge": {"start": {"value": "1"}, "end": {"value": "2"}, "kind": "GithubMemberOf"}}
+const (
+	NDJSONMetadata    = "metadata"
+	NDJSONNode        = "node"
+	NDJSONEdge        = "edge"
+	NDJSONDeletedNode = "deleted_node"
+	NDJSONDeletedEdge = "deleted_edge"
+)
+
+// ErrStopNDJSONScan can be returned by the delegate of ScanNDJSON to stop reading the payload without failing
+var ErrStopNDJSONScan = errors.New("stop scanning ndjson payload")
+
+// NDJSONRecord is a single line of an NDJSON OpenGraph payload
+type NDJSONRecord struct {
+	Line  int
+	Type  string
+	Value json.RawMessage
+}
+
+// ScanNDJSON reads an NDJSON OpenGraph payload and passes each of its records to delegate, in order. Blank lines are
+// skipped. Records are not validated against the ingest schema; see ValidateNDJSON for that. Reading stops at the
+// first line that is not a record, or at the first error returned by delegate.
+func ScanNDJSON(reader io.Reader, delegate func(record NDJSONRecord) error) error {
+	return readNDJSONLines(reader, func(lineNumber int, line []byte) error {
+		if recordType, value, err := parseNDJSONLine(line); err != nil {
+			return fmt.Errorf("line %d: %w", lineNumber, err)
+		} else {
+			return delegate(NDJSONRecord{Line: lineNumber, Type: recordType, Value: value})
+		}
+	})
+}
+
+// ValidateNDJSON validates an NDJSON OpenGraph payload line by line. Each record is validated against the same JSON
+// Schema used for the matching section of a JSON payload, along with the property constraints enforced by
+// ValidateGraph. A metadata record is only allowed as the first record, and at least one node, edge or deletion record
+// is required.
+//
+// The payload is always read to the end so that it can be teed to disk while it is validated. If any record is
+// invalid a ValidationReport is returned as an error.
+func ValidateNDJSON(reader io.Reader, schema IngestSchema) error {
+	var (
+		v = &validator{
+			maxErrors: 15,
+		}
+		schemas = map[string]*jsonschema.Schema{
+			NDJSONMetadata:    schema.MetaSchema,
+			NDJSONNode:        schema.NodeSchema,
+			NDJSONEdge:        schema.EdgeSchema,
+			NDJSONDeletedNode: schema.DeletedNodeSchema,
+			NDJSONDeletedEdge: schema.DeletedEdgeSchema,
+		}
+		recordCount      = 0
+		graphRecordFound = false
+	)
+
+	if err := readNDJSONLines(reader, func(lineNumber int, line []byte) error {
+		if len(v.validationErrors) >= v.maxErrors {
+			return nil
+		}
+
+		recordType, value, err := parseNDJSONLine(line)
+		if err != nil {
+			v.reportValidation(lineNumber, fmt.Sprintf("line %d %s", lineNumber, err))
+			return nil
+		}
+
+		location := fmt.Sprintf("line %d (%s)", lineNumber, recordType)
+		recordSchema, ok := schemas[recordType]
+		if !ok {
+			v.reportValidation(lineNumber, fmt.Sprintf("%s unknown record type", location))
+			return nil
+		} else if recordType == NDJSONMetadata && recordCount > 0 {
+			v.reportValidation(lineNumber, fmt.Sprintf("%s metadata must be the first record", location))
+		}
+
+		recordCount++
+		if recordType != NDJSONMetadata {
+			graphRecordFound = true
+		}
+
+		var item map[string]any
+		if err := json.Unmarshal(value, &item); err != nil {
+			v.reportValidation(lineNumber, fmt.Sprintf("%s type mismatch: %s", location, err))
+		} else if err := recordSchema.Validate(item); err != nil {
+			v.reportValidation(lineNumber, formatSchemaValidationError(location, err))
+		}
+
+		v.validateProperties(location, lineNumber, item)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if !graphRecordFound && !v.hasErrors() {
+		v.reportCritical(0, "ndjson payload is empty. at least one node, edge, deleted_node or deleted_edge record is required")
+	}
+
+	return v.report()
+}
+
+// readNDJSONLines calls delegate with every non-blank line of the reader given, along with its 1-based line number
+func readNDJSONLines(reader io.Reader, delegate func(lineNumber int, line []byte) error) error {
+	var (
+		bufferedReader = bufio.NewReader(reader)
+		lineNumber     = 0
+	)
+
+	for {
+		line, err := bufferedReader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		lineNumber++
+		if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+			if err := delegate(lineNumber, trimmed); errors.Is(err, ErrStopNDJSONScan) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+	}
+}
+
+// parseNDJSONLine splits a line of an NDJSON OpenGraph payload into its record type and value
+func parseNDJSONLine(line []byte) (string, json.RawMessage, error) {
+	var record map[string]json.RawMessage
+	if err := json.Unmarshal(line, &record); err != nil {
+		return "", nil, fmt.Errorf("syntax error: %w", err)
+	} else if len(record) != 1 {
+		return "", nil, fmt.Errorf("record must have exactly one key, found %d", len(record))
+	}
+
+	for recordType, value := range record {
+		return recordType, value, nil
+	}
+
+	return "", nil, nil
+}
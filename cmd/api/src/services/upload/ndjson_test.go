@@ -0,0 +1,150 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package upload
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNDJSON(t *testing.T) {
+	schema, err := LoadIngestSchema()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		payload          string
+		expectedCritical []string
+		expectedErrors   []string
+	}{
+		{
+			name: "valid payload",
+			payload: `{"metadata": {"source_kind": "GithubBase"}}
+{"node": {"id": "1", "kinds": ["GithubUser"], "properties": {"login": "bob"}}}
+
+{"edge": {"start": {"value": "1"}, "end": {"value": "2"}, "kind": "GithubMemberOf"}}
+{"node": {"id": "2", "kinds": ["GithubTeam"]}}
+{"deleted_node": {"value": "3"}}
+{"deleted_edge": {"start": {"value": "1"}, "end": {"value": "4"}, "kind": "GithubMemberOf"}}`,
+		},
+		{
+			name:    "valid payload without metadata and with CRLF line endings",
+			payload: "{\"node\": {\"id\": \"1\", \"kinds\": [\"GithubUser\"]}}\r\n{\"node\": {\"id\": \"2\", \"kinds\": [\"GithubUser\"]}}\n",
+		},
+		{
+			name: "invalid records are reported by line",
+			payload: `{"node": {"id": "1", "kinds": ["GithubUser"]}}
+{"node": {"kinds": ["GithubUser"]}}
+{"edge": {"start": {"value": "1"}, "end": {"value": "2"}, "kind": "GithubMemberOf"}
+{"node": {"id": "2"}, "edge": {}}
+{"group": {"id": "3"}}
+{"node": {"id": "4", "kinds": ["GithubUser"], "properties": {"emails": ["a", 1]}}}
+{"metadata": {"source_kind": "GithubBase"}}`,
+			expectedErrors: []string{
+				"line 2 (node) schema validation failed with 1 error(s): [at '': missing property 'id']",
+				"line 3 syntax error: unexpected end of JSON input",
+				"line 4 record must have exactly one key, found 2",
+				"line 5 (group) unknown record type",
+				"line 6 (node) schema validation error. properties[\"emails\"] contains a mixed-type array",
+				"line 7 (metadata) metadata must be the first record",
+			},
+		},
+		{
+			name:             "empty payload",
+			payload:          "\n\n",
+			expectedCritical: []string{"ndjson payload is empty. at least one node, edge, deleted_node or deleted_edge record is required"},
+		},
+		{
+			name:             "metadata only",
+			payload:          `{"metadata": {"source_kind": "GithubBase"}}`,
+			expectedCritical: []string{"ndjson payload is empty. at least one node, edge, deleted_node or deleted_edge record is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNDJSON(strings.NewReader(tt.payload), schema)
+			if len(tt.expectedCritical) == 0 && len(tt.expectedErrors) == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			report, ok := err.(ValidationReport)
+			require.True(t, ok, "expected a ValidationReport, got %v", err)
+
+			var critical, validation []string
+			for _, criticalErr := range report.CriticalErrors {
+				critical = append(critical, criticalErr.Message)
+			}
+			for _, valErr := range report.ValidationErrors {
+				validation = append(validation, valErr.Message)
+			}
+
+			assert.Equal(t, tt.expectedCritical, critical)
+			assert.Equal(t, tt.expectedErrors, validation)
+		})
+	}
+}
+
+func TestScanNDJSON(t *testing.T) {
+	var records []NDJSONRecord
+
+	err := ScanNDJSON(strings.NewReader("{\"metadata\": {}}\n\n{\"node\": {\"id\": \"1\"}}\n"), func(record NDJSONRecord) error {
+		records = append(records, record)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, NDJSONRecord{Line: 1, Type: NDJSONMetadata, Value: []byte(`{}`)}, records[0])
+	assert.Equal(t, NDJSONRecord{Line: 3, Type: NDJSONNode, Value: []byte(`{"id": "1"}`)}, records[1])
+
+	t.Run("stops when asked", func(t *testing.T) {
+		count := 0
+		require.NoError(t, ScanNDJSON(strings.NewReader("{\"node\": {}}\n{\"node\": {}}"), func(record NDJSONRecord) error {
+			count++
+			return ErrStopNDJSONScan
+		}))
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("fails on a malformed line", func(t *testing.T) {
+		err := ScanNDJSON(strings.NewReader("{\"node\": {}}\nnot json"), func(record NDJSONRecord) error { return nil })
+		assert.ErrorContains(t, err, "line 2: syntax error")
+	})
+}
+
+func TestWriteAndValidateNDJSON(t *testing.T) {
+	schema, err := LoadIngestSchema()
+	require.NoError(t, err)
+
+	var (
+		v       = NewIngestValidator(schema)
+		payload = append([]byte{0xEF, 0xBB, 0xBF}, []byte(`{"node": {"id": "1", "kinds": ["GithubUser"]}}`)...)
+		dst     = &bytes.Buffer{}
+	)
+
+	meta, err := v.FileValidatorFor(model.FileTypeNDJSON)(bytes.NewReader(payload), dst)
+	require.NoError(t, err)
+	assert.Equal(t, ingest.Metadata{Type: ingest.DataTypeOpenGraph}, meta)
+	assert.Equal(t, payload[3:], dst.Bytes())
+}
@@ -28,13 +28,15 @@ import (
 var schemaFiles embed.FS
 
 // IngestSchema holds compiled JSON schemas used to validate
-// generic-ingested graph data. It includes separate schemas for nodes
-// and edges, which are reused across multiple ingestion requests
-// to avoid recompiling on every request.
+// generic-ingested graph data. It includes separate schemas for nodes,
+// edges and the nodes and edges to delete, which are reused across
+// multiple ingestion requests to avoid recompiling on every request.
 type IngestSchema struct {
-	NodeSchema *jsonschema.Schema
-	EdgeSchema *jsonschema.Schema
-	MetaSchema *jsonschema.Schema
+	NodeSchema        *jsonschema.Schema
+	EdgeSchema        *jsonschema.Schema
+	DeletedNodeSchema *jsonschema.Schema
+	DeletedEdgeSchema *jsonschema.Schema
+	MetaSchema        *jsonschema.Schema
 }
 
 // LoadIngestSchema constructs the JSON schema for OpenGraph ingest payloads
@@ -44,11 +46,17 @@ func LoadIngestSchema() (IngestSchema, error) {
 		return schema, err
 	} else if edgeSchema, err := loadSchema("edge.json"); err != nil {
 		return schema, err
+	} else if deletedNodeSchema, err := loadSchema("deleted_node.json"); err != nil {
+		return schema, err
+	} else if deletedEdgeSchema, err := loadSchema("deleted_edge.json"); err != nil {
+		return schema, err
 	} else if metaSchema, err := loadSchema("metadata.json"); err != nil {
 		return schema, err
 	} else {
 		schema.NodeSchema = nodeSchema
 		schema.EdgeSchema = edgeSchema
+		schema.DeletedNodeSchema = deletedNodeSchema
+		schema.DeletedEdgeSchema = deletedEdgeSchema
 		schema.MetaSchema = metaSchema
 		return schema, nil
 	}
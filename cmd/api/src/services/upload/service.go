@@ -27,6 +27,8 @@ import (
 type UploadData interface {
 	// Task handlers
 	CreateIngestTask(ctx context.Context, task model.IngestTask) (model.IngestTask, error)
+	GetIngestTask(ctx context.Context, id int64) (model.IngestTask, error)
+	UpdateIngestTask(ctx context.Context, task model.IngestTask) error
 	DeleteAllIngestTasks(ctx context.Context) error
 	CreateCompositionInfo(ctx context.Context, nodes model.EdgeCompositionNodes, edges model.EdgeCompositionEdges) (model.EdgeCompositionNodes, model.EdgeCompositionEdges, error)
 
@@ -38,4 +40,8 @@ type UploadData interface {
 	GetIngestJobsWithStatus(ctx context.Context, status model.JobStatus) ([]model.IngestJob, error)
 	DeleteAllIngestJobs(ctx context.Context) error
 	CancelAllIngestJobs(ctx context.Context) error
+
+	// File result handlers
+	CreateIngestFileResults(ctx context.Context, results model.IngestFileResults) error
+	GetIngestFileResultsForJob(ctx context.Context, jobID int64, skip int, limit int) (model.IngestFileResults, int, error)
 }
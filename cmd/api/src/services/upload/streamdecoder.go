@@ -18,21 +18,28 @@ package upload
 
 import (
 	"archive/zip"
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"reflect"
+	"slices"
 	"strings"
 
 	"github.com/santhosh-tekuri/jsonschema/v6"
 	"github.com/santhosh-tekuri/jsonschema/v6/kind"
 
 	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
+	"github.com/specterops/bloodhound/packages/go/ein"
 )
 
-var ZipMagicBytes = []byte{0x50, 0x4b, 0x03, 0x04}
+var (
+	ZipMagicBytes  = []byte{0x50, 0x4b, 0x03, 0x04}
+	GzipMagicBytes = []byte{0x1f, 0x8b}
+	ZstdMagicBytes = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
 
 // ParseAndValidatePayload scans a JSON stream to detect and validate the metadata tag
 // required for ingesting graph data. It ensures that either top-level "meta" and "data" tags
@@ -62,8 +69,9 @@ func ParseAndValidatePayload(reader io.Reader, schema IngestSchema, shouldValida
 }
 
 // ValidateGraph validates a generic ingest graph payload from a JSON stream.
-// The input is expected to be a JSON object containing one or both of the keys
-// "nodes" and "edges", each mapping to an array of graph elements.
+// The input is expected to be a JSON object containing one or more of the keys
+// "nodes", "edges", "deleted_nodes" and "deleted_edges", each mapping to an array
+// of graph elements.
 // Each element is validated against the corresponding JSON Schema provided in the
 // IngestSchema struct. In addition to schema validation, this function enforces
 // constraints not expressible in JSON Schema, such as nested objects and type homogeneity in
@@ -74,11 +82,13 @@ func ParseAndValidatePayload(reader io.Reader, schema IngestSchema, shouldValida
 // If no errors are found, the function returns nil.
 func ValidateGraph(decoder *json.Decoder, schema IngestSchema) error {
 	v := &validator{
-		decoder:    decoder,
-		nodeSchema: schema.NodeSchema,
-		edgeSchema: schema.EdgeSchema,
-		metaSchema: schema.MetaSchema,
-		maxErrors:  15,
+		decoder:           decoder,
+		nodeSchema:        schema.NodeSchema,
+		edgeSchema:        schema.EdgeSchema,
+		deletedNodeSchema: schema.DeletedNodeSchema,
+		deletedEdgeSchema: schema.DeletedEdgeSchema,
+		metaSchema:        schema.MetaSchema,
+		maxErrors:         15,
 	}
 
 	if err := expectOpenObject(decoder, "graph"); err != nil {
@@ -111,6 +121,18 @@ func ValidateGraph(decoder *json.Decoder, schema IngestSchema) error {
 				if len(v.criticalErrors) > 0 {
 					return v.report()
 				}
+			case "deleted_nodes":
+				v.deletionsFound = true
+				v.validateArray("deleted_nodes", v.deletedNodeSchema)
+				if len(v.criticalErrors) > 0 {
+					return v.report()
+				}
+			case "deleted_edges":
+				v.deletionsFound = true
+				v.validateArray("deleted_edges", v.deletedEdgeSchema)
+				if len(v.criticalErrors) > 0 {
+					return v.report()
+				}
 			}
 
 			if len(v.validationErrors) >= v.maxErrors {
@@ -124,8 +146,8 @@ func ValidateGraph(decoder *json.Decoder, schema IngestSchema) error {
 		return v.report()
 	}
 
-	if !v.nodesFound && !v.edgesFound {
-		v.reportCritical(0, "graph tag is empty. at least one of nodes: [] or edges: [] is required")
+	if !v.nodesFound && !v.edgesFound && !v.deletionsFound {
+		v.reportCritical(0, "graph tag is empty. at least one of nodes: [], edges: [], deleted_nodes: [] or deleted_edges: [] is required")
 	}
 
 	return v.report()
@@ -195,9 +217,11 @@ func decodeMetaTag(decoder *json.Decoder) (ingest.Metadata, error) {
 
 func scanAndDetectMetaOrGraph(scanner *tagScanner, shouldValidateGraph bool, schema IngestSchema) (ingest.Metadata, error) {
 	var (
-		dataFound bool
-		metaFound bool
-		meta      ingest.Metadata
+		dataFound   bool
+		metaFound   bool
+		meta        ingest.Metadata
+		legacyTags  []string
+		recordFound bool
 	)
 
 	for {
@@ -205,6 +229,15 @@ func scanAndDetectMetaOrGraph(scanner *tagScanner, shouldValidateGraph bool, sch
 			return handleScannerError(err, dataFound, metaFound)
 		} else {
 			switch tag {
+			case "type":
+				// APOC JSON exports are a stream of node and relationship records, each with a top level type
+				if recordFound || dataFound || metaFound {
+					break
+				} else if tok, err := scanner.nextToken(); err != nil {
+					return ingest.Metadata{}, ErrInvalidJSON
+				} else if recordType, ok := tok.(string); ok && (recordType == ein.ApocRecordTypeNode || recordType == ein.ApocRecordTypeRelationship) {
+					return ingest.Metadata{Type: ingest.DataTypeApocExport}, nil
+				}
 			case "meta":
 				if m, err := decodeMetaTag(scanner.decoder); err != nil {
 					return m, err
@@ -245,10 +278,19 @@ func scanAndDetectMetaOrGraph(scanner *tagScanner, shouldValidateGraph bool, sch
 					}
 				}
 				return meta, nil
+			default:
+				// v3 collections store their data under a key named after their data type
+				if ingest.DataType(tag).IsValid() {
+					legacyTags = append(legacyTags, tag)
+				}
 			}
 
+			recordFound = true
+
 			if metaFound && dataFound {
 				return meta, nil
+			} else if metaFound && meta.Version <= ingest.FormatVersionV3 && slices.Contains(legacyTags, string(meta.Type)) {
+				return meta, nil
 			}
 		}
 	}
@@ -279,7 +321,12 @@ type ValidationReport struct {
 }
 
 func (s ValidationReport) BuildAPIError() []string {
-	msgs := []string{"Error saving ingest file. File failed schema validation."}
+	return append([]string{"Error saving ingest file. File failed schema validation."}, s.Messages()...)
+}
+
+// Messages returns the message of every critical and validation error in the report, critical errors first
+func (s ValidationReport) Messages() []string {
+	msgs := make([]string, 0, len(s.CriticalErrors)+len(s.ValidationErrors))
 
 	for _, criticalErr := range s.CriticalErrors {
 		msgs = append(msgs, criticalErr.Message)
@@ -305,11 +352,12 @@ func (s ValidationReport) Error() string {
 	return sb.String()
 }
 
-func formatSchemaValidationError(arrayName string, index int, err error) string {
+// formatSchemaValidationError describes a schema validation failure of the element at location, e.g. "nodes[3]"
+func formatSchemaValidationError(location string, err error) string {
 	var sb strings.Builder
 	if ve, ok := err.(*jsonschema.ValidationError); ok {
 		numberOfViolations := len(ve.Causes)
-		sb.WriteString(fmt.Sprintf("%s[%d] schema validation failed with %d error(s): ", arrayName, index, numberOfViolations))
+		sb.WriteString(fmt.Sprintf("%s schema validation failed with %d error(s): ", location, numberOfViolations))
 
 		sb.WriteString("[")
 
@@ -397,34 +445,46 @@ func ReadZippedFile(zf *zip.File) ([]byte, error) {
 }
 
 func ValidateZipFile(reader io.Reader) error {
-	bytes := make([]byte, 4)
-	if readBytes, err := reader.Read(bytes); err != nil {
+	return validateMagicBytes(reader, ZipMagicBytes, ingest.ErrInvalidZipFile)
+}
+
+func ValidateGzipFile(reader io.Reader) error {
+	return validateMagicBytes(reader, GzipMagicBytes, ingest.ErrInvalidGzipFile)
+}
+
+func ValidateZstdFile(reader io.Reader) error {
+	return validateMagicBytes(reader, ZstdMagicBytes, ingest.ErrInvalidZstdFile)
+}
+
+// validateMagicBytes checks that the stream starts with the given file signature and then consumes the remainder of
+// the stream, so that any reader teed off of it receives the complete file.
+func validateMagicBytes(reader io.Reader, magicBytes []byte, invalidErr error) error {
+	header := make([]byte, len(magicBytes))
+	if _, err := io.ReadFull(reader, header); errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return invalidErr
+	} else if err != nil {
 		return err
-	} else if readBytes < 4 {
-		return ingest.ErrInvalidZipFile
+	} else if !bytes.Equal(header, magicBytes) {
+		return invalidErr
 	} else {
-		for i := 0; i < 4; i++ {
-			if bytes[i] != ZipMagicBytes[i] {
-				return ingest.ErrInvalidZipFile
-			}
-		}
-
 		_, err := io.Copy(io.Discard, reader)
-
 		return err
 	}
 }
 
 type validator struct {
-	decoder          *json.Decoder
-	nodeSchema       *jsonschema.Schema
-	edgeSchema       *jsonschema.Schema
-	metaSchema       *jsonschema.Schema
-	maxErrors        int
-	nodesFound       bool
-	edgesFound       bool
-	criticalErrors   []validationError
-	validationErrors []validationError
+	decoder           *json.Decoder
+	nodeSchema        *jsonschema.Schema
+	edgeSchema        *jsonschema.Schema
+	deletedNodeSchema *jsonschema.Schema
+	deletedEdgeSchema *jsonschema.Schema
+	metaSchema        *jsonschema.Schema
+	maxErrors         int
+	nodesFound        bool
+	edgesFound        bool
+	deletionsFound    bool
+	criticalErrors    []validationError
+	validationErrors  []validationError
 }
 
 func (v *validator) reportCritical(index int, msg string) {
@@ -456,16 +516,10 @@ func (v *validator) validateArray(arrayName string, schema *jsonschema.Schema) {
 				v.reportCritical(index, fmt.Sprintf("%s[%d] syntax error: %s", arrayName, index, err))
 			}
 		} else if err := schema.Validate(item); err != nil {
-			v.reportValidation(index, formatSchemaValidationError(arrayName, index, err))
+			v.reportValidation(index, formatSchemaValidationError(fmt.Sprintf("%s[%d]", arrayName, index), err))
 		}
 
-		if props, ok := item["properties"].(map[string]any); ok {
-			for key, val := range props {
-				if arr, ok := val.([]any); ok && !isHomogeneousArray(arr) {
-					v.reportValidation(index, fmt.Sprintf("%s[%d] schema validation error. properties[\"%s\"] contains a mixed-type array", arrayName, index, key))
-				}
-			}
-		}
+		v.validateProperties(fmt.Sprintf("%s[%d]", arrayName, index), index, item)
 
 		if len(v.validationErrors) >= v.maxErrors || len(v.criticalErrors) > 0 {
 			return
@@ -478,6 +532,18 @@ func (v *validator) validateArray(arrayName string, schema *jsonschema.Schema) {
 	}
 }
 
+// validateProperties enforces the constraints on the property bag of the element at location that JSON Schema can not
+// express, such as array-valued properties holding a single type of value
+func (v *validator) validateProperties(location string, index int, item map[string]any) {
+	if props, ok := item["properties"].(map[string]any); ok {
+		for key, val := range props {
+			if arr, ok := val.([]any); ok && !isHomogeneousArray(arr) {
+				v.reportValidation(index, fmt.Sprintf("%s schema validation error. properties[\"%s\"] contains a mixed-type array", location, key))
+			}
+		}
+	}
+}
+
 func (v *validator) report() error {
 	if v.hasErrors() {
 		return ValidationReport{
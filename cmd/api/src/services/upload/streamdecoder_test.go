@@ -68,6 +68,43 @@ func Test_ParseAndValidatePayload(t *testing.T) {
 			rawString: `{"metadata":{"random field": "hello"},"graph": {"nodes":[]}}`,
 			err:       fmt.Errorf("error validating metadata tag: jsonschema validation failed"),
 		},
+		{
+			name:         "successful opengraph metadata with schema",
+			rawString:    `{"metadata":{"source_kind": "GithubBase", "schema": {"node_kinds": [{"name": "GithubUser", "display_name": "GitHub User", "icon": {"type": "font-awesome", "name": "user", "color": "#FFF"}, "properties": [{"name": "login", "type": "string", "indexed": true}]}], "edge_kinds": [{"name": "GithubMemberOf", "traversable": true}]}},"graph": {"nodes":[]}}`,
+			err:          nil,
+			expectedType: ingest.DataTypeOpenGraph,
+		},
+		{
+			name:      "unsuccessful opengraph metadata schema, edge kind missing traversable",
+			rawString: `{"metadata":{"schema": {"edge_kinds": [{"name": "GithubMemberOf"}]}},"graph": {"nodes":[]}}`,
+			err:       fmt.Errorf("error validating metadata tag: jsonschema validation failed"),
+		},
+		{
+			name:      "unsuccessful opengraph metadata schema, invalid property type",
+			rawString: `{"metadata":{"schema": {"node_kinds": [{"name": "GithubUser", "properties": [{"name": "login", "type": "text"}]}]}},"graph": {"nodes":[]}}`,
+			err:       fmt.Errorf("error validating metadata tag: jsonschema validation failed"),
+		},
+		{
+			name:         "successful opengraph metadata schema, required edge property",
+			rawString:    `{"metadata":{"schema": {"edge_kinds": [{"name": "GithubMemberOf", "traversable": true, "properties": [{"name": "risk_score", "type": "float", "required": true}]}]}},"graph": {"nodes":[]}}`,
+			err:          nil,
+			expectedType: ingest.DataTypeOpenGraph,
+		},
+		{
+			name:      "unsuccessful opengraph metadata schema, required node property",
+			rawString: `{"metadata":{"schema": {"node_kinds": [{"name": "GithubUser", "properties": [{"name": "login", "type": "string", "required": true}]}]}},"graph": {"nodes":[]}}`,
+			err:       fmt.Errorf("error validating metadata tag: jsonschema validation failed"),
+		},
+		{
+			name:      "unsuccessful opengraph metadata schema, invalid property name",
+			rawString: `{"metadata":{"schema": {"node_kinds": [{"name": "GithubUser", "properties": [{"name": "login name", "type": "string"}]}]}},"graph": {"nodes":[]}}`,
+			err:       fmt.Errorf("error validating metadata tag: jsonschema validation failed"),
+		},
+		{
+			name:      "unsuccessful opengraph metadata schema, invalid icon color",
+			rawString: `{"metadata":{"schema": {"node_kinds": [{"name": "GithubUser", "icon": {"type": "font-awesome", "name": "user", "color": "blue"}}]}},"graph": {"nodes":[]}}`,
+			err:       fmt.Errorf("error validating metadata tag: jsonschema validation failed"),
+		},
 		{
 			name:         "enforce mutual exclusivity",
 			rawString:    `{"data": [], "graph": {}}`,
@@ -112,6 +149,39 @@ func Test_ParseAndValidatePayload(t *testing.T) {
 			rawString: `{"data": [],"meta": {"methods": 0, "type": "invalid", "count": 0, "version": 5}}`,
 			err:       ingest.ErrMetaTagNotFound,
 		},
+		{
+			name:         "v4 collection",
+			rawString:    `{"data": [],"meta": {"methods": 0, "type": "computers", "count": 0, "version": 4}}`,
+			err:          nil,
+			expectedType: ingest.DataTypeComputer,
+		},
+		{
+			name:         "v3 collection with data under its type",
+			rawString:    `{"computers": [{"Properties": {"objectid": "S-1-5-21-1"}}],"meta": {"type": "computers", "count": 1, "version": 3}}`,
+			err:          nil,
+			expectedType: ingest.DataTypeComputer,
+		},
+		{
+			name:      "v3 collection with data under another type",
+			rawString: `{"users": [],"meta": {"type": "computers", "count": 0, "version": 3}}`,
+			err:       ingest.ErrDataTagNotFound,
+		},
+		{
+			name:      "data under its type in a current collection",
+			rawString: `{"computers": [],"meta": {"type": "computers", "count": 0, "version": 6}}`,
+			err:       ingest.ErrDataTagNotFound,
+		},
+		{
+			name:         "apoc export",
+			rawString:    `{"type":"node","id":"0","labels":["Base","User"],"properties":{"objectid":"S-1-5-21-1"}}` + "\n" + `{"type":"node","id":"1","labels":["Base","Group"],"properties":{"objectid":"S-1-5-21-2"}}`,
+			err:          nil,
+			expectedType: ingest.DataTypeApocExport,
+		},
+		{
+			name:      "type tag that is not an apoc record",
+			rawString: `{"type": "computers"}`,
+			err:       ingest.ErrNoTagFound,
+		},
 	}
 
 	schema, err := LoadIngestSchema()
@@ -191,14 +261,24 @@ type testEdge struct {
 }
 
 type edgePiece struct {
-	Value   string `json:"value,omitempty"`
-	MatchBy string `json:"match_by,omitempty"`
-	Kind    string `json:"kind,omitempty"`
+	Value         string `json:"value,omitempty"`
+	MatchBy       string `json:"match_by,omitempty"`
+	Kind          string `json:"kind,omitempty"`
+	Property      string `json:"property,omitempty"`
+	CaseSensitive bool   `json:"case_sensitive,omitempty"`
+}
+
+type testDeletedEdge struct {
+	Start *edgePiece `json:"start"`
+	End   *edgePiece `json:"end"`
+	Kind  string     `json:"kind,omitempty"`
 }
 
 type testPayload struct {
-	Nodes []testNode `json:"nodes,omitempty"`
-	Edges []testEdge `json:"edges,omitempty"`
+	Nodes        []testNode        `json:"nodes,omitempty"`
+	Edges        []testEdge        `json:"edges,omitempty"`
+	DeletedNodes []edgePiece       `json:"deleted_nodes,omitempty"`
+	DeletedEdges []testDeletedEdge `json:"deleted_edges,omitempty"`
 }
 
 func prepareReader(assertion genericIngestAssertion) (io.Reader, error) {
@@ -228,6 +308,7 @@ func Test_ValidateGraph(t *testing.T) {
 	negativeCases = append(negativeCases, criticalFailureCases()...)
 	negativeCases = append(negativeCases, nodeSchemaFailureCases()...)
 	negativeCases = append(negativeCases, edgeSchemaFailureCases()...)
+	negativeCases = append(negativeCases, deletionSchemaFailureCases()...)
 	negativeCases = append(negativeCases, itemsWithMultipleFailureCases()...)
 
 	ingestSchema, err := LoadIngestSchema()
@@ -379,6 +460,27 @@ func positiveGenericIngestCases() []genericIngestAssertion {
 				},
 			},
 		},
+		{
+			name: "edge specifies match_by property",
+			payload: &testPayload{
+				Edges: []testEdge{
+					{
+						Start: &edgePiece{
+							Value:    "alice@example.com",
+							MatchBy:  "property",
+							Property: "email",
+						},
+						End: &edgePiece{
+							Value:         "CN=BOB,DC=EXAMPLE,DC=COM",
+							MatchBy:       "property",
+							Property:      "distinguishedname",
+							CaseSensitive: true,
+						},
+						Kind: "kindA",
+					},
+				},
+			},
+		},
 		{
 			name: "edge specifies kind filter",
 			payload: &testPayload{
@@ -397,6 +499,49 @@ func positiveGenericIngestCases() []genericIngestAssertion {
 				},
 			},
 		},
+		{
+			name: "payload contains only deletions",
+			payload: &testPayload{
+				DeletedNodes: []edgePiece{
+					{
+						Value: "1234",
+					},
+					{
+						Value:   "alice",
+						MatchBy: "name",
+						Kind:    "User",
+					},
+					{
+						Value:    "alice@example.com",
+						MatchBy:  "property",
+						Property: "email",
+					},
+				},
+				DeletedEdges: []testDeletedEdge{
+					{
+						Start: &edgePiece{Value: "1234"},
+						End:   &edgePiece{Value: "server", MatchBy: "name"},
+						Kind:  "kindA",
+					},
+				},
+			},
+		},
+		{
+			name: "payload contains nodes and deletions",
+			payload: &testPayload{
+				Nodes: []testNode{
+					{
+						ID:    "1234",
+						Kinds: []string{"a"},
+					},
+				},
+				DeletedNodes: []edgePiece{
+					{
+						Value: "5678",
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -590,7 +735,7 @@ func nodeSchemaFailureCases() []genericIngestAssertion {
 		{
 			name:            "payload doesn't contain atleast one of nodes or edges",
 			payload:         &testPayload{},
-			criticalErrMsgs: []string{"graph tag is empty. at least one of nodes: [] or edges: [] is required"},
+			criticalErrMsgs: []string{"graph tag is empty. at least one of nodes: [], edges: [], deleted_nodes: [] or deleted_edges: [] is required"},
 		},
 		{
 			name: "node validation: ID is null",
@@ -792,7 +937,145 @@ func edgeSchemaFailureCases() []genericIngestAssertion {
 				},
 			},
 			validationErrContains: [][]string{
-				{"edges[0]", "at '/start/match_by'", "value must be one of 'id', 'name'"},
+				{"edges[0]", "at '/start/match_by'", "value must be one of 'id', 'name', 'property'"},
+			},
+		},
+		{
+			name: "edge validation: end node match_by property without a property",
+			payload: &testPayload{
+				Edges: []testEdge{
+					{
+						Start: &edgePiece{
+							Value: "1234",
+						},
+						End: &edgePiece{
+							Value:   "alice@example.com",
+							MatchBy: "property",
+						},
+						Kind: "kind A",
+					},
+				},
+			},
+			validationErrContains: [][]string{
+				{"edges[0]", "at '/end'", "missing property 'property'"},
+			},
+		},
+		{
+			name: "edge validation: start node match_by property with a quote in the property name",
+			payload: &testPayload{
+				Edges: []testEdge{
+					{
+						Start: &edgePiece{
+							Value:    "alice@example.com",
+							MatchBy:  "property",
+							Property: "x') is not null; drop table users; --",
+						},
+						End: &edgePiece{
+							Value: "1234",
+						},
+						Kind: "kind A",
+					},
+				},
+			},
+			validationErrContains: [][]string{
+				{"edges[0]", "at '/start/property'", "does not match pattern"},
+			},
+		},
+	}
+}
+
+// these test cases represent all the ways a deleted node or edge can fail schema validation.
+func deletionSchemaFailureCases() []genericIngestAssertion {
+	return []genericIngestAssertion{
+		{
+			name: "deleted node validation: value not provided",
+			payload: &testPayload{
+				DeletedNodes: []edgePiece{
+					{
+						MatchBy: "name",
+					},
+				},
+			},
+			validationErrContains: [][]string{
+				{"deleted_nodes[0]", "at '': missing property 'value'"},
+			},
+		},
+		{
+			name: "deleted node validation: match_by property without a property",
+			payload: &testPayload{
+				DeletedNodes: []edgePiece{
+					{
+						Value:   "alice@example.com",
+						MatchBy: "property",
+					},
+				},
+			},
+			validationErrContains: [][]string{
+				{"deleted_nodes[0]", "missing property 'property'"},
+			},
+		},
+		{
+			name: "deleted node validation: match_by property with a quote in the property name",
+			payload: &testPayload{
+				DeletedNodes: []edgePiece{
+					{
+						Value:    "alice@example.com",
+						MatchBy:  "property",
+						Property: "x') is not null; drop table users; --",
+					},
+				},
+			},
+			validationErrContains: [][]string{
+				{"deleted_nodes[0]", "at '/property'", "does not match pattern"},
+			},
+		},
+		{
+			name:            "deleted nodes array is not opened properly with '['",
+			rawPayload:      `{"deleted_nodes": "some string"}`,
+			criticalErrMsgs: []string{"error opening deleted_nodes array: expected '[', got some string"},
+		},
+		{
+			name: "deleted edge validation: kind not provided",
+			payload: &testPayload{
+				DeletedEdges: []testDeletedEdge{
+					{
+						Start: &edgePiece{Value: "1234"},
+						End:   &edgePiece{Value: "5678"},
+					},
+				},
+			},
+			validationErrContains: [][]string{
+				{"deleted_edges[0]", "at '': missing property 'kind'"},
+			},
+		},
+		{
+			name: "deleted edge validation: invalid match_by on start",
+			payload: &testPayload{
+				DeletedEdges: []testDeletedEdge{
+					{
+						Start: &edgePiece{Value: "1234", MatchBy: "objectid"},
+						End:   &edgePiece{Value: "5678"},
+						Kind:  "kind A",
+					},
+				},
+			},
+			validationErrContains: [][]string{
+				{"deleted_edges[0]", "at '/start/match_by'", "value must be one of 'id', 'name', 'property'"},
+			},
+		},
+		{
+			name: "deleted edge validation: end match_by property with a quote in the property name",
+			payload: &testPayload{
+				DeletedEdges: []testDeletedEdge{
+					{
+						Start: &edgePiece{Value: "1234"},
+						End:   &edgePiece{Value: "alice@example.com", MatchBy: "property", Property: "x') is not null; drop table users; --"},
+						Kind:  "kind A",
+					},
+				},
+			},
+			validationErrContains: [][]string{
+				{"deleted_edges[0]", "at '/end/property'", "does not match pattern"},
 			},
 		},
 	}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/specterops/bloodhound/cmd/api/src/database/types/null"
 	"github.com/specterops/bloodhound/cmd/api/src/model"
+	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
 )
 
 type IngestTaskParams struct {
@@ -28,14 +29,26 @@ type IngestTaskParams struct {
 	FileType  model.FileType
 	RequestID string
 	JobID     int64
+
+	// OriginalFilename is the name the file was uploaded with, if the client gave one
+	OriginalFilename string
+
+	// CSVMapping is the column mapping CSV files were uploaded with
+	CSVMapping *ingest.CSVMapping
+
+	// Environment is the environment label of the ingest job the file was uploaded to
+	Environment string
 }
 
 func CreateIngestTask(ctx context.Context, db UploadData, params IngestTaskParams) (model.IngestTask, error) {
 	newIngestTask := model.IngestTask{
-		FileName:    params.Filename,
-		RequestGUID: params.RequestID,
-		JobId:       null.Int64From(params.JobID),
-		FileType:    params.FileType,
+		FileName:         params.Filename,
+		RequestGUID:      params.RequestID,
+		JobId:            null.Int64From(params.JobID),
+		FileType:         params.FileType,
+		OriginalFileName: params.OriginalFilename,
+		CSVMapping:       params.CSVMapping,
+		Environment:      params.Environment,
 	}
 
 	return db.CreateIngestTask(ctx, newIngestTask)
@@ -21,8 +21,11 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"mime"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/specterops/bloodhound/cmd/api/src/model"
 	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
@@ -31,36 +34,91 @@ import (
 	"github.com/specterops/bloodhound/packages/go/mediatypes"
 )
 
-var ErrInvalidJSON = errors.New("file is not valid json")
+var (
+	ErrInvalidJSON        = errors.New("file is not valid json")
+	ErrInvalidContentType = errors.New("invalid content type for ingest file")
+)
 
 func SaveIngestFile(location string, request *http.Request, validator IngestValidator) (IngestTaskParams, error) {
 	fileData := request.Body
 
-	var (
-		fileType     model.FileType
-		validationFn FileValidator
-	)
-
-	switch {
-	case utils.HeaderMatches(request.Header, headers.ContentType.String(), mediatypes.ApplicationJson.String()):
-		fileType = model.FileTypeJson
-		validationFn = validator.WriteAndValidateJSON
-	case utils.HeaderMatches(request.Header, headers.ContentType.String(), ingest.AllowedZipFileUploadTypes...):
-		fileType = model.FileTypeZip
-		validationFn = WriteAndValidateZip
-	default:
-		return IngestTaskParams{}, fmt.Errorf("invalid content type for ingest file")
-	}
-
-	if tempFileName, err := WriteAndValidateFile(fileData, location, validationFn); err != nil {
+	if fileType, err := FileTypeFromHeader(request.Header); err != nil {
+		return IngestTaskParams{}, err
+	} else if fileType == model.FileTypeCSV {
+		return SaveCSVIngestFile(location, request, validator)
+	} else if tempFileName, err := WriteAndValidateFile(fileData, location, validator.FileValidatorFor(fileType)); err != nil {
 		return IngestTaskParams{}, err
 	} else {
 		return IngestTaskParams{
-			Filename: tempFileName,
-			FileType: fileType,
+			Filename:         tempFileName,
+			FileType:         fileType,
+			OriginalFilename: FileNameFromHeader(request.Header),
 		}, nil
 	}
+}
+
+// FileNameFromHeader returns the file name given by the Content-Disposition of an upload request, without any
+// directories, or an empty string if the request names no file
+func FileNameFromHeader(header http.Header) string {
+	if _, params, err := mime.ParseMediaType(header.Get(headers.ContentDisposition.String())); err != nil {
+		return ""
+	} else {
+		return uploadedFileName(params["filename"])
+	}
+}
 
+// uploadedFileName strips any directories from a file name given by a client
+func uploadedFileName(name string) string {
+	if name = filepath.Base(filepath.Clean("/" + name)); name == "/" {
+		return ""
+	}
+
+	return name
+}
+
+// FileTypeFromHeader maps the Content-Type of an upload request to the ingest file type it carries
+func FileTypeFromHeader(header http.Header) (model.FileType, error) {
+	switch {
+	case utils.HeaderMatches(header, headers.ContentType.String(), mediatypes.ApplicationJson.String()):
+		return model.FileTypeJson, nil
+	case utils.HeaderMatches(header, headers.ContentType.String(), ingest.AllowedNDJSONFileUploadTypes...):
+		return model.FileTypeNDJSON, nil
+	case utils.HeaderMatches(header, headers.ContentType.String(), ingest.AllowedCSVFileUploadTypes...):
+		return model.FileTypeCSV, nil
+	case utils.HeaderMatches(header, headers.ContentType.String(), ingest.AllowedZipFileUploadTypes...):
+		return model.FileTypeZip, nil
+	case utils.HeaderMatches(header, headers.ContentType.String(), ingest.AllowedGzipFileUploadTypes...):
+		return model.FileTypeGzip, nil
+	case utils.HeaderMatches(header, headers.ContentType.String(), ingest.AllowedTarGzipFileUploadTypes...):
+		return model.FileTypeTarGzip, nil
+	case utils.HeaderMatches(header, headers.ContentType.String(), ingest.AllowedZstdFileUploadTypes...):
+		return model.FileTypeZstd, nil
+	default:
+		return 0, ErrInvalidContentType
+	}
+}
+
+// FileTypeFromName maps the extension of a file name to the ingest file type it carries, for files picked up from
+// somewhere other than an upload request
+func FileTypeFromName(name string) (model.FileType, bool) {
+	lowerName := strings.ToLower(name)
+
+	switch {
+	case strings.HasSuffix(lowerName, ".json"):
+		return model.FileTypeJson, true
+	case strings.HasSuffix(lowerName, ".ndjson"), strings.HasSuffix(lowerName, ".jsonl"):
+		return model.FileTypeNDJSON, true
+	case strings.HasSuffix(lowerName, ".zip"):
+		return model.FileTypeZip, true
+	case strings.HasSuffix(lowerName, ".tar.gz"), strings.HasSuffix(lowerName, ".tgz"):
+		return model.FileTypeTarGzip, true
+	case strings.HasSuffix(lowerName, ".gz"):
+		return model.FileTypeGzip, true
+	case strings.HasSuffix(lowerName, ".zst"):
+		return model.FileTypeZstd, true
+	default:
+		return 0, false
+	}
 }
 
 func WriteAndValidateFile(fileData io.Reader, location string, validationFunc FileValidator) (string, error) {
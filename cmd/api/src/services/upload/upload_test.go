@@ -21,11 +21,14 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"strings"
 	"testing"
 
+	"github.com/specterops/bloodhound/cmd/api/src/model"
 	"github.com/specterops/bloodhound/cmd/api/src/model/ingest"
+	"github.com/specterops/bloodhound/packages/go/headers"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -51,6 +54,47 @@ func TestWriteAndValidateZip(t *testing.T) {
 	})
 }
 
+func TestWriteAndValidateGzip(t *testing.T) {
+	t.Run("gzip header is ok", func(t *testing.T) {
+		var (
+			writer = bytes.Buffer{}
+			input  = append([]byte{0x1f, 0x8b}, []byte("rest of the stream")...)
+		)
+
+		_, err := WriteAndValidateGzip(bytes.NewReader(input), &writer)
+		assert.Nil(t, err)
+		assert.Equal(t, input, writer.Bytes())
+	})
+
+	t.Run("missing gzip header causes error", func(t *testing.T) {
+		_, err := WriteAndValidateGzip(strings.NewReader("123123"), &bytes.Buffer{})
+		assert.Equal(t, ingest.ErrInvalidGzipFile, err)
+	})
+
+	t.Run("short stream causes error", func(t *testing.T) {
+		_, err := WriteAndValidateGzip(strings.NewReader("1"), &bytes.Buffer{})
+		assert.Equal(t, ingest.ErrInvalidGzipFile, err)
+	})
+}
+
+func TestWriteAndValidateZstd(t *testing.T) {
+	t.Run("zstd header is ok", func(t *testing.T) {
+		var (
+			writer = bytes.Buffer{}
+			input  = append([]byte{0x28, 0xb5, 0x2f, 0xfd}, []byte("rest of the stream")...)
+		)
+
+		_, err := WriteAndValidateZstd(bytes.NewReader(input), &writer)
+		assert.Nil(t, err)
+		assert.Equal(t, input, writer.Bytes())
+	})
+
+	t.Run("missing zstd header causes error", func(t *testing.T) {
+		_, err := WriteAndValidateZstd(strings.NewReader("123123"), &bytes.Buffer{})
+		assert.Equal(t, ingest.ErrInvalidZstdFile, err)
+	})
+}
+
 func TestWriteAndValidateJSON(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -147,3 +191,39 @@ type ErrorReader struct {
 func (er *ErrorReader) Read(p []byte) (n int, err error) {
 	return 0, er.err
 }
+
+func TestFileTypeFromName(t *testing.T) {
+	for name, expected := range map[string]model.FileType{
+		"computers.json":     model.FileTypeJson,
+		"Collection.ZIP":     model.FileTypeZip,
+		"collection.tar.gz":  model.FileTypeTarGzip,
+		"collection.tgz":     model.FileTypeTarGzip,
+		"computers.json.gz":  model.FileTypeGzip,
+		"computers.json.zst": model.FileTypeZstd,
+		"graph.ndjson":       model.FileTypeNDJSON,
+		"graph.JSONL":        model.FileTypeNDJSON,
+	} {
+		fileType, ok := FileTypeFromName(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, expected, fileType, name)
+	}
+
+	_, ok := FileTypeFromName("notes.txt")
+	assert.False(t, ok)
+}
+
+func TestFileNameFromHeader(t *testing.T) {
+	for value, expected := range map[string]string{
+		`attachment; filename="computers.json"`:           "computers.json",
+		`attachment; filename="../../etc/collection.zip"`: "collection.zip",
+		`attachment; filename*=UTF-8''caf%C3%A9.json`:     "café.json",
+		`attachment; filename=""`:                         "",
+		`attachment`:                                      "",
+		``:                                                "",
+	} {
+		header := http.Header{}
+		header.Set(headers.ContentDisposition.String(), value)
+
+		assert.Equal(t, expected, FileNameFromHeader(header), value)
+	}
+}
@@ -9887,6 +9887,7 @@ func (s *ResolveEndpointsByName) Setup(graphTestContext *GraphTestContext) {
 	s.Node4 = graphTestContext.NewNode(graph.AsProperties(graph.PropertyMap{
 		common.ObjectID: "1234",
 		common.Name:     "BOB",
+		common.Email:    "Bob@Example.com",
 	}), graph.StringKind("GenericDevice"))
 
 }
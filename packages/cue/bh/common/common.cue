@@ -164,6 +164,14 @@ PrimaryKind: types.#StringEnum & {
 	representation: "primarykind"
 }
 
+// Used to specify the environment label of the ingest job that wrote a node or edge
+Environment: types.#StringEnum & {
+	symbol:         "Environment"
+	schema:         "common"
+	name:           "Environment"
+	representation: "environment"
+}
+
 Properties: [
 	ObjectID,
 	Name,
@@ -183,7 +191,8 @@ Properties: [
 	Email,
 	IsInherited,
 	CompositionID,
-	PrimaryKind
+	PrimaryKind,
+	Environment
 ]
 
 // Kinds
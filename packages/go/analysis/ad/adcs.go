@@ -34,7 +34,7 @@ var (
 	EkuCertRequestAgent = "1.3.6.1.4.1.311.20.2.1"
 )
 
-func PostADCS(ctx context.Context, db graph.Database, groupExpansions impact.PathAggregator, adcsEnabled bool) (*analysis.AtomicPostProcessingStats, ADCSCache, error) {
+func PostADCS(ctx context.Context, db graph.Database, groupExpansions impact.PathAggregator, adcsEnabled bool, scope analysis.Scope) (*analysis.AtomicPostProcessingStats, ADCSCache, error) {
 	var cache = NewADCSCache()
 	if enterpriseCertAuthorities, err := FetchNodesByKind(ctx, db, ad.EnterpriseCA); err != nil {
 		return &analysis.AtomicPostProcessingStats{}, cache, fmt.Errorf("failed fetching enterpriseCA nodes: %w", err)
@@ -63,7 +63,9 @@ func PostADCS(ctx context.Context, db graph.Database, groupExpansions impact.Pat
 			for _, domain := range cache.GetDomains() {
 				innerDomain := domain
 
-				if cache.DoesCAChainProperlyToDomain(innerEnterpriseCA, innerDomain) && cache.DoesCAHaveHostingComputer(innerEnterpriseCA) {
+				if !scope.ContainsNode(innerDomain, ad.DomainSID.String()) {
+					continue
+				} else if cache.DoesCAChainProperlyToDomain(innerEnterpriseCA, innerDomain) && cache.DoesCAHaveHostingComputer(innerEnterpriseCA) {
 					targetDomains.Add(innerDomain)
 				}
 			}
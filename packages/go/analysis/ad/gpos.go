@@ -32,8 +32,8 @@ import (
 
 const maxDepth = 1024
 
-func PostGPOs(ctx context.Context, db graph.Database) (*analysis.AtomicPostProcessingStats, error) {
-	if domainNodes, err := fetchCollectedDomainNodes(ctx, db); err != nil {
+func PostGPOs(ctx context.Context, db graph.Database, scope analysis.Scope) (*analysis.AtomicPostProcessingStats, error) {
+	if domainNodes, err := fetchCollectedDomainNodes(ctx, db, scope); err != nil {
 		return &analysis.AtomicPostProcessingStats{}, err
 	} else {
 		operation := analysis.NewPostRelationshipOperation(ctx, db, "GPOs Post Processing")
@@ -124,7 +124,7 @@ func NewNTLMCache(ctx context.Context, db graph.Database, groupExpansions impact
 }
 
 // PostNTLM is the initial function used to execute our NTLM analysis
-func PostNTLM(ctx context.Context, db graph.Database, groupExpansions impact.PathAggregator, adcsCache ADCSCache, ntlmEnabled bool, compositionCounter *analysis.CompositionCounter) (*analysis.AtomicPostProcessingStats, error) {
+func PostNTLM(ctx context.Context, db graph.Database, groupExpansions impact.PathAggregator, adcsCache ADCSCache, ntlmEnabled bool, compositionCounter *analysis.CompositionCounter, scope analysis.Scope) (*analysis.AtomicPostProcessingStats, error) {
 	var (
 		operation = analysis.NewPostRelationshipOperation(ctx, db, "PostNTLM")
 		// compositionChannel      = make(chan analysis.CompositionInfo)
@@ -166,7 +166,7 @@ func PostNTLM(ctx context.Context, db graph.Database, groupExpansions impact.Pat
 		operation.Done()
 		return nil, err
 	} else if err := db.ReadTransaction(ctx, func(tx graph.Transaction) error {
-		return tx.Nodes().Filter(query.Kind(query.Node(), ad.Computer)).Fetch(func(cursor graph.Cursor[*graph.Node]) error {
+		return tx.Nodes().Filter(scope.Filter(query.NodeProperty(ad.DomainSID.String()), query.Kind(query.Node(), ad.Computer))).Fetch(func(cursor graph.Cursor[*graph.Node]) error {
 			for computer := range cursor.Chan() {
 				innerComputer := computer
 
@@ -35,7 +35,7 @@ import (
 	"github.com/specterops/dawgs/query"
 )
 
-func PostOwnsAndWriteOwner(ctx context.Context, db graph.Database, groupExpansions impact.PathAggregator) (*analysis.AtomicPostProcessingStats, error) {
+func PostOwnsAndWriteOwner(ctx context.Context, db graph.Database, groupExpansions impact.PathAggregator, scope analysis.Scope) (*analysis.AtomicPostProcessingStats, error) {
 	operation := analysis.NewPostRelationshipOperation(ctx, db, "PostOwnsAndWriteOwner")
 
 	// Get the dSHeuristics values for all domains
@@ -50,7 +50,7 @@ func PostOwnsAndWriteOwner(ctx context.Context, db graph.Database, groupExpansio
 		// Get all source nodes of Owns ACEs (i.e., owning principals) where the target node has no ACEs granting abusable explicit permissions to OWNER RIGHTS
 		if err := operation.Operation.SubmitReader(func(ctx context.Context, tx graph.Transaction, outC chan<- analysis.CreatePostRelationshipJob) error {
 			if relationships, err := ops.FetchRelationships(tx.Relationships().Filterf(func() graph.Criteria {
-				return query.And(
+				return scope.Filter(query.EndProperty(ad.DomainSID.String()),
 					query.Kind(query.Relationship(), ad.OwnsRaw),
 					query.Kind(query.Start(), ad.Entity),
 				)
@@ -107,7 +107,7 @@ func PostOwnsAndWriteOwner(ctx context.Context, db graph.Database, groupExpansio
 		if err := operation.Operation.SubmitReader(func(ctx context.Context, tx graph.Transaction, outC chan<- analysis.CreatePostRelationshipJob) error {
 
 			if relationships, err := ops.FetchRelationships(tx.Relationships().Filterf(func() graph.Criteria {
-				return query.And(
+				return scope.Filter(query.EndProperty(ad.DomainSID.String()),
 					query.Kind(query.Relationship(), ad.WriteOwnerRaw),
 					query.Kind(query.Start(), ad.Entity),
 				)
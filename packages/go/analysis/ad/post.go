@@ -74,8 +74,8 @@ func PostProcessedRelationships() []graph.Kind {
 	}
 }
 
-func PostSyncLAPSPassword(ctx context.Context, db graph.Database, groupExpansions impact.PathAggregator) (*analysis.AtomicPostProcessingStats, error) {
-	if domainNodes, err := fetchCollectedDomainNodes(ctx, db); err != nil {
+func PostSyncLAPSPassword(ctx context.Context, db graph.Database, groupExpansions impact.PathAggregator, scope analysis.Scope) (*analysis.AtomicPostProcessingStats, error) {
+	if domainNodes, err := fetchCollectedDomainNodes(ctx, db, scope); err != nil {
 		return &analysis.AtomicPostProcessingStats{}, err
 	} else {
 		operation := analysis.NewPostRelationshipOperation(ctx, db, "SyncLAPSPassword Post Processing")
@@ -109,8 +109,8 @@ func PostSyncLAPSPassword(ctx context.Context, db graph.Database, groupExpansion
 	}
 }
 
-func PostDCSync(ctx context.Context, db graph.Database, groupExpansions impact.PathAggregator) (*analysis.AtomicPostProcessingStats, error) {
-	if domainNodes, err := fetchCollectedDomainNodes(ctx, db); err != nil {
+func PostDCSync(ctx context.Context, db graph.Database, groupExpansions impact.PathAggregator, scope analysis.Scope) (*analysis.AtomicPostProcessingStats, error) {
+	if domainNodes, err := fetchCollectedDomainNodes(ctx, db, scope); err != nil {
 		return &analysis.AtomicPostProcessingStats{}, err
 	} else {
 		operation := analysis.NewPostRelationshipOperation(ctx, db, "DCSync Post Processing")
@@ -142,7 +142,7 @@ func PostDCSync(ctx context.Context, db graph.Database, groupExpansions impact.P
 }
 
 func PostHasTrustKeys(ctx context.Context, db graph.Database) (*analysis.AtomicPostProcessingStats, error) {
-	if domainNodes, err := fetchCollectedDomainNodes(ctx, db); err != nil {
+	if domainNodes, err := fetchCollectedDomainNodes(ctx, db, analysis.Scope{}); err != nil {
 		return &analysis.AtomicPostProcessingStats{}, err
 	} else {
 		operation := analysis.NewPostRelationshipOperation(ctx, db, "HasTrustKeys Post Processing")
@@ -185,11 +185,17 @@ func PostHasTrustKeys(ctx context.Context, db graph.Database) (*analysis.AtomicP
 }
 
 func FetchComputers(ctx context.Context, db graph.Database) (*roaring64.Bitmap, error) {
+	return fetchComputersInScope(ctx, db, analysis.Scope{})
+}
+
+func fetchComputersInScope(ctx context.Context, db graph.Database, scope analysis.Scope) (*roaring64.Bitmap, error) {
 	computerNodeIds := roaring64.NewBitmap()
 
 	return computerNodeIds, db.ReadTransaction(ctx, func(tx graph.Transaction) error {
 		return tx.Nodes().Filterf(func() graph.Criteria {
-			return query.Kind(query.Node(), ad.Computer)
+			return scope.Filter(query.NodeProperty(ad.DomainSID.String()),
+				query.Kind(query.Node(), ad.Computer),
+			)
 		}).FetchIDs(func(cursor graph.Cursor[graph.ID]) error {
 			for id := range cursor.Chan() {
 				computerNodeIds.Add(id.Uint64())
@@ -216,12 +222,12 @@ func FetchNodesByKind(ctx context.Context, db graph.Database, kinds ...graph.Kin
 	})
 }
 
-func fetchCollectedDomainNodes(ctx context.Context, db graph.Database) ([]*graph.Node, error) {
+func fetchCollectedDomainNodes(ctx context.Context, db graph.Database, scope analysis.Scope) ([]*graph.Node, error) {
 	var nodes []*graph.Node
 	return nodes, db.ReadTransaction(ctx, func(tx graph.Transaction) error {
 		var err error
 		if nodes, err = ops.FetchNodes(tx.Nodes().Filterf(func() graph.Criteria {
-			return query.And(
+			return scope.Filter(query.NodeProperty(ad.DomainSID.String()),
 				query.Kind(query.Node(), ad.Domain),
 				query.Equals(query.NodeProperty(common.Collected.String()), true),
 			)
@@ -308,14 +314,14 @@ func getLAPSComputersForDomain(tx graph.Transaction, domain *graph.Node) ([]grap
 	}
 }
 
-func PostLocalGroups(ctx context.Context, db graph.Database, localGroupExpansions impact.PathAggregator, enforceURA bool, citrixEnabled bool) (*analysis.AtomicPostProcessingStats, error) {
+func PostLocalGroups(ctx context.Context, db graph.Database, localGroupExpansions impact.PathAggregator, enforceURA bool, citrixEnabled bool, scope analysis.Scope) (*analysis.AtomicPostProcessingStats, error) {
 	var (
 		adminGroupSuffix    = "-544"
 		psRemoteGroupSuffix = "-580"
 		dcomGroupSuffix     = "-562"
 	)
 
-	if computers, err := FetchComputers(ctx, db); err != nil {
+	if computers, err := fetchComputersInScope(ctx, db, scope); err != nil {
 		return &analysis.AtomicPostProcessingStats{}, err
 	} else {
 		var (
@@ -202,8 +202,8 @@ func aggregateSourceReadWriteServicePrincipals(tx graph.Transaction, tenantConta
 	return sourceNodes, nil
 }
 
-func AppRoleAssignments(ctx context.Context, db graph.Database) (*analysis.AtomicPostProcessingStats, error) {
-	if tenants, err := FetchTenants(ctx, db); err != nil {
+func AppRoleAssignments(ctx context.Context, db graph.Database, scope analysis.Scope) (*analysis.AtomicPostProcessingStats, error) {
+	if tenants, err := FetchTenantsInScope(ctx, db, scope); err != nil {
 		return &analysis.AtomicPostProcessingStats{}, err
 	} else {
 		operation := analysis.NewPostRelationshipOperation(ctx, db, "Azure App Role Assignments Post Processing")
@@ -679,8 +679,8 @@ func addSecret(operation analysis.StatTrackedOperation[analysis.CreatePostRelati
 	})
 }
 
-func ExecuteCommand(ctx context.Context, db graph.Database) (*analysis.AtomicPostProcessingStats, error) {
-	if tenants, err := FetchTenants(ctx, db); err != nil {
+func ExecuteCommand(ctx context.Context, db graph.Database, scope analysis.Scope) (*analysis.AtomicPostProcessingStats, error) {
+	if tenants, err := FetchTenantsInScope(ctx, db, scope); err != nil {
 		return &analysis.AtomicPostProcessingStats{}, err
 	} else {
 		operation := analysis.NewPostRelationshipOperation(ctx, db, "AZExecuteCommand Post Processing")
@@ -899,8 +899,8 @@ func addMembers(roleAssignments RoleAssignments, operation analysis.StatTrackedO
 	}
 }
 
-func UserRoleAssignments(ctx context.Context, db graph.Database) (*analysis.AtomicPostProcessingStats, error) {
-	if tenantNodes, err := FetchTenants(ctx, db); err != nil {
+func UserRoleAssignments(ctx context.Context, db graph.Database, scope analysis.Scope) (*analysis.AtomicPostProcessingStats, error) {
+	if tenantNodes, err := FetchTenantsInScope(ctx, db, scope); err != nil {
 		return &analysis.AtomicPostProcessingStats{}, err
 	} else {
 		operation := analysis.NewPostRelationshipOperation(ctx, db, "Azure User Role Assignments Post Processing")
@@ -956,13 +956,14 @@ func UserRoleAssignments(ctx context.Context, db graph.Database) (*analysis.Atom
 func CreateAZRoleApproverEdge(
 	ctx context.Context,
 	db graph.Database,
+	scope analysis.Scope,
 ) (
 	*analysis.AtomicPostProcessingStats,
 	error,
 ) {
 	// Step 0: Identify each AZTenant labeled node in the database.
 	operation := analysis.NewPostRelationshipOperation(ctx, db, "AZRoleApprover Post Processing")
-	tenantNodes, err := FetchTenants(ctx, db)
+	tenantNodes, err := FetchTenantsInScope(ctx, db, scope)
 	if err != nil {
 		return &operation.Stats, err
 	}
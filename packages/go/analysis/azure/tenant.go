@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/specterops/bloodhound/packages/go/analysis"
 	"github.com/specterops/bloodhound/packages/go/bhlog/measure"
 	"github.com/specterops/bloodhound/packages/go/graphschema/azure"
 	"github.com/specterops/dawgs/graph"
@@ -84,11 +85,18 @@ func TenantPrincipals(tx graph.Transaction, tenant *graph.Node) (graph.NodeSet,
 }
 
 func FetchTenants(ctx context.Context, db graph.Database) (graph.NodeSet, error) {
+	return FetchTenantsInScope(ctx, db, analysis.Scope{})
+}
+
+// FetchTenantsInScope returns the tenants covered by the scope, as identified by their tenant IDs
+func FetchTenantsInScope(ctx context.Context, db graph.Database, scope analysis.Scope) (graph.NodeSet, error) {
 	var nodeSet graph.NodeSet
 	if err := db.ReadTransaction(ctx, func(tx graph.Transaction) error {
 		var err error
 		if nodeSet, err = ops.FetchNodeSet(tx.Nodes().Filterf(func() graph.Criteria {
-			return query.Kind(query.Node(), azure.Tenant)
+			return scope.Filter(query.NodeProperty(azure.TenantID.String()),
+				query.Kind(query.Node(), azure.Tenant),
+			)
 		})); err != nil {
 			return err
 		} else {
@@ -0,0 +1,37 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package hybrid
+
+import (
+	adAnalysis "github.com/specterops/bloodhound/packages/go/analysis/ad"
+	"github.com/specterops/bloodhound/packages/go/analysis/azure"
+	"github.com/specterops/dawgs/graph"
+)
+
+// PostProcessedRelationships returns every relationship kind created by Active Directory, Azure and hybrid
+// post-processing, without duplicates
+func PostProcessedRelationships() []graph.Kind {
+	var kinds graph.Kinds
+
+	for _, kind := range append(adAnalysis.PostProcessedRelationships(), azure.PostProcessedRelationships()...) {
+		if !kinds.ContainsOneOf(kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+
+	return kinds
+}
@@ -162,6 +162,12 @@ type DeleteRelationshipJob struct {
 }
 
 func DeleteTransitEdges(ctx context.Context, db graph.Database, baseKinds graph.Kinds, targetRelationships ...graph.Kind) (*AtomicPostProcessingStats, error) {
+	return DeleteTransitEdgesInScope(ctx, db, baseKinds, Scope{}, "", targetRelationships...)
+}
+
+// DeleteTransitEdgesInScope deletes the post-processed relationships of the kinds given that end at a node covered by
+// the scope, as recorded by the scope property of the node
+func DeleteTransitEdgesInScope(ctx context.Context, db graph.Database, baseKinds graph.Kinds, scope Scope, scopeProperty string, targetRelationships ...graph.Kind) (*AtomicPostProcessingStats, error) {
 	defer measure.ContextMeasure(ctx, slog.LevelInfo, "Finished deleting transit edges")()
 
 	var (
@@ -169,12 +175,16 @@ func DeleteTransitEdges(ctx context.Context, db graph.Database, baseKinds graph.
 		stats           = NewAtomicPostProcessingStats()
 	)
 
+	if scope.IsEmpty() {
+		return &stats, nil
+	}
+
 	for _, kind := range targetRelationships {
 		closureKindCopy := kind
 
 		if err := db.ReadTransaction(ctx, func(tx graph.Transaction) error {
 			fetchedRelationshipIDs, err := ops.FetchRelationshipIDs(tx.Relationships().Filterf(func() graph.Criteria {
-				return query.And(
+				return scope.Filter(query.EndProperty(scopeProperty),
 					query.KindIn(query.Start(), baseKinds...),
 					query.Kind(query.Relationship(), closureKindCopy),
 					query.KindIn(query.End(), baseKinds...),
@@ -0,0 +1,310 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package analysis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/specterops/dawgs/graph"
+)
+
+var (
+	ErrPostProcessorExists            = errors.New("post-processor already registered")
+	ErrPostProcessorInvalid           = errors.New("invalid post-processor")
+	ErrPostProcessorUnknownDependency = errors.New("post-processor depends on an unknown post-processor")
+	ErrPostProcessorCycle             = errors.New("post-processor dependencies form a cycle")
+	ErrPostProcessorDependencyFailed  = errors.New("post-processor dependency failed")
+)
+
+// PostProcessor is a single step of post-processing. Steps share the state S, through which a step hands what it
+// computed to the steps that depend on it.
+type PostProcessor[S any] struct {
+	// Name identifies the step in dependencies, logs, metrics and configuration
+	Name string
+
+	// Kinds are the relationship kinds created by the step. They are deleted before any step runs.
+	Kinds []graph.Kind
+
+	// ScopeProperty names the property of the end node of the relationships created by the step that identifies its
+	// domain or tenant. The Kinds of a step with a ScopeProperty are only deleted and recreated within the scope of
+	// the analysis, except for those also listed in GlobalKinds. The Kinds of a step without one are always deleted
+	// and recreated for the whole graph.
+	ScopeProperty string
+	GlobalKinds   []graph.Kind
+
+	// DependsOn names the steps that must complete before this step runs. The step is not run if any of them fails
+	// or is disabled.
+	DependsOn []string
+
+	// FeatureFlag is the key of the feature flag that must be enabled for the step to run, if any
+	FeatureFlag string
+
+	Run func(ctx context.Context, state S) (*AtomicPostProcessingStats, error)
+}
+
+// PostProcessorResult is the outcome of a step of post-processing
+type PostProcessorResult struct {
+	Name string
+
+	// Stats counts the relationships created by the step, along with the relationships of its kinds deleted before
+	// any step ran
+	Stats    *AtomicPostProcessingStats
+	Duration time.Duration
+
+	// Skipped is set if the step was disabled, or if it did not run because one of its dependencies was disabled
+	Skipped bool
+	Err     error
+}
+
+// PostProcessingOptions control a run of the steps of a PostProcessorRegistry
+type PostProcessingOptions[S any] struct {
+	// BaseKinds are the node kinds the relationships created by post-processing connect
+	BaseKinds graph.Kinds
+
+	// Scope returns the scope of the analysis for the scope property of a step. A nil Scope covers the whole graph.
+	Scope func(scopeProperty string) Scope
+
+	// Enabled returns false for the steps that must not run. A nil Enabled runs every step.
+	Enabled func(processor PostProcessor[S]) bool
+
+	// OnStepDone, if set, is called with the result of every step once it is known
+	OnStepDone func(result PostProcessorResult)
+}
+
+// PostProcessorRegistry holds the steps of post-processing and runs them in the order their dependencies require
+type PostProcessorRegistry[S any] struct {
+	processors []PostProcessor[S]
+}
+
+func NewPostProcessorRegistry[S any]() *PostProcessorRegistry[S] {
+	return &PostProcessorRegistry[S]{}
+}
+
+// Register adds the steps given to the registry. Steps with no ordering between them run in the order they were
+// registered.
+func (s *PostProcessorRegistry[S]) Register(processors ...PostProcessor[S]) error {
+	for _, processor := range processors {
+		if processor.Name == "" || processor.Run == nil {
+			return fmt.Errorf("%w: a name and a run function are required", ErrPostProcessorInvalid)
+		} else if _, found := s.Get(processor.Name); found {
+			return fmt.Errorf("%w: %s", ErrPostProcessorExists, processor.Name)
+		}
+
+		s.processors = append(s.processors, processor)
+	}
+
+	return nil
+}
+
+// Get returns the step registered with the name given
+func (s *PostProcessorRegistry[S]) Get(name string) (PostProcessor[S], bool) {
+	for _, processor := range s.processors {
+		if processor.Name == name {
+			return processor, true
+		}
+	}
+
+	return PostProcessor[S]{}, false
+}
+
+// PostProcessedRelationships returns every relationship kind created by the registered steps
+func (s *PostProcessorRegistry[S]) PostProcessedRelationships() []graph.Kind {
+	var kinds graph.Kinds
+
+	for _, processor := range s.processors {
+		for _, kind := range processor.Kinds {
+			if !kinds.ContainsOneOf(kind) {
+				kinds = append(kinds, kind)
+			}
+		}
+	}
+
+	return kinds
+}
+
+// Plan returns the registered steps ordered so that every step comes after its dependencies
+func (s *PostProcessorRegistry[S]) Plan() ([]PostProcessor[S], error) {
+	var (
+		plan    = make([]PostProcessor[S], 0, len(s.processors))
+		planned = make(map[string]bool, len(s.processors))
+	)
+
+	for _, processor := range s.processors {
+		for _, dependency := range processor.DependsOn {
+			if _, found := s.Get(dependency); !found {
+				return nil, fmt.Errorf("%w: %s depends on %s", ErrPostProcessorUnknownDependency, processor.Name, dependency)
+			}
+		}
+	}
+
+	// Each pass plans the first registered step whose dependencies are all planned
+	for len(plan) < len(s.processors) {
+		next := slices.IndexFunc(s.processors, func(processor PostProcessor[S]) bool {
+			return !planned[processor.Name] && !slices.ContainsFunc(processor.DependsOn, func(dependency string) bool {
+				return !planned[dependency]
+			})
+		})
+
+		if next < 0 {
+			return nil, ErrPostProcessorCycle
+		}
+
+		plan = append(plan, s.processors[next])
+		planned[s.processors[next].Name] = true
+	}
+
+	return plan, nil
+}
+
+// Run deletes the relationships created by the registered steps and runs the steps enabled. A step that fails does
+// not stop the others, but the steps that depend on it fail as well, as do the steps left once the context is done.
+// An error is only returned if the steps could not be planned or their relationships could not be deleted.
+func (s *PostProcessorRegistry[S]) Run(ctx context.Context, db graph.Database, state S, options PostProcessingOptions[S]) ([]PostProcessorResult, error) {
+	plan, err := s.Plan()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		enabled = make(map[string]bool, len(plan))
+		results = make([]PostProcessorResult, 0, len(plan))
+		outcome = make(map[string]PostProcessorResult, len(plan))
+	)
+
+	for _, processor := range plan {
+		enabled[processor.Name] = options.Enabled == nil || options.Enabled(processor)
+	}
+
+	deleted, err := s.deletePostProcessedEdges(ctx, db, plan, enabled, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, processor := range plan {
+		var (
+			result        = PostProcessorResult{Name: processor.Name}
+			deletedByStep = deletedKinds(deleted, processor.Kinds)
+		)
+
+		if !enabled[processor.Name] {
+			result.Skipped = true
+		} else if dependency := slices.IndexFunc(processor.DependsOn, func(dependency string) bool {
+			return outcome[dependency].Skipped || outcome[dependency].Err != nil
+		}); dependency >= 0 {
+			if dependencyResult := outcome[processor.DependsOn[dependency]]; dependencyResult.Err != nil {
+				result.Err = fmt.Errorf("%w: %s", ErrPostProcessorDependencyFailed, dependencyResult.Name)
+			} else {
+				result.Skipped = true
+			}
+		} else if err := ctx.Err(); err != nil {
+			// The steps left once the context is done fail without being run
+			result.Err = err
+		} else {
+			start := time.Now()
+			result.Stats, result.Err = processor.Run(ctx, state)
+			result.Duration = time.Since(start)
+		}
+
+		if result.Stats == nil {
+			result.Stats = deletedByStep
+		} else {
+			result.Stats.Merge(deletedByStep)
+		}
+
+		if result.Err != nil {
+			slog.ErrorContext(ctx, fmt.Sprintf("Post-processing step %s failed: %v", processor.Name, result.Err))
+		} else if result.Skipped {
+			slog.InfoContext(ctx, fmt.Sprintf("Post-processing step %s skipped", processor.Name))
+		} else if result.Stats != nil {
+			result.Stats.LogStats()
+		}
+
+		if options.OnStepDone != nil {
+			options.OnStepDone(result)
+		}
+
+		outcome[processor.Name] = result
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// deletePostProcessedEdges deletes the relationships of the planned steps. The relationships of a scoped step that
+// runs are deleted within the scope of the analysis, while those of the steps that do not run are deleted from the
+// whole graph so that a disabled step leaves none behind.
+func (s *PostProcessorRegistry[S]) deletePostProcessedEdges(ctx context.Context, db graph.Database, plan []PostProcessor[S], enabled map[string]bool, options PostProcessingOptions[S]) (*AtomicPostProcessingStats, error) {
+	var (
+		globalKinds graph.Kinds
+		scopedKinds = map[string]graph.Kinds{}
+		deleted     = NewAtomicPostProcessingStats()
+	)
+
+	for _, processor := range plan {
+		scope := Scope{}
+		if processor.ScopeProperty != "" && options.Scope != nil && enabled[processor.Name] {
+			scope = options.Scope(processor.ScopeProperty)
+		}
+
+		for _, kind := range processor.Kinds {
+			if scope.IsFull() || graph.Kinds(processor.GlobalKinds).ContainsOneOf(kind) {
+				globalKinds = append(globalKinds, kind)
+			} else {
+				scopedKinds[processor.ScopeProperty] = append(scopedKinds[processor.ScopeProperty], kind)
+			}
+		}
+	}
+
+	if len(globalKinds) > 0 {
+		if stats, err := DeleteTransitEdges(ctx, db, options.BaseKinds, globalKinds...); err != nil {
+			return &deleted, err
+		} else {
+			deleted.Merge(stats)
+		}
+	}
+
+	for scopeProperty, kinds := range scopedKinds {
+		if stats, err := DeleteTransitEdgesInScope(ctx, db, options.BaseKinds, options.Scope(scopeProperty), scopeProperty, kinds...); err != nil {
+			return &deleted, err
+		} else {
+			deleted.Merge(stats)
+		}
+	}
+
+	return &deleted, nil
+}
+
+// deletedKinds returns the deletions of the relationship kinds given counted by the stats
+func deletedKinds(stats *AtomicPostProcessingStats, kinds []graph.Kind) *AtomicPostProcessingStats {
+	deleted := NewAtomicPostProcessingStats()
+
+	for _, kind := range kinds {
+		for deletedKind, numDeleted := range stats.RelationshipsDeleted {
+			if deletedKind.Is(kind) {
+				deleted.AddRelationshipsDeleted(kind, atomic.LoadInt32(numDeleted))
+			}
+		}
+	}
+
+	return &deleted
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package analysis_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/specterops/bloodhound/packages/go/analysis"
+	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
+	"github.com/specterops/dawgs/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type registryState struct {
+	ran []string
+}
+
+func recordingStep(name string, err error, dependsOn ...string) analysis.PostProcessor[*registryState] {
+	return analysis.PostProcessor[*registryState]{
+		Name:      name,
+		DependsOn: dependsOn,
+		Run: func(_ context.Context, state *registryState) (*analysis.AtomicPostProcessingStats, error) {
+			state.ran = append(state.ran, name)
+
+			stats := analysis.NewAtomicPostProcessingStats()
+			return &stats, err
+		},
+	}
+}
+
+func TestPostProcessorRegistry_Register(t *testing.T) {
+	registry := analysis.NewPostProcessorRegistry[*registryState]()
+
+	require.NoError(t, registry.Register(recordingStep("a", nil)))
+	assert.ErrorIs(t, registry.Register(recordingStep("a", nil)), analysis.ErrPostProcessorExists)
+	assert.ErrorIs(t, registry.Register(analysis.PostProcessor[*registryState]{Name: "b"}), analysis.ErrPostProcessorInvalid)
+}
+
+func TestPostProcessorRegistry_Plan(t *testing.T) {
+	t.Run("steps come after their dependencies and otherwise keep their registration order", func(t *testing.T) {
+		registry := analysis.NewPostProcessorRegistry[*registryState]()
+		require.NoError(t, registry.Register(
+			recordingStep("ntlm", nil, "adcs", "expansions"),
+			recordingStep("gpos", nil),
+			recordingStep("adcs", nil, "expansions"),
+			recordingStep("expansions", nil),
+		))
+
+		plan, err := registry.Plan()
+		require.NoError(t, err)
+
+		var names []string
+		for _, processor := range plan {
+			names = append(names, processor.Name)
+		}
+
+		assert.Equal(t, []string{"gpos", "expansions", "adcs", "ntlm"}, names)
+	})
+
+	t.Run("unknown dependencies are rejected", func(t *testing.T) {
+		registry := analysis.NewPostProcessorRegistry[*registryState]()
+		require.NoError(t, registry.Register(recordingStep("a", nil, "missing")))
+
+		_, err := registry.Plan()
+		assert.ErrorIs(t, err, analysis.ErrPostProcessorUnknownDependency)
+	})
+
+	t.Run("cycles are rejected", func(t *testing.T) {
+		registry := analysis.NewPostProcessorRegistry[*registryState]()
+		require.NoError(t, registry.Register(recordingStep("a", nil, "b"), recordingStep("b", nil, "a")))
+
+		_, err := registry.Plan()
+		assert.ErrorIs(t, err, analysis.ErrPostProcessorCycle)
+	})
+}
+
+func TestPostProcessorRegistry_Run(t *testing.T) {
+	var (
+		registry = analysis.NewPostProcessorRegistry[*registryState]()
+		state    = &registryState{}
+		failure  = errors.New("failure")
+		done     []string
+	)
+
+	require.NoError(t, registry.Register(
+		recordingStep("fails", failure),
+		recordingStep("after_failure", nil, "fails"),
+		recordingStep("disabled", nil),
+		recordingStep("after_disabled", nil, "disabled"),
+		recordingStep("independent", nil),
+	))
+
+	// None of the steps create relationships, so nothing is deleted from the graph
+	results, err := registry.Run(context.Background(), nil, state, analysis.PostProcessingOptions[*registryState]{
+		Enabled: func(processor analysis.PostProcessor[*registryState]) bool {
+			return processor.Name != "disabled"
+		},
+		OnStepDone: func(result analysis.PostProcessorResult) {
+			done = append(done, result.Name)
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 5)
+
+	assert.Equal(t, []string{"fails", "independent"}, state.ran)
+	assert.Equal(t, []string{"fails", "after_failure", "disabled", "after_disabled", "independent"}, done)
+
+	assert.ErrorIs(t, results[0].Err, failure)
+	assert.ErrorIs(t, results[1].Err, analysis.ErrPostProcessorDependencyFailed)
+	assert.True(t, results[2].Skipped)
+	assert.True(t, results[3].Skipped)
+	assert.NoError(t, results[3].Err)
+	assert.NoError(t, results[4].Err)
+	assert.False(t, results[4].Skipped)
+}
+
+func TestPostProcessorRegistry_Run_Canceled(t *testing.T) {
+	var (
+		registry    = analysis.NewPostProcessorRegistry[*registryState]()
+		state       = &registryState{}
+		ctx, cancel = context.WithCancel(context.Background())
+		cancels     = recordingStep("cancels", nil)
+	)
+
+	defer cancel()
+
+	cancels.Run = func(_ context.Context, state *registryState) (*analysis.AtomicPostProcessingStats, error) {
+		state.ran = append(state.ran, "cancels")
+		cancel()
+
+		stats := analysis.NewAtomicPostProcessingStats()
+		return &stats, nil
+	}
+
+	require.NoError(t, registry.Register(cancels, recordingStep("after_cancel", nil)))
+
+	results, err := registry.Run(ctx, nil, state, analysis.PostProcessingOptions[*registryState]{})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, []string{"cancels"}, state.ran)
+	assert.NoError(t, results[0].Err)
+	assert.ErrorIs(t, results[1].Err, context.Canceled)
+}
+
+func TestPostProcessorRegistry_PostProcessedRelationships(t *testing.T) {
+	registry := analysis.NewPostProcessorRegistry[*registryState]()
+
+	owns := recordingStep("owns", nil)
+	owns.Kinds = []graph.Kind{ad.Owns, ad.WriteOwner}
+
+	ownsAgain := recordingStep("owns_again", nil)
+	ownsAgain.Kinds = []graph.Kind{ad.WriteOwner, ad.DCSync}
+
+	require.NoError(t, registry.Register(owns, ownsAgain))
+	assert.Equal(t, []graph.Kind{ad.Owns, ad.WriteOwner, ad.DCSync}, registry.PostProcessedRelationships())
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package analysis
+
+import (
+	"slices"
+
+	"github.com/specterops/dawgs/graph"
+	"github.com/specterops/dawgs/query"
+)
+
+// Scope limits post-processing to the data of some domains or tenants, identified by their domain SIDs or tenant IDs.
+// The zero value covers the whole graph.
+type Scope struct {
+	ids    []string
+	scoped bool
+}
+
+// NewScope returns a scope covering only the domains or tenants with the IDs given. A scope without IDs covers
+// nothing.
+func NewScope(ids ...string) Scope {
+	scope := Scope{scoped: true}
+	return scope.With(ids...)
+}
+
+// With returns a copy of the scope that also covers the domains or tenants with the IDs given
+func (s Scope) With(ids ...string) Scope {
+	if !s.scoped {
+		return s
+	}
+
+	extended := Scope{ids: slices.Clone(s.ids), scoped: true}
+
+	for _, id := range ids {
+		if id != "" && !slices.Contains(extended.ids, id) {
+			extended.ids = append(extended.ids, id)
+		}
+	}
+
+	return extended
+}
+
+// IsFull returns true if the scope covers the whole graph
+func (s Scope) IsFull() bool {
+	return !s.scoped
+}
+
+// IsEmpty returns true if the scope covers nothing
+func (s Scope) IsEmpty() bool {
+	return s.scoped && len(s.ids) == 0
+}
+
+// IDs returns the domain SIDs or tenant IDs covered by the scope. It is empty for a scope covering the whole graph.
+func (s Scope) IDs() []string {
+	return slices.Clone(s.ids)
+}
+
+// Contains returns true if the scope covers the domain or tenant with the ID given
+func (s Scope) Contains(id string) bool {
+	return !s.scoped || slices.Contains(s.ids, id)
+}
+
+// ContainsNode returns true if the scope covers the domain or tenant the node belongs to, as recorded by the property
+// given
+func (s Scope) ContainsNode(node *graph.Node, property string) bool {
+	if !s.scoped {
+		return true
+	} else if id, err := node.Properties.Get(property).String(); err != nil {
+		return false
+	} else {
+		return slices.Contains(s.ids, id)
+	}
+}
+
+// Filter combines the criteria given with one requiring the property reference to hold an ID covered by the scope. A
+// scope covering the whole graph adds nothing to the criteria.
+func (s Scope) Filter(reference graph.Criteria, criteria ...graph.Criteria) graph.Criteria {
+	if s.scoped {
+		criteria = append(criteria, query.In(reference, s.ids))
+	}
+
+	return query.And(criteria...)
+}
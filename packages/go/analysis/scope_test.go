@@ -0,0 +1,71 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package analysis_test
+
+import (
+	"testing"
+
+	"github.com/specterops/bloodhound/packages/go/analysis"
+	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
+	"github.com/specterops/dawgs/graph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScope(t *testing.T) {
+	t.Run("the zero value covers the whole graph", func(t *testing.T) {
+		scope := analysis.Scope{}.With("S-1-5-21-1")
+
+		assert.True(t, scope.IsFull())
+		assert.False(t, scope.IsEmpty())
+		assert.True(t, scope.Contains("S-1-5-21-2"))
+		assert.Empty(t, scope.IDs())
+	})
+
+	t.Run("a scope without IDs covers nothing", func(t *testing.T) {
+		scope := analysis.NewScope()
+
+		assert.False(t, scope.IsFull())
+		assert.True(t, scope.IsEmpty())
+		assert.False(t, scope.Contains("S-1-5-21-1"))
+	})
+
+	t.Run("extending a scope leaves the original unchanged", func(t *testing.T) {
+		var (
+			scope    = analysis.NewScope("S-1-5-21-1", "")
+			extended = scope.With("S-1-5-21-2", "S-1-5-21-1")
+		)
+
+		assert.Equal(t, []string{"S-1-5-21-1"}, scope.IDs())
+		assert.Equal(t, []string{"S-1-5-21-1", "S-1-5-21-2"}, extended.IDs())
+		assert.True(t, extended.Contains("S-1-5-21-2"))
+		assert.False(t, scope.Contains("S-1-5-21-2"))
+	})
+
+	t.Run("nodes are matched by the property given", func(t *testing.T) {
+		var (
+			scope   = analysis.NewScope("S-1-5-21-1")
+			inScope = graph.NewNode(1, graph.AsProperties(map[string]any{ad.DomainSID.String(): "S-1-5-21-1"}), ad.Computer)
+			outside = graph.NewNode(2, graph.AsProperties(map[string]any{ad.DomainSID.String(): "S-1-5-21-2"}), ad.Computer)
+			unknown = graph.NewNode(3, graph.NewProperties(), ad.Computer)
+		)
+
+		assert.True(t, scope.ContainsNode(inScope, ad.DomainSID.String()))
+		assert.False(t, scope.ContainsNode(outside, ad.DomainSID.String()))
+		assert.False(t, scope.ContainsNode(unknown, ad.DomainSID.String()))
+		assert.True(t, analysis.Scope{}.ContainsNode(unknown, ad.DomainSID.String()))
+	})
+}
@@ -0,0 +1,240 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ein
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/specterops/bloodhound/packages/go/analysis"
+	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
+	"github.com/specterops/bloodhound/packages/go/graphschema/azure"
+	"github.com/specterops/bloodhound/packages/go/graphschema/common"
+	"github.com/specterops/dawgs/graph"
+)
+
+const (
+	ApocRecordTypeNode         = "node"
+	ApocRecordTypeRelationship = "relationship"
+)
+
+// legacyKindNames maps the kinds BloodHound Legacy stored under a different name to their current kind
+var legacyKindNames = map[string]graph.Kind{
+	"GpLink": ad.GPLink,
+}
+
+// ApocID is the identifier APOC gives to a node or relationship in an export. Depending on the version of APOC it is
+// written either as a number or as a string.
+type ApocID string
+
+func (s *ApocID) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '"' {
+		return json.Unmarshal(trimmed, (*string)(s))
+	} else {
+		var number json.Number
+		if err := json.Unmarshal(trimmed, &number); err != nil {
+			return err
+		}
+
+		*s = ApocID(number.String())
+		return nil
+	}
+}
+
+// ApocNodeReference is the start or end node of a relationship record. Properties are only written when the export
+// was run with writeNodeProperties enabled.
+type ApocNodeReference struct {
+	ID         ApocID         `json:"id"`
+	Labels     []string       `json:"labels"`
+	Properties map[string]any `json:"properties"`
+}
+
+// ApocRecord is a single node or relationship of a JSON export written by apoc.export.json.all, or one of the other
+// apoc.export.json procedures, in their default JSON lines format
+type ApocRecord struct {
+	Type       string            `json:"type"`
+	ID         ApocID            `json:"id"`
+	Labels     []string          `json:"labels"`
+	Label      string            `json:"label"`
+	Properties map[string]any    `json:"properties"`
+	Start      ApocNodeReference `json:"start"`
+	End        ApocNodeReference `json:"end"`
+}
+
+// ApocNodeKinds is the identity of a node in an APOC export: its base kind, ad.Entity or azure.Entity, and the rest
+// of its AD or Azure kinds
+type ApocNodeKinds struct {
+	Base  graph.Kind
+	Kinds graph.Kinds
+}
+
+// ParseApocNodeKinds maps the labels of a node in a BloodHound Legacy database to current kinds. Labels that are not
+// AD or Azure node kinds are dropped. Returns false if the labels do not identify an AD or Azure node.
+func ParseApocNodeKinds(labels []string) (ApocNodeKinds, bool) {
+	var (
+		parsed     ApocNodeKinds
+		isAD       bool
+		isAzure    bool
+		adKinds    = graph.Kinds(ad.NodeKinds())
+		azureKinds = graph.Kinds(azure.NodeKinds())
+	)
+
+	for _, label := range labels {
+		if kind, err := analysis.ParseKind(label); err != nil {
+			continue
+		} else if kind.Is(ad.Entity) {
+			isAD = true
+		} else if kind.Is(azure.Entity) {
+			isAzure = true
+		} else if adKinds.ContainsOneOf(kind) {
+			isAD = true
+			parsed.Kinds = append(parsed.Kinds, kind)
+		} else if azureKinds.ContainsOneOf(kind) {
+			isAzure = true
+			parsed.Kinds = append(parsed.Kinds, kind)
+		}
+	}
+
+	switch {
+	case isAD && !isAzure:
+		parsed.Base = ad.Entity
+	case isAzure && !isAD:
+		parsed.Base = azure.Entity
+	default:
+		return ApocNodeKinds{}, false
+	}
+
+	return parsed, true
+}
+
+// ParseApocRelationshipKind maps the type of a relationship in a BloodHound Legacy database to its current AD or
+// Azure relationship kind, returning false if there is none
+func ParseApocRelationshipKind(label string) (graph.Kind, bool) {
+	if kind, ok := legacyKindNames[label]; ok {
+		return kind, true
+	} else if kind, err := analysis.ParseKind(label); err != nil {
+		return nil, false
+	} else if graph.Kinds(ad.Relationships()).ContainsOneOf(kind) || graph.Kinds(azure.Relationships()).ContainsOneOf(kind) {
+		return kind, true
+	} else {
+		return nil, false
+	}
+}
+
+// ApocObjectID returns the object ID stored in the properties of an exported node, uppercased as current ingest
+// stores object IDs
+func ApocObjectID(properties map[string]any) (string, bool) {
+	if objectID, ok := properties[common.ObjectID.String()].(string); ok && objectID != "" {
+		return strings.ToUpper(objectID), true
+	}
+
+	return "", false
+}
+
+// ApocNode is what is kept of a converted node of an APOC export to resolve the relationships that reference it by its
+// APOC identifier
+type ApocNode struct {
+	ApocNodeKinds
+	ObjectID string
+	Name     string
+}
+
+// ConvertApocNode converts a node record of an APOC export, returning false if the node has no object ID or is not an
+// AD or Azure node
+func ConvertApocNode(record ApocRecord) (IngestibleNode, ApocNode, bool) {
+	if objectID, ok := ApocObjectID(record.Properties); !ok {
+		return IngestibleNode{}, ApocNode{}, false
+	} else if kinds, ok := ParseApocNodeKinds(record.Labels); !ok {
+		return IngestibleNode{}, ApocNode{}, false
+	} else {
+		name, _ := record.Properties[common.Name.String()].(string)
+
+		return IngestibleNode{
+			ObjectID:    objectID,
+			PropertyMap: record.Properties,
+			Labels:      kinds.Kinds,
+		}, ApocNode{
+			ApocNodeKinds: kinds,
+			ObjectID:      objectID,
+			Name:          strings.ToUpper(name),
+		}, true
+	}
+}
+
+// apocLocalGroupRights are the relationships BloodHound Legacy derived from membership of a local group of a computer.
+// Current collections write the local groups themselves, and the relationships are derived from them by post-processing.
+var apocLocalGroupRights = map[graph.Kind]legacyLocalGroup{
+	ad.AdminTo:     legacyAdministrators,
+	ad.CanRDP:      legacyRemoteDesktopUsers,
+	ad.ExecuteDCOM: legacyDistributedCOMUsers,
+	ad.CanPSRemote: legacyRemoteManagementUsers,
+}
+
+// apocRawRights are the relationships BloodHound Legacy wrote directly that are now derived by post-processing from a
+// raw relationship
+var apocRawRights = map[graph.Kind]graph.Kind{
+	ad.Owns:       ad.OwnsRaw,
+	ad.WriteOwner: ad.WriteOwnerRaw,
+}
+
+// ConvertApocRelationship converts a relationship record of an APOC export between the start and end nodes given.
+// Rights granted by membership of a local group are converted to the membership of that local group, and ownership
+// rights to their raw relationships. Returns false if the relationship is not an AD or Azure relationship.
+func ConvertApocRelationship(record ApocRecord, start, end ApocNode) (ParsedLocalGroupData, bool) {
+	var parsed ParsedLocalGroupData
+
+	kind, ok := ParseApocRelationshipKind(record.Label)
+	if !ok {
+		return parsed, false
+	}
+
+	properties := record.Properties
+	if properties == nil {
+		properties = map[string]any{}
+	}
+
+	if localGroup, ok := apocLocalGroupRights[kind]; ok && end.Kinds.ContainsOneOf(ad.Computer) {
+		member := TypedPrincipal{ObjectIdentifier: start.ObjectID}
+		if len(start.Kinds) > 0 {
+			member.ObjectType = start.Kinds[0].String()
+		}
+
+		return ConvertLocalGroup(localGroup.APIResult(end.ObjectID, end.Name, APIResult{Collected: true}, []TypedPrincipal{member}), Computer{
+			IngestBase: IngestBase{ObjectIdentifier: end.ObjectID},
+		}), true
+	} else if rawKind, ok := apocRawRights[kind]; ok {
+		kind = rawKind
+	}
+
+	parsed.Relationships = append(parsed.Relationships, NewIngestibleRelationship(
+		IngestibleEndpoint{
+			Value: start.ObjectID,
+			Kind:  start.Base,
+		},
+		IngestibleEndpoint{
+			Value: end.ObjectID,
+			Kind:  end.Base,
+		},
+		IngestibleRel{
+			RelProps: properties,
+			RelType:  kind,
+		},
+	))
+
+	return parsed, true
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ein_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/specterops/bloodhound/packages/go/ein"
+	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
+	"github.com/specterops/bloodhound/packages/go/graphschema/azure"
+	"github.com/specterops/dawgs/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApocRecord_Unmarshal(t *testing.T) {
+	var record ein.ApocRecord
+	require.NoError(t, json.Unmarshal([]byte(`{"type":"relationship","id":"7","label":"MemberOf","properties":{"isacl":false},"start":{"id":0,"labels":["Base","User"]},"end":{"id":"1","labels":["Base","Group"]}}`), &record))
+
+	assert.Equal(t, ein.ApocRecordTypeRelationship, record.Type)
+	assert.Equal(t, ein.ApocID("7"), record.ID)
+	assert.Equal(t, ein.ApocID("0"), record.Start.ID)
+	assert.Equal(t, ein.ApocID("1"), record.End.ID)
+}
+
+func TestParseApocNodeKinds(t *testing.T) {
+	kinds, ok := ein.ParseApocNodeKinds([]string{"Base", "User", "highvalue"})
+	require.True(t, ok)
+	assert.Equal(t, ein.ApocNodeKinds{Base: ad.Entity, Kinds: graph.Kinds{ad.User}}, kinds)
+
+	kinds, ok = ein.ParseApocNodeKinds([]string{"AZBase", "AZUser"})
+	require.True(t, ok)
+	assert.Equal(t, ein.ApocNodeKinds{Base: azure.Entity, Kinds: graph.Kinds{azure.User}}, kinds)
+
+	_, ok = ein.ParseApocNodeKinds([]string{"Unknown"})
+	assert.False(t, ok)
+}
+
+func TestParseApocRelationshipKind(t *testing.T) {
+	kind, ok := ein.ParseApocRelationshipKind("GpLink")
+	require.True(t, ok)
+	assert.Equal(t, ad.GPLink, kind)
+
+	kind, ok = ein.ParseApocRelationshipKind("AZOwns")
+	require.True(t, ok)
+	assert.Equal(t, azure.Owns, kind)
+
+	_, ok = ein.ParseApocRelationshipKind("Unknown")
+	assert.False(t, ok)
+}
+
+func TestConvertApocNode(t *testing.T) {
+	node, apocNode, ok := ein.ConvertApocNode(ein.ApocRecord{
+		Type:       ein.ApocRecordTypeNode,
+		Labels:     []string{"Base", "Computer"},
+		Properties: map[string]any{"objectid": "s-1-5-21-1-1000", "name": "ws01.testlab.local"},
+	})
+	require.True(t, ok)
+	assert.Equal(t, "S-1-5-21-1-1000", node.ObjectID)
+	assert.Equal(t, []graph.Kind{ad.Computer}, node.Labels)
+	assert.Equal(t, ein.ApocNode{
+		ApocNodeKinds: ein.ApocNodeKinds{Base: ad.Entity, Kinds: graph.Kinds{ad.Computer}},
+		ObjectID:      "S-1-5-21-1-1000",
+		Name:          "WS01.TESTLAB.LOCAL",
+	}, apocNode)
+
+	_, _, ok = ein.ConvertApocNode(ein.ApocRecord{Labels: []string{"Base", "User"}, Properties: map[string]any{"name": "NO ID"}})
+	assert.False(t, ok)
+}
+
+func TestConvertApocRelationship(t *testing.T) {
+	var (
+		user     = ein.ApocNode{ApocNodeKinds: ein.ApocNodeKinds{Base: ad.Entity, Kinds: graph.Kinds{ad.User}}, ObjectID: "S-1-5-21-1-1104"}
+		group    = ein.ApocNode{ApocNodeKinds: ein.ApocNodeKinds{Base: ad.Entity, Kinds: graph.Kinds{ad.Group}}, ObjectID: "S-1-5-21-1-512"}
+		computer = ein.ApocNode{ApocNodeKinds: ein.ApocNodeKinds{Base: ad.Entity, Kinds: graph.Kinds{ad.Computer}}, ObjectID: "S-1-5-21-1-1000", Name: "WS01.TESTLAB.LOCAL"}
+	)
+
+	t.Run("relationship", func(t *testing.T) {
+		parsed, ok := ein.ConvertApocRelationship(ein.ApocRecord{Label: "MemberOf", Properties: map[string]any{"isacl": false}}, user, group)
+		require.True(t, ok)
+		assert.Empty(t, parsed.Nodes)
+		require.Len(t, parsed.Relationships, 1)
+		assert.Equal(t, ad.MemberOf, parsed.Relationships[0].RelType)
+		assert.Equal(t, ein.IngestibleEndpoint{Value: user.ObjectID, Kind: ad.Entity}, parsed.Relationships[0].Source)
+		assert.Equal(t, ein.IngestibleEndpoint{Value: group.ObjectID, Kind: ad.Entity}, parsed.Relationships[0].Target)
+	})
+
+	t.Run("ownership is converted to its raw relationship", func(t *testing.T) {
+		parsed, ok := ein.ConvertApocRelationship(ein.ApocRecord{Label: "Owns"}, group, computer)
+		require.True(t, ok)
+		require.Len(t, parsed.Relationships, 1)
+		assert.Equal(t, ad.OwnsRaw, parsed.Relationships[0].RelType)
+	})
+
+	t.Run("local group rights are converted to local group membership", func(t *testing.T) {
+		parsed, ok := ein.ConvertApocRelationship(ein.ApocRecord{Label: "CanRDP"}, user, computer)
+		require.True(t, ok)
+		require.Len(t, parsed.Nodes, 1)
+		assert.Equal(t, "S-1-5-21-1-1000-555", parsed.Nodes[0].ObjectID)
+		assert.Equal(t, "REMOTE DESKTOP USERS@WS01.TESTLAB.LOCAL", parsed.Nodes[0].PropertyMap["name"])
+		require.Len(t, parsed.Relationships, 2)
+		assert.Equal(t, ad.LocalToComputer, parsed.Relationships[0].RelType)
+		assert.Equal(t, ad.MemberOfLocalGroup, parsed.Relationships[1].RelType)
+		assert.Equal(t, ein.IngestibleEndpoint{Value: user.ObjectID, Kind: ad.User}, parsed.Relationships[1].Source)
+	})
+
+	t.Run("unknown relationship", func(t *testing.T) {
+		_, ok := ein.ConvertApocRelationship(ein.ApocRecord{Label: "Unknown"}, user, group)
+		assert.False(t, ok)
+	})
+}
@@ -363,7 +363,43 @@ type OU struct {
 }
 
 type GenericMetadata struct {
-	SourceKind string `json:"source_kind"`
+	SourceKind string         `json:"source_kind"`
+	Schema     *GenericSchema `json:"schema"`
+}
+
+// GenericSchema declares the node and edge kinds of an OpenGraph payload
+type GenericSchema struct {
+	NodeKinds []GenericNodeKind `json:"node_kinds"`
+	EdgeKinds []GenericEdgeKind `json:"edge_kinds"`
+}
+
+type GenericNodeKind struct {
+	Name        string                `json:"name"`
+	DisplayName string                `json:"display_name"`
+	Icon        *GenericNodeIcon      `json:"icon"`
+	Properties  []GenericPropertyType `json:"properties"`
+}
+
+type GenericNodeIcon struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type GenericEdgeKind struct {
+	Name        string                `json:"name"`
+	DisplayName string                `json:"display_name"`
+	Traversable bool                  `json:"traversable"`
+	Properties  []GenericPropertyType `json:"properties"`
+}
+
+// GenericPropertyType declares a property of a node or edge kind. Only the properties of edge kinds may be required.
+type GenericPropertyType struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Type        string `json:"type"`
+	Indexed     bool   `json:"indexed"`
+	Required    bool   `json:"required"`
 }
 
 type GenericNode struct {
@@ -380,7 +416,17 @@ type GenericEdge struct {
 }
 
 type EdgeEndpoint struct {
-	Value   string
-	Kind    string
-	MatchBy string `json:"match_by"`
+	Value         string
+	Kind          string
+	MatchBy       string `json:"match_by"`
+	Property      string `json:"property"`
+	CaseSensitive bool   `json:"case_sensitive"`
+}
+
+// GenericDeletedEdge is an entry of the deleted_edges section of an OpenGraph payload. Its endpoints are matched the same
+// way as those of GenericEdge. Entries of the deleted_nodes section are matched as a single EdgeEndpoint.
+type GenericDeletedEdge struct {
+	Start EdgeEndpoint
+	End   EdgeEndpoint
+	Kind  string
 }
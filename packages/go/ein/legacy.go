@@ -0,0 +1,417 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ein
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
+)
+
+// The models in this file decode the JSON written by SharpHound before v5 of the collection format: v3 (SharpHound 3)
+// and v4 (SharpHound 1.x). Each model upgrades to the model of the current format so that legacy collections are
+// converted to the graph exactly like current ones.
+
+// LegacyRightNames maps the RightName and AceType pair written by v3 collections for extended rights and property
+// writes to the right name used since v4
+var LegacyRightNames = map[string]map[string]string{
+	"ExtendedRight": {
+		"All":                        ad.AllExtendedRights.String(),
+		"User-Force-Change-Password": ad.ForceChangePassword.String(),
+		"GetChanges":                 ad.GetChanges.String(),
+		"GetChangesAll":              ad.GetChangesAll.String(),
+	},
+	"WriteProperty": {
+		"AddMember":    ad.AddMember.String(),
+		"AllowedToAct": ad.AddAllowedToAct.String(),
+	},
+}
+
+// Trust directions and types were written by legacy collections as the values of the enumerations below
+var (
+	legacyTrustDirections = []string{TrustDirectionDisabled, TrustDirectionInbound, TrustDirectionOutbound, TrustDirectionBidirectional}
+	legacyTrustTypes      = []string{"ParentChild", "CrossLink", "Forest", "External", "Unknown"}
+)
+
+// LegacyTypedPrincipal is a principal written either as {ObjectIdentifier, ObjectType} (v4), as {MemberId, MemberType}
+// (v3), or as a bare object identifier string where v3 did not record the type of the principal
+type LegacyTypedPrincipal struct {
+	ObjectIdentifier string
+	ObjectType       string
+	MemberId         string
+	MemberType       string
+}
+
+func (s *LegacyTypedPrincipal) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '"' {
+		return json.Unmarshal(trimmed, &s.ObjectIdentifier)
+	}
+
+	type legacyTypedPrincipal LegacyTypedPrincipal
+	return json.Unmarshal(data, (*legacyTypedPrincipal)(s))
+}
+
+func (s LegacyTypedPrincipal) Upgrade() TypedPrincipal {
+	if s.ObjectIdentifier == "" {
+		return TypedPrincipal{ObjectIdentifier: s.MemberId, ObjectType: s.MemberType}
+	}
+
+	return TypedPrincipal{ObjectIdentifier: s.ObjectIdentifier, ObjectType: s.ObjectType}
+}
+
+func upgradeLegacyPrincipals(principals []LegacyTypedPrincipal) []TypedPrincipal {
+	upgraded := make([]TypedPrincipal, 0, len(principals))
+	for _, principal := range principals {
+		upgraded = append(upgraded, principal.Upgrade())
+	}
+
+	return upgraded
+}
+
+// legacyPrincipals builds the principals of legacy fields that only held object identifiers of a single type
+func legacyPrincipals(objectIdentifiers []string, objectType string) []TypedPrincipal {
+	principals := make([]TypedPrincipal, 0, len(objectIdentifiers))
+	for _, objectIdentifier := range objectIdentifiers {
+		principals = append(principals, TypedPrincipal{ObjectIdentifier: objectIdentifier, ObjectType: objectType})
+	}
+
+	return principals
+}
+
+// LegacyAPIResult is the result of a computer API call, written as a bare array of results by v3 collections and as
+// an object holding the results and collection status by v4 collections
+type LegacyAPIResult[T any] struct {
+	APIResult
+	Results []T
+}
+
+func (s *LegacyAPIResult[T]) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		// v3 collections only wrote results that were collected
+		s.Collected = true
+		return json.Unmarshal(trimmed, &s.Results)
+	}
+
+	type legacyAPIResult LegacyAPIResult[T]
+	return json.Unmarshal(data, (*legacyAPIResult)(s))
+}
+
+// LegacyACE is an ACE as written by v3 and v4 collections. v3 collections qualify extended rights and property writes
+// with the AceType; v4 collections do not write inheritance hashes.
+type LegacyACE struct {
+	PrincipalSID  string
+	PrincipalType string
+	RightName     string
+	AceType       string
+	IsInherited   bool
+}
+
+func (s LegacyACE) Upgrade() ACE {
+	rightName := s.RightName
+	if qualifiedRights, ok := LegacyRightNames[s.RightName]; ok {
+		if qualifiedRight, ok := qualifiedRights[s.AceType]; ok {
+			rightName = qualifiedRight
+		}
+	}
+
+	return ACE{
+		PrincipalSID:  s.PrincipalSID,
+		PrincipalType: s.PrincipalType,
+		RightName:     rightName,
+		IsInherited:   s.IsInherited,
+	}
+}
+
+type LegacyIngestBase struct {
+	ObjectIdentifier string
+	Properties       map[string]any
+	Aces             []LegacyACE
+	IsDeleted        bool
+	IsACLProtected   bool
+}
+
+func (s LegacyIngestBase) Upgrade() IngestBase {
+	aces := make([]ACE, 0, len(s.Aces))
+	for _, ace := range s.Aces {
+		aces = append(aces, ace.Upgrade())
+	}
+
+	objectIdentifier := s.ObjectIdentifier
+	if objectIdentifier == "" {
+		// v3 collections of some object types only wrote the object identifier to the properties
+		if propertyID, ok := s.Properties["objectid"].(string); ok {
+			objectIdentifier = propertyID
+		}
+	}
+
+	return IngestBase{
+		ObjectIdentifier: objectIdentifier,
+		Properties:       s.Properties,
+		Aces:             aces,
+		IsDeleted:        s.IsDeleted,
+		IsACLProtected:   s.IsACLProtected,
+	}
+}
+
+// legacyStringProperty returns a string property of a legacy object, or an empty string if it is not set
+func (s LegacyIngestBase) legacyStringProperty(name string) string {
+	value, _ := s.Properties[name].(string)
+	return value
+}
+
+// LegacySession is a session written either with UserSID and ComputerSID (v4) or with UserId and ComputerId (v3)
+type LegacySession struct {
+	UserSID     string
+	ComputerSID string
+	UserId      string
+	ComputerId  string
+}
+
+func (s LegacySession) Upgrade() Session {
+	if s.UserSID == "" && s.ComputerSID == "" {
+		return Session{UserSID: s.UserId, ComputerSID: s.ComputerId}
+	}
+
+	return Session{UserSID: s.UserSID, ComputerSID: s.ComputerSID}
+}
+
+func upgradeLegacySessions(sessions LegacyAPIResult[LegacySession]) SessionAPIResult {
+	upgraded := SessionAPIResult{
+		APIResult: sessions.APIResult,
+		Results:   make([]Session, 0, len(sessions.Results)),
+	}
+
+	for _, session := range sessions.Results {
+		upgraded.Results = append(upgraded.Results, session.Upgrade())
+	}
+
+	return upgraded
+}
+
+type LegacyComputer struct {
+	LegacyIngestBase
+	PrimaryGroupSID    string
+	AllowedToDelegate  []LegacyTypedPrincipal
+	AllowedToAct       []LegacyTypedPrincipal
+	Sessions           LegacyAPIResult[LegacySession]
+	PrivilegedSessions LegacyAPIResult[LegacySession]
+	RegistrySessions   LegacyAPIResult[LegacySession]
+	LocalAdmins        LegacyAPIResult[LegacyTypedPrincipal]
+	RemoteDesktopUsers LegacyAPIResult[LegacyTypedPrincipal]
+	DcomUsers          LegacyAPIResult[LegacyTypedPrincipal]
+	PSRemoteUsers      LegacyAPIResult[LegacyTypedPrincipal]
+	Status             ComputerStatus
+	HasSIDHistory      []LegacyTypedPrincipal
+}
+
+// legacyLocalGroup is a local group of a computer that legacy collections did not write as a group, only as the
+// principals the group grants access to the computer
+type legacyLocalGroup struct {
+	RID  string
+	Name string
+}
+
+var (
+	legacyAdministrators        = legacyLocalGroup{RID: "544", Name: "ADMINISTRATORS"}
+	legacyRemoteDesktopUsers    = legacyLocalGroup{RID: "555", Name: "REMOTE DESKTOP USERS"}
+	legacyDistributedCOMUsers   = legacyLocalGroup{RID: "562", Name: "DISTRIBUTED COM USERS"}
+	legacyRemoteManagementUsers = legacyLocalGroup{RID: "580", Name: "REMOTE MANAGEMENT USERS"}
+)
+
+// APIResult returns the local group of the computer given, with the members given, as current collections write it
+func (s legacyLocalGroup) APIResult(computerSID, computerName string, result APIResult, members []TypedPrincipal) LocalGroupAPIResult {
+	return LocalGroupAPIResult{
+		APIResult:        result,
+		Results:          members,
+		Name:             fmt.Sprintf("%s@%s", s.Name, computerName),
+		ObjectIdentifier: computerSID + "-" + s.RID,
+	}
+}
+
+// Upgrade converts a legacy computer to the current model. Legacy collections wrote the members of the local groups
+// that grant access to a computer as separate lists; these become the local groups that current collections write, so
+// that AdminTo, CanRDP, ExecuteDCOM and CanPSRemote are derived from them by post-processing.
+func (s LegacyComputer) Upgrade() Computer {
+	computer := Computer{
+		IngestBase:         s.LegacyIngestBase.Upgrade(),
+		PrimaryGroupSID:    s.PrimaryGroupSID,
+		AllowedToDelegate:  upgradeLegacyPrincipals(s.AllowedToDelegate),
+		AllowedToAct:       upgradeLegacyPrincipals(s.AllowedToAct),
+		Sessions:           upgradeLegacySessions(s.Sessions),
+		PrivilegedSessions: upgradeLegacySessions(s.PrivilegedSessions),
+		RegistrySessions:   upgradeLegacySessions(s.RegistrySessions),
+		Status:             s.Status,
+		HasSIDHistory:      upgradeLegacyPrincipals(s.HasSIDHistory),
+		DomainSID:          s.legacyStringProperty("domainsid"),
+	}
+
+	computerName := s.legacyStringProperty("name")
+	for _, localGroup := range []struct {
+		Group   legacyLocalGroup
+		Members LegacyAPIResult[LegacyTypedPrincipal]
+	}{
+		{legacyAdministrators, s.LocalAdmins},
+		{legacyRemoteDesktopUsers, s.RemoteDesktopUsers},
+		{legacyDistributedCOMUsers, s.DcomUsers},
+		{legacyRemoteManagementUsers, s.PSRemoteUsers},
+	} {
+		computer.LocalGroups = append(computer.LocalGroups, localGroup.Group.APIResult(computer.ObjectIdentifier, computerName, localGroup.Members.APIResult, upgradeLegacyPrincipals(localGroup.Members.Results)))
+	}
+
+	return computer
+}
+
+type LegacyUser struct {
+	LegacyIngestBase
+	AllowedToDelegate []LegacyTypedPrincipal
+	SPNTargets        []SPNTarget
+	PrimaryGroupSID   string
+	HasSIDHistory     []LegacyTypedPrincipal
+}
+
+func (s LegacyUser) Upgrade() User {
+	return User{
+		IngestBase:        s.LegacyIngestBase.Upgrade(),
+		AllowedToDelegate: upgradeLegacyPrincipals(s.AllowedToDelegate),
+		SPNTargets:        s.SPNTargets,
+		PrimaryGroupSID:   s.PrimaryGroupSID,
+		HasSIDHistory:     upgradeLegacyPrincipals(s.HasSIDHistory),
+		DomainSID:         s.legacyStringProperty("domainsid"),
+	}
+}
+
+type LegacyGroup struct {
+	LegacyIngestBase
+	Members []LegacyTypedPrincipal
+}
+
+func (s LegacyGroup) Upgrade() Group {
+	return Group{
+		IngestBase: s.LegacyIngestBase.Upgrade(),
+		Members:    upgradeLegacyPrincipals(s.Members),
+	}
+}
+
+type LegacyGPO LegacyIngestBase
+
+func (s LegacyGPO) Upgrade() GPO {
+	return GPO(LegacyIngestBase(s).Upgrade())
+}
+
+type LegacyContainer struct {
+	LegacyIngestBase
+	ChildObjects []LegacyTypedPrincipal
+}
+
+func (s LegacyContainer) Upgrade() Container {
+	return Container{
+		IngestBase:   s.LegacyIngestBase.Upgrade(),
+		ChildObjects: upgradeLegacyPrincipals(s.ChildObjects),
+	}
+}
+
+// LegacyChildObjects holds the children of a domain or OU. v4 collections write them as ChildObjects, while v3
+// collections listed the users, computers and OUs directly contained by the object.
+type LegacyChildObjects struct {
+	ChildObjects []LegacyTypedPrincipal
+	Users        []string
+	Computers    []string
+	ChildOus     []string
+}
+
+func (s LegacyChildObjects) Upgrade() []TypedPrincipal {
+	return append(upgradeLegacyPrincipals(s.ChildObjects), append(append(
+		legacyPrincipals(s.Users, ad.User.String()),
+		legacyPrincipals(s.Computers, ad.Computer.String())...),
+		legacyPrincipals(s.ChildOus, ad.OU.String())...)...)
+}
+
+// LegacyTrust is a domain trust whose direction and type may have been written as the values of their enumerations
+type LegacyTrust struct {
+	TargetDomainSid     string
+	TargetDomainName    string
+	IsTransitive        bool
+	SidFilteringEnabled bool
+	TrustDirection      any
+	TrustType           any
+}
+
+func (s LegacyTrust) Upgrade() Trust {
+	return Trust{
+		TargetDomainSid:     s.TargetDomainSid,
+		TargetDomainName:    s.TargetDomainName,
+		IsTransitive:        s.IsTransitive,
+		SidFilteringEnabled: s.SidFilteringEnabled,
+		TrustDirection:      legacyEnumName(s.TrustDirection, legacyTrustDirections),
+		TrustType:           legacyEnumName(s.TrustType, legacyTrustTypes),
+	}
+}
+
+// legacyEnumName returns the name of an enumeration value that was written either by name or by its index in names
+func legacyEnumName(value any, names []string) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case float64:
+		if index := int(typed); index >= 0 && index < len(names) {
+			return names[index]
+		}
+	}
+
+	return ""
+}
+
+type LegacyDomain struct {
+	LegacyIngestBase
+	LegacyChildObjects
+	Trusts     []LegacyTrust
+	Links      []GPLink
+	GPOChanges GPOChanges
+}
+
+func (s LegacyDomain) Upgrade() Domain {
+	trusts := make([]Trust, 0, len(s.Trusts))
+	for _, trust := range s.Trusts {
+		trusts = append(trusts, trust.Upgrade())
+	}
+
+	return Domain{
+		IngestBase:   s.LegacyIngestBase.Upgrade(),
+		ChildObjects: s.LegacyChildObjects.Upgrade(),
+		Trusts:       trusts,
+		Links:        s.Links,
+		GPOChanges:   s.GPOChanges,
+	}
+}
+
+type LegacyOU struct {
+	LegacyIngestBase
+	LegacyChildObjects
+	Links      []GPLink
+	GPOChanges GPOChanges
+}
+
+func (s LegacyOU) Upgrade() OU {
+	return OU{
+		IngestBase:   s.LegacyIngestBase.Upgrade(),
+		ChildObjects: s.LegacyChildObjects.Upgrade(),
+		Links:        s.Links,
+		GPOChanges:   s.GPOChanges,
+	}
+}
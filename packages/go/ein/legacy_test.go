@@ -0,0 +1,115 @@
+// Copyright 2025 Specter Ops, Inc.
+//
+// Licensed under the Apache License, Version 2.0
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package ein_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/specterops/bloodhound/packages/go/ein"
+	"github.com/specterops/bloodhound/packages/go/graphschema/ad"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLegacyComputer_Upgrade(t *testing.T) {
+	t.Run("v3 computer", func(t *testing.T) {
+		var legacy ein.LegacyComputer
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"ObjectIdentifier": "S-1-5-21-1-1000",
+			"Properties": {"name": "WS01.TESTLAB.LOCAL", "domainsid": "S-1-5-21-1"},
+			"Aces": [{"PrincipalSID": "S-1-5-21-1-512", "PrincipalType": "Group", "RightName": "ExtendedRight", "AceType": "All", "IsInherited": false}],
+			"LocalAdmins": [{"MemberId": "S-1-5-21-1-512", "MemberType": "Group"}],
+			"RemoteDesktopUsers": [],
+			"Sessions": [{"UserId": "S-1-5-21-1-1104", "ComputerId": "S-1-5-21-1-1000"}],
+			"AllowedToDelegate": ["S-1-5-21-1-1001"]
+		}`), &legacy))
+
+		computer := legacy.Upgrade()
+
+		assert.Equal(t, "S-1-5-21-1-1000", computer.ObjectIdentifier)
+		assert.Equal(t, "S-1-5-21-1", computer.DomainSID)
+		assert.Equal(t, []ein.ACE{{PrincipalSID: "S-1-5-21-1-512", PrincipalType: "Group", RightName: ad.AllExtendedRights.String()}}, computer.Aces)
+		assert.Equal(t, []ein.TypedPrincipal{{ObjectIdentifier: "S-1-5-21-1-1001"}}, computer.AllowedToDelegate)
+		assert.True(t, computer.Sessions.Collected)
+		assert.Equal(t, []ein.Session{{UserSID: "S-1-5-21-1-1104", ComputerSID: "S-1-5-21-1-1000"}}, computer.Sessions.Results)
+
+		require.Len(t, computer.LocalGroups, 4)
+		assert.Equal(t, "S-1-5-21-1-1000-544", computer.LocalGroups[0].ObjectIdentifier)
+		assert.Equal(t, "ADMINISTRATORS@WS01.TESTLAB.LOCAL", computer.LocalGroups[0].Name)
+		assert.True(t, computer.LocalGroups[0].Collected)
+		assert.Equal(t, []ein.TypedPrincipal{{ObjectIdentifier: "S-1-5-21-1-512", ObjectType: "Group"}}, computer.LocalGroups[0].Results)
+		assert.Equal(t, "S-1-5-21-1-1000-555", computer.LocalGroups[1].ObjectIdentifier)
+		assert.True(t, computer.LocalGroups[1].Collected)
+		assert.Empty(t, computer.LocalGroups[1].Results)
+		assert.False(t, computer.LocalGroups[2].Collected)
+	})
+
+	t.Run("v4 computer", func(t *testing.T) {
+		var legacy ein.LegacyComputer
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"ObjectIdentifier": "S-1-5-21-1-1000",
+			"Properties": {"name": "WS01.TESTLAB.LOCAL"},
+			"LocalAdmins": {"Collected": true, "Results": [{"ObjectIdentifier": "S-1-5-21-1-512", "ObjectType": "Group"}]},
+			"Sessions": {"Collected": true, "Results": [{"UserSID": "S-1-5-21-1-1104", "ComputerSID": "S-1-5-21-1-1000"}]}
+		}`), &legacy))
+
+		computer := legacy.Upgrade()
+
+		assert.Equal(t, []ein.Session{{UserSID: "S-1-5-21-1-1104", ComputerSID: "S-1-5-21-1-1000"}}, computer.Sessions.Results)
+		assert.Equal(t, []ein.TypedPrincipal{{ObjectIdentifier: "S-1-5-21-1-512", ObjectType: "Group"}}, computer.LocalGroups[0].Results)
+	})
+}
+
+func TestLegacyACE_Upgrade(t *testing.T) {
+	for _, tc := range []struct {
+		ace      ein.LegacyACE
+		expected string
+	}{
+		{ein.LegacyACE{RightName: "ExtendedRight", AceType: "User-Force-Change-Password"}, ad.ForceChangePassword.String()},
+		{ein.LegacyACE{RightName: "ExtendedRight", AceType: "GetChangesAll"}, ad.GetChangesAll.String()},
+		{ein.LegacyACE{RightName: "WriteProperty", AceType: "AddMember"}, ad.AddMember.String()},
+		{ein.LegacyACE{RightName: "WriteProperty", AceType: "AllowedToAct"}, ad.AddAllowedToAct.String()},
+		{ein.LegacyACE{RightName: "GenericAll"}, ad.GenericAll.String()},
+		{ein.LegacyACE{RightName: "AddKeyCredentialLink"}, ad.AddKeyCredentialLink.String()},
+	} {
+		assert.Equal(t, tc.expected, tc.ace.Upgrade().RightName)
+	}
+}
+
+func TestLegacyDomain_Upgrade(t *testing.T) {
+	var legacy ein.LegacyDomain
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"Properties": {"objectid": "S-1-5-21-1", "name": "TESTLAB.LOCAL"},
+		"Users": ["S-1-5-21-1-1104"],
+		"Computers": ["S-1-5-21-1-1000"],
+		"ChildOus": ["2A3B6A8C-4F35-4D14-8D13-1B48F0B1C3A7"],
+		"Trusts": [{"TargetDomainSid": "S-1-5-21-2", "TargetDomainName": "EXTERNAL.LOCAL", "IsTransitive": true, "TrustDirection": 3, "TrustType": 2}]
+	}`), &legacy))
+
+	domain := legacy.Upgrade()
+
+	assert.Equal(t, "S-1-5-21-1", domain.ObjectIdentifier)
+	assert.Equal(t, []ein.TypedPrincipal{
+		{ObjectIdentifier: "S-1-5-21-1-1104", ObjectType: ad.User.String()},
+		{ObjectIdentifier: "S-1-5-21-1-1000", ObjectType: ad.Computer.String()},
+		{ObjectIdentifier: "2A3B6A8C-4F35-4D14-8D13-1B48F0B1C3A7", ObjectType: ad.OU.String()},
+	}, domain.ChildObjects)
+	require.Len(t, domain.Trusts, 1)
+	assert.Equal(t, ein.TrustDirectionBidirectional, domain.Trusts[0].TrustDirection)
+	assert.Equal(t, "Forest", domain.Trusts[0].TrustType)
+}
@@ -33,19 +33,22 @@ func NewIngestibleRelationship(source IngestibleEndpoint, target IngestibleEndpo
 }
 
 // IngestMatchStrategy defines how a node should be matched during ingestion—
-// either by its object ID (default) or by its name.
+// either by its object ID (default), by its name or by the value of another property.
 type IngestMatchStrategy string
 
 const (
-	MatchByID   IngestMatchStrategy = "id"
-	MatchByName IngestMatchStrategy = "name"
+	MatchByID       IngestMatchStrategy = "id"
+	MatchByName     IngestMatchStrategy = "name"
+	MatchByProperty IngestMatchStrategy = "property"
 )
 
 // IngestibleEndpoint represents a node reference in a relationship to be ingested.
 type IngestibleEndpoint struct {
-	Value   string              // The actual lookup value (either objectid or name)
-	MatchBy IngestMatchStrategy // Strategy used to resolve the node
-	Kind    graph.Kind          // Optional kind filter to help disambiguate nodes
+	Value         string              // The actual lookup value (objectid, name or property value)
+	MatchBy       IngestMatchStrategy // Strategy used to resolve the node
+	Kind          graph.Kind          // Optional kind filter to help disambiguate nodes
+	Property      string              // The property to match on when matching by property
+	CaseSensitive bool                // Whether property values are compared with case when matching by property
 }
 
 type IngestibleRel struct {
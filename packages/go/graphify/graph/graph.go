@@ -181,7 +181,7 @@ func (s *CommunityGraphService) Ingest(ctx context.Context, batch *graphify.Time
 }
 
 func (s *CommunityGraphService) RunAnalysis(ctx context.Context, graphDB graph.Database) error {
-	return datapipe.RunAnalysisOperations(ctx, s.db, graphDB, config.Configuration{})
+	return datapipe.RunAnalysisOperations(ctx, s.db, graphDB, config.Configuration{}, datapipe.AnalysisScope{})
 }
 
 // Run generate command
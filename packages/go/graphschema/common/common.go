@@ -68,10 +68,11 @@ const (
 	IsInherited     Property = "isinherited"
 	CompositionID   Property = "compositionid"
 	PrimaryKind     Property = "primarykind"
+	Environment     Property = "environment"
 )
 
 func AllProperties() []Property {
-	return []Property{ObjectID, Name, DisplayName, Description, OwnerObjectID, Collected, OperatingSystem, SystemTags, UserTags, LastSeen, LastCollected, WhenCreated, Enabled, PasswordLastSet, Title, Email, IsInherited, CompositionID, PrimaryKind}
+	return []Property{ObjectID, Name, DisplayName, Description, OwnerObjectID, Collected, OperatingSystem, SystemTags, UserTags, LastSeen, LastCollected, WhenCreated, Enabled, PasswordLastSet, Title, Email, IsInherited, CompositionID, PrimaryKind, Environment}
 }
 func ParseProperty(source string) (Property, error) {
 	switch source {
@@ -113,6 +114,8 @@ func ParseProperty(source string) (Property, error) {
 		return CompositionID, nil
 	case "primarykind":
 		return PrimaryKind, nil
+	case "environment":
+		return Environment, nil
 	default:
 		return "", errors.New("Invalid enumeration value: " + source)
 	}
@@ -157,6 +160,8 @@ func (s Property) String() string {
 		return string(CompositionID)
 	case PrimaryKind:
 		return string(PrimaryKind)
+	case Environment:
+		return string(Environment)
 	default:
 		return "Invalid enumeration case: " + string(s)
 	}
@@ -201,6 +206,8 @@ func (s Property) Name() string {
 		return "Composition ID"
 	case PrimaryKind:
 		return "Primary Kind"
+	case Environment:
+		return "Environment"
 	default:
 		return "Invalid enumeration case: " + string(s)
 	}
@@ -1265,6 +1265,7 @@ export enum CommonKindProperties {
     IsInherited = 'isinherited',
     CompositionID = 'compositionid',
     PrimaryKind = 'primarykind',
+    Environment = 'environment',
 }
 export function CommonKindPropertiesToDisplay(value: CommonKindProperties): string | undefined {
     switch (value) {
@@ -1306,6 +1307,8 @@ export function CommonKindPropertiesToDisplay(value: CommonKindProperties): stri
             return 'Composition ID';
         case CommonKindProperties.PrimaryKind:
             return 'Primary Kind';
+        case CommonKindProperties.Environment:
+            return 'Environment';
         default:
             return undefined;
     }